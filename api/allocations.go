@@ -321,6 +321,8 @@ type AllocationMetric struct {
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	GangFailed        []string
+	ScoringWeights    *SchedulerScoringWeights
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -427,6 +429,7 @@ type AllocDeploymentStatus struct {
 	Healthy     *bool
 	Timestamp   time.Time
 	Canary      bool
+	Replaces    string
 	ModifyIndex uint64
 }
 
@@ -202,6 +202,16 @@ type Config struct {
 
 	Headers http.Header
 
+	// InjectTraceContext, if set, is called with the context and headers of
+	// every request so that the trace the request is part of can be continued
+	// by Nomad. Nomad accepts W3C Trace Context headers, which are injected
+	// by the OpenTelemetry SDK with:
+	//
+	//	func(ctx context.Context, h http.Header) {
+	//		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+	//	}
+	InjectTraceContext func(ctx context.Context, header http.Header)
+
 	// retryOptions holds the configuration necessary to perform retries
 	// on put calls.
 	retryOptions *retryOptions
@@ -234,6 +244,8 @@ func (c *Config) ClientConfig(region, address string, tlsEnabled bool) *Config {
 		HttpAuth:   c.HttpAuth,
 		WaitTime:   c.WaitTime,
 		TLSConfig:  c.TLSConfig.Copy(),
+
+		InjectTraceContext: c.InjectTraceContext,
 	}
 
 	// Update the tls server name for connecting to a client
@@ -798,6 +810,9 @@ func (r *request) toHTTP() (*http.Request, error) {
 	if r.token != "" {
 		req.Header.Set("X-Nomad-Token", r.token)
 	}
+	if r.config.InjectTraceContext != nil {
+		r.config.InjectTraceContext(ctx, req.Header)
+	}
 
 	req.URL.Host = r.url.Host
 	req.URL.Scheme = r.url.Scheme
@@ -294,6 +294,34 @@ func TestRequestToHTTP(t *testing.T) {
 	}
 }
 
+func TestRequestToHTTP_InjectTraceContext(t *testing.T) {
+	testutil.Parallel(t)
+
+	type traceKey struct{}
+	conf := DefaultConfig()
+	conf.InjectTraceContext = func(ctx context.Context, h http.Header) {
+		if parent, ok := ctx.Value(traceKey{}).(string); ok {
+			h.Set("Traceparent", parent)
+		}
+	}
+	c, err := NewClient(conf)
+	must.NoError(t, err)
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	r, err := c.newRequest("GET", "/v1/jobs")
+	must.NoError(t, err)
+	r.setQueryOptions((&QueryOptions{}).WithContext(
+		context.WithValue(context.Background(), traceKey{}, parent)))
+
+	req, err := r.toHTTP()
+	must.NoError(t, err)
+	must.Eq(t, parent, req.Header.Get("Traceparent"))
+
+	// Requests to client agents propagate the trace context too
+	nodeConf := conf.ClientConfig("global", "127.0.0.1:4646", false)
+	must.NotNil(t, nodeConf.InjectTraceContext)
+}
+
 func TestParseQueryMeta(t *testing.T) {
 	testutil.Parallel(t)
 	resp := &http.Response{
@@ -14,6 +14,8 @@ const (
 	ConstraintSetContainsAny    = "set_contains_any"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintColocatedWith     = "colocated_with"
+	ConstraintNotColocatedWith  = "not_colocated_with"
 )
 
 // Constraint is used to serialize a job placement constraint.
@@ -207,10 +207,31 @@ type DeploymentState struct {
 	RequireProgressBy time.Time
 	Promoted          bool
 	DesiredCanaries   int
+	DesiredSurge      int
 	DesiredTotal      int
 	PlacedAllocs      int
 	HealthyAllocs     int
 	UnhealthyAllocs   int
+	Analysis          *DeploymentAnalysis
+}
+
+// DeploymentAnalysis is the state of the canary analysis of a task group in a
+// deployment.
+type DeploymentAnalysis struct {
+	PassedRuns int
+	FailedRuns int
+	LastRun    time.Time
+	Metrics    []*AnalysisMetricResult
+}
+
+// AnalysisMetricResult is the result of a metric in the last run of a canary
+// analysis.
+type AnalysisMetricResult struct {
+	Name   string
+	Canary float64
+	Stable *float64
+	Passed bool
+	Error  string
 }
 
 // DeploymentIndexSort is a wrapper to sort deployments by CreateIndex. We
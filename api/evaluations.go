@@ -120,6 +120,7 @@ type Evaluation struct {
 	ClassEligibility     map[string]bool
 	EscapedComputedClass bool
 	QuotaLimitReached    string
+	UnmetDependencies    []*JobDependency
 	AnnotatePlan         bool
 	QueuedAllocations    map[string]int
 	SnapshotIndex        uint64
@@ -21,6 +21,7 @@ const (
 	TopicNode       Topic = "Node"
 	TopicNodePool   Topic = "NodePool"
 	TopicService    Topic = "Service"
+	TopicVariable   Topic = "Variable"
 	TopicAll        Topic = "*"
 )
 
@@ -90,6 +91,17 @@ func (e *Event) Job() (*Job, error) {
 	return out.Job, nil
 }
 
+// PeriodicLaunch returns a PeriodicLaunch struct from a given event payload.
+// If the Event Type is PeriodicLaunchDecision this will return a valid
+// PeriodicLaunch.
+func (e *Event) PeriodicLaunch() (*PeriodicLaunch, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.PeriodicLaunch, nil
+}
+
 // Node returns a Node struct from a given event payload. If the
 // Event Topic is Node this will return a valid Node.
 func (e *Event) Node() (*Node, error) {
@@ -120,14 +132,27 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// Variable returns a VariableMetadata struct from a given event payload. If
+// the Event Topic is Variable this will return valid VariableMetadata. The
+// variable items are never included in events.
+func (e *Event) Variable() (*VariableMetadata, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Variable, nil
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	NodePool   *NodePool            `mapstructure:"NodePool"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation     *Allocation          `mapstructure:"Allocation"`
+	Deployment     *Deployment          `mapstructure:"Deployment"`
+	Evaluation     *Evaluation          `mapstructure:"Evaluation"`
+	Job            *Job                 `mapstructure:"Job"`
+	Node           *Node                `mapstructure:"Node"`
+	NodePool       *NodePool            `mapstructure:"NodePool"`
+	PeriodicLaunch *PeriodicLaunch      `mapstructure:"PeriodicLaunch"`
+	Service        *ServiceRegistration `mapstructure:"Service"`
+	Variable       *VariableMetadata    `mapstructure:"Variable"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
@@ -293,6 +293,23 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 				}, j)
 			},
 		},
+		{
+			desc:  "periodic_launch",
+			input: []byte(`{"Topic": "Job", "Type": "PeriodicLaunchDecision", "Payload": {"PeriodicLaunch":{"ID":"some-id","Namespace":"some-namespace-id","Decision":{"Decision":"skipped","Launch":"2024-03-01T02:00:00Z"}}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicJob, event.Topic)
+				l, err := event.PeriodicLaunch()
+				must.NoError(t, err)
+				must.Eq(t, &PeriodicLaunch{
+					ID:        "some-id",
+					Namespace: "some-namespace-id",
+					Decision: &PeriodicLaunchDecision{
+						Decision: PeriodicDecisionSkipped,
+						Launch:   time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC),
+					},
+				}, l)
+			},
+		},
 		{
 			desc:  "node",
 			input: []byte(`{"Topic": "Node", "Payload": {"Node":{"ID":"some-id","Datacenter":"some-dc-id"}}}`),
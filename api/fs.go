@@ -4,10 +4,14 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
+	"regexp"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -323,6 +327,263 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 	return frames, errCh
 }
 
+// JobLogsOptions are the options used when streaming the logs of every running
+// allocation of a job.
+type JobLogsOptions struct {
+	// TaskGroup restricts the logs to the allocations of a task group. If
+	// empty, the allocations of every task group are used.
+	TaskGroup string
+
+	// Task is the task to stream the logs of. If empty, the logs of every task
+	// in the allocations are streamed.
+	Task string
+
+	// LogType is either "stdout" or "stderr".
+	LogType string
+
+	// Follow causes the logs to be followed, and allocations placed while
+	// following to be picked up.
+	Follow bool
+
+	// Origin and Offset define where streaming starts for the allocations
+	// running when streaming begins. Allocations placed while following are
+	// always streamed from the start of their logs.
+	Origin string
+	Offset int64
+
+	// Filter, if set, only emits the lines which match it.
+	Filter *regexp.Regexp
+}
+
+// JobLogLine is a single line of the logs of a task in one of the allocations
+// of a job.
+type JobLogLine struct {
+	AllocID   string
+	AllocName string
+	TaskGroup string
+	Task      string
+
+	// Line is the content of the line without its trailing newline.
+	Line string
+}
+
+// jobLogsRetryInterval is the time to wait before retrying to list the
+// allocations of a job when following its logs.
+var jobLogsRetryInterval = 5 * time.Second
+
+// JobLogs streams the logs of every running allocation of a job, split into
+// lines. Each allocation is streamed using Logs.
+//
+// The return value is a channel that will emit the lines as they are read.
+// The chan will be closed once the logs of every allocation have been read
+// when opts.Follow=false, or when cancel is closed.
+//
+// Errors streaming the logs of an allocation are sent on the error chan and
+// don't stop the logs of the other allocations from being streamed. If the
+// allocations of the job can't be listed when starting, the error is sent
+// and the line chan is closed.
+func (a *AllocFS) JobLogs(jobID string, opts *JobLogsOptions, cancel <-chan struct{},
+	q *QueryOptions) (<-chan *JobLogLine, <-chan error) {
+
+	if opts == nil {
+		opts = &JobLogsOptions{}
+	}
+	if opts.LogType == "" {
+		opts.LogType = FSLogNameStdout
+	}
+	if opts.Origin == "" {
+		opts.Origin = OriginStart
+	}
+
+	lines := make(chan *JobLogLine, 10)
+	errCh := make(chan error, 1)
+
+	// Blocking queries for new allocations are canceled along with the log
+	// streams.
+	ctx, cancelCtx := context.WithCancel(q.Context())
+	go func() {
+		select {
+		case <-cancel:
+		case <-ctx.Done():
+		}
+		cancelCtx()
+	}()
+
+	allocs, meta, err := a.client.Jobs().Allocations(jobID, false, copyQueryOptions(q, ctx))
+	if err != nil {
+		cancelCtx()
+		errCh <- err
+		close(lines)
+		return lines, errCh
+	}
+
+	var wg sync.WaitGroup
+	seen := make(map[string]struct{})
+	start := func(allocs []*AllocationListStub, origin string, offset int64) {
+		for _, alloc := range allocs {
+			if _, ok := seen[alloc.ID]; ok || !jobLogsAllocRunning(alloc, opts.TaskGroup) {
+				continue
+			}
+			seen[alloc.ID] = struct{}{}
+
+			for _, task := range jobLogsTasks(alloc, opts.Task) {
+				wg.Add(1)
+				go func(alloc *AllocationListStub, task string) {
+					defer wg.Done()
+					a.streamJobLogs(ctx, alloc, task, opts, origin, offset, q, lines, errCh)
+				}(alloc, task)
+			}
+		}
+	}
+	start(allocs, opts.Origin, opts.Offset)
+
+	if opts.Follow {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			index := meta.LastIndex
+			for {
+				wq := copyQueryOptions(q, ctx)
+				wq.WaitIndex = index
+				allocs, meta, err := a.client.Jobs().Allocations(jobID, false, wq)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					sendJobLogsError(ctx, errCh, fmt.Errorf("failed to list allocations: %w", err))
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(jobLogsRetryInterval):
+					}
+					continue
+				}
+				index = meta.LastIndex
+
+				// Allocations placed while following are new, so all of their
+				// logs are streamed.
+				start(allocs, OriginStart, 0)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		cancelCtx()
+		close(lines)
+	}()
+
+	return lines, errCh
+}
+
+// streamJobLogs streams the logs of a task of an allocation to lines, until
+// the end of the logs is reached or ctx is canceled.
+func (a *AllocFS) streamJobLogs(ctx context.Context, alloc *AllocationListStub, task string,
+	opts *JobLogsOptions, origin string, offset int64, q *QueryOptions,
+	lines chan<- *JobLogLine, errCh chan<- error) {
+
+	stub := &Allocation{ID: alloc.ID, NodeID: alloc.NodeID, Namespace: alloc.Namespace}
+	frames, errs := a.Logs(stub, opts.Follow, task, opts.LogType, origin, offset,
+		ctx.Done(), copyQueryOptions(q, ctx))
+
+	emit := func(line []byte) bool {
+		if opts.Filter != nil && !opts.Filter.Match(line) {
+			return true
+		}
+		select {
+		case lines <- &JobLogLine{
+			AllocID:   alloc.ID,
+			AllocName: alloc.Name,
+			TaskGroup: alloc.TaskGroup,
+			Task:      task,
+			Line:      string(line),
+		}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var buf []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errs:
+			if ctx.Err() == nil {
+				sendJobLogsError(ctx, errCh, fmt.Errorf("failed to stream logs of task %q in allocation %s: %w",
+					task, alloc.ID, err))
+			}
+			return
+		case frame, ok := <-frames:
+			if !ok || frame == nil {
+				// Emit what remains of a line without a trailing newline.
+				if len(buf) > 0 {
+					emit(buf)
+				}
+				return
+			}
+			buf = append(buf, frame.Data...)
+			for {
+				idx := bytes.IndexByte(buf, '\n')
+				if idx == -1 {
+					break
+				}
+				line := buf[:idx]
+				buf = buf[idx+1:]
+				if !emit(line) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// jobLogsAllocRunning returns whether the logs of an allocation should be
+// streamed.
+func jobLogsAllocRunning(alloc *AllocationListStub, taskGroup string) bool {
+	if taskGroup != "" && alloc.TaskGroup != taskGroup {
+		return false
+	}
+	return alloc.DesiredStatus == AllocDesiredStatusRun &&
+		alloc.ClientStatus == AllocClientStatusRunning
+}
+
+// jobLogsTasks returns the tasks of an allocation to stream the logs of.
+func jobLogsTasks(alloc *AllocationListStub, task string) []string {
+	if task != "" {
+		return []string{task}
+	}
+	tasks := make([]string, 0, len(alloc.TaskStates))
+	for name := range alloc.TaskStates {
+		tasks = append(tasks, name)
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// sendJobLogsError sends err without blocking once ctx is canceled.
+func sendJobLogsError(ctx context.Context, errCh chan<- error, err error) {
+	select {
+	case errCh <- err:
+	case <-ctx.Done():
+	}
+}
+
+// copyQueryOptions returns a copy of q using ctx, which is safe to use
+// concurrently with other copies.
+func copyQueryOptions(q *QueryOptions, ctx context.Context) *QueryOptions {
+	c := q.WithContext(ctx)
+	if q != nil && q.Params != nil {
+		c.Params = make(map[string]string, len(q.Params))
+		for k, v := range q.Params {
+			c.Params[k] = v
+		}
+	}
+	return c
+}
+
 // FrameReader is used to convert a stream of frames into a read closer.
 type FrameReader struct {
 	frames   <-chan *StreamFrame
@@ -141,6 +141,29 @@ func TestFS_Logs(t *testing.T) {
 	}
 }
 
+func TestFS_JobLogsAllocs(t *testing.T) {
+	testutil.Parallel(t)
+
+	alloc := &AllocationListStub{
+		TaskGroup:     "web",
+		DesiredStatus: AllocDesiredStatusRun,
+		ClientStatus:  AllocClientStatusRunning,
+		TaskStates: map[string]*TaskState{
+			"server":  {},
+			"sidecar": {},
+		},
+	}
+	must.True(t, jobLogsAllocRunning(alloc, ""))
+	must.True(t, jobLogsAllocRunning(alloc, "web"))
+	must.False(t, jobLogsAllocRunning(alloc, "db"))
+
+	alloc.ClientStatus = AllocClientStatusPending
+	must.False(t, jobLogsAllocRunning(alloc, ""))
+
+	must.Eq(t, []string{"server", "sidecar"}, jobLogsTasks(alloc, ""))
+	must.Eq(t, []string{"server"}, jobLogsTasks(alloc, "server"))
+}
+
 func TestFS_FrameReader(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -37,6 +37,29 @@ const (
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
 
+	// PeriodicCatchUpSkip, PeriodicCatchUpLatest and PeriodicCatchUpAll are
+	// the policies for the launches a periodic job missed.
+	PeriodicCatchUpSkip   = "skip"
+	PeriodicCatchUpLatest = "latest"
+	PeriodicCatchUpAll    = "all"
+
+	// PeriodicConcurrencyAllow, PeriodicConcurrencyForbid,
+	// PeriodicConcurrencyReplace and PeriodicConcurrencyQueue are the
+	// policies for the launches of a periodic job while a previous instance
+	// is running.
+	PeriodicConcurrencyAllow   = "allow"
+	PeriodicConcurrencyForbid  = "forbid"
+	PeriodicConcurrencyReplace = "replace"
+	PeriodicConcurrencyQueue   = "queue"
+
+	// PeriodicDecisionSkipped, PeriodicDecisionReplaced,
+	// PeriodicDecisionQueued and PeriodicDecisionDequeued are the decisions
+	// taken by the concurrency policy of a periodic job.
+	PeriodicDecisionSkipped  = "skipped"
+	PeriodicDecisionReplaced = "replaced"
+	PeriodicDecisionQueued   = "queued"
+	PeriodicDecisionDequeued = "dequeued"
+
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace = "default"
 
@@ -484,6 +507,17 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 	return resp.EvalID, wm, nil
 }
 
+// PeriodicLaunch returns the last launch of the periodic job and the last
+// decision taken by its concurrency policy.
+func (j *Jobs) PeriodicLaunch(jobID string, q *QueryOptions) (*PeriodicLaunch, *QueryMeta, error) {
+	var resp PeriodicLaunch
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/periodic/launch", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // PlanOptions is used to pass through job planning parameters
 type PlanOptions struct {
 	Diff           bool
@@ -617,17 +651,102 @@ type periodicForceResponse struct {
 	EvalID string
 }
 
+// PeriodicLaunch is the last launch of a periodic job.
+type PeriodicLaunch struct {
+	ID          string
+	Namespace   string
+	Launch      time.Time
+	Decision    *PeriodicLaunchDecision
+	Queued      time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// PeriodicLaunchDecision is a decision taken by the concurrency policy of a
+// periodic job for one of its launches.
+type PeriodicLaunchDecision struct {
+	Decision    string
+	Description string
+	Launch      time.Time
+	Time        time.Time
+}
+
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
-	Stagger          *time.Duration `mapstructure:"stagger" hcl:"stagger,optional"`
-	MaxParallel      *int           `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
-	HealthCheck      *string        `mapstructure:"health_check" hcl:"health_check,optional"`
-	MinHealthyTime   *time.Duration `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
-	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
-	ProgressDeadline *time.Duration `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
-	Canary           *int           `mapstructure:"canary" hcl:"canary,optional"`
-	AutoRevert       *bool          `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
-	AutoPromote      *bool          `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Stagger          *time.Duration  `mapstructure:"stagger" hcl:"stagger,optional"`
+	MaxParallel      *int            `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
+	MaxSurge         *int            `mapstructure:"max_surge" hcl:"max_surge,optional"`
+	HealthCheck      *string         `mapstructure:"health_check" hcl:"health_check,optional"`
+	MinHealthyTime   *time.Duration  `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
+	HealthyDeadline  *time.Duration  `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
+	ProgressDeadline *time.Duration  `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
+	Canary           *int            `mapstructure:"canary" hcl:"canary,optional"`
+	CanaryPercent    *int            `mapstructure:"canary_percent" hcl:"canary_percent,optional"`
+	AutoRevert       *bool           `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
+	AutoPromote      *bool           `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	BlueGreen        *bool           `mapstructure:"blue_green" hcl:"blue_green,optional"`
+	Analysis         *CanaryAnalysis `hcl:"analysis,block"`
+}
+
+// CanaryAnalysis configures the metric queries run periodically against the
+// canaries and the stable allocations of a task group during a deployment,
+// which gate the promotion of the canaries and may fail the deployment.
+type CanaryAnalysis struct {
+	Address      string            `hcl:"address"`
+	Interval     *time.Duration    `hcl:"interval,optional"`
+	Runs         *int              `hcl:"runs,optional"`
+	FailureLimit *int              `mapstructure:"failure_limit" hcl:"failure_limit,optional"`
+	Metrics      []*AnalysisMetric `hcl:"metric,block"`
+}
+
+// AnalysisMetric is a metric compared between the canaries and the stable
+// allocations by a canary analysis. The query is a template whose AllocIDs
+// field is a regular expression matching the IDs of the allocations.
+type AnalysisMetric struct {
+	Name     string   `hcl:"name,label"`
+	Query    string   `hcl:"query"`
+	Max      *float64 `hcl:"max,optional"`
+	MaxRatio *float64 `mapstructure:"max_ratio" hcl:"max_ratio,optional"`
+}
+
+func (c *CanaryAnalysis) Canonicalize() {
+	if c.Interval == nil {
+		c.Interval = pointerOf(time.Minute)
+	}
+	if c.Runs == nil {
+		c.Runs = pointerOf(1)
+	}
+	if c.FailureLimit == nil {
+		c.FailureLimit = pointerOf(0)
+	}
+}
+
+func (c *CanaryAnalysis) Copy() *CanaryAnalysis {
+	if c == nil {
+		return nil
+	}
+
+	copy := &CanaryAnalysis{Address: c.Address}
+	if c.Interval != nil {
+		copy.Interval = pointerOf(*c.Interval)
+	}
+	if c.Runs != nil {
+		copy.Runs = pointerOf(*c.Runs)
+	}
+	if c.FailureLimit != nil {
+		copy.FailureLimit = pointerOf(*c.FailureLimit)
+	}
+	for _, m := range c.Metrics {
+		metric := &AnalysisMetric{Name: m.Name, Query: m.Query}
+		if m.Max != nil {
+			metric.Max = pointerOf(*m.Max)
+		}
+		if m.MaxRatio != nil {
+			metric.MaxRatio = pointerOf(*m.MaxRatio)
+		}
+		copy.Metrics = append(copy.Metrics, metric)
+	}
+	return copy
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -661,6 +780,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.MaxParallel = pointerOf(*u.MaxParallel)
 	}
 
+	if u.MaxSurge != nil {
+		copy.MaxSurge = pointerOf(*u.MaxSurge)
+	}
+
 	if u.HealthCheck != nil {
 		copy.HealthCheck = pointerOf(*u.HealthCheck)
 	}
@@ -685,10 +808,20 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.Canary = pointerOf(*u.Canary)
 	}
 
+	if u.CanaryPercent != nil {
+		copy.CanaryPercent = pointerOf(*u.CanaryPercent)
+	}
+
 	if u.AutoPromote != nil {
 		copy.AutoPromote = pointerOf(*u.AutoPromote)
 	}
 
+	if u.BlueGreen != nil {
+		copy.BlueGreen = pointerOf(*u.BlueGreen)
+	}
+
+	copy.Analysis = u.Analysis.Copy()
+
 	return copy
 }
 
@@ -705,6 +838,10 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.MaxParallel = pointerOf(*o.MaxParallel)
 	}
 
+	if o.MaxSurge != nil {
+		u.MaxSurge = pointerOf(*o.MaxSurge)
+	}
+
 	if o.HealthCheck != nil {
 		u.HealthCheck = pointerOf(*o.HealthCheck)
 	}
@@ -729,9 +866,21 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.Canary = pointerOf(*o.Canary)
 	}
 
+	if o.CanaryPercent != nil {
+		u.CanaryPercent = pointerOf(*o.CanaryPercent)
+	}
+
 	if o.AutoPromote != nil {
 		u.AutoPromote = pointerOf(*o.AutoPromote)
 	}
+
+	if o.BlueGreen != nil {
+		u.BlueGreen = pointerOf(*o.BlueGreen)
+	}
+
+	if o.Analysis != nil {
+		u.Analysis = o.Analysis.Copy()
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -772,6 +921,10 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.Analysis != nil {
+		u.Analysis.Canonicalize()
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -788,6 +941,10 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.MaxSurge != nil && *u.MaxSurge != 0 {
+		return false
+	}
+
 	if u.HealthCheck != nil && *u.HealthCheck != "" {
 		return false
 	}
@@ -816,6 +973,18 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.CanaryPercent != nil && *u.CanaryPercent != 0 {
+		return false
+	}
+
+	if u.BlueGreen != nil && *u.BlueGreen {
+		return false
+	}
+
+	if u.Analysis != nil {
+		return false
+	}
+
 	return true
 }
 
@@ -900,6 +1069,10 @@ type PeriodicConfig struct {
 	SpecType        *string
 	ProhibitOverlap *bool   `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
 	TimeZone        *string `mapstructure:"time_zone" hcl:"time_zone,optional"`
+
+	CatchUp           *string        `mapstructure:"catch_up" hcl:"catch_up,optional"`
+	StartingDeadline  *time.Duration `mapstructure:"starting_deadline" hcl:"starting_deadline,optional"`
+	ConcurrencyPolicy *string        `mapstructure:"concurrency_policy" hcl:"concurrency_policy,optional"`
 }
 
 func (p *PeriodicConfig) Canonicalize() {
@@ -921,6 +1094,19 @@ func (p *PeriodicConfig) Canonicalize() {
 	if p.TimeZone == nil || *p.TimeZone == "" {
 		p.TimeZone = pointerOf("UTC")
 	}
+	if p.CatchUp == nil || *p.CatchUp == "" {
+		p.CatchUp = pointerOf(PeriodicCatchUpSkip)
+	}
+	if p.StartingDeadline == nil {
+		p.StartingDeadline = pointerOf(time.Duration(0))
+	}
+	if p.ConcurrencyPolicy == nil || *p.ConcurrencyPolicy == "" {
+		if *p.ProhibitOverlap {
+			p.ConcurrencyPolicy = pointerOf(PeriodicConcurrencyForbid)
+		} else {
+			p.ConcurrencyPolicy = pointerOf(PeriodicConcurrencyAllow)
+		}
+	}
 }
 
 // Next returns the closest time instant matching the spec that is after the
@@ -977,9 +1163,30 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
-	Payload      string   `hcl:"payload,optional"`
-	MetaRequired []string `mapstructure:"meta_required" hcl:"meta_required,optional"`
-	MetaOptional []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	Payload       string   `hcl:"payload,optional"`
+	MetaRequired  []string `mapstructure:"meta_required" hcl:"meta_required,optional"`
+	MetaOptional  []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	MaxConcurrent int      `mapstructure:"max_concurrent" hcl:"max_concurrent,optional"`
+}
+
+// RebalancePolicy opts a service job into having its running allocations
+// moved by the servers to nodes they would score significantly better on.
+type RebalancePolicy struct {
+	Threshold     *float64       `hcl:"threshold,optional"`
+	MaxDisruption *int           `mapstructure:"max_disruption" hcl:"max_disruption,optional"`
+	Interval      *time.Duration `hcl:"interval,optional"`
+}
+
+func (r *RebalancePolicy) Canonicalize() {
+	if r.Threshold == nil {
+		r.Threshold = pointerOf(0.2)
+	}
+	if r.MaxDisruption == nil {
+		r.MaxDisruption = pointerOf(1)
+	}
+	if r.Interval == nil {
+		r.Interval = pointerOf(10 * time.Minute)
+	}
 }
 
 // JobSubmission is used to hold information about the original content of a job
@@ -1005,6 +1212,19 @@ type JobSubmission struct {
 	Variables string
 }
 
+const (
+	JobDependencyConditionComplete             = "complete"
+	JobDependencyConditionDeploymentSuccessful = "deployment_successful"
+	JobDependencyConditionHealthy              = "healthy"
+)
+
+// JobDependency is a job in the same namespace which must reach a condition
+// before a new version of the job depending on it is rolled out.
+type JobDependency struct {
+	JobID     string `mapstructure:"job" hcl:"job"`
+	Condition string `hcl:"condition"`
+}
+
 type JobUIConfig struct {
 	Description string       `hcl:"description,optional"`
 	Links       []*JobUILink `hcl:"link,block"`
@@ -1112,16 +1332,19 @@ type Job struct {
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
 	NodePool         *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
+	ActiveDeadline   *time.Duration          `mapstructure:"active_deadline" hcl:"active_deadline,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
 	Affinities       []*Affinity             `hcl:"affinity,block"`
 	TaskGroups       []*TaskGroup            `hcl:"group,block"`
 	Update           *UpdateStrategy         `hcl:"update,block"`
 	Multiregion      *Multiregion            `hcl:"multiregion,block"`
 	Spreads          []*Spread               `hcl:"spread,block"`
+	DependsOn        []*JobDependency        `mapstructure:"depends_on" hcl:"depends_on,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
 	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
+	Rebalance        *RebalancePolicy        `hcl:"rebalance,block"`
 	Meta             map[string]string       `hcl:"meta,block"`
 	UI               *JobUIConfig            `hcl:"ui,block"`
 
@@ -1130,6 +1353,7 @@ type Job struct {
 	Stop                     *bool
 	ParentID                 *string
 	Dispatched               bool
+	DispatchQueued           bool
 	DispatchIdempotencyToken *string
 	Payload                  []byte
 	ConsulNamespace          *string `mapstructure:"consul_namespace"`
@@ -1233,6 +1457,9 @@ func (j *Job) Canonicalize() {
 	if j.Multiregion != nil {
 		j.Multiregion.Canonicalize()
 	}
+	if j.Rebalance != nil {
+		j.Rebalance.Canonicalize()
+	}
 
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
@@ -1274,6 +1501,7 @@ type JobSummary struct {
 
 // JobChildrenSummary contains the summary of children job status
 type JobChildrenSummary struct {
+	Queued  int64
 	Pending int64
 	Running int64
 	Dead    int64
@@ -1284,7 +1512,7 @@ func (jc *JobChildrenSummary) Sum() int {
 		return 0
 	}
 
-	return int(jc.Pending + jc.Running + jc.Dead)
+	return int(jc.Queued + jc.Pending + jc.Running + jc.Dead)
 }
 
 // TaskGroup summarizes the state of all the allocations of a particular
@@ -1615,6 +1843,7 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+	Queued          bool
 	WriteMeta
 }
 
@@ -812,12 +812,15 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoPromote:      pointerOf(false),
 				},
 				Periodic: &PeriodicConfig{
-					Enabled:         pointerOf(true),
-					Spec:            pointerOf(""),
-					Specs:           []string{},
-					SpecType:        pointerOf(PeriodicSpecCron),
-					ProhibitOverlap: pointerOf(false),
-					TimeZone:        pointerOf("UTC"),
+					Enabled:           pointerOf(true),
+					Spec:              pointerOf(""),
+					Specs:             []string{},
+					SpecType:          pointerOf(PeriodicSpecCron),
+					ProhibitOverlap:   pointerOf(false),
+					TimeZone:          pointerOf("UTC"),
+					CatchUp:           pointerOf(PeriodicCatchUpSkip),
+					StartingDeadline:  pointerOf(time.Duration(0)),
+					ConcurrencyPolicy: pointerOf(PeriodicConcurrencyAllow),
 				},
 			},
 		},
@@ -2040,6 +2043,31 @@ func TestJobs_PeriodicForce(t *testing.T) {
 	must.Eq(t, eval.ID, evalID)
 }
 
+func TestJobs_PeriodicLaunch(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	jobs := c.Jobs()
+
+	// A nonexistent job has no launch
+	_, _, err := jobs.PeriodicLaunch("job1", nil)
+	must.ErrorContains(t, err, "not found")
+
+	// Create a new job
+	job := testPeriodicJob()
+	_, _, err = jobs.Register(job, nil)
+	must.NoError(t, err)
+
+	// The launch is recorded when the job is registered
+	launch, qm, err := jobs.PeriodicLaunch(*job.ID, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.Eq(t, *job.ID, launch.ID)
+	must.Nil(t, launch.Decision)
+}
+
 func TestJobs_Plan(t *testing.T) {
 	testutil.Parallel(t)
 
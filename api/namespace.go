@@ -70,16 +70,17 @@ func (n *Namespaces) Delete(namespace string, q *WriteOptions) (*WriteMeta, erro
 
 // Namespace is used to serialize a namespace.
 type Namespace struct {
-	Name                  string
-	Description           string
-	Quota                 string
-	Capabilities          *NamespaceCapabilities          `hcl:"capabilities,block"`
-	NodePoolConfiguration *NamespaceNodePoolConfiguration `hcl:"node_pool_config,block"`
-	VaultConfiguration    *NamespaceVaultConfiguration    `hcl:"vault,block"`
-	ConsulConfiguration   *NamespaceConsulConfiguration   `hcl:"consul,block"`
-	Meta                  map[string]string
-	CreateIndex           uint64
-	ModifyIndex           uint64
+	Name                   string
+	Description            string
+	Quota                  string
+	Capabilities           *NamespaceCapabilities           `hcl:"capabilities,block"`
+	NodePoolConfiguration  *NamespaceNodePoolConfiguration  `hcl:"node_pool_config,block"`
+	VaultConfiguration     *NamespaceVaultConfiguration     `hcl:"vault,block"`
+	ConsulConfiguration    *NamespaceConsulConfiguration    `hcl:"consul,block"`
+	VariablesConfiguration *NamespaceVariablesConfiguration `hcl:"variables,block"`
+	Meta                   map[string]string
+	CreateIndex            uint64
+	ModifyIndex            uint64
 }
 
 // NamespaceCapabilities represents a set of capabilities allowed for this
@@ -143,6 +144,20 @@ type NamespaceConsulConfiguration struct {
 	Denied []string
 }
 
+// NamespaceVariablesConfiguration stores configuration about how variables in
+// the namespace are stored.
+type NamespaceVariablesConfiguration struct {
+	// RetainVersions is the number of previous versions of each variable in
+	// the namespace that are kept after the variable is updated or deleted. A
+	// value of 0 disables variable versioning.
+	RetainVersions int `hcl:"retain_versions"`
+
+	// PathRetainVersions overrides RetainVersions for variables under a given
+	// path prefix. When more than one prefix matches a variable path, the
+	// longest prefix wins.
+	PathRetainVersions map[string]int `hcl:"path_retain_versions"`
+}
+
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
 // reverse the test so that we get the highest index first.
 type NamespaceIndexSort []*Namespace
@@ -170,6 +170,10 @@ type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
 	SchedulerAlgorithm SchedulerAlgorithm
 
+	// ScoringWeights are the weights of each resource used by the weighted
+	// scheduling algorithm.
+	ScoringWeights *SchedulerScoringWeights
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
@@ -185,6 +189,14 @@ type SchedulerConfiguration struct {
 	// until the configuration is updated and written to the Nomad servers.
 	PauseEvalBroker bool
 
+	// EvalDequeuePolicy is the order in which the leader evaluation broker
+	// dequeues evaluations.
+	EvalDequeuePolicy EvalDequeuePolicy
+
+	// NamespaceWeights are the number of evaluations of each namespace
+	// dequeued in a row by the fair share eval dequeue policy.
+	NamespaceWeights map[string]int
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -214,10 +226,29 @@ type SchedulerSetConfigurationResponse struct {
 type SchedulerAlgorithm string
 
 const (
-	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
-	SchedulerAlgorithmSpread  SchedulerAlgorithm = "spread"
+	SchedulerAlgorithmBinpack  SchedulerAlgorithm = "binpack"
+	SchedulerAlgorithmSpread   SchedulerAlgorithm = "spread"
+	SchedulerAlgorithmWeighted SchedulerAlgorithm = "weighted"
 )
 
+// EvalDequeuePolicy is an enum string that encapsulates the valid options for
+// a SchedulerConfiguration block's EvalDequeuePolicy.
+type EvalDequeuePolicy string
+
+const (
+	EvalDequeuePolicyPriority  EvalDequeuePolicy = "priority"
+	EvalDequeuePolicyFairShare EvalDequeuePolicy = "fair_share"
+)
+
+// SchedulerScoringWeights are the relative weights given to each resource
+// when the weighted scheduling algorithm scores nodes.
+type SchedulerScoringWeights struct {
+	CPU       float64 `hcl:"cpu,optional"`
+	Memory    float64 `hcl:"memory,optional"`
+	MemoryMax float64 `hcl:"memory_max,optional"`
+	Devices   float64 `hcl:"devices,optional"`
+}
+
 // PreemptionConfig specifies whether preemption is enabled based on scheduler type
 type PreemptionConfig struct {
 	SystemSchedulerEnabled   bool
@@ -103,6 +103,7 @@ type RestartPolicy struct {
 	Delay           *time.Duration `hcl:"delay,optional"`
 	Mode            *string        `hcl:"mode,optional"`
 	RenderTemplates *bool          `mapstructure:"render_templates" hcl:"render_templates,optional"`
+	ExitRules       []*ExitRule    `mapstructure:"exit_rule" hcl:"exit_rule,block"`
 }
 
 func (r *RestartPolicy) Merge(rp *RestartPolicy) {
@@ -121,6 +122,25 @@ func (r *RestartPolicy) Merge(rp *RestartPolicy) {
 	if rp.RenderTemplates != nil {
 		r.RenderTemplates = rp.RenderTemplates
 	}
+	if rp.ExitRules != nil {
+		r.ExitRules = rp.ExitRules
+	}
+}
+
+// ExitRule overrides the default handling of the exit of a task by a restart
+// or reschedule policy when the task exits with one of the exit codes or is
+// killed by one of the signals.
+type ExitRule struct {
+	// ExitCodes are the exit codes matched by the rule.
+	ExitCodes []int `mapstructure:"exit_codes" hcl:"exit_codes,optional"`
+
+	// Signals are the signal numbers matched by the rule.
+	Signals []int `mapstructure:"signals" hcl:"signals,optional"`
+
+	// Action is what happens when the rule matches. Valid values are
+	// "restart", "reschedule", "fail", and "succeed". Rules of a reschedule
+	// policy only accept "reschedule" and "fail".
+	Action string `mapstructure:"action" hcl:"action,optional"`
 }
 
 // Disconnect strategy defines how both clients and server should behave in case of
@@ -175,6 +195,10 @@ type ReschedulePolicy struct {
 
 	// Unlimited allows rescheduling attempts until they succeed
 	Unlimited *bool `mapstructure:"unlimited" hcl:"unlimited,optional"`
+
+	// ExitRules override whether an allocation is rescheduled based on the
+	// exit codes and signals of its failed tasks.
+	ExitRules []*ExitRule `mapstructure:"exit_rule" hcl:"exit_rule,block"`
 }
 
 func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
@@ -199,6 +223,9 @@ func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
 	if rp.Unlimited != nil {
 		r.Unlimited = rp.Unlimited
 	}
+	if rp.ExitRules != nil {
+		r.ExitRules = rp.ExitRules
+	}
 }
 
 func (r *ReschedulePolicy) Canonicalize(jobType string) {
@@ -322,9 +349,12 @@ func (r *ReschedulePolicy) String() string {
 
 // Spread is used to serialize task group allocation spread preferences
 type Spread struct {
-	Attribute    string          `hcl:"attribute,optional"`
-	Weight       *int8           `hcl:"weight,optional"`
-	SpreadTarget []*SpreadTarget `hcl:"target,block"`
+	Attribute           string          `hcl:"attribute,optional"`
+	Weight              *int8           `hcl:"weight,optional"`
+	SpreadTarget        []*SpreadTarget `hcl:"target,block"`
+	MaxSkew             int             `mapstructure:"max_skew" hcl:"max_skew,optional"`
+	MinDomains          int             `mapstructure:"min_domains" hcl:"min_domains,optional"`
+	InsufficientDomains string          `mapstructure:"insufficient_domains" hcl:"insufficient_domains,optional"`
 }
 
 // SpreadTarget is used to serialize target allocation spread percentages
@@ -490,6 +520,7 @@ func (vm *VolumeMount) Canonicalize() {
 type TaskGroup struct {
 	Name             *string                   `hcl:"name,label"`
 	Count            *int                      `hcl:"count,optional"`
+	Gang             *string                   `hcl:"gang,optional"`
 	Constraints      []*Constraint             `hcl:"constraint,block"`
 	Affinities       []*Affinity               `hcl:"affinity,block"`
 	Tasks            []*Task                   `hcl:"task,block"`
@@ -505,6 +536,7 @@ type TaskGroup struct {
 	Meta             map[string]string         `hcl:"meta,block"`
 	Services         []*Service                `hcl:"service,block"`
 	ShutdownDelay    *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
+	ActiveDeadline   *time.Duration            `mapstructure:"active_deadline" hcl:"active_deadline,optional"`
 	// Deprecated: StopAfterClientDisconnect is deprecated in Nomad 1.8 and ignored in Nomad 1.10. Use Disconnect.StopOnClientAfter.
 	StopAfterClientDisconnect *time.Duration `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	// Deprecated: MaxClientDisconnect is deprecated in Nomad 1.8.0 and ignored in Nomad 1.10. Use Disconnect.LostAfter.
@@ -713,6 +745,29 @@ type LogConfig struct {
 	Enabled *bool `mapstructure:"enabled" hcl:"enabled,optional"`
 
 	Disabled *bool `mapstructure:"disabled" hcl:"disabled,optional"`
+
+	// Sink optionally forwards the task's logs to an external sink.
+	Sink *LogSinkConfig `mapstructure:"sink" hcl:"sink,block"`
+}
+
+const (
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeUnix   = "unix"
+	LogSinkTypeHTTP   = "http"
+)
+
+// LogSinkConfig configures an external sink the lines of a task's logs are
+// forwarded to.
+type LogSinkConfig struct {
+	Type       string `hcl:"type"`
+	Address    string `hcl:"address"`
+	BufferSize *int   `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+}
+
+func (l *LogSinkConfig) Canonicalize() {
+	if l.BufferSize == nil {
+		l.BufferSize = pointerOf(1024)
+	}
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -733,6 +788,9 @@ func (l *LogConfig) Canonicalize() {
 	if l.Disabled == nil {
 		l.Disabled = pointerOf(false)
 	}
+	if l.Sink != nil {
+		l.Sink.Canonicalize()
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -771,6 +829,7 @@ type Task struct {
 	RestartPolicy   *RestartPolicy         `hcl:"restart,block"`
 	Meta            map[string]string      `hcl:"meta,block"`
 	KillTimeout     *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
+	ActiveDeadline  *time.Duration         `mapstructure:"active_deadline" hcl:"active_deadline,optional"`
 	LogConfig       *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
 	Artifacts       []*TaskArtifact        `hcl:"artifact,block"`
 	Vault           *Vault                 `hcl:"vault,block"`
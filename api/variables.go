@@ -154,6 +154,32 @@ func (vars *Variables) PrefixList(prefix string, qo *QueryOptions) ([]*VariableM
 	return vars.List(qo)
 }
 
+// History is used to list the previous versions of the variable at the given
+// path, newest first. Versions are only retained when the variable's namespace
+// is configured to keep them.
+func (vars *Variables) History(path string, qo *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	path = cleanPathString(path)
+	var resp []*VariableMetadata
+	qm, err := vars.client.query("/v1/var/"+path+"?versions", &resp, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Restore is used to make a previous version of the variable at the given
+// path its current value. The version is the ModifyIndex of one of the
+// entries returned by History.
+func (vars *Variables) Restore(path string, version uint64, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+	path = cleanPathString(path)
+	var out Variable
+	wm, err := vars.client.put(fmt.Sprintf("/v1/var/%s?restore=%d", path, version), nil, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
 // GetItems returns the inner Items collection from a variable at a given path.
 //
 // Deprecated: Use GetVariableItems instead.
@@ -83,6 +83,10 @@ type Tracker struct {
 	// These tasks may terminate without affecting alloc health
 	lifecycleTasks map[string]string
 
+	// batch marks whether the allocation belongs to a sysbatch job, whose
+	// tasks may terminate successfully without affecting alloc health
+	batch bool
+
 	// lock is used to lock shared fields listed below
 	lock sync.Mutex
 
@@ -139,6 +143,7 @@ func NewTracker(
 		checkLookupInterval: checkLookupInterval,
 		logger:              logger,
 		lifecycleTasks:      map[string]string{},
+		batch:               alloc.Job.Type == structs.JobTypeSysBatch,
 	}
 
 	// Build the map of TaskEnv for each task. Create the group-level TaskEnv
@@ -354,7 +359,7 @@ func (t *Tracker) watchTaskEvents() {
 			}
 
 			// One of the tasks has failed so we can exit watching
-			if state.Failed || (!state.FinishedAt.IsZero() && t.lifecycleTasks[taskName] != structs.TaskLifecycleHookPrestart) {
+			if state.Failed || (!state.FinishedAt.IsZero() && t.lifecycleTasks[taskName] != structs.TaskLifecycleHookPrestart && !t.batch) {
 				t.setTaskHealth(false, true)
 				return
 			}
@@ -368,6 +373,13 @@ func (t *Tracker) watchTaskEvents() {
 			}
 		}
 
+		// A sysbatch alloc is healthy once all of its tasks have completed
+		// successfully.
+		if t.batch && alloc.ClientStatus == structs.AllocClientStatusComplete {
+			t.setTaskHealth(true, true)
+			return
+		}
+
 		// If the alloc is marked as failed by the client but none of the
 		// individual tasks failed, that means something failed at the alloc
 		// level.
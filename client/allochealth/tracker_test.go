@@ -493,6 +493,44 @@ func TestTracker_Succeeded_PostStart_Healthy(t *testing.T) {
 	}
 }
 
+func TestTracker_SysBatch_Complete_Healthy(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.SysBatchAlloc()
+
+	// Synthesize an alloc whose task has completed successfully
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	alloc.TaskStates = map[string]*structs.TaskState{
+		"pinger": {
+			State:      structs.TaskStateDead,
+			StartedAt:  time.Now(),
+			FinishedAt: time.Now(),
+		},
+	}
+
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
+
+	consul := regmock.NewServiceRegistrationHandler(logger)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
+	env := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region).Build()
+
+	// The min healthy time is never reached as the task has exited
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), env, consul, checks, time.Hour, true)
+	tracker.Start()
+
+	select {
+	case <-time.After(time.Second):
+		must.Unreachable(t, must.Sprint("timed out while waiting for health"))
+	case h := <-tracker.HealthyCh():
+		must.True(t, h)
+	}
+}
+
 func TestTracker_ConsulChecks_Unhealthy(t *testing.T) {
 	ci.Parallel(t)
 
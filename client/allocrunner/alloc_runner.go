@@ -919,7 +919,7 @@ func (ar *allocRunner) clientAlloc(taskStates map[string]*structs.TaskState) *st
 // getClientStatus takes in the task states for a given allocation and computes
 // the client status and description
 func getClientStatus(taskStates map[string]*structs.TaskState) (status, description string) {
-	var pending, running, dead, failed bool
+	var pending, running, dead, failed, deadlineExceeded bool
 	for _, state := range taskStates {
 		switch state.State {
 		case structs.TaskStateRunning:
@@ -929,6 +929,7 @@ func getClientStatus(taskStates map[string]*structs.TaskState) (status, descript
 		case structs.TaskStateDead:
 			if state.Failed {
 				failed = true
+				deadlineExceeded = deadlineExceeded || state.DeadlineExceeded()
 			} else {
 				dead = true
 			}
@@ -936,7 +937,9 @@ func getClientStatus(taskStates map[string]*structs.TaskState) (status, descript
 	}
 
 	// Determine the alloc status
-	if failed {
+	if deadlineExceeded {
+		return structs.AllocClientStatusFailed, structs.AllocClientDescriptionDeadlineExceeded
+	} else if failed {
 		return structs.AllocClientStatusFailed, "Failed tasks"
 	} else if running {
 		return structs.AllocClientStatusRunning, "Tasks are running"
@@ -4,11 +4,14 @@
 package allocrunner
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	clientconfig "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/taskenv"
@@ -41,6 +44,15 @@ func (a *allocHealthSetter) ClearHealth() {
 	a.ar.stateLock.Unlock()
 }
 
+// TaskStates returns the current states of the alloc's tasks.
+func (a *allocHealthSetter) TaskStates() map[string]*structs.TaskState {
+	states := make(map[string]*structs.TaskState, len(a.ar.tasks))
+	for name, tr := range a.ar.tasks {
+		states[name] = tr.TaskState()
+	}
+	return states
+}
+
 // SetHealth allows the health watcher hook to set the alloc's
 // deployment/migration health and emit task events.
 //
@@ -82,6 +94,35 @@ func (a *allocHealthSetter) SetHealth(healthy, isDeploy bool, trackerTaskEvents
 	a.ar.allocBroadcaster.Send(calloc)
 }
 
+// allocTaskKiller is a shim to allow the group deadline hook to kill the
+// alloc's tasks without full access to the alloc runner state
+type allocTaskKiller struct {
+	ar *allocRunner
+}
+
+// KillTasks kills the alloc's tasks which haven't finished with the passed
+// event and waits for them to exit.
+//
+// Only for use by group deadline hook.
+func (a *allocTaskKiller) KillTasks(event *structs.TaskEvent) {
+	var wg sync.WaitGroup
+	for name, tr := range a.ar.tasks {
+		if !tr.TaskState().FinishedAt.IsZero() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, tr *taskrunner.TaskRunner) {
+			defer wg.Done()
+			err := tr.Kill(context.TODO(), event.Copy())
+			if err != nil && err != taskrunner.ErrTaskNotRunning {
+				a.ar.logger.Warn("error stopping task", "error", err, "task_name", name)
+			}
+		}(name, tr)
+	}
+	wg.Wait()
+}
+
 // initRunnerHooks initializes the runners hooks.
 func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 	hookLogger := ar.logger.Named("runner_hook")
@@ -141,6 +182,7 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 			config.GetConsulConfigs(ar.logger)),
 		newCSIHook(alloc, hookLogger, ar.csiManager, ar.rpcClient, ar, ar.hookResources, ar.clientConfig.Node.SecretID),
 		newChecksHook(hookLogger, alloc, ar.checkStore, ar),
+		newGroupDeadlineHook(hookLogger, alloc, &allocTaskKiller{ar}),
 	}
 	if config.ExtraAllocHooks != nil {
 		ar.runnerHooks = append(ar.runnerHooks, config.ExtraAllocHooks...)
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const groupDeadlineHookName = "group_deadline"
+
+// taskKiller kills the tasks of an allocation.
+type taskKiller interface {
+	// KillTasks kills the tasks of the allocation which haven't finished
+	// with the passed event.
+	KillTasks(event *structs.TaskEvent)
+}
+
+// groupDeadlineHook kills and fails the tasks of an allocation which exceeds
+// the active deadline of its group. The deadline is measured from the creation
+// of the allocation, so it also bounds the time spent in prestart tasks and
+// restart delays.
+type groupDeadlineHook struct {
+	alloc  *structs.Allocation
+	killer taskKiller
+	logger log.Logger
+
+	// cancel stops the deadline timer. It is nil while no deadline is
+	// watched.
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+}
+
+func newGroupDeadlineHook(logger log.Logger, alloc *structs.Allocation, killer taskKiller) *groupDeadlineHook {
+	h := &groupDeadlineHook{
+		alloc:  alloc,
+		killer: killer,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+// statically assert the hook implements the expected interfaces
+var (
+	_ interfaces.RunnerPrerunHook  = (*groupDeadlineHook)(nil)
+	_ interfaces.RunnerUpdateHook  = (*groupDeadlineHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*groupDeadlineHook)(nil)
+	_ interfaces.RunnerDestroyHook = (*groupDeadlineHook)(nil)
+	_ interfaces.ShutdownHook      = (*groupDeadlineHook)(nil)
+)
+
+func (*groupDeadlineHook) Name() string {
+	return groupDeadlineHookName
+}
+
+func (h *groupDeadlineHook) Prerun(_ *taskenv.TaskEnv) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.watch()
+	return nil
+}
+
+func (h *groupDeadlineHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.alloc = req.Alloc
+
+	// Apply the updated deadline, unless the allocation is done
+	if h.cancel != nil {
+		h.watch()
+	}
+	return nil
+}
+
+func (h *groupDeadlineHook) Postrun() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+	return nil
+}
+
+func (h *groupDeadlineHook) Destroy() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+	return nil
+}
+
+func (h *groupDeadlineHook) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+}
+
+// watch starts the timer of the deadline of the group, replacing any existing
+// timer. It must be called with the lock held.
+func (h *groupDeadlineHook) watch() {
+	h.stop()
+
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil || tg.ActiveDeadline <= 0 {
+		return
+	}
+
+	deadline := time.Unix(0, h.alloc.CreateTime).Add(tg.ActiveDeadline)
+	event := structs.NewTaskEvent(structs.TaskDeadlineExceeded).
+		SetKillReason(fmt.Sprintf("Allocation exceeded the active deadline of its group of %v", tg.ActiveDeadline)).
+		SetFailsTask()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.run(ctx, deadline, event)
+}
+
+// stop stops the deadline timer. It must be called with the lock held.
+func (h *groupDeadlineHook) stop() {
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
+
+// run kills the tasks with the passed event once the deadline is reached,
+// unless the context is canceled first.
+func (h *groupDeadlineHook) run(ctx context.Context, deadline time.Time, event *structs.TaskEvent) {
+	timer, stop := helper.NewSafeTimer(time.Until(deadline))
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	h.logger.Info("allocation exceeded the active deadline of its group", "reason", event.KillReason)
+
+	// The tasks are failed so the reschedule policy applies
+	h.killer.KillTasks(event)
+}
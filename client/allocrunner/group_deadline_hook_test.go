@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+type mockTaskKiller struct {
+	ch chan *structs.TaskEvent
+}
+
+func newMockTaskKiller() *mockTaskKiller {
+	return &mockTaskKiller{ch: make(chan *structs.TaskEvent, 1)}
+}
+
+func (m *mockTaskKiller) KillTasks(event *structs.TaskEvent) {
+	m.ch <- event
+}
+
+func TestGroupDeadlineHook_Kill(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	alloc.CreateTime = time.Now().UnixNano()
+	tg := alloc.Job.TaskGroups[0]
+	tg.ActiveDeadline = 50 * time.Millisecond
+	tg.Tasks[0].ActiveDeadline = time.Hour
+
+	killer := newMockTaskKiller()
+	h := newGroupDeadlineHook(testlog.HCLogger(t), alloc, killer)
+
+	// The deadline is armed before any task starts so it also bounds
+	// prestart hooks and restart delays
+	must.NoError(t, h.Prerun(nil))
+
+	// Allocations exceeding the deadline of their group have their tasks
+	// killed and failed
+	select {
+	case event := <-killer.ch:
+		must.Eq(t, structs.TaskDeadlineExceeded, event.Type)
+		must.True(t, event.FailsTask)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tasks were not killed")
+	}
+}
+
+func TestGroupDeadlineHook_CreateTime(t *testing.T) {
+	ci.Parallel(t)
+
+	// The deadline is measured from the creation of the allocation, so
+	// restarting the client doesn't extend it
+	alloc := mock.BatchAlloc()
+	alloc.CreateTime = time.Now().Add(-time.Hour).UnixNano()
+	alloc.Job.TaskGroups[0].ActiveDeadline = time.Minute
+
+	killer := newMockTaskKiller()
+	h := newGroupDeadlineHook(testlog.HCLogger(t), alloc, killer)
+	must.NoError(t, h.Prerun(nil))
+
+	select {
+	case <-killer.ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tasks were not killed")
+	}
+}
+
+func TestGroupDeadlineHook_Postrun(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	alloc.CreateTime = time.Now().UnixNano()
+	alloc.Job.TaskGroups[0].ActiveDeadline = 100 * time.Millisecond
+
+	killer := newMockTaskKiller()
+	h := newGroupDeadlineHook(testlog.HCLogger(t), alloc, killer)
+
+	must.NoError(t, h.Prerun(nil))
+	must.NoError(t, h.Postrun())
+
+	// Allocations which finished before their deadline are left alone
+	select {
+	case <-killer.ch:
+		t.Fatal("tasks were killed after the allocation finished")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// Updates don't watch the deadline of finished allocations
+	update := alloc.Copy()
+	update.Job.TaskGroups[0].ActiveDeadline = time.Millisecond
+	must.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: update}))
+
+	select {
+	case <-killer.ch:
+		t.Fatal("tasks were killed after the allocation finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -29,6 +29,9 @@ type healthSetter interface {
 
 	// ClearHealth for when the deployment ID changes.
 	ClearHealth()
+
+	// TaskStates returns the current states of the alloc's tasks.
+	TaskStates() map[string]*structs.TaskState
 }
 
 // allocHealthWatcherHook is responsible for watching an allocation's task
@@ -72,6 +75,12 @@ type allocHealthWatcherHook struct {
 	// hold hookLock to access.
 	isDeploy bool
 
+	// healthOnExit is true if the health of the deployment is set from the
+	// final state of the tasks when they exit before the tracker sets it, as
+	// is expected of sysbatch allocations. Set in init(). Must hold hookLock
+	// to access.
+	healthOnExit bool
+
 	logger hclog.Logger
 }
 
@@ -84,9 +93,11 @@ func newAllocHealthWatcherHook(
 	checkStore checkstore.Shim,
 ) interfaces.RunnerHook {
 
-	// Neither deployments nor migrations care about the health of
-	// non-service jobs so never watch their health
-	if alloc.Job.Type != structs.JobTypeService {
+	// Neither deployments nor migrations care about the health of batch
+	// jobs so never watch their health
+	switch alloc.Job.Type {
+	case structs.JobTypeService, structs.JobTypeSystem, structs.JobTypeSysBatch:
+	default:
 		return noopAllocHealthWatcherHook{}
 	}
 
@@ -137,6 +148,13 @@ func (h *allocHealthWatcherHook) init(allocEnv *taskenv.TaskEnv) error {
 	}
 
 	h.isDeploy = h.alloc.DeploymentID != ""
+	h.healthOnExit = false
+
+	// Only the migrations of service jobs care about their health, system
+	// and sysbatch jobs only watch it for deployments
+	if !h.isDeploy && h.alloc.Job.Type != structs.JobTypeService {
+		return nil
+	}
 
 	// No need to watch allocs for deployments that rely on operators
 	// manually setting health
@@ -144,6 +162,8 @@ func (h *allocHealthWatcherHook) init(allocEnv *taskenv.TaskEnv) error {
 		return nil
 	}
 
+	h.healthOnExit = h.isDeploy && h.alloc.Job.Type == structs.JobTypeSysBatch
+
 	// Define the deadline, health method, min healthy time from the
 	// deployment if this is a deployment; otherwise from the migration
 	// strategy.
@@ -207,18 +227,36 @@ func (h *allocHealthWatcherHook) Postrun() error {
 	h.hookLock.Lock()
 	defer h.hookLock.Unlock()
 
-	h.cancelFn()
-	h.listener.Close()
+	h.stop()
 
-	// Wait until the watcher exits
-	<-h.watchDone
+	// Sysbatch allocations are usually done before the tracker sees their
+	// final state, so their health is set from the state of their tasks.
+	if h.healthOnExit && !h.healthSetter.HasHealth() && !h.alloc.ServerTerminalStatus() {
+		healthy := true
+		for _, state := range h.healthSetter.TaskStates() {
+			healthy = healthy && state.Successful()
+		}
+		h.logger.Trace("tasks exited; setting health", "healthy", healthy)
+		h.healthSetter.SetHealth(healthy, true, nil)
+	}
 
 	return nil
 }
 
 func (h *allocHealthWatcherHook) Shutdown() {
-	// Same as Postrun
-	_ = h.Postrun()
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	h.stop()
+}
+
+// stop cancels the watcher and waits for it to exit. Must hold hookLock.
+func (h *allocHealthWatcherHook) stop() {
+	h.cancelFn()
+	h.listener.Close()
+
+	// Wait until the watcher exits
+	<-h.watchDone
 }
 
 // watchHealth watches alloc health until it is set, the alloc is stopped, the
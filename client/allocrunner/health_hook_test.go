@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +37,7 @@ type mockHealthSetter struct {
 	healthy    *bool
 	isDeploy   *bool
 	taskEvents map[string]*structs.TaskEvent
+	taskStates map[string]*structs.TaskState
 	mu         sync.Mutex
 
 	healthCh chan allocHealth
@@ -80,6 +82,12 @@ func (m *mockHealthSetter) HasHealth() bool {
 	return m.healthy != nil
 }
 
+func (m *mockHealthSetter) TaskStates() map[string]*structs.TaskState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.taskStates
+}
+
 // TestHealthHook_PrerunPostrun asserts a health hook does not error if it is
 // run and postrunned.
 func TestHealthHook_PrerunPostrun(t *testing.T) {
@@ -396,26 +404,82 @@ func TestHealthHook_SetHealth_unhealthy(t *testing.T) {
 	require.NoError(h.Postrun())
 }
 
-// TestHealthHook_SystemNoop asserts that system jobs return the noop tracker.
-func TestHealthHook_SystemNoop(t *testing.T) {
+// TestHealthHook_SystemNoDeployment asserts that the health of system allocs
+// is only watched for deployments.
+func TestHealthHook_SystemNoDeployment(t *testing.T) {
 	ci.Parallel(t)
 
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
+
 	alloc := mock.SystemAlloc()
-	h := newAllocHealthWatcherHook(testlog.HCLogger(t), alloc.Copy(), nil, nil, nil, nil)
+	hs := newMockHealthSetter()
+	consul := regMock.NewServiceRegistrationHandler(logger)
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, new(mock.CheckShim)).(*allocHealthWatcherHook)
+	env := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region).Build()
 
-	// Assert that it's the noop impl
-	_, ok := h.(noopAllocHealthWatcherHook)
-	require.True(t, ok)
+	must.NoError(t, h.Prerun(env))
 
-	// Assert the noop impl does not implement any hooks
-	_, ok = h.(interfaces.RunnerPrerunHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.RunnerUpdateHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.RunnerPostrunHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.ShutdownHook)
-	require.False(t, ok)
+	// Assert no tracker was started
+	h.hookLock.Lock()
+	must.False(t, h.isDeploy)
+	select {
+	case <-h.watchDone:
+	default:
+		t.Fatal("expected no health watcher")
+	}
+	h.hookLock.Unlock()
+
+	must.NoError(t, h.Postrun())
+	must.False(t, hs.HasHealth())
+}
+
+// TestHealthHook_SysBatchHealthOnExit asserts that the deployment health of
+// sysbatch allocs is set from the state of their tasks once they exit.
+func TestHealthHook_SysBatchHealthOnExit(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		failed  bool
+		healthy bool
+	}{
+		{name: "successful", failed: false, healthy: true},
+		{name: "failed", failed: true, healthy: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := testlog.HCLogger(t)
+			b := cstructs.NewAllocBroadcaster(logger)
+			defer b.Close()
+
+			alloc := mock.SysBatchAlloc()
+			alloc.DeploymentID = uuid.Generate()
+			alloc.Job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+			alloc.Job.TaskGroups[0].Update.HealthCheck = structs.UpdateStrategyHealthCheck_TaskStates
+
+			hs := newMockHealthSetter()
+			hs.taskStates = map[string]*structs.TaskState{
+				"pinger": {State: structs.TaskStateDead, Failed: tc.failed},
+			}
+			consul := regMock.NewServiceRegistrationHandler(logger)
+			h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, new(mock.CheckShim))
+			env := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region).Build()
+
+			must.NoError(t, h.(interfaces.RunnerPrerunHook).Prerun(env))
+			must.NoError(t, h.(interfaces.RunnerPostrunHook).Postrun())
+
+			select {
+			case health := <-hs.healthCh:
+				must.Eq(t, tc.healthy, health.healthy)
+			default:
+				t.Fatal("expected health to be set")
+			}
+			must.True(t, *hs.isDeploy)
+		})
+	}
 }
 
 // TestHealthHook_BatchNoop asserts that batch jobs return the noop tracker.
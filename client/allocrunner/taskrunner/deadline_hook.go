@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const deadlineHookName = "deadline"
+
+// deadlineHook restarts tasks which exceed their active deadline according to
+// their restart policy. The deadline bounds each run of the task. The deadline
+// of the group is enforced by the alloc runner.
+type deadlineHook struct {
+	alloc     *structs.Allocation
+	taskName  string
+	lifecycle ti.TaskLifecycle
+	logger    log.Logger
+
+	// started is when the current run of the task started
+	started time.Time
+
+	// cancel stops the deadline timer. It is nil while the task isn't
+	// running.
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+}
+
+func newDeadlineHook(alloc *structs.Allocation, taskName string, lifecycle ti.TaskLifecycle, logger log.Logger) *deadlineHook {
+	h := &deadlineHook{
+		alloc:     alloc,
+		taskName:  taskName,
+		lifecycle: lifecycle,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*deadlineHook) Name() string {
+	return deadlineHookName
+}
+
+func (h *deadlineHook) Poststart(_ context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.started = time.Now()
+	h.watch()
+	return nil
+}
+
+func (h *deadlineHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.alloc = req.Alloc
+
+	// Apply the updated deadline to the running task
+	if h.cancel != nil {
+		h.watch()
+	}
+	return nil
+}
+
+func (h *deadlineHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+	return nil
+}
+
+func (h *deadlineHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+	return nil
+}
+
+// watch starts the timer of the deadline of the running task, replacing any
+// existing timer. It must be called with the lock held.
+func (h *deadlineHook) watch() {
+	h.stop()
+
+	deadline, event := h.deadline()
+	if event == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.run(ctx, deadline, event)
+}
+
+// stop stops the deadline timer. It must be called with the lock held.
+func (h *deadlineHook) stop() {
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
+
+// deadline returns the deadline of the running task and the event to restart
+// the task with once it is exceeded, or a nil event if the task has no
+// deadline.
+func (h *deadlineHook) deadline() (time.Time, *structs.TaskEvent) {
+	task := h.alloc.LookupTask(h.taskName)
+	if task == nil || task.ActiveDeadline <= 0 {
+		return time.Time{}, nil
+	}
+
+	event := structs.NewTaskEvent(structs.TaskDeadlineExceeded).
+		SetKillReason(fmt.Sprintf("Task exceeded its active deadline of %v", task.ActiveDeadline))
+	return h.started.Add(task.ActiveDeadline), event
+}
+
+// run kills the task with the passed event once the deadline is reached,
+// unless the context is canceled first.
+func (h *deadlineHook) run(ctx context.Context, deadline time.Time, event *structs.TaskEvent) {
+	timer, stop := helper.NewSafeTimer(time.Until(deadline))
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	h.logger.Info("task exceeded its active deadline", "reason", event.KillReason)
+
+	err := h.lifecycle.Restart(context.Background(), event, true)
+	if err != nil {
+		h.logger.Error("failed to restart task which exceeded its active deadline", "error", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+// Statically assert the deadline hook implements the expected interfaces
+var _ interfaces.TaskPoststartHook = (*deadlineHook)(nil)
+var _ interfaces.TaskUpdateHook = (*deadlineHook)(nil)
+var _ interfaces.TaskExitedHook = (*deadlineHook)(nil)
+var _ interfaces.TaskStopHook = (*deadlineHook)(nil)
+
+func TestTaskRunner_DeadlineHook_Task(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.ActiveDeadline = 50 * time.Millisecond
+
+	lifecycle := trtesting.NewMockTaskHooks()
+	h := newDeadlineHook(alloc, task.Name, lifecycle, testlog.HCLogger(t))
+
+	must.NoError(t, h.Poststart(context.Background(), nil, nil))
+
+	// Tasks exceeding their own deadline are restarted as a failure
+	select {
+	case <-lifecycle.RestartCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task was not restarted")
+	}
+	must.Nil(t, lifecycle.KillEvent())
+}
+
+func TestTaskRunner_DeadlineHook_Exited(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.ActiveDeadline = 100 * time.Millisecond
+
+	lifecycle := trtesting.NewMockTaskHooks()
+	h := newDeadlineHook(alloc, task.Name, lifecycle, testlog.HCLogger(t))
+
+	must.NoError(t, h.Poststart(context.Background(), nil, nil))
+	must.NoError(t, h.Exited(context.Background(), nil, nil))
+
+	// Tasks which exited before their deadline are left alone
+	select {
+	case <-lifecycle.RestartCh:
+		t.Fatal("task was restarted after exiting")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// Updates don't watch the deadline of tasks which aren't running
+	update := alloc.Copy()
+	update.Job.TaskGroups[0].Tasks[0].ActiveDeadline = time.Millisecond
+	must.NoError(t, h.Update(context.Background(), &interfaces.TaskUpdateRequest{Alloc: update}, nil))
+
+	select {
+	case <-lifecycle.RestartCh:
+		t.Fatal("task was restarted after exiting")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
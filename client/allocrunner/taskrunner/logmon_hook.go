@@ -40,6 +40,10 @@ type logmonHook struct {
 
 	config *logmonHookConfig
 
+	// sinkAllowlist is the list of the addresses of the log sinks allowed by
+	// the configuration of the client.
+	sinkAllowlist []string
+
 	logger hclog.Logger
 }
 
@@ -56,6 +60,9 @@ func newLogMonHook(tr *TaskRunner, logger hclog.Logger) *logmonHook {
 		config: tr.logmonHookConfig,
 		logger: logger,
 	}
+	if tr.clientConfig != nil {
+		hook.sinkAllowlist = tr.clientConfig.LogSinkAllowlist
+	}
 
 	return hook
 }
@@ -119,6 +126,12 @@ func (h *logmonHook) Prestart(ctx context.Context,
 		return nil
 	}
 
+	// Sinks are dialed by logmon on the client, so tasks may only forward
+	// their logs to the sinks allowed by the operator.
+	if sink := req.Task.LogConfig.Sink; sink != nil && !sink.AddressAllowed(h.sinkAllowlist) {
+		return fmt.Errorf("log sink address %q is not allowed by the client configuration", sink.Address)
+	}
+
 	attempts := 0
 	for {
 		err := h.prestartOneLoop(ctx, req)
@@ -192,7 +205,7 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
-	err := h.logmon.Start(&logmon.LogConfig{
+	cfg := &logmon.LogConfig{
 		LogDir:        h.config.logDir,
 		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
 		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
@@ -200,7 +213,16 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		StderrFifo:    h.config.stderrFifo,
 		MaxFiles:      req.Task.LogConfig.MaxFiles,
 		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
-	})
+		TaskName:      req.Task.Name,
+		Sink:          req.Task.LogConfig.Sink,
+	}
+	if alloc := h.runner.Alloc(); alloc != nil {
+		cfg.AllocID = alloc.ID
+		cfg.JobID = alloc.JobID
+		cfg.Namespace = alloc.Namespace
+	}
+
+	err := h.logmon.Start(cfg)
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
 		return err
@@ -13,8 +13,10 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
@@ -153,3 +155,40 @@ func TestTaskRunner_LogmonHook_Disabled(t *testing.T) {
 	}
 	must.NoError(t, hook.Stop(context.Background(), &stopReq, nil))
 }
+
+// TestTaskRunner_LogmonHook_SinkNotAllowed asserts that tasks can't start when
+// their log sink isn't allowed by the client configuration.
+func TestTaskRunner_LogmonHook_SinkNotAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.LogConfig.Sink = &structs.LogSinkConfig{
+		Type:    structs.LogSinkTypeUnix,
+		Address: "/var/run/docker.sock",
+	}
+
+	dir := t.TempDir()
+
+	hookConf := newLogMonHookConfig(task.Name, task.LogConfig, dir)
+	runner := &TaskRunner{
+		logmonHookConfig: hookConf,
+		clientConfig:     &config.Config{LogSinkAllowlist: []string{"/run/nomad/logs.sock"}},
+	}
+	hook := newLogMonHook(runner, testlog.HCLogger(t))
+
+	req := interfaces.TaskPrestartRequest{Task: task}
+	resp := interfaces.TaskPrestartResponse{}
+
+	err := hook.Prestart(context.Background(), &req, &resp)
+	must.ErrorContains(t, err, `log sink address "/var/run/docker.sock" is not allowed`)
+	must.False(t, structs.IsRecoverable(err))
+	must.Nil(t, hook.logmonPluginClient)
+	must.MapNotContainsKey(t, resp.State, logmonReattachKey)
+
+	// Sinks are rejected when the client doesn't allow any
+	runner.clientConfig.LogSinkAllowlist = nil
+	hook = newLogMonHook(runner, testlog.HCLogger(t))
+	task.LogConfig.Sink.Address = "/run/nomad/logs.sock"
+	must.ErrorContains(t, hook.Prestart(context.Background(), &req, &resp), "is not allowed")
+}
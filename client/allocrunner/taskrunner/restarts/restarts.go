@@ -21,6 +21,8 @@ const (
 	ReasonUnrecoverableError = "Error was unrecoverable"
 	ReasonWithinPolicy       = "Restart within policy"
 	ReasonDelay              = "Exceeded allowed attempts, applying a delay"
+	ReasonExitRuleSucceed    = "Exit rule %q treats the exit as successful"
+	ReasonExitRuleFail       = "Exit rule %q does not allow restarts"
 )
 
 func NewRestartTracker(policy *structs.RestartPolicy, jobType string, tlc *structs.TaskLifecycleConfig) *RestartTracker {
@@ -57,13 +59,14 @@ func NewRestartTracker(policy *structs.RestartPolicy, jobType string, tlc *struc
 type RestartTracker struct {
 	exitRes          *drivers.ExitResult
 	startErr         error
-	killed           bool      // Whether the task has been killed
-	restartTriggered bool      // Whether the task has been signalled to be restarted
-	failure          bool      // Whether a failure triggered the restart
-	count            int       // Current number of attempts.
-	onSuccess        bool      // Whether to restart on successful exit code.
-	startTime        time.Time // When the interval began
-	reason           string    // The reason for the last state
+	killed           bool              // Whether the task has been killed
+	restartTriggered bool              // Whether the task has been signalled to be restarted
+	failure          bool              // Whether a failure triggered the restart
+	count            int               // Current number of attempts.
+	onSuccess        bool              // Whether to restart on successful exit code.
+	startTime        time.Time         // When the interval began
+	reason           string            // The reason for the last state
+	exitRule         *structs.ExitRule // The exit rule which matched the last state
 	policy           *structs.RestartPolicy
 	rand             *rand.Rand
 	lock             sync.Mutex
@@ -133,6 +136,14 @@ func (r *RestartTracker) GetReason() string {
 	return r.reason
 }
 
+// GetExitRule returns the exit rule of the restart policy which matched the
+// exit of the task for the last state returned by GetState, if any.
+func (r *RestartTracker) GetExitRule() *structs.ExitRule {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.exitRule.Copy()
+}
+
 // GetCount returns the current restart count
 func (r *RestartTracker) GetCount() int {
 	r.lock.Lock()
@@ -162,6 +173,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		r.failure = false
 		r.killed = false
 	}()
+	r.exitRule = nil
 
 	// Hot path if task was killed
 	if r.killed {
@@ -175,13 +187,31 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		return structs.TaskRestarting, 0
 	}
 
+	// Exit rules take precedence over the default handling of the exit code
+	onSuccess := r.onSuccess
+	if r.exitRes != nil {
+		r.exitRule = structs.MatchExitRule(r.policy.ExitRules, r.exitRes.ExitCode, r.exitRes.Signal)
+		if r.exitRule != nil {
+			switch r.exitRule.Action {
+			case structs.ExitRuleActionRestart:
+				onSuccess = true
+			case structs.ExitRuleActionSucceed:
+				r.reason = fmt.Sprintf(ReasonExitRuleSucceed, r.exitRule)
+				return structs.TaskTerminated, 0
+			case structs.ExitRuleActionFail, structs.ExitRuleActionReschedule:
+				r.reason = fmt.Sprintf(ReasonExitRuleFail, r.exitRule)
+				return structs.TaskNotRestarting, 0
+			}
+		}
+	}
+
 	// Hot path if no attempts are expected
 	if r.policy.Attempts == 0 {
 		r.reason = ReasonNoRestartsAllowed
 
 		// If the task does not restart on a successful exit code and
 		// the exit code was successful: terminate.
-		if !r.onSuccess && r.exitRes != nil && r.exitRes.Successful() {
+		if !onSuccess && r.exitRes != nil && r.exitRes.Successful() {
 			return structs.TaskTerminated, 0
 		}
 
@@ -214,7 +244,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	} else if r.exitRes != nil {
 		// If the task started successfully and restart on success isn't specified,
 		// don't restart but don't mark as failed.
-		if r.exitRes.Successful() && !r.onSuccess {
+		if r.exitRes.Successful() && !onSuccess {
 			r.reason = "Restart unnecessary as task terminated successfully"
 			return structs.TaskTerminated, 0
 		}
@@ -124,6 +124,110 @@ func TestClient_RestartTracker_ZeroAttempts(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_ExitRules(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.ExitRules = []*structs.ExitRule{
+		{ExitCodes: []int{3}, Action: structs.ExitRuleActionFail},
+		{ExitCodes: []int{4}, Action: structs.ExitRuleActionReschedule},
+		{ExitCodes: []int{75}, Signals: []int{15}, Action: structs.ExitRuleActionRestart},
+		{ExitCodes: []int{0, 2}, Action: structs.ExitRuleActionSucceed},
+	}
+
+	testCases := []struct {
+		name    string
+		jobType string
+		result  *drivers.ExitResult
+		state   string
+		action  string
+	}{
+		{
+			name:    "fail",
+			jobType: structs.JobTypeBatch,
+			result:  testExitResult(3),
+			state:   structs.TaskNotRestarting,
+			action:  structs.ExitRuleActionFail,
+		},
+		{
+			name:    "reschedule",
+			jobType: structs.JobTypeBatch,
+			result:  testExitResult(4),
+			state:   structs.TaskNotRestarting,
+			action:  structs.ExitRuleActionReschedule,
+		},
+		{
+			name:    "restart exit code",
+			jobType: structs.JobTypeBatch,
+			result:  testExitResult(75),
+			state:   structs.TaskRestarting,
+			action:  structs.ExitRuleActionRestart,
+		},
+		{
+			name:    "restart signal",
+			jobType: structs.JobTypeBatch,
+			result:  &drivers.ExitResult{ExitCode: 143, Signal: 15},
+			state:   structs.TaskRestarting,
+			action:  structs.ExitRuleActionRestart,
+		},
+		{
+			name:    "succeed non-zero exit code",
+			jobType: structs.JobTypeBatch,
+			result:  testExitResult(2),
+			state:   structs.TaskTerminated,
+			action:  structs.ExitRuleActionSucceed,
+		},
+		{
+			name:    "succeed service",
+			jobType: structs.JobTypeService,
+			result:  testExitResult(0),
+			state:   structs.TaskTerminated,
+			action:  structs.ExitRuleActionSucceed,
+		},
+		{
+			name:    "no match",
+			jobType: structs.JobTypeBatch,
+			result:  testExitResult(1),
+			state:   structs.TaskRestarting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := NewRestartTracker(p, tc.jobType, nil)
+			state, _ := rt.SetExitResult(tc.result).GetState()
+			require.Equal(t, tc.state, state)
+
+			rule := rt.GetExitRule()
+			if tc.action == "" {
+				require.Nil(t, rule)
+			} else {
+				require.NotNil(t, rule)
+				require.Equal(t, tc.action, rule.Action)
+			}
+		})
+	}
+
+	// A rule restarting a successful exit of a batch task still honors the
+	// restart attempts
+	p = testPolicy(true, structs.RestartPolicyModeFail)
+	p.ExitRules = []*structs.ExitRule{
+		{ExitCodes: []int{0}, Action: structs.ExitRuleActionRestart},
+	}
+	rt := NewRestartTracker(p, structs.JobTypeBatch, nil)
+	for i := 0; i < p.Attempts; i++ {
+		state, _ := rt.SetExitResult(testExitResult(0)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+	}
+	state, _ := rt.SetExitResult(testExitResult(0)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+
+	// The matched rule is cleared by the next state
+	rt.SetKilled()
+	state, _ = rt.GetState()
+	require.Equal(t, structs.TaskKilled, state)
+	require.Nil(t, rt.GetExitRule())
+}
+
 func TestClient_RestartTracker_TaskKilled(t *testing.T) {
 	ci.Parallel(t)
 	p := testPolicy(true, structs.RestartPolicyModeFail)
@@ -891,6 +891,7 @@ func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
 	// Determine if we should restart
 	state, when := tr.restartTracker.GetState()
 	reason := tr.restartTracker.GetReason()
+	rule := tr.restartTracker.GetExitRule()
 	switch state {
 	case structs.TaskKilled:
 		// Never restart an explicitly killed task. Kill method handles
@@ -900,12 +901,15 @@ func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
 	case structs.TaskNotRestarting, structs.TaskTerminated:
 		tr.logger.Info("not restarting task", "reason", reason)
 		if state == structs.TaskNotRestarting {
-			tr.UpdateState(structs.TaskStateDead, structs.NewTaskEvent(structs.TaskNotRestarting).SetRestartReason(reason).SetFailsTask())
+			tr.UpdateState(structs.TaskStateDead, structs.NewTaskEvent(structs.TaskNotRestarting).SetRestartReason(reason).SetExitRule(rule).SetRescheduleExitRule(tr.rescheduleExitRule(rule)).SetFailsTask())
+		} else if rule != nil {
+			// Record the exit rule which treated the exit as successful
+			tr.EmitEvent(structs.NewTaskEvent(structs.TaskNotRestarting).SetRestartReason(reason).SetExitRule(rule))
 		}
 		return false, 0
 	case structs.TaskRestarting:
 		tr.logger.Info("restarting task", "reason", reason, "delay", when)
-		tr.UpdateState(structs.TaskStatePending, structs.NewTaskEvent(structs.TaskRestarting).SetRestartDelay(when).SetRestartReason(reason))
+		tr.UpdateState(structs.TaskStatePending, structs.NewTaskEvent(structs.TaskRestarting).SetRestartDelay(when).SetRestartReason(reason).SetExitRule(rule))
 		return true, when
 	default:
 		tr.logger.Error("restart tracker returned unknown state", "state", state)
@@ -913,6 +917,22 @@ func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
 	}
 }
 
+// rescheduleExitRule returns the exit rule of the reschedule policy which
+// matches the last exit of the failed task, so that it can be recorded. A fail
+// rule of the restart policy prevents rescheduling regardless of the
+// reschedule policy, so no rule is returned then.
+func (tr *TaskRunner) rescheduleExitRule(restartRule *structs.ExitRule) *structs.ExitRule {
+	if restartRule != nil && restartRule.Action == structs.ExitRuleActionFail {
+		return nil
+	}
+	alloc := tr.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+	return tg.ReschedulePolicy.MatchExitRule(tr.TaskState())
+}
+
 func (tr *TaskRunner) assignCgroup(taskConfig *drivers.TaskConfig) {
 	reserveCores := len(tr.taskResources.Cpu.ReservedCores) > 0
 	p := cgroupslib.LinuxResourcesPath(taskConfig.AllocID, taskConfig.Name, reserveCores)
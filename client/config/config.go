@@ -151,6 +151,11 @@ type Config struct {
 	// Servers is a list of known server addresses. These are as "host:port"
 	Servers []string
 
+	// LogSinkAllowlist is the list of the addresses of the log sinks tasks
+	// are allowed to forward their logs to. Tasks can't use log sinks if it
+	// is empty.
+	LogSinkAllowlist []string
+
 	// RPCHandler can be provided to avoid network traffic if the
 	// server is running locally.
 	RPCHandler RPCHandler
@@ -877,6 +882,7 @@ func (c *Config) Copy() *Config {
 	nc := *c
 	nc.Node = nc.Node.Copy()
 	nc.Servers = slices.Clone(nc.Servers)
+	nc.LogSinkAllowlist = slices.Clone(nc.LogSinkAllowlist)
 	nc.Options = maps.Clone(nc.Options)
 	nc.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(nc.HostVolumes)
 	nc.ConsulConfigs = helper.DeepCopyMap(c.ConsulConfigs)
@@ -29,6 +29,17 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		AllocId:        cfg.AllocID,
+		JobId:          cfg.JobID,
+		Namespace:      cfg.Namespace,
+		TaskName:       cfg.TaskName,
+	}
+	if cfg.Sink != nil {
+		req.Sink = &proto.LogSink{
+			Type:       cfg.Sink.Type,
+			Address:    cfg.Sink.Address,
+			BufferSize: uint32(cfg.Sink.BufferSize),
+		}
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
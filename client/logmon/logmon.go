@@ -14,6 +14,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/lib/fifo"
 	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 const (
@@ -44,6 +45,16 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// AllocID, JobID, Namespace and TaskName identify the task, and are added
+	// to the lines forwarded to the sink
+	AllocID   string
+	JobID     string
+	Namespace string
+	TaskName  string
+
+	// Sink optionally configures an external sink log lines are forwarded to
+	Sink *structs.LogSinkConfig
 }
 
 type LogMon interface {
@@ -109,6 +120,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sink the lines of both streams are forwarded to, if configured
+	sink *logSink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -136,20 +150,33 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// The sink is closed once nothing more can be written to it
+	tl.closeSink()
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	if cfg.Sink != nil {
+		sink, err := newLogSink(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log sink: %v", err)
+		}
+		tl.sink = sink
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSink()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withSink(lro, "stdout"))
 	if err != nil {
+		tl.closeSink()
 		return nil, err
 	}
 
@@ -158,11 +185,13 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSink()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withSink(lre, "stderr"))
 	if err != nil {
+		tl.closeSink()
 		return nil, err
 	}
 
@@ -172,6 +201,36 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// withSink returns a writer which writes to the rotator and forwards the lines
+// of the stream to the sink, if one is configured. Closing it closes the
+// rotator.
+func (tl *TaskLogger) withSink(rotator io.WriteCloser, stream string) io.WriteCloser {
+	if tl.sink == nil {
+		return rotator
+	}
+	return &sinkRotatorWriter{
+		Writer:  io.MultiWriter(rotator, tl.sink.Writer(stream)),
+		rotator: rotator,
+	}
+}
+
+func (tl *TaskLogger) closeSink() {
+	if tl.sink != nil {
+		tl.sink.Close()
+	}
+}
+
+// sinkRotatorWriter writes to both a rotator and a sink, and closes the rotator
+// when closed.
+type sinkRotatorWriter struct {
+	io.Writer
+	rotator io.WriteCloser
+}
+
+func (w *sinkRotatorWriter) Close() error {
+	return w.rotator.Close()
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -32,6 +32,11 @@ type StartRequest struct {
 	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
 	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
 	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	AllocId              string   `protobuf:"bytes,8,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	JobId                string   `protobuf:"bytes,9,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Namespace            string   `protobuf:"bytes,10,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	TaskName             string   `protobuf:"bytes,11,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	Sink                 *LogSink `protobuf:"bytes,12,opt,name=sink,proto3" json:"sink,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -111,6 +116,41 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *StartRequest) GetSink() *LogSink {
+	if m != nil {
+		return m.Sink
+	}
+	return nil
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +244,67 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type LogSink struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,3,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
 }
 
 func init() {
@@ -216,27 +312,35 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 447 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0x31, 0x4d, 0xec, 0x64, 0x12, 0x97, 0x6a, 0x25, 0xc4, 0x52, 0x90, 0x88, 0xc2, 0x81,
+	0x1c, 0x90, 0x4b, 0xc3, 0x0b, 0x20, 0x84, 0x90, 0x2a, 0xb5, 0x1c, 0x9c, 0x0b, 0xe2, 0x62, 0xad,
+	0xe3, 0xb5, 0xbb, 0x8d, 0xed, 0x31, 0xbb, 0x1b, 0xa9, 0xf4, 0xb1, 0x78, 0x2b, 0xde, 0x02, 0x79,
+	0xbc, 0x76, 0x73, 0x4c, 0x4f, 0xf6, 0xcc, 0xff, 0x8d, 0x77, 0xfe, 0xfd, 0x0d, 0x8b, 0x6d, 0xa9,
+	0x64, 0x6d, 0x2f, 0x4a, 0x2c, 0x2a, 0xac, 0x2f, 0x1a, 0x8d, 0x16, 0x5d, 0x11, 0x51, 0xc1, 0xde,
+	0xdf, 0x0a, 0x73, 0xab, 0xb6, 0xa8, 0x9b, 0xa8, 0xc6, 0x4a, 0x64, 0x51, 0x37, 0x11, 0x1d, 0x42,
+	0xcb, 0xbf, 0x27, 0x30, 0xdf, 0x58, 0xa1, 0x6d, 0x2c, 0x7f, 0xef, 0xa5, 0xb1, 0xec, 0x15, 0x04,
+	0x25, 0x16, 0x49, 0xa6, 0x34, 0xf7, 0x16, 0xde, 0x6a, 0x1a, 0xfb, 0x25, 0x16, 0xdf, 0x94, 0x66,
+	0x2b, 0x38, 0x33, 0x36, 0xc3, 0xbd, 0x4d, 0x72, 0x55, 0xca, 0xa4, 0x16, 0x95, 0xe4, 0xcf, 0x89,
+	0x38, 0xed, 0xfa, 0xdf, 0x55, 0x29, 0x7f, 0x88, 0x4a, 0x3a, 0x52, 0x6a, 0x7d, 0x40, 0x9e, 0x0c,
+	0xa4, 0xd4, 0x7a, 0x20, 0xdf, 0xc0, 0xb4, 0x12, 0xf7, 0x84, 0x19, 0x3e, 0x5a, 0x78, 0xab, 0x30,
+	0x9e, 0x54, 0xe2, 0xbe, 0xd5, 0x0d, 0xfb, 0x00, 0x67, 0xbd, 0x98, 0x18, 0xf5, 0x20, 0x93, 0x2a,
+	0xe5, 0x63, 0x62, 0x42, 0xc7, 0x6c, 0xd4, 0x83, 0xbc, 0x49, 0xd9, 0x3b, 0x98, 0x0d, 0x9b, 0xe5,
+	0xc8, 0x7d, 0x3a, 0x0a, 0xfa, 0xa5, 0x72, 0x74, 0x40, 0xb7, 0x50, 0x8e, 0x3c, 0x18, 0x00, 0xda,
+	0x25, 0x47, 0xf6, 0x1a, 0x26, 0xa2, 0x2c, 0x71, 0x9b, 0xa8, 0x8c, 0x4f, 0x48, 0x0d, 0xa8, 0xbe,
+	0xca, 0xd8, 0x4b, 0xf0, 0xef, 0x30, 0x6d, 0x85, 0x29, 0x09, 0xe3, 0x3b, 0x4c, 0xaf, 0x32, 0xf6,
+	0x16, 0xa6, 0xad, 0x2f, 0xd3, 0x88, 0xad, 0xe4, 0x40, 0xca, 0x63, 0xa3, 0xf5, 0x65, 0x85, 0xd9,
+	0x75, 0xd6, 0x67, 0xa4, 0x4e, 0xda, 0x06, 0x99, 0xfe, 0x02, 0x23, 0xa3, 0xea, 0x1d, 0x9f, 0x2f,
+	0xbc, 0xd5, 0x6c, 0xfd, 0x31, 0x3a, 0x22, 0xa6, 0xe8, 0x1a, 0x8b, 0x8d, 0xaa, 0x77, 0x31, 0x4d,
+	0x2e, 0x5f, 0x40, 0xe8, 0x32, 0x33, 0x0d, 0xd6, 0x46, 0x2e, 0x43, 0x98, 0x6d, 0x2c, 0x36, 0x2e,
+	0xc3, 0xe5, 0x29, 0xcc, 0xbb, 0xd2, 0xc9, 0x3f, 0x21, 0x70, 0x1f, 0x60, 0x0c, 0x46, 0xf6, 0x4f,
+	0x23, 0x5d, 0xb6, 0xf4, 0xce, 0x38, 0x04, 0x22, 0xcb, 0xb4, 0x34, 0xc6, 0x05, 0xda, 0x97, 0xed,
+	0xc5, 0xa5, 0xfb, 0x3c, 0x97, 0x9a, 0x02, 0xa0, 0x10, 0xc3, 0x18, 0xba, 0x56, 0x7b, 0xf9, 0xeb,
+	0x7f, 0x1e, 0xf8, 0xd7, 0x58, 0xdc, 0x60, 0xcd, 0x1a, 0x18, 0xd3, 0x52, 0xec, 0xf2, 0x28, 0x47,
+	0x87, 0x3f, 0xdd, 0xf9, 0xfa, 0x29, 0x23, 0xce, 0xd4, 0x33, 0x56, 0xc1, 0xa8, 0xb5, 0xc9, 0x3e,
+	0x1d, 0x39, 0x3d, 0x5c, 0xd0, 0xf9, 0xe5, 0x13, 0x26, 0xfa, 0xe3, 0xbe, 0x06, 0xbf, 0xc6, 0xd4,
+	0x4f, 0x7d, 0x7a, 0x7c, 0xfe, 0x3f, 0x00, 0x56, 0x11, 0x3d, 0xf5, 0x83, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/logmon/proto"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 type logmonServer struct {
@@ -24,6 +25,17 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 		MaxFileSizeMB: int(req.MaxFileSizeMb),
 		StdoutFifo:    req.StdoutFifo,
 		StderrFifo:    req.StderrFifo,
+		AllocID:       req.AllocId,
+		JobID:         req.JobId,
+		Namespace:     req.Namespace,
+		TaskName:      req.TaskName,
+	}
+	if req.Sink != nil {
+		cfg.Sink = &structs.LogSinkConfig{
+			Type:       req.Sink.Type,
+			Address:    req.Sink.Address,
+			BufferSize: int(req.Sink.BufferSize),
+		}
 	}
 
 	err := s.impl.Start(cfg)
@@ -0,0 +1,491 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// sinkMaxBatch is the maximum number of lines sent to a sink at once.
+	sinkMaxBatch = 128
+
+	// sinkMaxLineSize is the maximum size of a line forwarded to a sink.
+	// Longer lines are split.
+	sinkMaxLineSize = 64 * 1024
+
+	// sinkTimeout is the timeout for connecting and sending lines to a sink.
+	sinkTimeout = 10 * time.Second
+
+	// sinkMinBackoff and sinkMaxBackoff bound the time to wait before
+	// retrying to send lines to a sink which failed.
+	sinkMinBackoff = 1 * time.Second
+	sinkMaxBackoff = 30 * time.Second
+)
+
+// logLine is a line of the logs of a task forwarded to a sink, along with the
+// metadata identifying the task.
+type logLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Namespace string    `json:"namespace"`
+	JobID     string    `json:"job_id"`
+	AllocID   string    `json:"alloc_id"`
+	Task      string    `json:"task"`
+	Stream    string    `json:"stream"`
+	Message   string    `json:"message"`
+}
+
+// sinkTransport sends batches of lines to an external sink.
+type sinkTransport interface {
+	// Send sends the lines to the sink. The lines must not be retained
+	// after Send returns.
+	Send(lines []*logLine) error
+
+	// Close releases any connection held to the sink.
+	Close() error
+}
+
+// logSink forwards the lines written to its writers to a transport. Lines are
+// buffered up to a fixed size and dropped once the buffer is full, so writing
+// lines never blocks the task, even if the sink is slow or unavailable.
+type logSink struct {
+	config    *LogConfig
+	transport sinkTransport
+	logger    hclog.Logger
+
+	lines   chan *logLine
+	dropped atomic.Uint64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// newLogSink returns a sink for the sink configuration of cfg, and starts
+// forwarding lines to it.
+func newLogSink(cfg *LogConfig, logger hclog.Logger) (*logSink, error) {
+	transport, err := newSinkTransport(cfg.Sink)
+	if err != nil {
+		return nil, err
+	}
+
+	size := cfg.Sink.BufferSize
+	if size <= 0 {
+		size = structs.DefaultLogSinkBufferSize
+	}
+
+	s := &logSink{
+		config:    cfg,
+		transport: transport,
+		logger:    logger.Named("sink").With("type", cfg.Sink.Type),
+		lines:     make(chan *logLine, size),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func newSinkTransport(cfg *structs.LogSinkConfig) (sinkTransport, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid log sink: %v", err)
+	}
+
+	switch cfg.Type {
+	case structs.LogSinkTypeSyslog:
+		return newSyslogTransport(cfg.Address)
+	case structs.LogSinkTypeUnix:
+		return &unixTransport{path: cfg.Address}, nil
+	case structs.LogSinkTypeHTTP:
+		return &httpTransport{
+			address: cfg.Address,
+			client:  &http.Client{Timeout: sinkTimeout},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+}
+
+// Writer returns a writer which splits what is written to it into lines of
+// the given stream and forwards them to the sink.
+func (s *logSink) Writer(stream string) io.Writer {
+	return &sinkWriter{sink: s, stream: stream}
+}
+
+// enqueue buffers a line, or drops it if the buffer is full.
+func (s *logSink) enqueue(stream string, message []byte) {
+	line := &logLine{
+		Timestamp: time.Now().UTC(),
+		Namespace: s.config.Namespace,
+		JobID:     s.config.JobID,
+		AllocID:   s.config.AllocID,
+		Task:      s.config.TaskName,
+		Stream:    stream,
+		Message:   string(message),
+	}
+
+	select {
+	case s.lines <- line:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run sends the buffered lines to the transport in batches until the sink is
+// closed, retrying with a backoff when sending fails.
+func (s *logSink) run() {
+	defer close(s.doneCh)
+	defer func() {
+		if err := s.transport.Close(); err != nil {
+			s.logger.Debug("error closing log sink", "error", err)
+		}
+	}()
+
+	batch := make([]*logLine, 0, sinkMaxBatch)
+	backoff := time.Duration(0)
+	for {
+		if len(batch) == 0 {
+			select {
+			case line := <-s.lines:
+				batch = append(batch, line)
+			case <-s.stopCh:
+				s.flush(batch)
+				return
+			}
+		}
+		batch = s.drain(batch)
+
+		if err := s.transport.Send(batch); err != nil {
+			backoff = min(max(2*backoff, sinkMinBackoff), sinkMaxBackoff)
+			s.logger.Warn("failed to forward logs", "error", err, "retry_in", backoff)
+			select {
+			case <-time.After(backoff):
+				continue
+			case <-s.stopCh:
+				return
+			}
+		}
+
+		backoff = 0
+		clear(batch)
+		batch = batch[:0]
+		if dropped := s.dropped.Swap(0); dropped > 0 {
+			s.logger.Warn("log sink buffer was full, dropped lines", "dropped", dropped)
+		}
+	}
+}
+
+// drain adds buffered lines to the batch without blocking, up to the maximum
+// batch size.
+func (s *logSink) drain(batch []*logLine) []*logLine {
+	for len(batch) < sinkMaxBatch {
+		select {
+		case line := <-s.lines:
+			batch = append(batch, line)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// flush makes a single attempt to send the lines remaining in the buffer.
+func (s *logSink) flush(batch []*logLine) {
+	for {
+		batch = s.drain(batch)
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.transport.Send(batch); err != nil {
+			s.logger.Debug("failed to flush logs", "error", err)
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// Close stops forwarding lines, waiting up to the close tolerance for the
+// buffered lines to be sent.
+func (s *logSink) Close() {
+	close(s.stopCh)
+	select {
+	case <-s.doneCh:
+	case <-time.After(processOutputCloseTolerance):
+		s.logger.Warn("timed out waiting for log sink to flush")
+	}
+}
+
+// sinkWriter splits the data written to it into lines which are forwarded to
+// the sink. Writes never fail or block.
+type sinkWriter struct {
+	sink   *logSink
+	stream string
+	buf    []byte
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx == -1 {
+			break
+		}
+		w.sink.enqueue(w.stream, bytes.TrimSuffix(w.buf[:idx], []byte{'\r'}))
+		w.buf = w.buf[idx+1:]
+	}
+
+	// Lines without a newline are split once they reach the maximum size
+	for len(w.buf) >= sinkMaxLineSize {
+		w.sink.enqueue(w.stream, w.buf[:sinkMaxLineSize])
+		w.buf = w.buf[sinkMaxLineSize:]
+	}
+
+	// Avoid holding on to a large buffer once it has been consumed
+	if len(w.buf) == 0 && cap(w.buf) > sinkMaxLineSize {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// unixTransport writes lines as newline delimited JSON to a unix socket.
+type unixTransport struct {
+	path string
+	conn net.Conn
+}
+
+func (t *unixTransport) Send(lines []*logLine) error {
+	if t.conn == nil {
+		conn, err := net.DialTimeout("unix", t.path, sinkTimeout)
+		if err != nil {
+			return err
+		}
+		t.conn = conn
+	}
+
+	body, err := encodeLines(lines)
+	if err != nil {
+		return err
+	}
+
+	t.conn.SetWriteDeadline(time.Now().Add(sinkTimeout))
+	if _, err := t.conn.Write(body); err != nil {
+		t.Close()
+		return err
+	}
+	return nil
+}
+
+func (t *unixTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// httpTransport posts batches of lines as newline delimited JSON to an HTTP
+// endpoint.
+type httpTransport struct {
+	address string
+	client  *http.Client
+}
+
+func (t *httpTransport) Send(lines []*logLine) error {
+	body, err := encodeLines(lines)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Post(t.address, "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// encodeLines encodes the lines as newline delimited JSON.
+func encodeLines(lines []*logLine) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+const (
+	// syslogFacilityUser is the syslog facility of the forwarded lines.
+	syslogFacilityUser = 1
+
+	// syslogSeverityInfo and syslogSeverityErr are the syslog severities of
+	// lines from stdout and stderr.
+	syslogSeverityInfo = 6
+	syslogSeverityErr  = 3
+
+	// syslogSDID is the ID of the structured data element holding the
+	// metadata of the task. 32473 is the private enterprise number reserved
+	// for documentation by RFC5612.
+	syslogSDID = "nomad@32473"
+
+	// syslogMaxAppName is the maximum length of the APP-NAME of a message.
+	syslogMaxAppName = 48
+)
+
+// syslogTransport sends lines as RFC5424 messages to a syslog server. Messages
+// sent over stream sockets are framed using octet counting as described in
+// RFC6587.
+type syslogTransport struct {
+	network  string
+	address  string
+	hostname string
+
+	conn   net.Conn
+	stream bool
+}
+
+func newSyslogTransport(address string) (*syslogTransport, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	t := &syslogTransport{network: u.Scheme, address: u.Host, hostname: hostname}
+	if u.Scheme == "unix" {
+		t.address = u.Path
+	}
+	return t, nil
+}
+
+func (t *syslogTransport) connect() error {
+	if t.network != "unix" {
+		conn, err := net.DialTimeout(t.network, t.address, sinkTimeout)
+		if err != nil {
+			return err
+		}
+		t.conn, t.stream = conn, t.network == "tcp"
+		return nil
+	}
+
+	// Local syslog sockets are usually datagram sockets, but may also be
+	// stream sockets.
+	conn, err := net.DialTimeout("unixgram", t.address, sinkTimeout)
+	if err == nil {
+		t.conn, t.stream = conn, false
+		return nil
+	}
+	conn, err = net.DialTimeout("unix", t.address, sinkTimeout)
+	if err != nil {
+		return err
+	}
+	t.conn, t.stream = conn, true
+	return nil
+}
+
+func (t *syslogTransport) Send(lines []*logLine) error {
+	if t.conn == nil {
+		if err := t.connect(); err != nil {
+			return err
+		}
+	}
+
+	t.conn.SetWriteDeadline(time.Now().Add(sinkTimeout))
+	for _, line := range lines {
+		msg := formatSyslogMessage(t.hostname, line)
+		if t.stream {
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		}
+		if _, err := io.WriteString(t.conn, msg); err != nil {
+			t.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *syslogTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// formatSyslogMessage formats the line as an RFC5424 message, with the
+// metadata of the task as structured data.
+func formatSyslogMessage(hostname string, line *logLine) string {
+	severity := syslogSeverityInfo
+	if line.Stream == "stderr" {
+		severity = syslogSeverityErr
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s [%s alloc_id=\"%s\" job_id=\"%s\" namespace=\"%s\" task=\"%s\"] %s",
+		syslogFacilityUser*8+severity,
+		line.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+		hostname,
+		syslogHeaderValue(line.Task, syslogMaxAppName),
+		syslogHeaderValue(line.Stream, 32),
+		syslogSDID,
+		syslogParamValue(line.AllocID),
+		syslogParamValue(line.JobID),
+		syslogParamValue(line.Namespace),
+		syslogParamValue(line.Task),
+		line.Message,
+	)
+}
+
+// syslogHeaderValue returns the value truncated to the maximum length, with
+// characters which aren't allowed in a header field replaced.
+func syslogHeaderValue(v string, maxLen int) string {
+	if v == "" {
+		return "-"
+	}
+	out := []byte(v)
+	if len(out) > maxLen {
+		out = out[:maxLen]
+	}
+	for i, c := range out {
+		if c < 33 || c > 126 {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
+
+// syslogParamValue escapes the characters which must be escaped in the value
+// of a structured data parameter.
+func syslogParamValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(v)
+}
@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// testSinkTransport records the lines it is sent.
+type testSinkTransport struct {
+	lock  sync.Mutex
+	lines []logLine
+}
+
+func (t *testSinkTransport) Send(lines []*logLine) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, line := range lines {
+		t.lines = append(t.lines, *line)
+	}
+	return nil
+}
+
+func (t *testSinkTransport) Close() error { return nil }
+
+func (t *testSinkTransport) Lines() []logLine {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]logLine(nil), t.lines...)
+}
+
+// testLogSink returns a started sink forwarding lines to the transport.
+func testLogSink(t *testing.T, transport sinkTransport, bufferSize int) *logSink {
+	s := newTestLogSink(t, transport, bufferSize)
+	go s.run()
+	return s
+}
+
+func newTestLogSink(t *testing.T, transport sinkTransport, bufferSize int) *logSink {
+	return &logSink{
+		config: &LogConfig{
+			AllocID:   "alloc",
+			JobID:     "job",
+			Namespace: "default",
+			TaskName:  "web",
+		},
+		transport: transport,
+		logger:    testlog.HCLogger(t),
+		lines:     make(chan *logLine, bufferSize),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+func TestLogSink_Writer(t *testing.T) {
+	ci.Parallel(t)
+
+	transport := &testSinkTransport{}
+	sink := testLogSink(t, transport, 10)
+
+	stdout := sink.Writer("stdout")
+	stderr := sink.Writer("stderr")
+
+	for _, chunk := range []string{"hel", "lo\nwor", "ld\r\n", "\n"} {
+		n, err := stdout.Write([]byte(chunk))
+		must.NoError(t, err)
+		must.Eq(t, len(chunk), n)
+	}
+	_, err := stderr.Write([]byte("oops\npartial"))
+	must.NoError(t, err)
+
+	sink.Close()
+
+	lines := transport.Lines()
+	must.Len(t, 4, lines)
+	for _, line := range lines {
+		must.Eq(t, "alloc", line.AllocID)
+		must.Eq(t, "job", line.JobID)
+		must.Eq(t, "default", line.Namespace)
+		must.Eq(t, "web", line.Task)
+	}
+
+	var got []string
+	for _, line := range lines {
+		got = append(got, line.Stream+":"+line.Message)
+	}
+	must.SliceContainsAll(t, []string{"stdout:hello", "stdout:world", "stdout:", "stderr:oops"}, got)
+}
+
+func TestLogSink_Writer_longLine(t *testing.T) {
+	ci.Parallel(t)
+
+	transport := &testSinkTransport{}
+	sink := testLogSink(t, transport, 10)
+
+	_, err := sink.Writer("stdout").Write([]byte(strings.Repeat("a", sinkMaxLineSize+10)))
+	must.NoError(t, err)
+	sink.Close()
+
+	lines := transport.Lines()
+	must.Len(t, 1, lines)
+	must.Eq(t, sinkMaxLineSize, len(lines[0].Message))
+}
+
+func TestLogSink_bufferFull(t *testing.T) {
+	ci.Parallel(t)
+
+	// Lines are only buffered until the sink is started
+	transport := &testSinkTransport{}
+	sink := newTestLogSink(t, transport, 2)
+
+	// Writes never block, even though the buffer is full
+	w := sink.Writer("stdout")
+	for i := 0; i < 20; i++ {
+		_, err := fmt.Fprintf(w, "line %d\n", i)
+		must.NoError(t, err)
+	}
+	must.Eq(t, 18, sink.dropped.Load())
+
+	go sink.run()
+	sink.Close()
+
+	lines := transport.Lines()
+	must.Len(t, 2, lines)
+	must.Eq(t, "line 0", lines[0].Message)
+	must.Eq(t, "line 1", lines[1].Message)
+}
+
+func TestLogSink_unix(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "sink.sock")
+	l, err := net.Listen("unix", path)
+	must.NoError(t, err)
+	defer l.Close()
+
+	received := make(chan logLine, 10)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		dec := json.NewDecoder(conn)
+		for {
+			var line logLine
+			if err := dec.Decode(&line); err != nil {
+				return
+			}
+			received <- line
+		}
+	}()
+
+	transport, err := newSinkTransport(&structs.LogSinkConfig{
+		Type:    structs.LogSinkTypeUnix,
+		Address: path,
+	})
+	must.NoError(t, err)
+	sink := testLogSink(t, transport, 10)
+	defer sink.Close()
+
+	_, err = sink.Writer("stderr").Write([]byte("hello\n"))
+	must.NoError(t, err)
+
+	select {
+	case line := <-received:
+		must.Eq(t, "hello", line.Message)
+		must.Eq(t, "stderr", line.Stream)
+		must.Eq(t, "alloc", line.AllocID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for line")
+	}
+}
+
+func TestLogSink_http(t *testing.T) {
+	ci.Parallel(t)
+
+	received := make(chan logLine, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		dec := json.NewDecoder(r.Body)
+		for {
+			var line logLine
+			if err := dec.Decode(&line); err != nil {
+				break
+			}
+			received <- line
+		}
+	}))
+	defer srv.Close()
+
+	transport, err := newSinkTransport(&structs.LogSinkConfig{
+		Type:    structs.LogSinkTypeHTTP,
+		Address: srv.URL + "/logs",
+	})
+	must.NoError(t, err)
+	sink := testLogSink(t, transport, 10)
+	defer sink.Close()
+
+	_, err = sink.Writer("stdout").Write([]byte("one\ntwo\n"))
+	must.NoError(t, err)
+
+	for _, exp := range []string{"one", "two"} {
+		select {
+		case line := <-received:
+			must.Eq(t, exp, line.Message)
+			must.Eq(t, "job", line.JobID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for line")
+		}
+	}
+}
+
+func TestLogSink_syslog(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		must.NoError(t, err)
+		defer conn.Close()
+
+		transport, err := newSinkTransport(&structs.LogSinkConfig{
+			Type:    structs.LogSinkTypeSyslog,
+			Address: "udp://" + conn.LocalAddr().String(),
+		})
+		must.NoError(t, err)
+		sink := testLogSink(t, transport, 10)
+		defer sink.Close()
+
+		_, err = sink.Writer("stderr").Write([]byte("failed\n"))
+		must.NoError(t, err)
+
+		buf := make([]byte, 1024)
+		must.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		must.NoError(t, err)
+
+		msg := string(buf[:n])
+		must.StrHasPrefix(t, "<11>1 ", msg)
+		must.StrContains(t, msg, ` web - stderr [nomad@32473 alloc_id="alloc" job_id="job" namespace="default" task="web"] failed`)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		must.NoError(t, err)
+		defer l.Close()
+
+		received := make(chan string, 1)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			var length int
+			if _, err := fmt.Fscanf(r, "%d ", &length); err != nil {
+				return
+			}
+			msg := make([]byte, length)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			received <- string(msg)
+		}()
+
+		transport, err := newSinkTransport(&structs.LogSinkConfig{
+			Type:    structs.LogSinkTypeSyslog,
+			Address: "tcp://" + l.Addr().String(),
+		})
+		must.NoError(t, err)
+		sink := testLogSink(t, transport, 10)
+		defer sink.Close()
+
+		_, err = sink.Writer("stdout").Write([]byte("started\n"))
+		must.NoError(t, err)
+
+		select {
+		case msg := <-received:
+			must.StrHasPrefix(t, "<14>1 ", msg)
+			must.StrHasSuffix(t, "] started", msg)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for message")
+		}
+	})
+}
+
+func TestSyslogMessage_escaping(t *testing.T) {
+	ci.Parallel(t)
+
+	line := &logLine{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		Namespace: "default",
+		JobID:     `my"job]`,
+		AllocID:   "alloc",
+		Task:      "my task",
+		Stream:    "stdout",
+		Message:   "hello",
+	}
+	must.Eq(t,
+		`<14>1 2024-01-02T03:04:05.000006Z host my_task - stdout [nomad@32473 alloc_id="alloc" job_id="my\"job\]" namespace="default" task="my task"] hello`,
+		formatSyslogMessage("host", line))
+}
+
+func TestLogmon_Start_sink(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sink.sock")
+	l, err := net.Listen("unix", path)
+	must.NoError(t, err)
+	defer l.Close()
+
+	var lock sync.Mutex
+	var messages []string
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		dec := json.NewDecoder(conn)
+		for {
+			var line logLine
+			if err := dec.Decode(&line); err != nil {
+				return
+			}
+			lock.Lock()
+			messages = append(messages, line.Stream+":"+line.Message)
+			lock.Unlock()
+		}
+	}()
+
+	stdoutFifoPath := filepath.Join(dir, "stdout.fifo")
+	stderrFifoPath := filepath.Join(dir, "stderr.fifo")
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		AllocID:       "alloc",
+		JobID:         "job",
+		Namespace:     "default",
+		TaskName:      "web",
+		Sink: &structs.LogSinkConfig{
+			Type:    structs.LogSinkTypeUnix,
+			Address: path,
+		},
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	must.NoError(t, lm.Start(cfg))
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	must.NoError(t, err)
+	stderr, err := fifo.OpenWriter(stderrFifoPath)
+	must.NoError(t, err)
+
+	_, err = stdout.Write([]byte("to stdout\n"))
+	must.NoError(t, err)
+	_, err = stderr.Write([]byte("to stderr\n"))
+	must.NoError(t, err)
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			if len(messages) != 2 {
+				return fmt.Errorf("expected 2 messages, got %v", messages)
+			}
+			return nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	must.SliceContainsAll(t, []string{"stdout:to stdout", "stderr:to stderr"}, messages)
+
+	// The lines are still written to the log files
+	must.NoError(t, lm.Stop())
+	must.FileContains(t, filepath.Join(dir, "stdout.0"), "to stdout")
+}
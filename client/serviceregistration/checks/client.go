@@ -21,6 +21,10 @@ import (
 	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/helper/useragent"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime"
 )
 
@@ -36,7 +40,7 @@ type Checker interface {
 	Do(context.Context, *QueryContext, *Query) *structs.CheckQueryResult
 }
 
-// New creates a new Checker capable of executing HTTP and TCP checks.
+// New creates a new Checker capable of executing HTTP, gRPC, and TCP checks.
 func New(log hclog.Logger) Checker {
 	httpClient := cleanhttp.DefaultPooledClient()
 	httpClient.Timeout = maxTimeoutHTTP
@@ -67,6 +71,8 @@ func (c *checker) Do(ctx context.Context, qc *QueryContext, q *Query) *structs.C
 	switch q.Type {
 	case "http":
 		qr = c.checkHTTP(timeout, qc, q)
+	case "grpc":
+		qr = c.checkGRPC(timeout, qc, q)
 	default:
 		qr = c.checkTCP(timeout, qc, q)
 	}
@@ -191,16 +197,21 @@ func (c *checker) checkHTTP(ctx context.Context, qc *QueryContext, q *Query) *st
 	request = request.WithContext(ctx)
 
 	// Leave this setup until the last as it doesn't generate an error. If the
-	// check has specified TLS skip verify, generate a new round tripper. This
-	// job specification "check.tls_skip_verify" parameter supports in-place
-	// updates, so we must do this on each check iteration.
-	if q.TLSSkipVerify {
-		trans := cleanhttp.DefaultPooledTransport()
-		trans.TLSClientConfig = &tls.Config{InsecureSkipVerify: q.TLSSkipVerify}
-		c.httpClient.Transport = trans
+	// check has specified TLS options, use a new client so the options don't
+	// apply to other checks. The job specification "check.tls_skip_verify" and
+	// "check.tls_server_name" parameters support in-place updates, so we must
+	// do this on each check iteration.
+	client := c.httpClient
+	if q.TLSSkipVerify || q.TLSServerName != "" {
+		trans := cleanhttp.DefaultTransport()
+		trans.TLSClientConfig = tlsConfig(q)
+		client = &http.Client{
+			Transport: trans,
+			Timeout:   c.httpClient.Timeout,
+		}
 	}
 
-	result, err := c.httpClient.Do(request)
+	result, err := client.Do(request)
 	if err != nil {
 		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
 		qr.Status = structs.CheckFailure
@@ -234,6 +245,66 @@ func (c *checker) checkHTTP(ctx context.Context, qc *QueryContext, q *Query) *st
 	return qr
 }
 
+func (c *checker) checkGRPC(ctx context.Context, qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	addr, err := address(qc, q)
+	if err != nil {
+		qr.Output = err.Error()
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	creds := insecure.NewCredentials()
+	if q.GRPCUseTLS {
+		creds = credentials.NewTLS(tlsConfig(q))
+	}
+
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent(useragent.String()),
+	)
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	result, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: q.GRPCService,
+	})
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	if status := result.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		qr.Output = fmt.Sprintf("nomad: grpc status %s", status)
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	qr.Output = "nomad: grpc ok"
+	qr.Status = structs.CheckSuccess
+	return qr
+}
+
+// tlsConfig returns the TLS configuration of a check using TLS.
+func tlsConfig(q *Query) *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: q.TLSSkipVerify,
+		ServerName:         q.TLSServerName,
+	}
+}
+
 const (
 	// outputSizeLimit is the maximum number of bytes to read and store of an http
 	// check output. Set to 3kb which fits in 1 page with room for other fields.
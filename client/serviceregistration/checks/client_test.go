@@ -5,6 +5,7 @@ package checks
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"maps"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,6 +23,10 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/shoenig/test/must"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime/libtimetest"
 )
 
@@ -467,6 +473,34 @@ func TestChecker_Do_HTTPS_TLS(t *testing.T) {
 			must.StrContains(t, result.Output, tc.expectedResultOutput)
 		})
 	}
+
+	// Skipping verification for one check doesn't skip it for other checks
+	// of the same checker
+	c := New(testlog.HCLogger(t))
+	c.(*checker).clock = clock
+	queryContext := &QueryContext{
+		ID:               "abc123",
+		CustomAddress:    addr,
+		ServicePortLabel: port,
+		NetworkStatus:    mock.NewNetworkStatus(addr),
+	}
+	query := &Query{
+		Mode:          structs.Healthiness,
+		Type:          "http",
+		Timeout:       1 * time.Second,
+		AddressMode:   "auto",
+		PortLabel:     port,
+		Protocol:      "https",
+		Path:          "/",
+		Method:        http.MethodGet,
+		TLSSkipVerify: true,
+	}
+	result := c.Do(context.Background(), queryContext, query)
+	must.Eq(t, structs.CheckSuccess, result.Status)
+	query.TLSSkipVerify = false
+	result = c.Do(context.Background(), queryContext, query)
+	must.Eq(t, structs.CheckFailure, result.Status)
+	must.StrContains(t, result.Output, "tls: failed to verify certificate: x509")
 }
 
 func TestChecker_Do_TCP(t *testing.T) {
@@ -595,6 +629,200 @@ func TestChecker_Do_TCP(t *testing.T) {
 	}
 }
 
+func TestChecker_Do_GRPC(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a mock clock so we can assert time is set
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("api", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("db", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(srv.Stop)
+
+	addr, port, err := net.SplitHostPort(l.Addr().String())
+	must.NoError(t, err)
+
+	queryContext := &QueryContext{
+		ID:               "abc123",
+		CustomAddress:    addr,
+		ServicePortLabel: port,
+		Networks:         nil,
+		NetworkStatus:    mock.NewNetworkStatus(addr),
+		Ports:            nil,
+		Group:            "group",
+		Task:             "task",
+		Service:          "service",
+		Check:            "check",
+	}
+
+	testCases := []struct {
+		name      string
+		service   string
+		useTLS    bool
+		expStatus structs.CheckStatus
+		expOutput string
+	}{
+		{
+			name:      "server ok",
+			expStatus: structs.CheckSuccess,
+			expOutput: "nomad: grpc ok",
+		},
+		{
+			name:      "service ok",
+			service:   "api",
+			expStatus: structs.CheckSuccess,
+			expOutput: "nomad: grpc ok",
+		},
+		{
+			name:      "service not serving",
+			service:   "db",
+			expStatus: structs.CheckFailure,
+			expOutput: "nomad: grpc status NOT_SERVING",
+		},
+		{
+			name:      "service unknown",
+			service:   "unknown",
+			expStatus: structs.CheckFailure,
+			expOutput: "code = NotFound",
+		},
+		{
+			name:      "tls to plaintext server",
+			useTLS:    true,
+			expStatus: structs.CheckFailure,
+			expOutput: "code = Unavailable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(testlog.HCLogger(t))
+			c.(*checker).clock = clock
+
+			result := c.Do(context.Background(), queryContext, &Query{
+				Mode:          structs.Healthiness,
+				Type:          "grpc",
+				Timeout:       1 * time.Second,
+				AddressMode:   "auto",
+				PortLabel:     port,
+				GRPCService:   tc.service,
+				GRPCUseTLS:    tc.useTLS,
+				TLSSkipVerify: true,
+			})
+
+			must.Eq(t, tc.expStatus, result.Status)
+			must.StrContains(t, result.Output, tc.expOutput)
+			must.Eq(t, now.Unix(), result.Timestamp)
+		})
+	}
+}
+
+func TestChecker_Do_GRPC_TLS(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	// Serve with the self-signed certificate of an httptest server, and
+	// record the server name sent by the client for SNI
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	ts.Close()
+	var lock sync.Mutex
+	var serverName string
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: ts.TLS.Certificates,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			serverName = hello.ServerName
+			return nil, nil
+		},
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	srv := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(srv.Stop)
+
+	addr, port, err := net.SplitHostPort(l.Addr().String())
+	must.NoError(t, err)
+	queryContext := &QueryContext{
+		ID:               "abc123",
+		CustomAddress:    addr,
+		ServicePortLabel: port,
+		NetworkStatus:    mock.NewNetworkStatus(addr),
+	}
+
+	testCases := []struct {
+		name          string
+		skipVerify    bool
+		tlsServerName string
+		expStatus     structs.CheckStatus
+		expOutput     string
+		expServerName string
+	}{
+		{
+			name:       "skip verify",
+			skipVerify: true,
+			expStatus:  structs.CheckSuccess,
+			expOutput:  "nomad: grpc ok",
+		},
+		{
+			name:          "skip verify with server name",
+			skipVerify:    true,
+			tlsServerName: "example.com",
+			expStatus:     structs.CheckSuccess,
+			expOutput:     "nomad: grpc ok",
+			expServerName: "example.com",
+		},
+		{
+			name:          "verify",
+			tlsServerName: "example.com",
+			expStatus:     structs.CheckFailure,
+			expOutput:     "x509",
+			expServerName: "example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(testlog.HCLogger(t))
+			c.(*checker).clock = clock
+
+			result := c.Do(context.Background(), queryContext, &Query{
+				Mode:          structs.Healthiness,
+				Type:          "grpc",
+				Timeout:       1 * time.Second,
+				AddressMode:   "auto",
+				PortLabel:     port,
+				GRPCUseTLS:    true,
+				TLSSkipVerify: tc.skipVerify,
+				TLSServerName: tc.tlsServerName,
+			})
+
+			must.Eq(t, tc.expStatus, result.Status)
+			must.StrContains(t, result.Output, tc.expOutput)
+			lock.Lock()
+			defer lock.Unlock()
+			must.Eq(t, tc.expServerName, serverName)
+		})
+	}
+}
+
 // tcpServer will start a tcp listener that accepts connections and closes them.
 // The caller can close the listener by cancelling ctx.
 func tcpServer(t *testing.T, ctx context.Context, port int) {
@@ -9,9 +9,11 @@ import (
 	"io"
 	golog "log"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -275,6 +277,13 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.EnabledSchedulers = schedulers
 
 	}
+	for _, addr := range agentConfig.Server.CanaryAnalysisAddresses {
+		u, err := url.Parse(addr)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("canary_analysis_addresses must be HTTP or HTTPS URLs, got %q", addr)
+		}
+	}
+	conf.CanaryAnalysisAddresses = slices.Clone(agentConfig.Server.CanaryAnalysisAddresses)
 	if agentConfig.ACL.Enabled {
 		conf.ACLEnabled = true
 	}
@@ -600,6 +609,35 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 	}
 
+	// Set the snapshot agent configuration.
+	if snapConf := agentConfig.Server.SnapshotAgent; snapConf != nil && snapConf.Enabled != nil && *snapConf.Enabled {
+		if snapConf.Interval <= 0 {
+			return nil, fmt.Errorf("snapshot_agent.interval must be greater than 0")
+		}
+		if snapConf.RetainAge < 0 {
+			return nil, fmt.Errorf("snapshot_agent.retain_age must not be negative")
+		}
+		retain := 0
+		if snapConf.Retain != nil {
+			if *snapConf.Retain < 0 {
+				return nil, fmt.Errorf("snapshot_agent.retain must not be negative")
+			}
+			retain = *snapConf.Retain
+		}
+		path := snapConf.Path
+		if path == "" {
+			path = filepath.Join(conf.DataDir, "snapshots")
+		}
+		conf.SnapshotAgentConfig = &nomad.SnapshotAgentConfig{
+			Enabled:   true,
+			Interval:  snapConf.Interval,
+			Path:      path,
+			Retain:    retain,
+			RetainAge: snapConf.RetainAge,
+			Redact:    snapConf.Redact != nil && *snapConf.Redact,
+		}
+	}
+
 	// Add Enterprise license configs
 	conf.LicenseConfig = &nomad.LicenseConfig{
 		BuildDate:         agentConfig.Version.BuildDate,
@@ -755,6 +793,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 
 	conf.Servers = agentConfig.Client.Servers
+	conf.LogSinkAllowlist = slices.Clone(agentConfig.Client.LogSinkAllowlist)
 	conf.DevMode = agentConfig.DevMode
 	conf.EnableDebug = agentConfig.EnableDebug
 
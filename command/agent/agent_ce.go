@@ -7,6 +7,8 @@
 package agent
 
 import (
+	"fmt"
+
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 )
@@ -17,11 +19,19 @@ type EnterpriseAgent struct{}
 
 func (a *Agent) setupEnterpriseAgent(log hclog.Logger) error {
 	// configure eventer
-	a.auditor = &noOpAuditor{}
+	auditor, err := newFileAuditor(a.config.Audit, a.config.DataDir, log)
+	if err != nil {
+		return fmt.Errorf("failed to setup audit logging: %v", err)
+	}
+	a.auditor = auditor
 
 	return nil
 }
 
 func (a *Agent) entReloadEventer(cfg *config.AuditConfig) error {
-	return nil
+	auditor, ok := a.auditor.(*fileAuditor)
+	if !ok {
+		return nil
+	}
+	return auditor.reload(cfg, a.GetConfig().DataDir)
 }
@@ -18,6 +18,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
@@ -449,6 +450,129 @@ func TestAgent_ServerConfig_PlanRejectionTracker(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_SnapshotAgent(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name           string
+		agentConfig    *SnapshotAgentConfig
+		expectedConfig *nomad.SnapshotAgentConfig
+		expectedErr    string
+	}{
+		{
+			name:           "default",
+			expectedConfig: nil,
+		},
+		{
+			name: "defaults when enabled",
+			agentConfig: &SnapshotAgentConfig{
+				Enabled: pointer.Of(true),
+			},
+			expectedConfig: &nomad.SnapshotAgentConfig{
+				Enabled:  true,
+				Interval: time.Hour,
+				Path:     "/tmp/nomad/server/snapshots",
+				Retain:   24,
+			},
+		},
+		{
+			name: "valid config",
+			agentConfig: &SnapshotAgentConfig{
+				Enabled:   pointer.Of(true),
+				Interval:  10 * time.Minute,
+				Path:      "/opt/snapshots",
+				Retain:    pointer.Of(0),
+				RetainAge: 48 * time.Hour,
+				Redact:    pointer.Of(true),
+			},
+			expectedConfig: &nomad.SnapshotAgentConfig{
+				Enabled:   true,
+				Interval:  10 * time.Minute,
+				Path:      "/opt/snapshots",
+				RetainAge: 48 * time.Hour,
+				Redact:    true,
+			},
+		},
+		{
+			name: "invalid interval",
+			agentConfig: &SnapshotAgentConfig{
+				Enabled:  pointer.Of(true),
+				Interval: -time.Minute,
+			},
+			expectedErr: "snapshot_agent.interval must be greater than 0",
+		},
+		{
+			name: "invalid retain",
+			agentConfig: &SnapshotAgentConfig{
+				Enabled: pointer.Of(true),
+				Retain:  pointer.Of(-1),
+			},
+			expectedErr: "snapshot_agent.retain must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DevConfig(nil)
+			must.NoError(t, config.normalizeAddrs())
+			config.DataDir = "/tmp/nomad"
+			config.Server.SnapshotAgent = config.Server.SnapshotAgent.Merge(tc.agentConfig)
+
+			serverConfig, err := convertServerConfig(config)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expectedConfig, serverConfig.SnapshotAgentConfig)
+		})
+	}
+}
+
+func TestAgent_ServerConfig_CanaryAnalysisAddresses(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		addresses   []string
+		expectedErr string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:      "valid",
+			addresses: []string{"http://prometheus.example:9090", "https://metrics.example/prometheus"},
+		},
+		{
+			name:        "no scheme",
+			addresses:   []string{"prometheus.example:9090"},
+			expectedErr: `canary_analysis_addresses must be HTTP or HTTPS URLs, got "prometheus.example:9090"`,
+		},
+		{
+			name:        "unsupported scheme",
+			addresses:   []string{"file:///etc/passwd"},
+			expectedErr: "canary_analysis_addresses must be HTTP or HTTPS URLs",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DevConfig(nil)
+			must.NoError(t, config.normalizeAddrs())
+			config.Server.CanaryAnalysisAddresses = tc.addresses
+
+			serverConfig, err := convertServerConfig(config)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.addresses, serverConfig.CanaryAnalysisAddresses)
+		})
+	}
+}
+
 func TestAgent_ServerConfig_RaftMultiplier_Ok(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,478 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !ent
+// +build !ent
+
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/ryanuber/go-glob"
+)
+
+const (
+	// auditStageReceived is the stage of the audit event written before an
+	// HTTP request is handled.
+	auditStageReceived = "OperationReceived"
+
+	// auditStageComplete is the stage of the audit event written after an
+	// HTTP request is handled but before the response body is returned.
+	auditStageComplete = "OperationComplete"
+
+	auditEventType          = "audit"
+	auditFilterTypeHTTP     = "HTTPEvent"
+	auditSinkTypeFile       = "file"
+	auditSinkFormatJSON     = "json"
+	auditDeliveryEnforced   = "enforced"
+	auditDeliveryBestEffort = "best-effort"
+
+	// auditVersion is the version of the audit log entry format.
+	auditVersion = 1
+
+	// auditMaxBodySize is the size of the largest request body that is
+	// recorded in an audit log entry. Larger bodies are omitted.
+	auditMaxBodySize = 64 * 1024
+
+	auditRedacted = "[REDACTED]"
+)
+
+var (
+	// auditHashedFields are request body fields which hold tokens. Their
+	// values are replaced with a hash so that requests made with the same
+	// token can still be correlated.
+	auditHashedFields = []string{
+		"AuthToken",
+		"BootstrapSecret",
+		"ConsulToken",
+		"LoginToken",
+		"OneTimeSecretID",
+		"SecretID",
+		"Token",
+		"VaultToken",
+	}
+
+	// auditRedactedFields are request body fields which are removed
+	// entirely, because they may hold low entropy secrets such as variable
+	// items that a hash would not protect.
+	auditRedactedFields = []string{
+		"Items",
+		"OIDCClientSecret",
+		"Password",
+	}
+)
+
+// auditEntry is a single line of the audit log.
+type auditEntry struct {
+	CreatedAt time.Time     `json:"created_at"`
+	EventType string        `json:"event_type"`
+	Payload   *auditPayload `json:"payload"`
+}
+
+// auditPayload describes an HTTP request at one stage of its lifecycle.
+type auditPayload struct {
+	ID        string         `json:"id"`
+	Stage     string         `json:"stage"`
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Version   int            `json:"version"`
+	Auth      *auditAuth     `json:"auth,omitempty"`
+	Request   *auditRequest  `json:"request"`
+	Response  *auditResponse `json:"response,omitempty"`
+}
+
+// auditAuth identifies the caller of an HTTP request. It is omitted when ACLs
+// are disabled.
+type auditAuth struct {
+	AccessorID string         `json:"accessor_id,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	Policies   []string       `json:"policies,omitempty"`
+	Roles      []string       `json:"roles,omitempty"`
+	Global     bool           `json:"global,omitempty"`
+	CreateTime *time.Time     `json:"create_time,omitempty"`
+	Workload   *auditWorkload `json:"workload,omitempty"`
+
+	// TokenHash is set instead of the other fields when the request token
+	// could not be resolved, such as when it doesn't exist or has expired.
+	TokenHash string `json:"token_hash,omitempty"`
+}
+
+// auditWorkload identifies a caller authenticated by a workload identity.
+type auditWorkload struct {
+	Namespace    string `json:"namespace"`
+	JobID        string `json:"job_id"`
+	AllocationID string `json:"alloc_id"`
+	Task         string `json:"task,omitempty"`
+	Service      string `json:"service,omitempty"`
+}
+
+type auditRequest struct {
+	ID          string           `json:"id"`
+	Operation   string           `json:"operation"`
+	Endpoint    string           `json:"endpoint"`
+	Namespace   auditNamespace   `json:"namespace"`
+	RequestMeta auditRequestMeta `json:"request_meta"`
+	NodeMeta    auditNodeMeta    `json:"node_meta"`
+	Body        any              `json:"body,omitempty"`
+
+	// path is the endpoint without its query string, which is what filters
+	// are matched against.
+	path string
+}
+
+type auditNamespace struct {
+	ID string `json:"id"`
+}
+
+type auditRequestMeta struct {
+	RemoteAddress string `json:"remote_address"`
+	UserAgent     string `json:"user_agent"`
+}
+
+type auditNodeMeta struct {
+	IP string `json:"ip"`
+}
+
+type auditResponse struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// fileAuditor is the community edition audit logger. It writes audit events
+// for HTTP requests as newline delimited JSON to a single rotating file sink.
+type fileAuditor struct {
+	logger  hclog.Logger
+	enabled atomic.Bool
+
+	// l guards the fields below, which are replaced when the agent
+	// configuration is reloaded
+	l        sync.RWMutex
+	enforced bool
+	filters  []*config.AuditFilter
+	sinkCfg  *config.AuditSink
+	sink     *logFile
+}
+
+// Ensure fileAuditor is an Auditor
+var _ event.Auditor = &fileAuditor{}
+
+// newFileAuditor returns an audit logger for the given configuration. The
+// auditor is returned even if audit logging is disabled, so that it can be
+// enabled when the agent configuration is reloaded.
+func newFileAuditor(cfg *config.AuditConfig, dataDir string, logger hclog.Logger) (*fileAuditor, error) {
+	a := &fileAuditor{
+		logger: logger.Named("audit"),
+	}
+	if err := a.reload(cfg, dataDir); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload applies a new audit configuration. The sink is only replaced if its
+// configuration has changed.
+func (a *fileAuditor) reload(cfg *config.AuditConfig, dataDir string) error {
+	if cfg == nil || cfg.Enabled == nil || !*cfg.Enabled {
+		a.SetEnabled(false)
+		return nil
+	}
+
+	sinkCfg, err := auditSinkConfig(cfg, dataDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range cfg.Filters {
+		if f.Type != auditFilterTypeHTTP {
+			return fmt.Errorf("audit filter %q has unsupported type %q", f.Name, f.Type)
+		}
+	}
+
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	if a.sink == nil || *a.sinkCfg != *sinkCfg {
+		sink, err := newAuditLogFile(sinkCfg)
+		if err != nil {
+			return err
+		}
+		if a.sink != nil {
+			if err := a.sink.Close(); err != nil {
+				a.logger.Warn("failed to close previous audit log file", "error", err)
+			}
+		}
+		a.sink = sink
+		a.sinkCfg = sinkCfg
+		a.logger.Info("writing audit log", "path", sinkCfg.Path,
+			"delivery_guarantee", sinkCfg.DeliveryGuarantee)
+	}
+
+	a.enforced = sinkCfg.DeliveryGuarantee == auditDeliveryEnforced
+	a.filters = make([]*config.AuditFilter, len(cfg.Filters))
+	for i, f := range cfg.Filters {
+		a.filters[i] = f.Copy()
+	}
+
+	a.SetEnabled(true)
+	return nil
+}
+
+// auditSinkConfig returns the configuration of the audit sink with defaults
+// applied.
+func auditSinkConfig(cfg *config.AuditConfig, dataDir string) (*config.AuditSink, error) {
+	var sink *config.AuditSink
+	switch len(cfg.Sinks) {
+	case 0:
+		sink = &config.AuditSink{Name: "audit"}
+	case 1:
+		sink = cfg.Sinks[0].Copy()
+	default:
+		return nil, errors.New("only a single audit sink is supported")
+	}
+
+	if sink.Type == "" {
+		sink.Type = auditSinkTypeFile
+	}
+	if sink.Format == "" {
+		sink.Format = auditSinkFormatJSON
+	}
+	if sink.DeliveryGuarantee == "" {
+		sink.DeliveryGuarantee = auditDeliveryEnforced
+	}
+	if sink.RotateDuration == 0 {
+		sink.RotateDuration = 24 * time.Hour
+	}
+	if sink.Mode == "" {
+		sink.Mode = "0600"
+	}
+	if sink.Path == "" {
+		if dataDir == "" {
+			return nil, fmt.Errorf("audit sink %q requires a path when data_dir is not set", sink.Name)
+		}
+		sink.Path = filepath.Join(dataDir, "audit", "audit.log")
+	}
+
+	if sink.Type != auditSinkTypeFile {
+		return nil, fmt.Errorf("audit sink %q has unsupported type %q", sink.Name, sink.Type)
+	}
+	if sink.Format != auditSinkFormatJSON {
+		return nil, fmt.Errorf("audit sink %q has unsupported format %q", sink.Name, sink.Format)
+	}
+	switch sink.DeliveryGuarantee {
+	case auditDeliveryEnforced, auditDeliveryBestEffort:
+	default:
+		return nil, fmt.Errorf("audit sink %q has invalid delivery_guarantee %q", sink.Name, sink.DeliveryGuarantee)
+	}
+	if _, err := strconv.ParseUint(sink.Mode, 8, 32); err != nil {
+		return nil, fmt.Errorf("audit sink %q has invalid mode %q: %v", sink.Name, sink.Mode, err)
+	}
+
+	return sink, nil
+}
+
+// newAuditLogFile creates the directory of the audit sink and returns a
+// rotating log file for it.
+func newAuditLogFile(sink *config.AuditSink) (*logFile, error) {
+	mode, err := strconv.ParseUint(sink.Mode, 8, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, fileName := filepath.Split(sink.Path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %v", err)
+	}
+
+	return &logFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: sink.RotateDuration,
+		MaxBytes: sink.RotateBytes,
+		MaxFiles: sink.RotateMaxFiles,
+		mode:     os.FileMode(mode),
+	}, nil
+}
+
+// Event writes an audit event to the sink, unless it matches a filter.
+func (a *fileAuditor) Event(ctx context.Context, eventType string, payload interface{}) error {
+	if !a.Enabled() {
+		return nil
+	}
+
+	p, ok := payload.(*auditPayload)
+	if !ok {
+		return fmt.Errorf("unsupported audit event payload %T", payload)
+	}
+
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	if a.filtered(p) {
+		return nil
+	}
+
+	buf, err := json.Marshal(&auditEntry{
+		CreatedAt: time.Now(),
+		EventType: eventType,
+		Payload:   p,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %v", err)
+	}
+	buf = append(buf, '\n')
+
+	if _, err := a.sink.Write(buf); err != nil {
+		return fmt.Errorf("failed to write audit event: %v", err)
+	}
+	return nil
+}
+
+// filtered returns true if the payload matches any of the filters and should
+// not be written. An empty list of endpoints, stages or operations matches
+// everything.
+func (a *fileAuditor) filtered(p *auditPayload) bool {
+	for _, f := range a.filters {
+		if auditFilterMatch(f.Endpoints, p.Request.path) &&
+			auditFilterMatch(f.Stages, p.Stage) &&
+			auditFilterMatch(f.Operations, p.Request.Operation) {
+			return true
+		}
+	}
+	return false
+}
+
+func auditFilterMatch(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled details if the auditor is enabled or not.
+func (a *fileAuditor) Enabled() bool {
+	return a.enabled.Load()
+}
+
+// Reopen closes the current audit log file so that the next event opens it
+// again. This allows the file to be moved by external log rotation.
+func (a *fileAuditor) Reopen() error {
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	if a.sink == nil {
+		return nil
+	}
+	return a.sink.Close()
+}
+
+// SetEnabled sets the auditor to enabled or disabled.
+func (a *fileAuditor) SetEnabled(enabled bool) {
+	a.enabled.Store(enabled)
+}
+
+// DeliveryEnforced returns whether requests must fail if their audit events
+// can't be written.
+func (a *fileAuditor) DeliveryEnforced() bool {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	return a.enforced
+}
+
+// newAuditAuth returns the audit representation of the identity that
+// authenticated a request.
+func newAuditAuth(ident *structs.AuthenticatedIdentity) *auditAuth {
+	switch {
+	case ident.ACLToken != nil:
+		token := ident.ACLToken
+		auth := &auditAuth{
+			AccessorID: token.AccessorID,
+			Name:       token.Name,
+			Policies:   token.Policies,
+			Global:     token.Global,
+			CreateTime: &token.CreateTime,
+		}
+		for _, role := range token.Roles {
+			auth.Roles = append(auth.Roles, role.Name)
+		}
+		return auth
+	case ident.Claims != nil:
+		return &auditAuth{
+			Workload: &auditWorkload{
+				Namespace:    ident.Claims.Namespace,
+				JobID:        ident.Claims.JobID,
+				AllocationID: ident.Claims.AllocationID,
+				Task:         ident.Claims.TaskName,
+				Service:      ident.Claims.ServiceName,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// redactAuditBody replaces the values of sensitive fields of a decoded JSON
+// request body, at any depth.
+func redactAuditBody(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			switch {
+			case auditFieldIn(auditRedactedFields, key):
+				v[key] = auditRedacted
+			case auditFieldIn(auditHashedFields, key):
+				v[key] = hashAuditValue(val)
+			default:
+				v[key] = redactAuditBody(val)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactAuditBody(v[i])
+		}
+	}
+	return v
+}
+
+func auditFieldIn(fields []string, key string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashAuditValue returns the SHA-256 hash of a token. Values that aren't
+// strings are redacted.
+func hashAuditValue(v any) any {
+	s, ok := v.(string)
+	switch {
+	case !ok:
+		return auditRedacted
+	case s == "":
+		return s
+	}
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,316 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !ent
+// +build !ent
+
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+// readAuditLog returns the entries written to the audit log at path.
+func readAuditLog(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]any
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	must.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestAuditSinkConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("defaults", func(t *testing.T) {
+		sink, err := auditSinkConfig(&config.AuditConfig{}, "/var/lib/nomad")
+		must.NoError(t, err)
+		must.Eq(t, &config.AuditSink{
+			Name:              "audit",
+			Type:              auditSinkTypeFile,
+			Format:            auditSinkFormatJSON,
+			DeliveryGuarantee: auditDeliveryEnforced,
+			Path:              "/var/lib/nomad/audit/audit.log",
+			RotateDuration:    24 * time.Hour,
+			Mode:              "0600",
+		}, sink)
+	})
+
+	testCases := []struct {
+		name   string
+		sinks  []*config.AuditSink
+		expErr string
+	}{
+		{
+			name:   "no path",
+			sinks:  []*config.AuditSink{{Name: "audit"}},
+			expErr: "requires a path",
+		},
+		{
+			name:   "multiple sinks",
+			sinks:  []*config.AuditSink{{Name: "a"}, {Name: "b"}},
+			expErr: "only a single audit sink",
+		},
+		{
+			name:   "bad type",
+			sinks:  []*config.AuditSink{{Name: "audit", Path: "/tmp/a.log", Type: "syslog"}},
+			expErr: "unsupported type",
+		},
+		{
+			name:   "bad format",
+			sinks:  []*config.AuditSink{{Name: "audit", Path: "/tmp/a.log", Format: "xml"}},
+			expErr: "unsupported format",
+		},
+		{
+			name:   "bad delivery guarantee",
+			sinks:  []*config.AuditSink{{Name: "audit", Path: "/tmp/a.log", DeliveryGuarantee: "maybe"}},
+			expErr: "invalid delivery_guarantee",
+		},
+		{
+			name:   "bad mode",
+			sinks:  []*config.AuditSink{{Name: "audit", Path: "/tmp/a.log", Mode: "0999"}},
+			expErr: "invalid mode",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := auditSinkConfig(&config.AuditConfig{Sinks: tc.sinks}, "")
+			must.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFileAuditor_Event(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	auditor, err := newFileAuditor(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "audit", Path: path}},
+		Filters: []*config.AuditFilter{
+			{
+				Name:      "metrics",
+				Type:      auditFilterTypeHTTP,
+				Endpoints: []string{"/v1/metrics"},
+			},
+			{
+				Name:       "received reads",
+				Type:       auditFilterTypeHTTP,
+				Endpoints:  []string{"/v1/job/*"},
+				Stages:     []string{auditStageReceived},
+				Operations: []string{http.MethodGet},
+			},
+		},
+	}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.True(t, auditor.Enabled())
+	must.True(t, auditor.DeliveryEnforced())
+
+	event := func(stage, method, path string) {
+		t.Helper()
+		must.NoError(t, auditor.Event(context.Background(), auditEventType, &auditPayload{
+			ID:    "id",
+			Stage: stage,
+			Request: &auditRequest{
+				Operation: method,
+				Endpoint:  path + "?pretty",
+				path:      path,
+			},
+		}))
+	}
+
+	event(auditStageReceived, http.MethodGet, "/v1/metrics")
+	event(auditStageComplete, http.MethodGet, "/v1/metrics")
+	event(auditStageReceived, http.MethodGet, "/v1/job/example")
+	event(auditStageComplete, http.MethodGet, "/v1/job/example")
+	event(auditStageReceived, http.MethodPost, "/v1/job/example")
+
+	entries := readAuditLog(t, path)
+	must.Len(t, 2, entries)
+
+	payload := entries[0]["payload"].(map[string]any)
+	must.Eq(t, auditStageComplete, payload["stage"])
+	must.Eq(t, "/v1/job/example?pretty", payload["request"].(map[string]any)["endpoint"])
+
+	info, err := os.Stat(path)
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Disabling audit logging stops events from being written
+	must.NoError(t, auditor.reload(&config.AuditConfig{Enabled: pointer.Of(false)}, ""))
+	must.False(t, auditor.Enabled())
+	event(auditStageReceived, http.MethodPost, "/v1/jobs")
+	must.Len(t, 2, readAuditLog(t, path))
+
+	// Reopening allows the file to be moved away
+	must.NoError(t, auditor.reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "audit", Path: path}},
+	}, ""))
+	must.NoError(t, os.Rename(path, path+".old"))
+	must.NoError(t, auditor.Reopen())
+	event(auditStageReceived, http.MethodGet, "/v1/metrics")
+	must.Len(t, 1, readAuditLog(t, path))
+}
+
+func TestRedactAuditBody(t *testing.T) {
+	ci.Parallel(t)
+
+	var body any
+	must.NoError(t, json.Unmarshal([]byte(`{
+  "Namespace": "default",
+  "Path": "app/config",
+  "Items": {"password": "hunter2"},
+  "Token": {"SecretID": "6d5f7e4c-2ea4-4ba6-8bb3-66c4dbc4dc32", "Name": "ci"},
+  "Policies": [{"Name": "a", "AuthToken": ""}]
+}`), &body))
+
+	redacted := redactAuditBody(body).(map[string]any)
+	must.Eq(t, "default", redacted["Namespace"])
+	must.Eq(t, "app/config", redacted["Path"])
+	must.Eq(t, auditRedacted, redacted["Items"])
+
+	// Values of hashed fields which aren't strings are redacted instead
+	must.Eq(t, auditRedacted, redacted["Token"])
+
+	policy := redacted["Policies"].([]any)[0].(map[string]any)
+	must.Eq(t, "a", policy["Name"])
+	must.Eq(t, "", policy["AuthToken"])
+
+	hash := hashAuditValue("6d5f7e4c-2ea4-4ba6-8bb3-66c4dbc4dc32").(string)
+	must.StrHasPrefix(t, "sha256:", hash)
+	must.StrNotContains(t, hash, "6d5f7e4c")
+	must.Eq[any](t, hash, hashAuditValue("6d5f7e4c-2ea4-4ba6-8bb3-66c4dbc4dc32"))
+}
+
+func TestHTTPServer_AuditHandler(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := filepath.Join(t.TempDir(), "audit")
+	path := filepath.Join(dir, "audit.log")
+	auditor, err := newFileAuditor(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "audit", Path: path}},
+	}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+
+	srv := &HTTPServer{
+		agent:        &Agent{config: DevConfig(nil)},
+		eventAuditor: auditor,
+		logger:       hclog.NewNullLogger(),
+		Addr:         "127.0.0.1:4646",
+	}
+
+	// The handler must still be able to read the whole body
+	var handlerBody string
+	handler := srv.wrap(func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		buf, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		handlerBody = string(buf)
+		if req.Method == http.MethodDelete {
+			return nil, structs.ErrPermissionDenied
+		}
+		return map[string]string{"ok": "true"}, nil
+	})
+
+	reqBody := `{"Path":"app/config","Items":{"password":"hunter2"}}`
+	req := httptest.NewRequest(http.MethodPut, "/v1/var/app/config?namespace=prod", strings.NewReader(reqBody))
+	req.Header.Set("User-Agent", "test")
+	resp := httptest.NewRecorder()
+	handler(resp, req)
+	must.Eq(t, http.StatusOK, resp.Code)
+	must.Eq(t, reqBody, handlerBody)
+
+	req = httptest.NewRequest(http.MethodDelete, "/v1/var/app/config", nil)
+	resp = httptest.NewRecorder()
+	handler(resp, req)
+	must.Eq(t, http.StatusForbidden, resp.Code)
+
+	entries := readAuditLog(t, path)
+	must.Len(t, 4, entries)
+
+	received := entries[0]["payload"].(map[string]any)
+	must.Eq(t, auditStageReceived, received["stage"])
+	must.Nil(t, received["response"])
+	request := received["request"].(map[string]any)
+	must.Eq(t, http.MethodPut, request["operation"])
+	must.Eq(t, "/v1/var/app/config?namespace=prod", request["endpoint"])
+	must.Eq(t, "prod", request["namespace"].(map[string]any)["id"])
+	must.Eq(t, "test", request["request_meta"].(map[string]any)["user_agent"])
+	must.Eq(t, "127.0.0.1:4646", request["node_meta"].(map[string]any)["ip"])
+	must.Eq[any](t, map[string]any{"Path": "app/config", "Items": auditRedacted}, request["body"])
+
+	// ACLs are disabled, so there is no identity to record
+	must.Nil(t, received["auth"])
+
+	complete := entries[1]["payload"].(map[string]any)
+	must.Eq(t, auditStageComplete, complete["stage"])
+	must.Eq(t, received["id"], complete["id"])
+	must.Eq[any](t, map[string]any{"status_code": float64(http.StatusOK)}, complete["response"])
+
+	failed := entries[3]["payload"].(map[string]any)
+	must.Eq[any](t, map[string]any{
+		"status_code": float64(http.StatusForbidden),
+		"error":       structs.ErrPermissionDenied.Error(),
+	}, failed["response"])
+
+	// Requests fail if their audit events can't be written when delivery is
+	// enforced
+	must.NoError(t, auditor.Reopen())
+	must.NoError(t, os.RemoveAll(dir))
+	must.NoError(t, os.WriteFile(dir, nil, 0o600))
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+	resp = httptest.NewRecorder()
+	handler(resp, req)
+	must.Eq(t, http.StatusInternalServerError, resp.Code)
+	must.Eq(t, "failed to write audit event", resp.Body.String())
+}
+
+func TestAuditResponseWriter(t *testing.T) {
+	ci.Parallel(t)
+
+	rec := httptest.NewRecorder()
+	rw := &auditResponseWriter{ResponseWriter: rec}
+	must.Eq(t, http.StatusOK, rw.status())
+
+	rw.WriteHeader(http.StatusConflict)
+	rw.WriteHeader(http.StatusOK)
+	_, err := rw.Write([]byte("conflict"))
+	must.NoError(t, err)
+	must.Eq(t, http.StatusConflict, rw.status())
+	must.Eq(t, http.StatusConflict, rec.Code)
+
+	// Websocket and streaming handlers rely on the writer's interfaces
+	var _ http.Hijacker = rw
+	var _ http.Flusher = rw
+}
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -33,6 +34,7 @@ import (
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	gatedwriter "github.com/hashicorp/nomad/helper/gated-writer"
 	"github.com/hashicorp/nomad/helper/logging"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/winsvc"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -835,6 +837,24 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Initialize tracing
+	shutdownTracing, err := c.setupTracing(config, logger)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing tracing: %s", err))
+		return 1
+	}
+	if shutdownTracing != nil {
+		defer func() {
+			// Flush spans after the agent has shut down so that it's
+			// included in any traces
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Warn("failed to flush traces", "error", err)
+			}
+		}()
+	}
+
 	// Create the agent
 	if err := c.setupAgent(config, logger, logOutput, inmem); err != nil {
 		logGate.Flush()
@@ -1206,6 +1226,26 @@ func (c *Command) handleReload() error {
 	return nil
 }
 
+// setupTracing is used to set up the export of trace spans. It returns a
+// function which flushes any spans yet to be exported, or nil if tracing is
+// disabled.
+func (c *Command) setupTracing(config *Config, logger hclog.Logger) (func(context.Context) error, error) {
+	cfg := config.Telemetry.TracingConfig()
+	if cfg == nil {
+		return nil, nil
+	}
+
+	cfg.Attributes = map[string]string{
+		"service.version":  config.Version.VersionNumber(),
+		"nomad.region":     config.Region,
+		"nomad.datacenter": config.Datacenter,
+	}
+	if config.NodeName != "" {
+		cfg.Attributes["service.instance.id"] = config.NodeName
+	}
+	return tracing.Setup(cfg, logger.Named("tracing"))
+}
+
 // setupTelemetry is used to set up the telemetry sub-systems.
 func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 
@@ -28,6 +28,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/ipaddr"
 	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/users"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -243,6 +244,10 @@ type ClientConfig struct {
 	// Servers is a list of known server addresses. These are as "host:port"
 	Servers []string `hcl:"servers"`
 
+	// LogSinkAllowlist is the list of the addresses of the log sinks tasks
+	// are allowed to forward their logs to.
+	LogSinkAllowlist []string `hcl:"log_sink_allowlist"`
+
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
@@ -433,6 +438,7 @@ func (c *ClientConfig) Copy() *ClientConfig {
 
 	nc := *c
 	nc.Servers = slices.Clone(c.Servers)
+	nc.LogSinkAllowlist = slices.Clone(c.LogSinkAllowlist)
 	nc.Options = maps.Clone(c.Options)
 	nc.Meta = maps.Clone(c.Meta)
 	nc.ChrootEnv = maps.Clone(c.ChrootEnv)
@@ -544,6 +550,10 @@ type ServerConfig struct {
 	// that the workers dequeue for processing.
 	EnabledSchedulers []string `hcl:"enabled_schedulers"`
 
+	// CanaryAnalysisAddresses are the addresses of the Prometheus-compatible
+	// HTTP APIs the canary analyses of jobs are allowed to query.
+	CanaryAnalysisAddresses []string `hcl:"canary_analysis_addresses"`
+
 	// NodeGCThreshold controls how "old" a node must be to be collected by GC.
 	// Age is not the only requirement for a node to be GCed but the threshold
 	// can be used to filter by age.
@@ -680,6 +690,10 @@ type ServerConfig struct {
 	// detects potentially bad nodes.
 	PlanRejectionTracker *PlanRejectionTracker `hcl:"plan_rejection_tracker"`
 
+	// SnapshotAgent configures the leader to periodically save snapshots of
+	// the Raft state to a local directory.
+	SnapshotAgent *SnapshotAgentConfig `hcl:"snapshot_agent"`
+
 	// EnableEventBroker configures whether this server's state store
 	// will generate events for its event stream.
 	EnableEventBroker *bool `hcl:"enable_event_broker"`
@@ -767,11 +781,13 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.RaftMultiplier = pointer.Copy(s.RaftMultiplier)
 	ns.NumSchedulers = pointer.Copy(s.NumSchedulers)
 	ns.EnabledSchedulers = slices.Clone(s.EnabledSchedulers)
+	ns.CanaryAnalysisAddresses = slices.Clone(s.CanaryAnalysisAddresses)
 	ns.StartJoin = slices.Clone(s.StartJoin)
 	ns.RetryJoin = slices.Clone(s.RetryJoin)
 	ns.ServerJoin = s.ServerJoin.Copy()
 	ns.DefaultSchedulerConfig = s.DefaultSchedulerConfig.Copy()
 	ns.PlanRejectionTracker = s.PlanRejectionTracker.Copy()
+	ns.SnapshotAgent = s.SnapshotAgent.Copy()
 	ns.EnableEventBroker = pointer.Copy(s.EnableEventBroker)
 	ns.EventBufferSize = pointer.Copy(s.EventBufferSize)
 	ns.JobMaxSourceSize = pointer.Copy(s.JobMaxSourceSize)
@@ -972,6 +988,82 @@ func (p *PlanRejectionTracker) Merge(b *PlanRejectionTracker) *PlanRejectionTrac
 	return &result
 }
 
+// SnapshotAgentConfig is used in servers to configure the periodic saving
+// of Raft snapshots by the leader.
+type SnapshotAgentConfig struct {
+	// Enabled controls if the leader saves snapshots.
+	Enabled *bool `hcl:"enabled"`
+
+	// Interval is the time between snapshots.
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	// Path is the directory snapshots are written to. Defaults to the
+	// "snapshots" directory in the server's data directory.
+	Path string `hcl:"path"`
+
+	// Retain is the number of snapshots to keep. Zero keeps all snapshots.
+	Retain *int `hcl:"retain"`
+
+	// RetainAge is how long snapshots are kept for. Zero keeps snapshots
+	// regardless of their age.
+	RetainAge    time.Duration
+	RetainAgeHCL string `hcl:"retain_age" json:"-"`
+
+	// Redact removes the key material of the keyring from snapshots before
+	// they are written.
+	Redact *bool `hcl:"redact"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+func (s *SnapshotAgentConfig) Copy() *SnapshotAgentConfig {
+	if s == nil {
+		return nil
+	}
+
+	ns := *s
+	ns.Enabled = pointer.Copy(s.Enabled)
+	ns.Retain = pointer.Copy(s.Retain)
+	ns.Redact = pointer.Copy(s.Redact)
+	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
+	return &ns
+}
+
+func (s *SnapshotAgentConfig) Merge(b *SnapshotAgentConfig) *SnapshotAgentConfig {
+	if s == nil {
+		return b.Copy()
+	}
+
+	result := s.Copy()
+
+	if b == nil {
+		return result
+	}
+
+	result.Enabled = pointer.Merge(s.Enabled, b.Enabled)
+	result.Retain = pointer.Merge(s.Retain, b.Retain)
+	result.Redact = pointer.Merge(s.Redact, b.Redact)
+
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.IntervalHCL != "" {
+		result.IntervalHCL = b.IntervalHCL
+	}
+	if b.Path != "" {
+		result.Path = b.Path
+	}
+	if b.RetainAge != 0 {
+		result.RetainAge = b.RetainAge
+	}
+	if b.RetainAgeHCL != "" {
+		result.RetainAgeHCL = b.RetainAgeHCL
+	}
+	return result
+}
+
 // Search is used in servers to configure search API options.
 type Search struct {
 	// FuzzyEnabled toggles whether the FuzzySearch API is enabled. If not
@@ -1145,6 +1237,22 @@ type Telemetry struct {
 	// metrics.
 	DisableAllocationHookMetrics *bool `hcl:"disable_allocation_hook_metrics"`
 
+	// OTLPTracesEndpoint is the host:port of an OTLP/HTTP collector to export
+	// trace spans to. Tracing is disabled if it is not set.
+	OTLPTracesEndpoint string `hcl:"otlp_traces_endpoint"`
+
+	// OTLPTracesInsecure exports trace spans over plain HTTP instead of HTTPS.
+	OTLPTracesInsecure bool `hcl:"otlp_traces_insecure"`
+
+	// OTLPTracesHeaders are sent with every request exporting trace spans,
+	// and are typically used to authenticate with the collector.
+	OTLPTracesHeaders map[string]string `hcl:"otlp_traces_headers"`
+
+	// TracesSampleRate is the fraction of traces started by this agent which
+	// are sampled, between 0 and 1. Traces started by API clients are sampled
+	// if the client sampled them. Default: 1
+	TracesSampleRate *float64 `hcl:"traces_sample_rate"`
+
 	// Circonus: see https://github.com/circonus-labs/circonus-gometrics
 	// for more details on the various configuration options.
 	// Valid configuration combinations:
@@ -1229,6 +1337,8 @@ func (t *Telemetry) Copy() *Telemetry {
 	nt.DataDogTags = slices.Clone(t.DataDogTags)
 	nt.PrefixFilter = slices.Clone(t.PrefixFilter)
 	nt.FilterDefault = pointer.Copy(t.FilterDefault)
+	nt.OTLPTracesHeaders = maps.Clone(t.OTLPTracesHeaders)
+	nt.TracesSampleRate = pointer.Copy(t.TracesSampleRate)
 	nt.ExtraKeysHCL = slices.Clone(t.ExtraKeysHCL)
 	return &nt
 }
@@ -1272,9 +1382,32 @@ func (t *Telemetry) Validate() error {
 		return errors.New("telemetry in-memory collection interval cannot be greater than retention period")
 	}
 
+	if rate := t.TracesSampleRate; rate != nil && (*rate < 0 || *rate > 1) {
+		return errors.New("telemetry traces sample rate must be between 0 and 1")
+	}
+
 	return nil
 }
 
+// TracingConfig returns the configuration for exporting trace spans, or nil
+// if tracing is disabled.
+func (t *Telemetry) TracingConfig() *tracing.Config {
+	if t == nil || t.OTLPTracesEndpoint == "" {
+		return nil
+	}
+
+	cfg := &tracing.Config{
+		Endpoint:   t.OTLPTracesEndpoint,
+		Insecure:   t.OTLPTracesInsecure,
+		Headers:    t.OTLPTracesHeaders,
+		SampleRate: 1,
+	}
+	if t.TracesSampleRate != nil {
+		cfg.SampleRate = *t.TracesSampleRate
+	}
+	return cfg
+}
+
 // Ports encapsulates the various ports we bind to for network services. If any
 // are not specified then the defaults are used instead.
 type Ports struct {
@@ -1578,6 +1711,12 @@ func DefaultConfig() *Config {
 				NodeThreshold: 100,
 				NodeWindow:    5 * time.Minute,
 			},
+			SnapshotAgent: &SnapshotAgentConfig{
+				Enabled:  pointer.Of(false),
+				Interval: 1 * time.Hour,
+				Retain:   pointer.Of(24),
+				Redact:   pointer.Of(false),
+			},
 			ServerJoin: &ServerJoin{
 				RetryJoin:        []string{},
 				RetryInterval:    30 * time.Second,
@@ -2405,6 +2544,10 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.PlanRejectionTracker = result.PlanRejectionTracker.Merge(b.PlanRejectionTracker)
 	}
 
+	if b.SnapshotAgent != nil {
+		result.SnapshotAgent = result.SnapshotAgent.Merge(b.SnapshotAgent)
+	}
+
 	if b.DefaultSchedulerConfig != nil {
 		c := *b.DefaultSchedulerConfig
 		result.DefaultSchedulerConfig = &c
@@ -2459,6 +2602,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
+	// Add the canary analysis addresses
+	result.CanaryAnalysisAddresses = append(result.CanaryAnalysisAddresses, b.CanaryAnalysisAddresses...)
+
 	// Copy the start join addresses
 	result.StartJoin = make([]string, 0, len(s.StartJoin)+len(b.StartJoin))
 	result.StartJoin = append(result.StartJoin, s.StartJoin...)
@@ -2587,6 +2733,9 @@ func (c *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
 
+	// Add the log sink addresses
+	result.LogSinkAllowlist = append(result.LogSinkAllowlist, b.LogSinkAllowlist...)
+
 	// Add the options map values
 	if result.Options == nil {
 		result.Options = make(map[string]string)
@@ -2784,6 +2933,18 @@ func (t *Telemetry) Merge(b *Telemetry) *Telemetry {
 	if b.DisableAllocationHookMetrics != nil {
 		result.DisableAllocationHookMetrics = b.DisableAllocationHookMetrics
 	}
+	if b.OTLPTracesEndpoint != "" {
+		result.OTLPTracesEndpoint = b.OTLPTracesEndpoint
+	}
+	if b.OTLPTracesInsecure {
+		result.OTLPTracesInsecure = true
+	}
+	if b.OTLPTracesHeaders != nil {
+		result.OTLPTracesHeaders = b.OTLPTracesHeaders
+	}
+	if b.TracesSampleRate != nil {
+		result.TracesSampleRate = b.TracesSampleRate
+	}
 
 	return &result
 }
@@ -56,6 +56,7 @@ func ParseConfigFile(path string) (*Config, error) {
 		Server: &ServerConfig{
 			PlanRejectionTracker: &PlanRejectionTracker{},
 			ServerJoin:           &ServerJoin{},
+			SnapshotAgent:        &SnapshotAgentConfig{},
 		},
 		ACL:       &ACLConfig{},
 		RPC:       &RPCConfig{},
@@ -116,6 +117,8 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"server.failover_heartbeat_ttl", &c.Server.FailoverHeartbeatTTL, &c.Server.FailoverHeartbeatTTLHCL, nil},
 		{"server.plan_rejection_tracker.node_window", &c.Server.PlanRejectionTracker.NodeWindow, &c.Server.PlanRejectionTracker.NodeWindowHCL, nil},
 		{"server.retry_interval", &c.Server.RetryInterval, &c.Server.RetryIntervalHCL, nil},
+		{"server.snapshot_agent.interval", &c.Server.SnapshotAgent.Interval, &c.Server.SnapshotAgent.IntervalHCL, nil},
+		{"server.snapshot_agent.retain_age", &c.Server.SnapshotAgent.RetainAge, &c.Server.SnapshotAgent.RetainAgeHCL, nil},
 		{"server.server_join.retry_interval", &c.Server.ServerJoin.RetryInterval, &c.Server.ServerJoin.RetryIntervalHCL, nil},
 		{"autopilot.server_stabilization_time", &c.Autopilot.ServerStabilizationTime, &c.Autopilot.ServerStabilizationTimeHCL, nil},
 		{"autopilot.last_contact_threshold", &c.Autopilot.LastContactThreshold, &c.Autopilot.LastContactThresholdHCL, nil},
@@ -339,7 +342,7 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
 	}
 
-	for _, k := range []string{"preemption_config"} {
+	for _, k := range []string{"preemption_config", "scoring_weights"} {
 		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
 	}
 
@@ -51,6 +51,10 @@ var basicConfig = &Config{
 		AllocMountsDir: "/tmp/mounts",
 		Servers:        []string{"a.b.c:80", "127.0.0.1:1234"},
 		NodeClass:      "linux-medium-64bit",
+		LogSinkAllowlist: []string{
+			"/run/nomad/logs.sock",
+			"tcp://syslog.example:514",
+		},
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -108,6 +112,7 @@ var basicConfig = &Config{
 		RaftMultiplier:            pointer.Of(4),
 		NumSchedulers:             pointer.Of(2),
 		EnabledSchedulers:         []string{"test"},
+		CanaryAnalysisAddresses:   []string{"http://prometheus.example:9090"},
 		NodeGCThreshold:           "12h",
 		EvalGCThreshold:           "12h",
 		JobGCInterval:             "3m",
@@ -142,6 +147,16 @@ var basicConfig = &Config{
 			NodeWindow:    41 * time.Minute,
 			NodeWindowHCL: "41m",
 		},
+		SnapshotAgent: &SnapshotAgentConfig{
+			Enabled:      pointer.Of(true),
+			Interval:     30 * time.Minute,
+			IntervalHCL:  "30m",
+			Path:         "/opt/nomad/snapshots",
+			Retain:       pointer.Of(12),
+			RetainAge:    72 * time.Hour,
+			RetainAgeHCL: "72h",
+			Redact:       pointer.Of(true),
+		},
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -215,6 +230,10 @@ var basicConfig = &Config{
 		collectionInterval:           3 * time.Second,
 		PublishAllocationMetrics:     true,
 		PublishNodeMetrics:           true,
+		OTLPTracesEndpoint:           "127.0.0.1:4318",
+		OTLPTracesInsecure:           true,
+		OTLPTracesHeaders:            map[string]string{"x-api-key": "secret"},
+		TracesSampleRate:             pointer.Of(0.5),
 	},
 	LeaveOnInt:                true,
 	LeaveOnTerm:               true,
@@ -620,6 +639,9 @@ func (c *Config) addDefaults() {
 	if c.Server.PlanRejectionTracker == nil {
 		c.Server.PlanRejectionTracker = &PlanRejectionTracker{}
 	}
+	if c.Server.SnapshotAgent == nil {
+		c.Server.SnapshotAgent = &SnapshotAgentConfig{}
+	}
 	if c.Reporting == nil {
 		c.Reporting = &config.ReportingConfig{
 			License: &config.LicenseReportingConfig{
@@ -712,6 +734,7 @@ var sample0 = &Config{
 			NodeWindow:    31 * time.Minute,
 			NodeWindowHCL: "31m",
 		},
+		SnapshotAgent: &SnapshotAgentConfig{},
 	},
 	ACL: &ACLConfig{
 		Enabled: true,
@@ -821,6 +844,7 @@ var sample1 = &Config{
 			NodeWindow:    31 * time.Minute,
 			NodeWindowHCL: "31m",
 		},
+		SnapshotAgent: &SnapshotAgentConfig{},
 	},
 	ACL: &ACLConfig{
 		Enabled: true,
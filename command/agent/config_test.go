@@ -19,6 +19,7 @@ import (
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/shoenig/test"
@@ -1411,6 +1412,29 @@ func TestTelemetry_PrefixFilters(t *testing.T) {
 	}
 }
 
+func TestTelemetry_TracingConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, (*Telemetry)(nil).TracingConfig())
+	must.Nil(t, (&Telemetry{}).TracingConfig())
+
+	tel := &Telemetry{OTLPTracesEndpoint: "127.0.0.1:4318"}
+	must.Eq(t, &tracing.Config{
+		Endpoint:   "127.0.0.1:4318",
+		SampleRate: 1,
+	}, tel.TracingConfig())
+
+	tel.OTLPTracesInsecure = true
+	tel.OTLPTracesHeaders = map[string]string{"x-api-key": "secret"}
+	tel.TracesSampleRate = pointer.Of(0.0)
+	must.Eq(t, &tracing.Config{
+		Endpoint:   "127.0.0.1:4318",
+		Insecure:   true,
+		Headers:    map[string]string{"x-api-key": "secret"},
+		SampleRate: 0,
+	}, tel.TracingConfig())
+}
+
 func TestTelemetry_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1454,6 +1478,16 @@ func TestTelemetry_Validate(t *testing.T) {
 			},
 			expectedError: errors.New("telemetry in-memory retention period must be greater than zero"),
 		},
+		{
+			name: "invalid traces sample rate",
+			inputTelemetry: &Telemetry{
+				inMemoryCollectionInterval: 1 * time.Second,
+				inMemoryRetentionPeriod:    10 * time.Second,
+				OTLPTracesEndpoint:         "127.0.0.1:4318",
+				TracesSampleRate:           pointer.Of(1.5),
+			},
+			expectedError: errors.New("telemetry traces sample rate must be between 0 and 1"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -28,6 +28,8 @@ import (
 	"github.com/hashicorp/go-msgpack/v2/codec"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/nomad/acl"
@@ -35,6 +37,7 @@ import (
 	"github.com/hashicorp/nomad/command/agent/event"
 	"github.com/hashicorp/nomad/helper/noxssrw"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -716,6 +719,7 @@ func errCodeFromHandler(err error) (int, string) {
 func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Request) (interface{}, error)) func(resp http.ResponseWriter, req *http.Request) {
 	f := func(resp http.ResponseWriter, req *http.Request) {
 		setHeaders(resp, s.agent.GetConfig().HTTPAPIResponseHeaders)
+		req, span := startHTTPSpan(req)
 		// Invoke the handler
 		reqURL := req.URL.String()
 		start := time.Now()
@@ -723,6 +727,9 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 			s.logger.Debug("request complete", "method", req.Method, "path", reqURL, "duration", time.Since(start))
 		}()
 		obj, err := s.auditHandler(handler)(resp, req)
+		defer func() {
+			tracing.End(span, err)
+		}()
 
 		// Check for an error
 	HAS_ERR:
@@ -799,6 +806,7 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *http.Request) ([]byte, error)) func(resp http.ResponseWriter, req *http.Request) {
 	f := func(resp http.ResponseWriter, req *http.Request) {
 		setHeaders(resp, s.agent.GetConfig().HTTPAPIResponseHeaders)
+		req, span := startHTTPSpan(req)
 		// Invoke the handler
 		reqURL := req.URL.String()
 		start := time.Now()
@@ -806,6 +814,7 @@ func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *htt
 			s.logger.Debug("request complete", "method", req.Method, "path", reqURL, "duration", time.Since(start))
 		}()
 		obj, err := s.auditNonJSONHandler(handler)(resp, req)
+		defer tracing.End(span, err)
 
 		// Check for an error
 		if err != nil {
@@ -828,6 +837,32 @@ func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *htt
 	return f
 }
 
+// startHTTPSpan starts a span for handling the request, which continues any
+// trace whose context was propagated in the request headers. The returned
+// request's context contains the span.
+func startHTTPSpan(req *http.Request) (*http.Request, trace.Span) {
+	name := req.Pattern
+	if name == "" {
+		name = req.URL.Path
+	}
+	ctx := tracing.ExtractHeader(req.Context(), req.Header)
+	ctx, span := tracing.Start(ctx, req.Method+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("user_agent.original", req.UserAgent()),
+		),
+	)
+	return req.WithContext(ctx), span
+}
+
+// parseTraceContext propagates the trace context of the request to the RPC
+// made to handle it.
+func parseTraceContext(req *http.Request, info *structs.InternalRpcInfo) {
+	info.SetTraceContext(tracing.Inject(req.Context()))
+}
+
 // isAPIClientError returns true if the passed http code represents a client error
 func isAPIClientError(code int) bool {
 	return 400 <= code && code <= 499
@@ -1038,6 +1073,7 @@ func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *strin
 	}
 	parseFilter(req, b)
 	parseReverse(req, b)
+	parseTraceContext(req, &b.InternalRpcInfo)
 	return parseWait(resp, req, b)
 }
 
@@ -1115,6 +1151,7 @@ func (s *HTTPServer) parseWriteRequest(req *http.Request, w *structs.WriteReques
 	s.parseToken(req, &w.AuthToken)
 	s.parseRegion(req, &w.Region)
 	parseIdempotencyToken(req, &w.IdempotencyToken)
+	parseTraceContext(req, &w.InternalRpcInfo)
 }
 
 // wrapUntrustedContent wraps handlers in a http.ResponseWriter that prevents
@@ -7,7 +7,18 @@
 package agent
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // registerEnterpriseHandlers is a no-op for the oss release
@@ -30,17 +41,258 @@ func (s *HTTPServer) entOnly(resp http.ResponseWriter, req *http.Request) (inter
 	return nil, CodedError(501, ErrEntOnly)
 }
 
-// auditHandler wraps the passed handlerFn
+// auditHandler wraps the passed handlerFn to write audit events before and
+// after the request is handled
 func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		payload, err := s.auditRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		rw := &auditResponseWriter{ResponseWriter: resp}
+		obj, rspErr := h(rw, req)
+
+		code, errMsg := errCodeFromHandler(rspErr)
+		if rspErr == nil {
+			code = rw.status()
+		}
+		if err := s.auditResponse(req, payload, code, errMsg); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
-// auditHTTPHandler wraps  the passed handlerByteFn
+// auditNonJSONHandler wraps the passed handlerByteFn to write audit events
+// before and after the request is handled
 func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		payload, err := s.auditRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		rw := &auditResponseWriter{ResponseWriter: resp}
+		obj, rspErr := h(rw, req)
+
+		code, errMsg := errCodeFromHandler(rspErr)
+		if rspErr == nil {
+			code = rw.status()
+		}
+		if err := s.auditResponse(req, payload, code, errMsg); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
-// auditHTTPHandler wraps the passed http.Handler
+// auditHTTPHandler wraps the passed http.Handler to write audit events before
+// and after the request is handled. The response has already been written
+// when the second event is, so failing to write it can't fail the request.
 func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
-	return h
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !s.auditEnabled() {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		payload, err := s.auditRequest(req)
+		if err != nil {
+			code, errMsg := errCodeFromHandler(err)
+			resp.Header().Set(contentTypeHeader, plainContentType)
+			resp.WriteHeader(code)
+			resp.Write([]byte(errMsg))
+			return
+		}
+
+		rw := &auditResponseWriter{ResponseWriter: resp}
+		h.ServeHTTP(rw, req)
+
+		if err := s.auditResponse(req, payload, rw.status(), ""); err != nil {
+			s.logger.Error("failed to audit response", "method", req.Method, "path", req.URL.Path, "error", err)
+		}
+	})
+}
+
+// auditEnabled returns true if requests should be audited.
+func (s *HTTPServer) auditEnabled() bool {
+	return s.eventAuditor != nil && s.eventAuditor.Enabled()
+}
+
+// auditRequest writes the audit event for a request that has been received
+// and returns its payload, so that it can be completed once the request has
+// been handled.
+func (s *HTTPServer) auditRequest(req *http.Request) (*auditPayload, error) {
+	body, err := auditRequestBody(req)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+	}
+
+	var namespace string
+	parseNamespace(req, &namespace)
+
+	payload := &auditPayload{
+		ID:        uuid.Generate(),
+		Stage:     auditStageReceived,
+		Type:      auditEventType,
+		Timestamp: time.Now(),
+		Version:   auditVersion,
+		Auth:      s.auditAuth(req),
+		Request: &auditRequest{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  req.URL.RequestURI(),
+			Namespace: auditNamespace{ID: namespace},
+			RequestMeta: auditRequestMeta{
+				RemoteAddress: req.RemoteAddr,
+				UserAgent:     req.UserAgent(),
+			},
+			NodeMeta: auditNodeMeta{IP: s.Addr},
+			Body:     body,
+			path:     req.URL.Path,
+		},
+	}
+
+	if err := s.writeAuditEvent(req, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// auditResponse writes the audit event for a request that has been handled.
+func (s *HTTPServer) auditResponse(req *http.Request, received *auditPayload, code int, errMsg string) error {
+	payload := *received
+	payload.Stage = auditStageComplete
+	payload.Response = &auditResponse{
+		StatusCode: code,
+		Error:      errMsg,
+	}
+	return s.writeAuditEvent(req, &payload)
+}
+
+// writeAuditEvent writes an audit event and returns an error if it fails and
+// delivery is enforced.
+func (s *HTTPServer) writeAuditEvent(req *http.Request, payload *auditPayload) error {
+	err := s.eventAuditor.Event(req.Context(), auditEventType, payload)
+	if err == nil {
+		return nil
+	}
+	if s.eventAuditor.DeliveryEnforced() {
+		s.logger.Error("failed to write audit event", "stage", payload.Stage, "error", err)
+		return CodedError(http.StatusInternalServerError, "failed to write audit event")
+	}
+	s.logger.Warn("failed to write audit event", "stage", payload.Stage, "error", err)
+	return nil
+}
+
+// auditAuth returns the identity of the token used for the request, or nil if
+// ACLs are disabled. Tokens which can't be resolved are recorded as a hash.
+func (s *HTTPServer) auditAuth(req *http.Request) *auditAuth {
+	if aclConf := s.agent.GetConfig().ACL; aclConf == nil || !aclConf.Enabled {
+		return nil
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	var ident *structs.AuthenticatedIdentity
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		ident, err = srv.ResolveIdentity(secret)
+	} else {
+		ident, err = s.agent.Client().ResolveIdentity(secret)
+	}
+	if err != nil || ident == nil {
+		return &auditAuth{TokenHash: hashAuditValue(secret).(string)}
+	}
+	return newAuditAuth(ident)
+}
+
+// auditRequestBody returns the decoded JSON body of a write request with its
+// sensitive fields redacted. The body is restored so that it can still be read
+// by the handler. Bodies which aren't JSON or are too large are not returned.
+func auditRequestBody(req *http.Request) (any, error) {
+	if req.Body == nil || req.Body == http.NoBody ||
+		req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return nil, nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(req.Body, auditMaxBodySize+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 || len(buf) > auditMaxBodySize {
+		return nil, nil
+	}
+
+	var body any
+	if err := json.Unmarshal(buf, &body); err != nil {
+		return nil, nil
+	}
+	return redactAuditBody(body), nil
+}
+
+// auditResponseWriter records the status code written by a handler. It
+// implements http.Hijacker and http.Flusher so that it can wrap websocket and
+// streaming handlers.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code written, which defaults to 200 if the
+// handler didn't write one.
+func (w *auditResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
 }
@@ -81,6 +81,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/periodic/force"):
 		jobID := strings.TrimSuffix(path, "/periodic/force")
 		return s.periodicForceRequest(resp, req, jobID)
+	case strings.HasSuffix(path, "/periodic/launch"):
+		jobID := strings.TrimSuffix(path, "/periodic/launch")
+		return s.periodicLaunchRequest(resp, req, jobID)
 	case strings.HasSuffix(path, "/plan"):
 		jobID := strings.TrimSuffix(path, "/plan")
 		return s.jobPlan(resp, req, jobID)
@@ -257,6 +260,31 @@ func (s *HTTPServer) periodicForceRequest(resp http.ResponseWriter, req *http.Re
 	return out, nil
 }
 
+func (s *HTTPServer) periodicLaunchRequest(resp http.ResponseWriter, req *http.Request,
+	jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.PeriodicLaunchRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.PeriodicLaunchResponse
+	if err := s.agent.RPC("Periodic.GetLaunch", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Launch == nil {
+		return nil, CodedError(404, "periodic launch not found")
+	}
+	return out.Launch, nil
+}
+
 func (s *HTTPServer) jobAllocations(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 	if req.Method != http.MethodGet {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -1175,12 +1203,25 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, len(job.DependsOn))
+		for i, dep := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				JobID:     dep.JobID,
+				Condition: dep.Condition,
+			}
+		}
+	}
+
 	if job.Periodic != nil {
 		j.Periodic = &structs.PeriodicConfig{
-			Enabled:         *job.Periodic.Enabled,
-			SpecType:        *job.Periodic.SpecType,
-			ProhibitOverlap: *job.Periodic.ProhibitOverlap,
-			TimeZone:        *job.Periodic.TimeZone,
+			Enabled:           *job.Periodic.Enabled,
+			SpecType:          *job.Periodic.SpecType,
+			ProhibitOverlap:   *job.Periodic.ProhibitOverlap,
+			TimeZone:          *job.Periodic.TimeZone,
+			CatchUp:           *job.Periodic.CatchUp,
+			StartingDeadline:  *job.Periodic.StartingDeadline,
+			ConcurrencyPolicy: *job.Periodic.ConcurrencyPolicy,
 		}
 
 		if job.Periodic.Spec != nil {
@@ -1194,9 +1235,22 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 
 	if job.ParameterizedJob != nil {
 		j.ParameterizedJob = &structs.ParameterizedJobConfig{
-			Payload:      job.ParameterizedJob.Payload,
-			MetaRequired: job.ParameterizedJob.MetaRequired,
-			MetaOptional: job.ParameterizedJob.MetaOptional,
+			Payload:       job.ParameterizedJob.Payload,
+			MetaRequired:  job.ParameterizedJob.MetaRequired,
+			MetaOptional:  job.ParameterizedJob.MetaOptional,
+			MaxConcurrent: job.ParameterizedJob.MaxConcurrent,
+		}
+	}
+
+	if job.ActiveDeadline != nil {
+		j.ActiveDeadline = *job.ActiveDeadline
+	}
+
+	if job.Rebalance != nil {
+		j.Rebalance = &structs.RebalancePolicy{
+			Threshold:     *job.Rebalance.Threshold,
+			MaxDisruption: *job.Rebalance.MaxDisruption,
+			Interval:      *job.Rebalance.Interval,
 		}
 	}
 
@@ -1234,6 +1288,9 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 	tg.Name = *taskGroup.Name
 	tg.Count = *taskGroup.Count
 	tg.Meta = taskGroup.Meta
+	if taskGroup.Gang != nil {
+		tg.Gang = *taskGroup.Gang
+	}
 	tg.Constraints = ApiConstraintsToStructs(taskGroup.Constraints)
 	tg.Affinities = ApiAffinitiesToStructs(taskGroup.Affinities)
 	tg.Networks = ApiNetworkResourceToStructs(taskGroup.Networks)
@@ -1246,12 +1303,17 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		Delay:           *taskGroup.RestartPolicy.Delay,
 		Mode:            *taskGroup.RestartPolicy.Mode,
 		RenderTemplates: *taskGroup.RestartPolicy.RenderTemplates,
+		ExitRules:       apiExitRulesToStructs(taskGroup.RestartPolicy.ExitRules),
 	}
 
 	if taskGroup.ShutdownDelay != nil {
 		tg.ShutdownDelay = taskGroup.ShutdownDelay
 	}
 
+	if taskGroup.ActiveDeadline != nil {
+		tg.ActiveDeadline = *taskGroup.ActiveDeadline
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1260,6 +1322,7 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 			DelayFunction: *taskGroup.ReschedulePolicy.DelayFunction,
 			MaxDelay:      *taskGroup.ReschedulePolicy.MaxDelay,
 			Unlimited:     *taskGroup.ReschedulePolicy.Unlimited,
+			ExitRules:     apiExitRulesToStructs(taskGroup.ReschedulePolicy.ExitRules),
 		}
 	}
 
@@ -1355,6 +1418,35 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		if taskGroup.Update.AutoPromote != nil {
 			tg.Update.AutoPromote = *taskGroup.Update.AutoPromote
 		}
+
+		if taskGroup.Update.CanaryPercent != nil {
+			tg.Update.CanaryPercent = *taskGroup.Update.CanaryPercent
+		}
+
+		if taskGroup.Update.BlueGreen != nil {
+			tg.Update.BlueGreen = *taskGroup.Update.BlueGreen
+		}
+
+		if taskGroup.Update.MaxSurge != nil {
+			tg.Update.MaxSurge = *taskGroup.Update.MaxSurge
+		}
+
+		if a := taskGroup.Update.Analysis; a != nil {
+			tg.Update.Analysis = &structs.CanaryAnalysis{
+				Address:      a.Address,
+				Interval:     *a.Interval,
+				Runs:         *a.Runs,
+				FailureLimit: *a.FailureLimit,
+			}
+			for _, m := range a.Metrics {
+				tg.Update.Analysis.Metrics = append(tg.Update.Analysis.Metrics, &structs.AnalysisMetric{
+					Name:     m.Name,
+					Query:    m.Query,
+					Max:      pointer.Copy(m.Max),
+					MaxRatio: pointer.Copy(m.MaxRatio),
+				})
+			}
+		}
 	}
 
 	if len(taskGroup.Tasks) > 0 {
@@ -1393,6 +1485,10 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Affinities = ApiAffinitiesToStructs(apiTask.Affinities)
 	structsTask.CSIPluginConfig = ApiCSIPluginConfigToStructsCSIPluginConfig(apiTask.CSIPluginConfig)
 
+	if apiTask.ActiveDeadline != nil {
+		structsTask.ActiveDeadline = *apiTask.ActiveDeadline
+	}
+
 	// Nomad 1.5 CLIs and JSON jobs may set the default identity parameters in
 	// the Task.Identity field, so if it is non-nil use it.
 	if id := apiTask.Identity; id != nil {
@@ -1417,6 +1513,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			Delay:           *apiTask.RestartPolicy.Delay,
 			Mode:            *apiTask.RestartPolicy.Mode,
 			RenderTemplates: *apiTask.RestartPolicy.RenderTemplates,
+			ExitRules:       apiExitRulesToStructs(apiTask.RestartPolicy.ExitRules),
 		}
 	}
 
@@ -2135,6 +2232,21 @@ func apiConnectSidecarTaskToStructs(in *api.SidecarTask) *structs.SidecarTask {
 	}
 }
 
+func apiExitRulesToStructs(in []*api.ExitRule) []*structs.ExitRule {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]*structs.ExitRule, len(in))
+	for i, rule := range in {
+		out[i] = &structs.ExitRule{
+			ExitCodes: slices.Clone(rule.ExitCodes),
+			Signals:   slices.Clone(rule.Signals),
+			Action:    rule.Action,
+		}
+	}
+	return out
+}
+
 func apiVolumeMountsToStructs(in []*api.VolumeMount) []*structs.VolumeMount {
 	if in == nil {
 		return nil
@@ -2179,6 +2291,19 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 		Disabled:      dereferenceBool(in.Disabled),
 		MaxFiles:      dereferenceInt(in.MaxFiles),
 		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+		Sink:          apiLogSinkConfigToStructs(in.Sink),
+	}
+}
+
+func apiLogSinkConfigToStructs(in *api.LogSinkConfig) *structs.LogSinkConfig {
+	if in == nil {
+		return nil
+	}
+
+	return &structs.LogSinkConfig{
+		Type:       in.Type,
+		Address:    in.Address,
+		BufferSize: dereferenceInt(in.BufferSize),
 	}
 }
 
@@ -2283,6 +2408,9 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
 	ret.Weight = *a1.Weight
+	ret.MaxSkew = a1.MaxSkew
+	ret.MinDomains = a1.MinDomains
+	ret.InsufficientDomains = a1.InsufficientDomains
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
@@ -1834,6 +1834,42 @@ func TestHTTP_PeriodicForce(t *testing.T) {
 	})
 }
 
+func TestHTTP_PeriodicLaunch(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create and register a periodic job.
+		job := mock.PeriodicJob()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/periodic/launch", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		launch := obj.(*structs.PeriodicLaunch)
+		must.Eq(t, job.ID, launch.ID)
+		must.Nil(t, launch.Decision)
+
+		// An unknown job is not found
+		req, err = http.NewRequest(http.MethodGet, "/v1/job/unknown/periodic/launch", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "periodic launch not found")
+	})
+}
+
 func TestHTTP_JobPlan(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2692,16 +2728,17 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 	ci.Parallel(t)
 
 	apiJob := &api.Job{
-		Stop:        pointer.Of(true),
-		Region:      pointer.Of("global"),
-		Namespace:   pointer.Of("foo"),
-		ID:          pointer.Of("foo"),
-		ParentID:    pointer.Of("lol"),
-		Name:        pointer.Of("name"),
-		Type:        pointer.Of("service"),
-		Priority:    pointer.Of(50),
-		AllAtOnce:   pointer.Of(true),
-		Datacenters: []string{"dc1", "dc2"},
+		Stop:           pointer.Of(true),
+		Region:         pointer.Of("global"),
+		Namespace:      pointer.Of("foo"),
+		ID:             pointer.Of("foo"),
+		ParentID:       pointer.Of("lol"),
+		Name:           pointer.Of("name"),
+		Type:           pointer.Of("service"),
+		Priority:       pointer.Of(50),
+		AllAtOnce:      pointer.Of(true),
+		Datacenters:    []string{"dc1", "dc2"},
+		ActiveDeadline: pointer.Of(2 * time.Hour),
 		Constraints: []*api.Constraint{
 			{
 				LTarget: "a",
@@ -2739,18 +2776,32 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*api.JobDependency{
+			{
+				JobID:     "migrate",
+				Condition: api.JobDependencyConditionComplete,
+			},
+		},
 		Periodic: &api.PeriodicConfig{
-			Enabled:         pointer.Of(true),
-			Spec:            pointer.Of("spec"),
-			Specs:           []string{"spec"},
-			SpecType:        pointer.Of("cron"),
-			ProhibitOverlap: pointer.Of(true),
-			TimeZone:        pointer.Of("test zone"),
+			Enabled:           pointer.Of(true),
+			Spec:              pointer.Of("spec"),
+			Specs:             []string{"spec"},
+			SpecType:          pointer.Of("cron"),
+			ProhibitOverlap:   pointer.Of(true),
+			TimeZone:          pointer.Of("test zone"),
+			CatchUp:           pointer.Of("latest"),
+			StartingDeadline:  pointer.Of(time.Hour),
+			ConcurrencyPolicy: pointer.Of("forbid"),
 		},
 		ParameterizedJob: &api.ParameterizedJobConfig{
-			Payload:      "payload",
-			MetaRequired: []string{"a", "b"},
-			MetaOptional: []string{"c", "d"},
+			Payload:       "payload",
+			MetaRequired:  []string{"a", "b"},
+			MetaOptional:  []string{"c", "d"},
+			MaxConcurrent: 10,
+		},
+		Rebalance: &api.RebalancePolicy{
+			Threshold:     pointer.Of(0.3),
+			MaxDisruption: pointer.Of(2),
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -2772,8 +2823,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		},
 		TaskGroups: []*api.TaskGroup{
 			{
-				Name:  pointer.Of("group1"),
-				Count: pointer.Of(5),
+				Name:           pointer.Of("group1"),
+				Count:          pointer.Of(5),
+				ActiveDeadline: pointer.Of(time.Hour),
 				Constraints: []*api.Constraint{
 					{
 						LTarget: "x",
@@ -2795,6 +2847,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:           pointer.Of(10 * time.Second),
 					Mode:            pointer.Of("delay"),
 					RenderTemplates: pointer.Of(false),
+					ExitRules: []*api.ExitRule{
+						{ExitCodes: []int{3, 4}, Action: "fail"},
+					},
 				},
 				ReschedulePolicy: &api.ReschedulePolicy{
 					Interval:      pointer.Of(12 * time.Hour),
@@ -2803,6 +2858,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:         pointer.Of(30 * time.Second),
 					Unlimited:     pointer.Of(true),
 					MaxDelay:      pointer.Of(20 * time.Minute),
+					ExitRules: []*api.ExitRule{
+						{Signals: []int{9}, Action: "fail"},
+					},
 				},
 				Migrate: &api.MigrateStrategy{
 					MaxParallel:     pointer.Of(12),
@@ -2958,6 +3016,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							Delay:           pointer.Of(20 * time.Second),
 							Mode:            pointer.Of("delay"),
 							RenderTemplates: pointer.Of(false),
+							ExitRules: []*api.ExitRule{
+								{ExitCodes: []int{75}, Action: "restart"},
+							},
 						},
 						Services: []*api.Service{
 							{
@@ -3063,8 +3124,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: pointer.Of(10 * time.Second),
-						KillSignal:  "SIGQUIT",
+						KillTimeout:    pointer.Of(10 * time.Second),
+						ActiveDeadline: pointer.Of(30 * time.Minute),
+						KillSignal:     "SIGQUIT",
 						LogConfig: &api.LogConfig{
 							Disabled:      pointer.Of(true),
 							MaxFiles:      pointer.Of(10),
@@ -3145,6 +3207,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		AllAtOnce:      true,
 		Datacenters:    []string{"dc1", "dc2"},
 		NodePool:       "",
+		ActiveDeadline: 2 * time.Hour,
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
@@ -3172,22 +3235,37 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*structs.JobDependency{
+			{
+				JobID:     "migrate",
+				Condition: structs.JobDependencyConditionComplete,
+			},
+		},
 		Update: structs.UpdateStrategy{
 			Stagger:     1 * time.Second,
 			MaxParallel: 5,
 		},
 		Periodic: &structs.PeriodicConfig{
-			Enabled:         true,
-			Spec:            "spec",
-			Specs:           []string{"spec"},
-			SpecType:        "cron",
-			ProhibitOverlap: true,
-			TimeZone:        "test zone",
+			Enabled:           true,
+			Spec:              "spec",
+			Specs:             []string{"spec"},
+			SpecType:          "cron",
+			ProhibitOverlap:   true,
+			TimeZone:          "test zone",
+			CatchUp:           "latest",
+			StartingDeadline:  time.Hour,
+			ConcurrencyPolicy: "forbid",
 		},
 		ParameterizedJob: &structs.ParameterizedJobConfig{
-			Payload:      "payload",
-			MetaRequired: []string{"a", "b"},
-			MetaOptional: []string{"c", "d"},
+			Payload:       "payload",
+			MetaRequired:  []string{"a", "b"},
+			MetaOptional:  []string{"c", "d"},
+			MaxConcurrent: 10,
+		},
+		Rebalance: &structs.RebalancePolicy{
+			Threshold:     0.3,
+			MaxDisruption: 2,
+			Interval:      10 * time.Minute,
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -3209,8 +3287,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		},
 		TaskGroups: []*structs.TaskGroup{
 			{
-				Name:  "group1",
-				Count: 5,
+				Name:           "group1",
+				Count:          5,
+				ActiveDeadline: time.Hour,
 				Constraints: []*structs.Constraint{
 					{
 						LTarget: "x",
@@ -3232,6 +3311,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:           10 * time.Second,
 					Mode:            "delay",
 					RenderTemplates: false,
+					ExitRules: []*structs.ExitRule{
+						{ExitCodes: []int{3, 4}, Action: "fail"},
+					},
 				},
 				Spreads: []*structs.Spread{
 					{
@@ -3252,6 +3334,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:         30 * time.Second,
 					Unlimited:     true,
 					MaxDelay:      20 * time.Minute,
+					ExitRules: []*structs.ExitRule{
+						{Signals: []int{9}, Action: "fail"},
+					},
 				},
 				Migrate: &structs.MigrateStrategy{
 					MaxParallel:     12,
@@ -3404,6 +3489,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							Delay:           20 * time.Second,
 							Mode:            "delay",
 							RenderTemplates: false,
+							ExitRules: []*structs.ExitRule{
+								{ExitCodes: []int{75}, Action: "restart"},
+							},
 						},
 						Services: []*structs.Service{
 							{
@@ -3516,8 +3604,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: 10 * time.Second,
-						KillSignal:  "SIGQUIT",
+						KillTimeout:    10 * time.Second,
+						ActiveDeadline: 30 * time.Minute,
+						KillSignal:     "SIGQUIT",
 						LogConfig: &structs.LogConfig{
 							Disabled:      true,
 							MaxFiles:      10,
@@ -3991,6 +4080,24 @@ func TestConversion_apiLogConfigToStructs(t *testing.T) {
 		MaxFileSizeMB: pointer.Of(8),
 	}))
 
+	must.Eq(t, &structs.LogConfig{
+		MaxFiles:      2,
+		MaxFileSizeMB: 8,
+		Sink: &structs.LogSinkConfig{
+			Type:       structs.LogSinkTypeHTTP,
+			Address:    "http://127.0.0.1:8080/logs",
+			BufferSize: 100,
+		},
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:      pointer.Of(2),
+		MaxFileSizeMB: pointer.Of(8),
+		Sink: &api.LogSinkConfig{
+			Type:       api.LogSinkTypeHTTP,
+			Address:    "http://127.0.0.1:8080/logs",
+			BufferSize: pointer.Of(100),
+		},
+	}))
+
 	// COMPAT(1.6.0): verify backwards compatibility fixes
 	// Note: we're intentionally ignoring the Enabled: false case
 	must.Eq(t, &structs.LogConfig{Disabled: false},
@@ -311,6 +311,8 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
 		RejectJobRegistration:         conf.RejectJobRegistration,
 		PauseEvalBroker:               conf.PauseEvalBroker,
+		EvalDequeuePolicy:             structs.EvalDequeuePolicy(conf.EvalDequeuePolicy),
+		NamespaceWeights:              conf.NamespaceWeights,
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
@@ -319,6 +321,15 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 		},
 	}
 
+	if weights := conf.ScoringWeights; weights != nil {
+		args.Config.ScoringWeights = &structs.SchedulerScoringWeights{
+			CPU:       weights.CPU,
+			Memory:    weights.Memory,
+			MemoryMax: weights.MemoryMax,
+			Devices:   weights.Devices,
+		}
+	}
+
 	if err := args.Config.Validate(); err != nil {
 		return nil, CodedError(http.StatusBadRequest, err.Error())
 	}
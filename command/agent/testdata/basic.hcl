@@ -49,6 +49,8 @@ client {
   servers          = ["a.b.c:80", "127.0.0.1:1234"]
   node_class       = "linux-medium-64bit"
 
+  log_sink_allowlist = ["/run/nomad/logs.sock", "tcp://syslog.example:514"]
+
   meta {
     foo = "bar"
     baz = "zip"
@@ -117,6 +119,7 @@ server {
   raft_protocol                 = 3
   num_schedulers                = 2
   enabled_schedulers            = ["test"]
+  canary_analysis_addresses     = ["http://prometheus.example:9090"]
   node_gc_threshold             = "12h"
   job_gc_interval               = "3m"
   job_gc_threshold              = "12h"
@@ -152,6 +155,15 @@ server {
     node_window    = "41m"
   }
 
+  snapshot_agent {
+    enabled    = true
+    interval   = "30m"
+    path       = "/opt/nomad/snapshots"
+    retain     = 12
+    retain_age = "72h"
+    redact     = true
+  }
+
   server_join {
     retry_join     = ["1.1.1.1", "2.2.2.2"]
     retry_max      = 3
@@ -213,6 +225,13 @@ telemetry {
   collection_interval             = "3s"
   publish_allocation_metrics      = true
   publish_node_metrics            = true
+  otlp_traces_endpoint            = "127.0.0.1:4318"
+  otlp_traces_insecure            = true
+  traces_sample_rate              = 0.5
+
+  otlp_traces_headers {
+    x-api-key = "secret"
+  }
 }
 
 leave_on_interrupt = true
@@ -19,6 +19,11 @@ var (
 
 	acquireLockQueryParam = string(structs.VarOpLockAcquire)
 	releaseLockQueryParam = string(structs.VarOpLockRelease)
+
+	// versionsQueryParam lists the previous versions of a variable, and
+	// restoreQueryParam restores the version it is set to.
+	versionsQueryParam = "versions"
+	restoreQueryParam  = "restore"
 )
 
 func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -50,11 +55,28 @@ func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http
 		return nil, CodedError(http.StatusBadRequest, "missing variable path")
 	}
 
+	urlParams := req.URL.Query()
+	if _, ok := urlParams[versionsQueryParam]; ok {
+		if req.Method != http.MethodGet {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.variableHistory(resp, req, path)
+	}
+	if _, ok := urlParams[restoreQueryParam]; ok {
+		if req.Method != http.MethodPut && req.Method != http.MethodPost {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		version, err := strconv.ParseUint(urlParams.Get(restoreQueryParam), 10, 64)
+		if err != nil || version == 0 {
+			return nil, CodedError(http.StatusBadRequest, "restore must be the index of a previous version")
+		}
+		return s.variableRestore(resp, req, path, version)
+	}
+
 	switch req.Method {
 	case http.MethodGet:
 		return s.variableQuery(resp, req, path)
 	case http.MethodPut, http.MethodPost:
-		urlParams := req.URL.Query()
 		lockOperation, err := getLockOperation(urlParams)
 		if err != nil {
 			return nil, CodedError(http.StatusBadRequest, err.Error())
@@ -268,6 +290,55 @@ func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request,
 	return nil, nil
 }
 
+func (s *HTTPServer) variableHistory(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+
+	args := structs.VariablesHistoryRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, CodedError(http.StatusBadRequest, "failed to parse parameters")
+	}
+
+	var out structs.VariablesHistoryResponse
+	if err := s.agent.RPC(structs.VariablesHistoryRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if out.Data == nil {
+		out.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableRestore(resp http.ResponseWriter, req *http.Request,
+	path string, version uint64) (interface{}, error) {
+
+	args := structs.VariablesRestoreRequest{
+		Path:    path,
+		Version: version,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesRestoreRPCMethod, &args, &out); err != nil {
+		setIndex(resp, out.WriteMeta.Index)
+		return nil, err
+	}
+
+	// A locked variable can't be overwritten by a restore
+	if out.Conflict != nil {
+		setIndex(resp, out.Conflict.ModifyIndex)
+		resp.WriteHeader(http.StatusConflict)
+		return out.Conflict, nil
+	}
+
+	setIndex(resp, out.WriteMeta.Index)
+	return out.Output, nil
+}
+
 func parseCAS(req *http.Request) (bool, uint64, error) {
 	if cq := req.URL.Query().Get("cas"); cq != "" {
 		ci, err := strconv.ParseUint(cq, 10, 64)
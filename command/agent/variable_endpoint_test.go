@@ -651,6 +651,83 @@ func TestHTTP_Variables(t *testing.T) {
 	})
 }
 
+func TestHTTP_Variables_Versions(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, cb, func(s *TestAgent) {
+		must.NoError(t, s.Agent.RPC("Namespace.UpsertNamespaces",
+			&structs.NamespaceUpsertRequest{
+				Namespaces: []*structs.Namespace{{
+					Name: "versioned",
+					VariablesConfiguration: &structs.NamespaceVariablesConfiguration{
+						RetainVersions: 5,
+					},
+				}},
+				WriteRequest: structs.WriteRequest{Region: "global"},
+			}, &structs.GenericResponse{}))
+
+		request := func(method, url string, body any) (any, *httptest.ResponseRecorder, error) {
+			var reader io.Reader
+			if body != nil {
+				buf, err := json.Marshal(body)
+				must.NoError(t, err)
+				reader = bytes.NewReader(buf)
+			}
+			req, err := http.NewRequest(method, url, reader)
+			must.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			return obj, respW, err
+		}
+
+		// A variable whose path ends in "versions" is a normal variable
+		sv := mock.Variable()
+		sv.Namespace = "versioned"
+		sv.Path = "app/versions"
+		for _, value := range []string{"v1", "v2"} {
+			sv.Items = structs.VariableItems{"value": value}
+			_, _, err := request(http.MethodPut, "/v1/var/app/versions?namespace=versioned", sv)
+			must.NoError(t, err)
+		}
+
+		obj, _, err := request(http.MethodGet, "/v1/var/app/versions?namespace=versioned", nil)
+		must.NoError(t, err)
+		current, ok := obj.(*structs.VariableDecrypted)
+		must.True(t, ok, must.Sprintf("Expected *structs.VariableDecrypted, got %T", obj))
+		must.Eq(t, "app/versions", current.Path)
+		must.Eq(t, "v2", current.Items["value"])
+
+		// The previous versions are listed with the versions query parameter
+		obj, _, err = request(http.MethodGet, "/v1/var/app/versions?namespace=versioned&versions", nil)
+		must.NoError(t, err)
+		history, ok := obj.([]*structs.VariableMetadata)
+		must.True(t, ok, must.Sprintf("Expected []*structs.VariableMetadata, got %T", obj))
+		must.Len(t, 1, history)
+		must.Eq(t, "app/versions", history[0].Path)
+
+		// and restored with the restore query parameter
+		obj, _, err = request(http.MethodPut, fmt.Sprintf(
+			"/v1/var/app/versions?namespace=versioned&restore=%d", history[0].ModifyIndex), nil)
+		must.NoError(t, err)
+		restored, ok := obj.(*structs.VariableDecrypted)
+		must.True(t, ok, must.Sprintf("Expected *structs.VariableDecrypted, got %T", obj))
+		must.Eq(t, "v1", restored.Items["value"])
+
+		_, _, err = request(http.MethodPut, "/v1/var/app/versions?namespace=versioned&restore=latest", nil)
+		must.ErrorContains(t, err, "restore must be the index of a previous version")
+		_, _, err = request(http.MethodDelete, "/v1/var/app/versions?namespace=versioned&versions", nil)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+
+		// The variable can still be deleted
+		_, respW, err := request(http.MethodDelete, "/v1/var/app/versions?namespace=versioned", nil)
+		must.NoError(t, err)
+		must.Eq(t, http.StatusNoContent, respW.Code)
+		deleted, err := rpcReadSV(s, "versioned", "app/versions")
+		must.NoError(t, err)
+		must.Nil(t, deleted)
+	})
+}
+
 // encodeBrokenReq is a test helper that damages input JSON in order to create
 // a parsing error for testing error pathways.
 func encodeBrokenReq(obj interface{}) io.ReadCloser {
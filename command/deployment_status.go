@@ -557,6 +557,11 @@ func formatDeployment(c *api.Client, d *api.Deployment, uuidLength int) string {
 	}
 	base += "\n\n[bold]Deployed[reset]\n"
 	base += formatDeploymentGroups(d, uuidLength)
+
+	if analysis := formatDeploymentAnalysis(d); analysis != "" {
+		base += "\n\n[bold]Canary Analysis[reset]\n"
+		base += analysis
+	}
 	return base
 }
 
@@ -629,7 +634,7 @@ func formatMultiregionDeployment(regions map[string]*api.Deployment, uuidLength
 
 func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	// Detect if we need to add these columns
-	var canaries, autorevert, progressDeadline bool
+	var canaries, surge, autorevert, progressDeadline bool
 	tgNames := make([]string, 0, len(d.TaskGroups))
 	for name, state := range d.TaskGroups {
 		tgNames = append(tgNames, name)
@@ -639,6 +644,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if state.DesiredCanaries > 0 {
 			canaries = true
 		}
+		if state.DesiredSurge > 0 {
+			surge = true
+		}
 		if state.ProgressDeadline != 0 {
 			progressDeadline = true
 		}
@@ -659,6 +667,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	if canaries {
 		rowString += "Canaries|"
 	}
+	if surge {
+		rowString += "Surge|"
+	}
 	rowString += "Placed|Healthy|Unhealthy"
 	if progressDeadline {
 		rowString += "|Progress Deadline"
@@ -684,6 +695,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if canaries {
 			row += fmt.Sprintf("%d|", state.DesiredCanaries)
 		}
+		if surge {
+			row += fmt.Sprintf("%d|", state.DesiredSurge)
+		}
 		row += fmt.Sprintf("%d|%d|%d", state.PlacedAllocs, state.HealthyAllocs, state.UnhealthyAllocs)
 		if progressDeadline {
 			if state.RequireProgressBy.IsZero() {
@@ -699,6 +713,39 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	return formatList(rows)
 }
 
+// formatDeploymentAnalysis returns the results of the last run of the canary
+// analysis of each task group, or an empty string if no analysis has run.
+func formatDeploymentAnalysis(d *api.Deployment) string {
+	tgNames := make([]string, 0, len(d.TaskGroups))
+	for name, state := range d.TaskGroups {
+		if state.Analysis != nil {
+			tgNames = append(tgNames, name)
+		}
+	}
+	if len(tgNames) == 0 {
+		return ""
+	}
+	sort.Strings(tgNames)
+
+	rows := []string{"Task Group|Passed Runs|Failed Runs|Metric|Canary|Stable|Passed"}
+	for _, tg := range tgNames {
+		analysis := d.TaskGroups[tg].Analysis
+		for _, m := range analysis.Metrics {
+			stable := "N/A"
+			if m.Stable != nil {
+				stable = fmt.Sprintf("%v", *m.Stable)
+			}
+			canary := fmt.Sprintf("%v", m.Canary)
+			if m.Error != "" {
+				canary, stable = m.Error, "N/A"
+			}
+			rows = append(rows, fmt.Sprintf("%s|%d|%d|%s|%s|%s|%v",
+				tg, analysis.PassedRuns, analysis.FailedRuns, m.Name, canary, stable, m.Passed))
+		}
+	}
+	return formatList(rows)
+}
+
 func hasAutoRevert(d *api.Deployment) bool {
 	taskGroups := d.TaskGroups
 	for _, state := range taskGroups {
@@ -215,8 +215,8 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		return 1
 	}
 
-	// See if an evaluation was created. If the job is periodic there will be no
-	// eval.
+	// See if an evaluation was created. If the job is periodic or queued there
+	// will be no eval.
 	evalCreated := resp.EvalID != ""
 
 	basic := []string{
@@ -227,6 +227,12 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	}
 	c.Ui.Output(formatKV(basic))
 
+	// A queued job has no evaluation until the parameterized job is below
+	// its max_concurrent limit.
+	if resp.Queued {
+		c.Ui.Output("\nJob queued until the parameterized job is below its max_concurrent limit")
+	}
+
 	// Nothing to do
 	if detach || !evalCreated {
 		return 0
@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobLogsCommand struct {
+	Meta
+
+	// The fields below represent the commands flags.
+	verbose, tail, stderr, follow bool
+	numLines                      int64
+	numBytes                      int64
+	task, group, filter           string
+}
+
+func (l *JobLogsCommand) Help() string {
+	helpText := `
+Usage: nomad job logs [options] <job>
+
+  Streams the stdout/stderr of every running allocation of the given job. Each
+  line is prefixed with the ID and index of the allocation it was read from.
+
+  When ACLs are enabled, this command requires a token with the 'read-logs',
+  'read-job', and 'list-jobs' capabilities for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Logs Specific Options:
+
+  -stderr
+    Display stderr logs instead of stdout logs.
+
+  -verbose
+    Show full allocation IDs.
+
+  -task <task-name>
+    Sets the task to view the logs of. If no task is given, the logs of every
+    task of the allocations are displayed, prefixed with the task name.
+
+  -group <group-name>
+    Only display the logs of the allocations of the given task group.
+
+  -filter <regex>
+    Only display the lines which match the given regular expression.
+
+  -f
+    Causes the output to not stop when the end of the logs are reached, but
+    rather to wait for additional output. Allocations placed while following
+    are streamed as they start running.
+
+  -tail
+    Show the logs contents with offsets relative to the end of the logs. If no
+    offset is given, -n is defaulted to 10.
+
+  -n
+    Sets the tail location in best-efforted number of lines relative to the end
+    of the logs of each allocation.
+
+  -c
+    Sets the tail location in number of bytes relative to the end of the logs
+    of each allocation.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (l *JobLogsCommand) Synopsis() string {
+	return "Streams the logs of every allocation of a job"
+}
+
+func (l *JobLogsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-stderr":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+			"-task":    complete.PredictAnything,
+			"-group":   complete.PredictAnything,
+			"-filter":  complete.PredictAnything,
+			"-f":       complete.PredictNothing,
+			"-tail":    complete.PredictAnything,
+			"-n":       complete.PredictAnything,
+			"-c":       complete.PredictAnything,
+		})
+}
+
+func (l *JobLogsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := l.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (l *JobLogsCommand) Name() string { return "job logs" }
+
+func (l *JobLogsCommand) Run(args []string) int {
+
+	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
+	flags.Usage = func() { l.Ui.Output(l.Help()) }
+	flags.BoolVar(&l.verbose, "verbose", false, "")
+	flags.BoolVar(&l.tail, "tail", false, "")
+	flags.BoolVar(&l.follow, "f", false, "")
+	flags.BoolVar(&l.stderr, "stderr", false, "")
+	flags.Int64Var(&l.numLines, "n", -1, "")
+	flags.Int64Var(&l.numBytes, "c", -1, "")
+	flags.StringVar(&l.task, "task", "", "")
+	flags.StringVar(&l.group, "group", "", "")
+	flags.StringVar(&l.filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		l.Ui.Error("This command takes one argument: <job>")
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	opts := &api.JobLogsOptions{
+		TaskGroup: l.group,
+		Task:      l.task,
+		LogType:   api.FSLogNameStdout,
+		Follow:    l.follow,
+		Origin:    api.OriginStart,
+	}
+	if l.stderr {
+		opts.LogType = api.FSLogNameStderr
+	}
+
+	if l.filter != "" {
+		filter, err := regexp.Compile(l.filter)
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error parsing filter: %v", err))
+			return 1
+		}
+		opts.Filter = filter
+	}
+
+	if l.tail {
+		offset, err := l.tailOffset()
+		if err != nil {
+			l.Ui.Error(err.Error())
+			return 1
+		}
+		opts.Origin = api.OriginEnd
+		opts.Offset = offset
+	}
+
+	client, err := l.Meta.Client()
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	jobID, ns, err := l.JobIDByPrefix(client, strings.TrimSpace(args[0]), nil)
+	if err != nil {
+		l.Ui.Error(err.Error())
+		return 1
+	}
+
+	if !l.streamLogs(client, jobID, ns, opts) {
+		return 1
+	}
+	return 0
+}
+
+// tailOffset returns the offset relative to the end of the logs to start
+// streaming each allocation from.
+func (l *JobLogsCommand) tailOffset() (int64, error) {
+	nLines, nBytes := l.numLines != -1, l.numBytes != -1
+	switch {
+	case nLines && nBytes:
+		return 0, errors.New("Both -n and -c set")
+	case nLines:
+		return l.numLines * bytesToLines, nil
+	case nBytes:
+		return l.numBytes, nil
+	default:
+		return defaultTailLines * bytesToLines, nil
+	}
+}
+
+// streamLogs outputs the lines of the logs of the job until every allocation
+// has been read, or the user cancels it. Errors streaming a single allocation
+// are reported without ending the streams of the other allocations, and false
+// is returned if any occurred.
+func (l *JobLogsCommand) streamLogs(client *api.Client, jobID, ns string, opts *api.JobLogsOptions) bool {
+
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	q := &api.QueryOptions{Namespace: ns}
+	lines, errCh := client.AllocFS().JobLogs(jobID, opts, cancel, q)
+
+	// Trap user signals, so we know when to exit and cancel the log streams
+	// running in the background.
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if l.verbose {
+		length = fullId
+	}
+
+	ok := true
+	reportErr := func(err error) {
+		l.Ui.Error(fmt.Sprintf("Error streaming logs: %v", err))
+		ok = false
+	}
+
+	for {
+		select {
+		case <-signalCh:
+			return ok
+		case err := <-errCh:
+			reportErr(err)
+		case line, open := <-lines:
+			if !open {
+				// Report an error sent just before the streams ended.
+				select {
+				case err := <-errCh:
+					reportErr(err)
+				default:
+				}
+				return ok
+			}
+			l.Ui.Output(formatJobLogLine(line, length, opts.Task == ""))
+		}
+	}
+}
+
+// formatJobLogLine prefixes the line with the ID and index of its allocation,
+// and the name of its task if the logs of several tasks are displayed.
+func formatJobLogLine(line *api.JobLogLine, length int, withTask bool) string {
+	prefix := limit(line.AllocID, length) + api.AllocSuffix(line.AllocName)
+	if withTask {
+		prefix += " " + line.Task
+	}
+	return fmt.Sprintf("%s: %s", prefix, line.Line)
+}
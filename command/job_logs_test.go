@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+var _ cli.Command = (*JobLogsCommand)(nil)
+
+func TestJobLogsCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobLogsCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid filter
+	code = cmd.Run([]string{"-address=" + url, "-filter=(", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error parsing filter")
+	ui.ErrorWriter.Reset()
+
+	// Fails when both tail offsets are set
+	code = cmd.Run([]string{"-address=" + url, "-tail", "-n=1", "-c=1", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Both -n and -c set")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying job prefix")
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing job
+	code = cmd.Run([]string{"-address=" + url, "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "No job(s) with prefix or ID")
+}
+
+func TestJobLogsCommand_formatJobLogLine(t *testing.T) {
+	ci.Parallel(t)
+
+	line := &api.JobLogLine{
+		AllocID:   "0f3ba2d4-0d3c-8a5b-e0ee-8c4b1e4e12d5",
+		AllocName: "example.web[3]",
+		TaskGroup: "web",
+		Task:      "server",
+		Line:      "listening on :8080",
+	}
+
+	must.Eq(t, "0f3ba2d4[3]: listening on :8080", formatJobLogLine(line, shortId, false))
+	must.Eq(t, "0f3ba2d4[3] server: listening on :8080", formatJobLogLine(line, shortId, true))
+	must.Eq(t, "0f3ba2d4-0d3c-8a5b-e0ee-8c4b1e4e12d5[3]: listening on :8080",
+		formatJobLogLine(line, fullId, false))
+}
@@ -271,6 +271,9 @@ func (c *JobStatusCommand) Run(args []string) int {
 	}
 
 	if periodic && !parameterized {
+		if job.Periodic.ConcurrencyPolicy != nil {
+			basic = append(basic, fmt.Sprintf("Concurrency Policy|%s", *job.Periodic.ConcurrencyPolicy))
+		}
 		if *job.Stop {
 			basic = append(basic, "Next Periodic Launch|none (job stopped)")
 		} else {
@@ -346,6 +349,21 @@ func (c *JobStatusCommand) outputPeriodicInfo(client *api.Client, job *api.Job)
 		return err
 	}
 
+	// Output the last decision of the concurrency policy
+	launch, _, err := client.Jobs().PeriodicLaunch(*job.ID, &api.QueryOptions{Namespace: *job.Namespace})
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("Error querying periodic launch: %s", err)
+	}
+	if launch != nil && launch.Decision != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Last Concurrency Decision[reset]"))
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Decision|%s", launch.Decision.Decision),
+			fmt.Sprintf("Launch Time|%s", formatTime(launch.Decision.Launch)),
+			fmt.Sprintf("Decision Time|%s", formatTime(launch.Decision.Time)),
+			fmt.Sprintf("Description|%s", launch.Decision.Description),
+		}))
+	}
+
 	// Generate the prefix that matches launched jobs from the periodic job.
 	prefix := fmt.Sprintf("%s%s", *job.ID, api.JobPeriodicLaunchSuffix)
 	children, _, err := client.Jobs().PrefixList(prefix)
@@ -386,6 +404,9 @@ func (c *JobStatusCommand) outputParameterizedInfo(client *api.Client, job *api.
 	parameterizedJob[0] = fmt.Sprintf("Payload|%s", job.ParameterizedJob.Payload)
 	parameterizedJob[1] = fmt.Sprintf("Required Metadata|%v", strings.Join(job.ParameterizedJob.MetaRequired, ", "))
 	parameterizedJob[2] = fmt.Sprintf("Optional Metadata|%v", strings.Join(job.ParameterizedJob.MetaOptional, ", "))
+	if job.ParameterizedJob.MaxConcurrent > 0 {
+		parameterizedJob = append(parameterizedJob, fmt.Sprintf("Max Concurrent|%d", job.ParameterizedJob.MaxConcurrent))
+	}
 	c.Ui.Output(formatKV(parameterizedJob))
 
 	// Output the summary
@@ -472,6 +493,10 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	var latestFailedPlacement *api.Evaluation
 	blockedEval := false
 
+	// Determine the blocked evaluation waiting on the dependencies of the job,
+	// if any
+	var waitingEval *api.Evaluation
+
 	// Format the evals
 	evals := make([]string, len(jobEvals)+1)
 	evals[0] = "ID|Priority|Triggered By|Status|Placement Failures"
@@ -487,6 +512,11 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 
 		if eval.Status == "blocked" {
 			blockedEval = true
+
+			if len(eval.UnmetDependencies) != 0 &&
+				(waitingEval == nil || waitingEval.CreateIndex < eval.CreateIndex) {
+				waitingEval = eval
+			}
 		}
 
 		if len(eval.FailedTGAllocs) == 0 {
@@ -504,6 +534,10 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		c.Ui.Output(formatList(evals))
 	}
 
+	if waitingEval != nil {
+		c.outputUnmetDependencies(waitingEval)
+	}
+
 	if blockedEval && latestFailedPlacement != nil {
 		c.outputFailedPlacements(latestFailedPlacement)
 	}
@@ -707,9 +741,10 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 			c.Ui.Output(c.Colorize().Color("\n[bold]Children Job Summary[reset]"))
 		}
 		summaries := make([]string, 2)
-		summaries[0] = "Pending|Running|Dead"
-		summaries[1] = fmt.Sprintf("%d|%d|%d",
-			summary.Children.Pending, summary.Children.Running, summary.Children.Dead)
+		summaries[0] = "Queued|Pending|Running|Dead"
+		summaries[1] = fmt.Sprintf("%d|%d|%d|%d",
+			summary.Children.Queued, summary.Children.Pending,
+			summary.Children.Running, summary.Children.Dead)
 		c.Ui.Output(formatList(summaries))
 	}
 
@@ -782,6 +817,19 @@ func (c *JobStatusCommand) outputReschedulingEvals(client *api.Client, job *api.
 	return nil
 }
 
+// outputUnmetDependencies outputs the dependencies of the job which the rollout
+// of the job is waiting on.
+func (c *JobStatusCommand) outputUnmetDependencies(waitingEval *api.Evaluation) {
+	deps := make([]string, len(waitingEval.UnmetDependencies)+1)
+	deps[0] = "Job ID|Condition"
+	for i, dep := range waitingEval.UnmetDependencies {
+		deps[i+1] = fmt.Sprintf("%s|%s", dep.JobID, dep.Condition)
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Waiting On Dependencies[reset]"))
+	c.Ui.Output(formatList(deps))
+}
+
 func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 	if failedEval == nil || len(failedEval.FailedTGAllocs) == 0 {
 		return
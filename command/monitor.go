@@ -359,8 +359,16 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 		out += fmt.Sprintf("%s* Quota limit hit %q\n", prefix, dim)
 	}
 
+	// Print gang info
+	for _, tg := range metrics.GangFailed {
+		out += fmt.Sprintf("%s* Placement rolled back: task group %q of the same gang could not be placed\n", prefix, tg)
+	}
+
 	// Print scores
 	if scores {
+		if metrics.ScoringWeights != nil {
+			out += fmt.Sprintf("%s* Scoring weights: %s\n", prefix, formatScoringWeights(metrics.ScoringWeights))
+		}
 		if len(metrics.ScoreMetaData) > 0 {
 			scoreOutput := make([]string, len(metrics.ScoreMetaData)+1)
 
@@ -97,6 +97,13 @@ consul {
   allowed = ["prod", "apps*"]
 }
 
+variables {
+  retain_versions = 3
+  path_retain_versions = {
+    "nomad/jobs" = 0
+  }
+}
+
 meta {
   dept = "eng"
 }`,
@@ -120,6 +127,12 @@ meta {
 					Default: "prod",
 					Allowed: []string{"prod", "apps*"},
 				},
+				VariablesConfiguration: &api.NamespaceVariablesConfiguration{
+					RetainVersions: 3,
+					PathRetainVersions: map[string]int{
+						"nomad/jobs": 0,
+					},
+				},
 				Meta: map[string]string{
 					"dept": "eng",
 				},
@@ -5,6 +5,8 @@ package command
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 
@@ -194,6 +196,20 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(cConfigOut))
 	}
 
+	if ns.VariablesConfiguration != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Variables Configuration[reset]"))
+		varConfig := ns.VariablesConfiguration
+		varConfigOut := []string{
+			fmt.Sprintf("Retain Versions|%d", varConfig.RetainVersions),
+		}
+		prefixes := slices.Sorted(maps.Keys(varConfig.PathRetainVersions))
+		for _, prefix := range prefixes {
+			varConfigOut = append(varConfigOut, fmt.Sprintf("Retain Versions (%s)|%d",
+				prefix, varConfig.PathRetainVersions[prefix]))
+		}
+		c.Ui.Output(formatKV(varConfigOut))
+	}
+
 	return 0
 }
 
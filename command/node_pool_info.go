@@ -139,6 +139,11 @@ func (c *NodePoolInfoCommand) Run(args []string) int {
 				fmt.Sprintf("Memory Oversubscription Enabled|%v", *schedConfig.MemoryOversubscriptionEnabled),
 			)
 		}
+		if schedConfig.ScoringWeights != nil {
+			schedConfigOut = append(schedConfigOut,
+				fmt.Sprintf("Scoring Weights|%s", formatScoringWeights(schedConfig.ScoringWeights)),
+			)
+		}
 		c.Ui.Output(formatKV(schedConfigOut))
 	} else {
 		c.Ui.Output("No scheduler configuration")
@@ -31,6 +31,10 @@ Usage: nomad operator scheduler <subcommand> [options]
 
       $ nomad operator scheduler set-config -scheduler-algorithm=spread
 
+  Simulate registering a job against the state of a snapshot:
+
+      $ nomad operator scheduler simulate backup.snap example.nomad.hcl
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
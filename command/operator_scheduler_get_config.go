@@ -5,9 +5,12 @@ package command
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
 )
 
@@ -79,9 +82,12 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 	// Output the information.
 	o.Ui.Output(formatKV([]string{
 		fmt.Sprintf("Scheduler Algorithm|%s", schedConfig.SchedulerAlgorithm),
+		fmt.Sprintf("Scoring Weights|%s", formatScoringWeights(schedConfig.ScoringWeights)),
 		fmt.Sprintf("Memory Oversubscription|%v", schedConfig.MemoryOversubscriptionEnabled),
 		fmt.Sprintf("Reject Job Registration|%v", schedConfig.RejectJobRegistration),
 		fmt.Sprintf("Pause Eval Broker|%v", schedConfig.PauseEvalBroker),
+		fmt.Sprintf("Eval Dequeue Policy|%s", formatEvalDequeuePolicy(schedConfig.EvalDequeuePolicy)),
+		fmt.Sprintf("Namespace Weights|%s", formatNamespaceWeights(schedConfig.NamespaceWeights)),
 		fmt.Sprintf("Preemption System Scheduler|%v", schedConfig.PreemptionConfig.SystemSchedulerEnabled),
 		fmt.Sprintf("Preemption Service Scheduler|%v", schedConfig.PreemptionConfig.ServiceSchedulerEnabled),
 		fmt.Sprintf("Preemption Batch Scheduler|%v", schedConfig.PreemptionConfig.BatchSchedulerEnabled),
@@ -91,6 +97,38 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 	return 0
 }
 
+// formatScoringWeights formats the scoring weights in the form accepted by the
+// set-config command.
+func formatScoringWeights(weights *api.SchedulerScoringWeights) string {
+	if weights == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("cpu=%g,memory=%g,memory_max=%g,devices=%g",
+		weights.CPU, weights.Memory, weights.MemoryMax, weights.Devices)
+}
+
+// formatEvalDequeuePolicy returns the eval dequeue policy, which is the
+// priority policy when unset.
+func formatEvalDequeuePolicy(policy api.EvalDequeuePolicy) api.EvalDequeuePolicy {
+	if policy == "" {
+		return api.EvalDequeuePolicyPriority
+	}
+	return policy
+}
+
+// formatNamespaceWeights formats the namespace weights in the form accepted by
+// the set-config command.
+func formatNamespaceWeights(weights map[string]int) string {
+	if len(weights) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(weights))
+	for _, namespace := range slices.Sorted(maps.Keys(weights)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", namespace, weights[namespace]))
+	}
+	return strings.Join(pairs, ",")
+}
+
 func (o *OperatorSchedulerGetConfig) Synopsis() string {
 	return "Display the current scheduler configuration"
 }
@@ -5,6 +5,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/cli"
@@ -24,6 +25,9 @@ type OperatorSchedulerSetConfig struct {
 	// with user supplied, selective updates.
 	checkIndex               string
 	schedulerAlgorithm       string
+	scoringWeights           string
+	evalDequeuePolicy        string
+	namespaceWeights         string
 	memoryOversubscription   flagHelper.BoolValue
 	rejectJobRegistration    flagHelper.BoolValue
 	pauseEvalBroker          flagHelper.BoolValue
@@ -40,7 +44,14 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 			"-scheduler-algorithm": complete.PredictSet(
 				string(api.SchedulerAlgorithmBinpack),
 				string(api.SchedulerAlgorithmSpread),
+				string(api.SchedulerAlgorithmWeighted),
 			),
+			"-scoring-weights": complete.PredictAnything,
+			"-eval-dequeue-policy": complete.PredictSet(
+				string(api.EvalDequeuePolicyPriority),
+				string(api.EvalDequeuePolicyFairShare),
+			),
+			"-namespace-weights":          complete.PredictAnything,
 			"-memory-oversubscription":    complete.PredictSet("true", "false"),
 			"-reject-job-registration":    complete.PredictSet("true", "false"),
 			"-pause-eval-broker":          complete.PredictSet("true", "false"),
@@ -65,6 +76,9 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 
 	flags.StringVar(&o.checkIndex, "check-index", "", "")
 	flags.StringVar(&o.schedulerAlgorithm, "scheduler-algorithm", "", "")
+	flags.StringVar(&o.scoringWeights, "scoring-weights", "", "")
+	flags.StringVar(&o.evalDequeuePolicy, "eval-dequeue-policy", "", "")
+	flags.StringVar(&o.namespaceWeights, "namespace-weights", "", "")
 	flags.Var(&o.memoryOversubscription, "memory-oversubscription", "")
 	flags.Var(&o.rejectJobRegistration, "reject-job-registration", "")
 	flags.Var(&o.pauseEvalBroker, "pause-eval-broker", "")
@@ -77,6 +91,27 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 		return 1
 	}
 
+	// Parse the scoring and namespace weights before making any requests, so
+	// mistakes are reported early.
+	var scoringWeights *api.SchedulerScoringWeights
+	if o.scoringWeights != "" {
+		weights, err := parseScoringWeights(o.scoringWeights)
+		if err != nil {
+			o.Ui.Error(fmt.Sprintf("Error parsing scoring-weights value %q: %v", o.scoringWeights, err))
+			return 1
+		}
+		scoringWeights = weights
+	}
+	var namespaceWeights map[string]int
+	if o.namespaceWeights != "" {
+		weights, err := parseNamespaceWeights(o.namespaceWeights)
+		if err != nil {
+			o.Ui.Error(fmt.Sprintf("Error parsing namespace-weights value %q: %v", o.namespaceWeights, err))
+			return 1
+		}
+		namespaceWeights = weights
+	}
+
 	// Set up a client.
 	client, err := o.Meta.Client()
 	if err != nil {
@@ -127,6 +162,15 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	if o.schedulerAlgorithm != "" {
 		schedulerConfig.SchedulerAlgorithm = api.SchedulerAlgorithm(o.schedulerAlgorithm)
 	}
+	if scoringWeights != nil {
+		schedulerConfig.ScoringWeights = scoringWeights
+	}
+	if o.evalDequeuePolicy != "" {
+		schedulerConfig.EvalDequeuePolicy = api.EvalDequeuePolicy(o.evalDequeuePolicy)
+	}
+	if namespaceWeights != nil {
+		schedulerConfig.NamespaceWeights = namespaceWeights
+	}
 	o.memoryOversubscription.Merge(&schedulerConfig.MemoryOversubscriptionEnabled)
 	o.rejectJobRegistration.Merge(&schedulerConfig.RejectJobRegistration)
 	o.pauseEvalBroker.Merge(&schedulerConfig.PauseEvalBroker)
@@ -149,6 +193,53 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	return 1
 }
 
+// parseScoringWeights parses scoring weights in the form "cpu=1,memory=3".
+func parseScoringWeights(s string) (*api.SchedulerScoringWeights, error) {
+	weights := &api.SchedulerScoringWeights{}
+	for _, pair := range strings.Split(s, ",") {
+		resource, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected resource=weight, got %q", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %q: %v", resource, err)
+		}
+
+		switch resource {
+		case "cpu":
+			weights.CPU = weight
+		case "memory":
+			weights.Memory = weight
+		case "memory_max":
+			weights.MemoryMax = weight
+		case "devices":
+			weights.Devices = weight
+		default:
+			return nil, fmt.Errorf("unknown resource %q", resource)
+		}
+	}
+	return weights, nil
+}
+
+// parseNamespaceWeights parses namespace weights in the form
+// "default=1,prod=3".
+func parseNamespaceWeights(s string) (map[string]int, error) {
+	weights := map[string]int{}
+	for _, pair := range strings.Split(s, ",") {
+		namespace, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("expected namespace=weight, got %q", pair)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %q: %v", namespace, err)
+		}
+		weights[namespace] = weight
+	}
+	return weights, nil
+}
+
 func (o *OperatorSchedulerSetConfig) Synopsis() string {
 	return "Modify the current scheduler configuration"
 }
@@ -173,9 +264,30 @@ Scheduler Set Config Options:
     matches the current server side version. If a non-zero value is passed, it
     ensures that the scheduler config is being updated from a known state.
 
-  -scheduler-algorithm=["binpack"|"spread"]
+  -scheduler-algorithm=["binpack"|"spread"|"weighted"]
     Specifies whether scheduler binpacks or spreads allocations on available
-    nodes.
+    nodes, or places them on the nodes with the least requested resources
+    according to the scoring weights.
+
+  -scoring-weights=<weights>
+    Specifies the weight of each resource when using the weighted scheduler
+    algorithm, as a comma separated list of resource=weight pairs. The
+    resources are "cpu", "memory", "memory_max" and "devices", and resources
+    which aren't listed have a weight of zero. For example,
+    "cpu=1,memory=3".
+
+  -eval-dequeue-policy=["priority"|"fair_share"]
+    Specifies the order in which the leader dequeues evaluations. The
+    "priority" policy dequeues evaluations with the highest priority first.
+    The "fair_share" policy takes turns dequeuing the evaluations of each
+    namespace, so a namespace with many queued evaluations doesn't delay the
+    evaluations of other namespaces.
+
+  -namespace-weights=<weights>
+    Specifies the number of evaluations of each namespace dequeued in a row
+    when using the fair_share eval dequeue policy, as a comma separated list
+    of namespace=weight pairs. Namespaces which aren't listed have a weight of
+    1. For example, "default=1,prod=3".
 
   -memory-oversubscription=[true|false]
     When true, tasks may exceed their reserved memory limit, if the client has
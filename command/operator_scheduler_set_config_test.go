@@ -43,7 +43,10 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 	// object via the CLI.
 	modifyingArgs := []string{
 		"-address=" + addr,
-		"-scheduler-algorithm=spread",
+		"-scheduler-algorithm=weighted",
+		"-scoring-weights=cpu=1,memory=3",
+		"-eval-dequeue-policy=fair_share",
+		"-namespace-weights=default=1,prod=3",
 		"-pause-eval-broker=true",
 		"-memory-oversubscription=true",
 		"-reject-job-registration=true",
@@ -59,7 +62,13 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 	modifiedConfig, _, err := srv.APIClient().Operator().SchedulerGetConfiguration(nil)
 	must.NoError(t, err)
 	schedulerConfigEquals(t, &api.SchedulerConfiguration{
-		SchedulerAlgorithm: "spread",
+		SchedulerAlgorithm: "weighted",
+		ScoringWeights: &api.SchedulerScoringWeights{
+			CPU:    1,
+			Memory: 3,
+		},
+		EvalDequeuePolicy: "fair_share",
+		NamespaceWeights:  map[string]int{"default": 1, "prod": 3},
 		PreemptionConfig: api.PreemptionConfig{
 			SystemSchedulerEnabled:   false,
 			SysBatchSchedulerEnabled: true,
@@ -81,6 +90,18 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 	ui.ErrorWriter.Reset()
 	ui.OutputWriter.Reset()
 
+	// Scoring weights for unknown resources are rejected.
+	must.One(t, c.Run([]string{"-address=" + addr, "-scoring-weights=disk=1"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `unknown resource "disk"`)
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Namespace weights must be integers.
+	must.One(t, c.Run([]string{"-address=" + addr, "-namespace-weights=prod=high"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `invalid weight for "prod"`)
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
 	// Try updating the config using an incorrect check-index value.
 	must.One(t, c.Run([]string{
 		"-address=" + addr,
@@ -104,8 +125,11 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 
 func schedulerConfigEquals(t *testing.T, expected, actual *api.SchedulerConfiguration) {
 	must.Eq(t, expected.SchedulerAlgorithm, actual.SchedulerAlgorithm)
+	must.Eq(t, expected.ScoringWeights, actual.ScoringWeights)
 	must.Eq(t, expected.RejectJobRegistration, actual.RejectJobRegistration)
 	must.Eq(t, expected.MemoryOversubscriptionEnabled, actual.MemoryOversubscriptionEnabled)
 	must.Eq(t, expected.PauseEvalBroker, actual.PauseEvalBroker)
+	must.Eq(t, expected.EvalDequeuePolicy, actual.EvalDequeuePolicy)
+	must.MapEq(t, expected.NamespaceWeights, actual.NamespaceWeights)
 	must.Eq(t, expected.PreemptionConfig, actual.PreemptionConfig)
 }
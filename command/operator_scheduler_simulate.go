@@ -0,0 +1,370 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler/simulator"
+	"github.com/posener/complete"
+)
+
+// Ensure OperatorSchedulerSimulateCommand satisfies the cli.Command interface.
+var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+// simulatedChange is the outcome of one of the changes of a simulation.
+type simulatedChange struct {
+	Description string
+	Result      *simulator.Result
+}
+
+// simulatedNodeUtilization is the allocated resources of a node at the end of
+// a simulation.
+type simulatedNodeUtilization struct {
+	NodeID            string
+	NodeName          string
+	NodePool          string
+	NodeClass         string
+	Allocs            int
+	CPUUsed           int64
+	CPUAvailable      int64
+	MemoryMBUsed      int64
+	MemoryMBAvailable int64
+}
+
+type simulationOutput struct {
+	Changes     []*simulatedChange
+	Utilization []*simulatedNodeUtilization
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] <snapshot> [<job>...]
+
+  Runs the scheduler against the state of a snapshot, such as one saved with
+  "nomad operator snapshot save", to show how the cluster would respond to
+  proposed node changes and jobs. The simulation runs entirely in memory and
+  never contacts a Nomad cluster.
+
+  Node changes are applied first, removals before additions, followed by the
+  given job files in order. Each change sees the placements of the changes
+  before it. Only the first evaluation of each change is run, so deployments
+  only place their first batch of updates.
+
+  Simulate adding 10 nodes of the "large" class and registering a job:
+
+      $ nomad operator scheduler simulate -add-nodes=large:10 backup.snap example.nomad.hcl
+
+  Simulate removing the "spot" node pool:
+
+      $ nomad operator scheduler simulate -remove-node-pool=spot backup.snap
+
+Simulate Options:
+
+  -add-nodes <class>:<count>
+    Adds count ready nodes copied from an existing node of the node class.
+    System jobs and jobs with blocked evaluations are evaluated for the new
+    nodes. May be specified multiple times.
+
+  -remove-node-pool <pool>
+    Marks every node of the node pool as down. The jobs with allocations on
+    them are evaluated to replace the lost allocations. May be specified
+    multiple times.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+
+  -json
+    Output the results of the simulation in JSON format.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulate scheduling against a snapshot"
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-add-nodes":        complete.PredictAnything,
+		"-remove-node-pool": complete.PredictAnything,
+		"-var":              complete.PredictAnything,
+		"-var-file":         complete.PredictFiles("*.var"),
+		"-json":             complete.PredictNothing,
+		"-verbose":          complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.snap"),
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+	)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var addNodes, removePools flaghelper.StringFlag
+	var jsonOutput, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var(&addNodes, "add-nodes", "")
+	flags.Var(&removePools, "remove-node-pool", "")
+	flags.Var(&c.JobGetter.Vars, "var", "")
+	flags.Var(&c.JobGetter.VarFiles, "var-file", "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) < 1 {
+		c.Ui.Error("This command takes at least one argument: <snapshot> [<job>...]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	additions := make([]nodeAddition, 0, len(addNodes))
+	for _, raw := range addNodes {
+		addition, err := parseNodeAddition(raw)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid -add-nodes value: %v", err))
+			return 1
+		}
+		additions = append(additions, addition)
+	}
+
+	// Parse every job before loading the snapshot to report errors early.
+	jobs := make([]*structs.Job, 0, len(args)-1)
+	for _, path := range args[1:] {
+		_, job, err := c.JobGetter.Get(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct from %s: %s", path, err))
+			return 1
+		}
+		job.Canonicalize()
+		jobs = append(jobs, agent.ApiJobToStructJob(job))
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	_, state, _, err := raftutil.RestoreFromArchive(f, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
+		return 1
+	}
+
+	sim, err := simulator.NewSimulator(hclog.NewNullLogger(), state)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting simulation: %s", err))
+		return 1
+	}
+
+	out := &simulationOutput{}
+	simulate := func(description string, fn func() (*simulator.Result, error)) bool {
+		result, err := fn()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error simulating %s: %s", description, err))
+			return false
+		}
+		out.Changes = append(out.Changes, &simulatedChange{Description: description, Result: result})
+		return true
+	}
+
+	for _, pool := range removePools {
+		if !simulate(fmt.Sprintf("removing node pool %q", pool), func() (*simulator.Result, error) {
+			return sim.RemoveNodePool(pool)
+		}) {
+			return 1
+		}
+	}
+	for _, a := range additions {
+		if !simulate(fmt.Sprintf("adding %d nodes of class %q", a.count, a.class), func() (*simulator.Result, error) {
+			return sim.AddNodes(a.class, a.count)
+		}) {
+			return 1
+		}
+	}
+	for _, job := range jobs {
+		if !simulate(fmt.Sprintf("registering job %q", job.ID), func() (*simulator.Result, error) {
+			return sim.RegisterJob(job)
+		}) {
+			return 1
+		}
+	}
+
+	utilization, err := sim.Utilization()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error computing node utilization: %s", err))
+		return 1
+	}
+	for _, u := range utilization {
+		out.Utilization = append(out.Utilization, &simulatedNodeUtilization{
+			NodeID:            u.Node.ID,
+			NodeName:          u.Node.Name,
+			NodePool:          u.Node.NodePool,
+			NodeClass:         u.Node.NodeClass,
+			Allocs:            u.Allocs,
+			CPUUsed:           u.Used.Flattened.Cpu.CpuShares,
+			CPUAvailable:      u.Available.Flattened.Cpu.CpuShares,
+			MemoryMBUsed:      u.Used.Flattened.Memory.MemoryMB,
+			MemoryMBAvailable: u.Available.Flattened.Memory.MemoryMB,
+		})
+	}
+
+	if jsonOutput {
+		formatted, err := Format(true, "", out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+	for _, change := range out.Changes {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]==> Result of %s[reset]", change.Description)))
+		c.Ui.Output(c.Colorize().Color(formatSimulationResult(change.Result, length)))
+		c.Ui.Output("")
+	}
+	c.Ui.Output(c.Colorize().Color("[bold]==> Node Utilization[reset]"))
+	c.Ui.Output(formatSimulatedUtilization(out.Utilization, length))
+	return 0
+}
+
+// nodeAddition is a parsed -add-nodes flag.
+type nodeAddition struct {
+	class string
+	count int
+}
+
+// parseNodeAddition parses a value of the form <class>:<count>.
+func parseNodeAddition(s string) (nodeAddition, error) {
+	class, rawCount, ok := strings.Cut(s, ":")
+	if !ok || class == "" {
+		return nodeAddition{}, fmt.Errorf("%q must be of the form <class>:<count>", s)
+	}
+	count, err := strconv.Atoi(rawCount)
+	if err != nil || count < 1 {
+		return nodeAddition{}, fmt.Errorf("count of %q must be a positive integer", s)
+	}
+	return nodeAddition{class: class, count: count}, nil
+}
+
+// formatSimulationResult formats the placements, stopped and preempted
+// allocations and failed placements of a simulated change.
+func formatSimulationResult(result *simulator.Result, length int) string {
+	if len(result.Placed)+len(result.Stopped)+len(result.Preempted)+len(result.Failures) == 0 {
+		return "No changes"
+	}
+
+	var sections []string
+	allocs := func(title string, allocs []*structs.Allocation, description bool) {
+		if len(allocs) == 0 {
+			return
+		}
+		header := "ID|Job ID|Task Group|Node ID|Node Name"
+		if description {
+			header += "|Description"
+		}
+		rows := []string{header}
+		for _, alloc := range allocs {
+			row := fmt.Sprintf("%s|%s|%s|%s|%s",
+				limit(alloc.ID, length), alloc.JobID, alloc.TaskGroup,
+				limit(alloc.NodeID, length), alloc.NodeName)
+			if description {
+				row += "|" + alloc.DesiredDescription
+			}
+			rows = append(rows, row)
+		}
+		sections = append(sections, fmt.Sprintf("[bold]%s[reset]\n%s", title, formatList(rows)))
+	}
+	allocs("Placements", result.Placed, false)
+	allocs("Stopped", result.Stopped, true)
+	allocs("Preempted", result.Preempted, false)
+
+	if len(result.Failures) > 0 {
+		out := "[bold]Failed Placements[reset]\n"
+		for _, failure := range result.Failures {
+			metrics := apiAllocMetric(failure.Metrics)
+			noun := "allocation"
+			if metrics.CoalescedFailures > 0 {
+				noun += "s"
+			}
+			out += fmt.Sprintf("[yellow]Task Group %q of job %q (failed to place %d %s):\n[reset]",
+				failure.TaskGroup, failure.JobID, metrics.CoalescedFailures+1, noun)
+			out += fmt.Sprintf("[yellow]%s[reset]\n", formatAllocMetrics(metrics, false, "  "))
+		}
+		sections = append(sections, strings.TrimSuffix(out, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// formatSimulatedUtilization formats the allocated resources of every node.
+func formatSimulatedUtilization(utilization []*simulatedNodeUtilization, length int) string {
+	if len(utilization) == 0 {
+		return "No nodes"
+	}
+	rows := []string{"Node ID|Node Name|Node Pool|Class|Allocs|CPU|Memory"}
+	for _, u := range utilization {
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%d|%s|%s",
+			limit(u.NodeID, length), u.NodeName, u.NodePool, u.NodeClass, u.Allocs,
+			formatUtilization(u.CPUUsed, u.CPUAvailable, "MHz"),
+			formatUtilization(u.MemoryMBUsed, u.MemoryMBAvailable, "MiB")))
+	}
+	return formatList(rows)
+}
+
+func formatUtilization(used, available int64, unit string) string {
+	if available <= 0 {
+		return fmt.Sprintf("%d/%d %s", used, available, unit)
+	}
+	return fmt.Sprintf("%d/%d %s (%d%%)", used, available, unit, used*100/available)
+}
+
+// apiAllocMetric converts the metrics of the scheduler to their API
+// representation, which the CLI knows how to format.
+func apiAllocMetric(m *structs.AllocMetric) *api.AllocationMetric {
+	out := &api.AllocationMetric{}
+	if buf, err := json.Marshal(m); err == nil {
+		_ = json.Unmarshal(buf, out)
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulateCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{
+			name:   "no snapshot",
+			args:   []string{},
+			expErr: "This command takes at least one argument",
+		},
+		{
+			name:   "invalid node addition",
+			args:   []string{"-add-nodes=large", "backup.snap"},
+			expErr: `"large" must be of the form <class>:<count>`,
+		},
+		{
+			name:   "invalid node count",
+			args:   []string{"-add-nodes=large:0", "backup.snap"},
+			expErr: `count of "large:0" must be a positive integer`,
+		},
+		{
+			name:   "missing snapshot",
+			args:   []string{filepath.Join(t.TempDir(), "missing.snap")},
+			expErr: "Error opening snapshot file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+			must.One(t, cmd.Run(tc.args))
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expErr)
+		})
+	}
+}
+
+func TestOperatorSchedulerSimulateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, _ *api.Client, _ string) {
+		state := srv.Agent.Server().State()
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+	})
+
+	jobPath := filepath.Join(t.TempDir(), "web.nomad.hcl")
+	must.NoError(t, os.WriteFile(jobPath, []byte(`
+job "web" {
+  group "web" {
+    count = 3
+
+    task "web" {
+      driver = "exec"
+
+      config {
+        command = "/bin/sleep"
+      }
+
+      resources {
+        cpu    = 100
+        memory = 128
+      }
+    }
+  }
+}
+`), 0o600))
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-add-nodes", node.NodeClass + ":2", snapPath, jobPath})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Result of adding 2 nodes of class "linux-medium-pci"`)
+	must.StrContains(t, out, `Result of registering job "web"`)
+	must.StrContains(t, out, "Placements")
+	must.StrContains(t, out, "Node Utilization")
+	must.Eq(t, 3, strings.Count(out, "linux-medium-pci  "))
+	must.StrContains(t, out, node.ID[:8])
+}
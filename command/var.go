@@ -60,6 +60,14 @@ Usage: nomad var <subcommand> [options] [args]
 
       $ nomad var purge <path>
 
+  List the previous versions of a variable:
+
+      $ nomad var history <path>
+
+  Restore a previous version of a variable:
+
+      $ nomad var rollback <path> <version>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -328,6 +336,8 @@ const (
 	errInvalidInFormat             = `Invalid value for "-in"; valid values are [hcl, json]`
 	errInvalidOutFormat            = `Invalid value for "-out"; valid values are [go-template, hcl, json, none, table]`
 	errInvalidListOutFormat        = `Invalid value for "-out"; valid values are [go-template, json, table, terse]`
+	errInvalidHistoryOutFormat     = `Invalid value for "-out"; valid values are [go-template, json, table]`
+	errNoVariableVersions          = `No previous versions found`
 	errWildcardNamespaceNotAllowed = `The wildcard namespace ("*") is not valid for this command.`
 
 	msgfmtCASMismatch = `
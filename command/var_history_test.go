@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarHistoryCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarHistoryCommand{}
+}
+
+func TestVarHistoryCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"some", "bad", "args"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "-out=json", "foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "retrieving variable history")
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+	t.Run("bad_out", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-out=bad", "foo"})
+		out := strings.TrimSpace(ui.ErrorWriter.String())
+		must.One(t, code)
+		must.Eq(t, errInvalidHistoryOutFormat+"\n"+commandErrorText(cmd), out)
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+	t.Run("missing_template", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-out=go-template", "foo"})
+		out := strings.TrimSpace(ui.ErrorWriter.String())
+		must.One(t, code)
+		must.Eq(t, errMissingTemplate+"\n"+commandErrorText(cmd), out)
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+}
+
+func TestVarHistoryCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create a namespace which retains previous versions of its variables
+	_, err := client.Namespaces().Register(&api.Namespace{
+		Name: "history",
+		VariablesConfiguration: &api.NamespaceVariablesConfiguration{
+			RetainVersions: 5,
+		},
+	}, nil)
+	must.NoError(t, err)
+
+	sv := testVariable()
+	sv.Namespace = "history"
+	sv, _, err = client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	firstVersion := sv.ModifyIndex
+
+	ui := cli.NewMockUi()
+	cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-namespace=history", "-out=table", sv.Path})
+	must.Zero(t, code)
+	must.Eq(t, errNoVariableVersions, strings.TrimSpace(ui.OutputWriter.String()))
+
+	sv.Items["keyA"] = "updated"
+	_, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-namespace=history", "-out=json", sv.Path})
+		must.Zero(t, code)
+
+		var versions []*api.VariableMetadata
+		must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &versions))
+		must.Len(t, 1, versions)
+		must.Eq(t, firstVersion, versions[0].ModifyIndex)
+	})
+
+	t.Run("go-template", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-namespace=history",
+			"-out=go-template", "-template={{range .}}{{.ModifyIndex}}{{end}}", sv.Path})
+		must.Zero(t, code)
+		must.Eq(t, fmt.Sprint(firstVersion), strings.TrimSpace(ui.OutputWriter.String()))
+	})
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-namespace=history", "-out=table", sv.Path})
+		must.Zero(t, code)
+		out := ui.OutputWriter.String()
+		must.StrContains(t, out, "Version")
+		must.StrContains(t, out, fmt.Sprint(firstVersion))
+	})
+}
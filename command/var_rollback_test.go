@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarRollbackCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarRollbackCommand{}
+}
+
+func TestVarRollbackCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("bad_version", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo", "nope"})
+		out := strings.TrimSpace(ui.ErrorWriter.String())
+		must.One(t, code)
+		must.Eq(t, `Invalid version "nope": must be a positive integer`, out)
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "foo", "1"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "rolling back variable")
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+}
+
+func TestVarRollbackCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create a namespace which retains previous versions of its variables
+	_, err := client.Namespaces().Register(&api.Namespace{
+		Name: "rollback",
+		VariablesConfiguration: &api.NamespaceVariablesConfiguration{
+			RetainVersions: 5,
+		},
+	}, nil)
+	must.NoError(t, err)
+
+	sv := testVariable()
+	sv.Namespace = "rollback"
+	sv, _, err = client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	firstVersion := sv.ModifyIndex
+
+	sv.Items["keyA"] = "updated"
+	_, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+
+	// Rolling back to a version that doesn't exist fails
+	code := cmd.Run([]string{"-address=" + url, "-namespace=rollback", sv.Path, "100000"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "variable version doesn't exist")
+
+	code = cmd.Run([]string{"-address=" + url, "-namespace=rollback", sv.Path, fmt.Sprint(firstVersion)})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(),
+		fmt.Sprintf("Successfully rolled back variable %q to version %d!", sv.Path, firstVersion))
+
+	restored, _, err := client.Variables().Read(sv.Path, &api.QueryOptions{Namespace: "rollback"})
+	must.NoError(t, err)
+	must.Eq(t, "valueA", restored.Items["keyA"])
+}
@@ -71,4 +71,9 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.HostVolumeRegisterRequestType:                "HostVolumeRegisterRequestType",
 	structs.HostVolumeDeleteRequestType:                  "HostVolumeDeleteRequestType",
 	structs.TaskGroupHostVolumeClaimDeleteRequestType:    "TaskGroupHostVolumeClaimDeleteRequestType",
+	structs.VariableVersionsDeleteRequestType:            "VariableVersionsDeleteRequestType",
+	structs.DeploymentAnalysisUpdateRequestType:          "DeploymentAnalysisUpdateRequestType",
+	structs.PeriodicLaunchDecisionRequestType:            "PeriodicLaunchDecisionRequestType",
+	structs.JobDispatchReleaseRequestType:                "JobDispatchReleaseRequestType",
+	structs.VariableVersionsRekeyRequestType:             "VariableVersionsRekeyRequestType",
 }
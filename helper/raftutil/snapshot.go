@@ -12,7 +12,6 @@ import (
 	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/state"
-	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
 )
 
@@ -54,47 +53,11 @@ func RestoreFromArchive(archive io.Reader, filter *nomad.FSMFilter) (raft.FSM, *
 
 func RedactSnapshot(srcFile *os.File) error {
 	srcFile.Seek(0, 0)
-	fsm, store, meta, err := RestoreFromArchive(srcFile, nil)
+	snap, err := nomad.RedactSnapshot(hclog.Default(), srcFile)
 	if err != nil {
-		return fmt.Errorf("Failed to load snapshot from archive: %w", err)
-	}
-
-	iter, err := store.RootKeys(nil)
-	if err != nil {
-		return fmt.Errorf("Failed to query for root keys: %v", err)
-	}
-
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		rootKey := raw.(*structs.RootKey)
-		if rootKey == nil {
-			break
-		}
-		if len(rootKey.WrappedKeys) > 0 {
-			rootKey.KeyID = rootKey.KeyID + " [REDACTED]"
-			rootKey.WrappedKeys = nil
-		}
-		msg, err := structs.Encode(structs.WrappedRootKeysUpsertRequestType,
-			&structs.KeyringUpsertWrappedRootKeyRequest{
-				WrappedRootKeys: rootKey,
-			})
-		if err != nil {
-			return fmt.Errorf("Could not re-encode redacted key: %v", err)
-		}
-
-		fsm.Apply(&raft.Log{
-			Type: raft.LogCommand,
-			Data: msg,
-		})
-	}
-
-	snap, err := snapshot.NewFromFSM(hclog.Default(), fsm, meta)
-	if err != nil {
-		return fmt.Errorf("Failed to create redacted snapshot: %v", err)
+		return fmt.Errorf("Failed to redact snapshot: %v", err)
 	}
+	defer snap.Close()
 
 	srcFile.Truncate(0)
 	srcFile.Seek(0, 0)
@@ -47,6 +47,116 @@ func New(logger hclog.Logger, r *raft.Raft) (*Snapshot, error) {
 	return writeSnapshot(logger, metadata, snap)
 }
 
+// StateFilter rewrites the encoded FSM state of a snapshot read from r into w.
+type StateFilter func(w io.Writer, r io.Reader) error
+
+// NewFiltered takes a state snapshot of the given Raft instance like New, but
+// passes the state through filter before archiving it. The filtered state is
+// staged in a temporary file, so the state is never held in memory. You must
+// arrange to call Close() on the returned object.
+func NewFiltered(logger hclog.Logger, r *raft.Raft, filter StateFilter) (*Snapshot, error) {
+	// Take the snapshot.
+	future := r.Snapshot()
+	if err := future.Error(); err != nil {
+		return nil, fmt.Errorf("Raft error when taking snapshot: %v", err)
+	}
+
+	// Open up the snapshot.
+	metadata, snap, err := future.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %v:", err)
+	}
+	defer func() {
+		if err := snap.Close(); err != nil {
+			logger.Error("Failed to close Raft snapshot", "error", err)
+		}
+	}()
+
+	state, size, err := filterState(snap, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer removeState(logger, state)
+
+	filtered := *metadata
+	filtered.Size = size
+	return writeSnapshot(logger, &filtered, io.NopCloser(state))
+}
+
+// Filter passes the state of the snapshot archive read from in through filter,
+// and returns a snapshot of the result like NewFiltered. You must arrange to
+// call Close() on the returned object.
+func Filter(logger hclog.Logger, in io.Reader, filter StateFilter) (*Snapshot, error) {
+	// r is closed below, w is closed by CopySnapshot
+	r, w := io.Pipe()
+	defer r.Close()
+
+	errCh := make(chan error, 1)
+	metaCh := make(chan *raft.SnapshotMeta, 1)
+	go func() {
+		meta, err := CopySnapshot(in, w)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to read snapshot: %v", err)
+		} else {
+			metaCh <- meta
+		}
+	}()
+
+	state, size, err := filterState(r, filter)
+	if err != nil {
+		// Unblock the copy of the rest of the archive
+		r.CloseWithError(err)
+		return nil, err
+	}
+	defer removeState(logger, state)
+
+	select {
+	case err := <-errCh:
+		return nil, err
+	case metadata := <-metaCh:
+		metadata.Size = size
+		return writeSnapshot(logger, metadata, io.NopCloser(state))
+	}
+}
+
+// filterState passes the state read from snap through filter into a temporary
+// file, which is returned rewound along with its size. You must arrange to call
+// removeState on the returned file.
+func filterState(snap io.Reader, filter StateFilter) (*os.File, int64, error) {
+	state, err := os.CreateTemp("", "snapshot-state")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create snapshot state file: %v", err)
+	}
+
+	size, err := func() (int64, error) {
+		if err := filter(state, snap); err != nil {
+			return 0, fmt.Errorf("failed to filter snapshot state: %v", err)
+		}
+		size, err := state.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure snapshot state: %v", err)
+		}
+		if _, err := state.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("failed to rewind snapshot state: %v", err)
+		}
+		return size, nil
+	}()
+	if err != nil {
+		state.Close()
+		os.Remove(state.Name())
+		return nil, 0, err
+	}
+	return state, size, nil
+}
+
+// removeState closes and removes a temporary file returned by filterState.
+func removeState(logger hclog.Logger, state *os.File) {
+	state.Close()
+	if err := os.Remove(state.Name()); err != nil {
+		logger.Error("Failed to clean up temp snapshot state", "error", err)
+	}
+}
+
 // NewFromFSM takes a state snapshot of the given FSM (for when we don't have a
 // Raft instance setup) into a temporary file and returns an object that gives
 // access to the file as an io.Reader. You must arrange to call Close() on the
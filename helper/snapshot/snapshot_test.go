@@ -421,3 +421,74 @@ func TestSnapshot_FromFSM(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshot_Filtered(t *testing.T) {
+	dir := testutil.TempDir(t, "snapshot")
+	defer os.RemoveAll(dir)
+
+	before, _ := makeRaft(t, filepath.Join(dir, "before"))
+	defer before.Shutdown()
+	var expected [][]byte
+	for i := 0; i < 16; i++ {
+		log := []byte(fmt.Sprintf("log %d", i))
+		must.NoError(t, before.Apply(log, time.Second).Error())
+		if i%2 == 0 {
+			expected = append(expected, log)
+		}
+	}
+
+	// The filter drops every other log, so the size of the state changes
+	var filtered int
+	dropOdd := func(w io.Writer, r io.Reader) error {
+		filtered++
+		var logs [][]byte
+		if err := codec.NewDecoder(r, structs.MsgpackHandle).Decode(&logs); err != nil {
+			return err
+		}
+		var kept [][]byte
+		for i, log := range logs {
+			if i%2 == 0 {
+				kept = append(kept, log)
+			}
+		}
+		return codec.NewEncoder(w, structs.MsgpackHandle).Encode(kept)
+	}
+	keepAll := func(w io.Writer, r io.Reader) error {
+		filtered++
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	logger := testutil.Logger(t)
+	snap, err := NewFiltered(logger, before, dropOdd)
+	must.NoError(t, err)
+	defer snap.Close()
+
+	// Filtering an archive passes it through the filter again
+	again, err := Filter(logger, snap, keepAll)
+	must.NoError(t, err)
+	defer again.Close()
+	must.Eq(t, 2, filtered)
+	must.Eq(t, snap.Index(), again.Index())
+
+	_, err = Verify(again)
+	must.NoError(t, err)
+	_, err = again.file.Seek(0, 0)
+	must.NoError(t, err)
+
+	after, fsm := makeRaft(t, filepath.Join(dir, "after"))
+	defer after.Shutdown()
+	must.NoError(t, Restore(logger, again, after))
+
+	fsm.Lock()
+	defer fsm.Unlock()
+	must.Eq(t, expected, fsm.logs)
+
+	// Errors of the filter are returned
+	_, err = snap.file.Seek(0, 0)
+	must.NoError(t, err)
+	_, err = Filter(logger, snap, func(io.Writer, io.Reader) error {
+		return fmt.Errorf("boom")
+	})
+	must.ErrorContains(t, err, "boom")
+}
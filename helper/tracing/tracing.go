@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package tracing provides distributed tracing of work done by the Nomad agent
+// using OpenTelemetry. Spans are exported over OTLP once Setup has been called
+// and are discarded otherwise, so callers can unconditionally start spans.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the instrumentation scope of Nomad's spans.
+	tracerName = "github.com/hashicorp/nomad"
+
+	// serviceName is the name of the service reported in exported spans.
+	serviceName = "nomad"
+)
+
+// Config configures the export of spans.
+type Config struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector spans are
+	// exported to.
+	Endpoint string
+
+	// Insecure exports spans over plain HTTP instead of HTTPS.
+	Insecure bool
+
+	// Headers are sent with every export request, and are typically used to
+	// authenticate with the collector.
+	Headers map[string]string
+
+	// SampleRate is the fraction of traces which are sampled, between 0 and 1.
+	// Traces which have been sampled by a caller, such as an API client, are
+	// always sampled.
+	SampleRate float64
+
+	// Attributes describe the agent exporting the spans, such as its node name
+	// and region.
+	Attributes map[string]string
+}
+
+// Setup configures the export of spans created by this package, and returns
+// a function which flushes any spans which have yet to be exported and stops
+// exporting.
+func Setup(cfg *Config, logger hclog.Logger) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("an endpoint is required to export traces")
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1, got %v", cfg.SampleRate)
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	for k, v := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	)
+	install(provider, logger)
+	return provider.Shutdown, nil
+}
+
+// install sets the provider used to create spans, and the propagator used to
+// carry their context between processes.
+func install(provider trace.TracerProvider, logger hclog.Logger) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("failed to export traces", "error", err)
+	}))
+}
+
+// Start starts a span as a child of any span in ctx. The returned context
+// contains the new span.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// Continue starts a span as a child of the span whose context was propagated
+// in carrier. Work which isn't part of an existing trace is not recorded, so
+// internal operations only appear in the traces of the requests causing them.
+func Continue(carrier map[string]string, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx := Extract(context.Background(), carrier)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Start(ctx, name, opts...)
+}
+
+// End ends the span, marking it as failed if err is non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the context of the span in ctx in a form which can be
+// carried in RPC arguments. It returns nil if there is no span to propagate.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns a copy of ctx containing the span context propagated in
+// carrier, if any.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// ExtractHeader returns a copy of ctx containing the span context propagated
+// in the HTTP headers, if any.
+func ExtractHeader(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// EvalAttributes returns the attributes which identify an evaluation.
+func EvalAttributes(eval *structs.Evaluation) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("nomad.eval.id", eval.ID),
+		attribute.String("nomad.eval.type", eval.Type),
+		attribute.String("nomad.eval.triggered_by", eval.TriggeredBy),
+		attribute.String("nomad.job.id", eval.JobID),
+		attribute.String("nomad.namespace", eval.Namespace),
+	}
+}
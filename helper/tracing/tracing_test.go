@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testExporter installs a tracer provider which samples every trace, and
+// returns the exporter its spans are recorded by. Tests using it modify global
+// state and so must not run in parallel.
+func testExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+	install(provider, hclog.NewNullLogger())
+	t.Cleanup(func() {
+		install(trace.NewNoopTracerProvider(), hclog.NewNullLogger())
+	})
+	return exporter
+}
+
+func TestSetup(t *testing.T) {
+	_, err := Setup(&Config{}, hclog.NewNullLogger())
+	must.ErrorContains(t, err, "endpoint is required")
+
+	_, err = Setup(&Config{Endpoint: "127.0.0.1:4318", SampleRate: 2}, hclog.NewNullLogger())
+	must.ErrorContains(t, err, "sample rate must be between 0 and 1")
+}
+
+func TestPropagation(t *testing.T) {
+	exporter := testExporter(t)
+
+	// Work which isn't part of a trace isn't recorded
+	_, span := Continue(nil, "orphan")
+	must.False(t, span.SpanContext().IsValid())
+	span.End()
+	must.Nil(t, Inject(context.Background()))
+
+	ctx, root := Start(context.Background(), "root")
+	carrier := Inject(ctx)
+	must.MapContainsKey(t, carrier, "traceparent")
+
+	// Spans continue the trace propagated in RPC args and HTTP headers
+	_, child := Continue(carrier, "child")
+	End(child, errors.New("failed"))
+
+	header := http.Header{}
+	header.Set("Traceparent", carrier["traceparent"])
+	_, fromHeader := Start(ExtractHeader(context.Background(), header), "from header")
+	End(fromHeader, nil)
+	root.End()
+
+	spans := exporter.GetSpans()
+	must.Len(t, 3, spans)
+	traceID := root.SpanContext().TraceID()
+
+	must.Eq(t, "child", spans[0].Name)
+	must.Eq(t, traceID, spans[0].SpanContext.TraceID())
+	must.Eq(t, root.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	must.Eq(t, codes.Error, spans[0].Status.Code)
+	must.Eq(t, "failed", spans[0].Status.Description)
+
+	must.Eq(t, "from header", spans[1].Name)
+	must.Eq(t, root.SpanContext().SpanID(), spans[1].Parent.SpanID())
+	must.Eq(t, codes.Unset, spans[1].Status.Code)
+}
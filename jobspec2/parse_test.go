@@ -1098,6 +1098,95 @@ func TestParseServiceCheck(t *testing.T) {
 	require.Equal(t, expectedJob, parsedJob)
 }
 
+func TestParse_DependsOn(t *testing.T) {
+	t.Parallel()
+
+	hcl := `job "api" {
+  depends_on {
+    job       = "migrate"
+    condition = "complete"
+  }
+
+  depends_on {
+    job       = "db"
+    condition = "healthy"
+  }
+
+  group "api" {}
+}
+`
+	parsedJob, err := ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.JobDependency{
+		{JobID: "migrate", Condition: api.JobDependencyConditionComplete},
+		{JobID: "db", Condition: api.JobDependencyConditionHealthy},
+	}, parsedJob.DependsOn)
+}
+
+func TestParse_LogSink(t *testing.T) {
+	t.Parallel()
+
+	hcl := `job "api" {
+  group "api" {
+    task "api" {
+      driver = "docker"
+
+      logs {
+        max_files = 5
+
+        sink {
+          type    = "syslog"
+          address = "tcp://10.0.0.1:514"
+        }
+      }
+    }
+  }
+}
+`
+	parsedJob, err := ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+
+	logs := parsedJob.TaskGroups[0].Tasks[0].LogConfig
+	require.Equal(t, 5, *logs.MaxFiles)
+	require.Equal(t, &api.LogSinkConfig{
+		Type:    api.LogSinkTypeSyslog,
+		Address: "tcp://10.0.0.1:514",
+	}, logs.Sink)
+}
+
+func TestParse_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	hcl := `job "api" {
+  rebalance {
+    threshold      = 0.3
+    max_disruption = 2
+    interval       = "30m"
+  }
+
+  group "api" {}
+}
+`
+	parsedJob, err := ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.RebalancePolicy{
+		Threshold:     pointerOf(0.3),
+		MaxDisruption: pointerOf(2),
+		Interval:      pointerOf(30 * time.Minute),
+	}, parsedJob.Rebalance)
+}
+
 func TestWaitConfig(t *testing.T) {
 	t.Parallel()
 
@@ -1153,6 +1242,43 @@ func TestRestartRenderTemplates(t *testing.T) {
 	require.False(t, *tg.Tasks[1].RestartPolicy.RenderTemplates)
 }
 
+func TestExitRules(t *testing.T) {
+	t.Parallel()
+	hclBytes, err := os.ReadFile("test-fixtures/exit-rules.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/exit-rules.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	tg := job.TaskGroups[0]
+	must.Eq(t, []*api.ExitRule{
+		{ExitCodes: []int{3, 4}, Action: "fail"},
+		{Signals: []int{15}, Action: "restart"},
+	}, tg.RestartPolicy.ExitRules)
+	must.Eq(t, []*api.ExitRule{
+		{ExitCodes: []int{75}, Action: "reschedule"},
+	}, tg.ReschedulePolicy.ExitRules)
+}
+
+func TestActiveDeadline(t *testing.T) {
+	t.Parallel()
+	hclBytes, err := os.ReadFile("test-fixtures/active-deadline.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/active-deadline.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	must.Eq(t, 2*time.Hour, *job.ActiveDeadline)
+	must.Eq(t, time.Hour, *job.TaskGroups[0].ActiveDeadline)
+	must.Eq(t, 30*time.Minute, *job.TaskGroups[0].Tasks[0].ActiveDeadline)
+}
+
 // TestIdentity asserts that the default identity will be moved from the
 // Identities slice to the pre-1.7 Identity field in case >=1.7 CLIs are used
 // with <1.7 APIs.
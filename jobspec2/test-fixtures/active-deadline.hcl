@@ -0,0 +1,15 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  type            = "batch"
+  active_deadline = "2h"
+
+  group "group" {
+    active_deadline = "1h"
+
+    task "foo" {
+      active_deadline = "30m"
+    }
+  }
+}
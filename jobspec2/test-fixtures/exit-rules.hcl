@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "group" {
+    restart {
+      exit_rule {
+        exit_codes = [3, 4]
+        action     = "fail"
+      }
+      exit_rule {
+        signals = [15]
+        action  = "restart"
+      }
+    }
+
+    reschedule {
+      exit_rule {
+        exit_codes = [75]
+        action     = "reschedule"
+      }
+    }
+
+    task "foo" {
+    }
+  }
+}
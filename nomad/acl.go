@@ -32,6 +32,10 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 	return s.auth.ResolveToken(secretID)
 }
 
+func (s *Server) ResolveIdentity(secretID string) (*structs.AuthenticatedIdentity, error) {
+	return s.auth.ResolveIdentity(secretID)
+}
+
 func (s *Server) ResolvePoliciesForClaims(claims *structs.IdentityClaims) ([]*structs.ACLPolicy, error) {
 	return s.auth.ResolvePoliciesForClaims(claims)
 }
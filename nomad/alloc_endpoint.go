@@ -297,6 +297,7 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 		JobID:          alloc.Job.ID,
 		JobModifyIndex: alloc.Job.ModifyIndex,
 		Status:         structs.EvalStatusPending,
+		TraceContext:   args.TraceContext(),
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
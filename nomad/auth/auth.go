@@ -575,6 +575,27 @@ func resolveACLFromToken(snap *state.StateSnapshot, cache *structs.ACLCache[*acl
 	return aclObj, nil
 }
 
+// ResolveIdentity is used to translate an ACL Token Secret ID or workload
+// identity into the identity it authenticates as, without resolving its
+// policies. This is used to identify the caller of HTTP requests for audit
+// logging, and never for authorization.
+func (s *Authenticator) ResolveIdentity(secretID string) (*structs.AuthenticatedIdentity, error) {
+	aclToken, err := s.resolveSecretToken(secretID)
+	switch {
+	case err == nil:
+		return &structs.AuthenticatedIdentity{ACLToken: aclToken}, nil
+	case errors.Is(err, structs.ErrTokenInvalid):
+		// if it's not a UUID it might be an identity claim
+		claims, err := s.VerifyClaim(secretID)
+		if err != nil {
+			return nil, err
+		}
+		return &structs.AuthenticatedIdentity{Claims: claims}, nil
+	default:
+		return nil, err
+	}
+}
+
 // resolveSecretToken is used to translate an ACL Token Secret ID into a
 // Accessor ID, the anonymous accessor, or an error.
 func (s *Authenticator) resolveSecretToken(secretID string) (*structs.ACLToken, error) {
@@ -1027,6 +1027,53 @@ func TestResolveSecretToken(t *testing.T) {
 	}
 }
 
+func TestResolveIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	store := testStateStore(t)
+	auth := NewAuthenticator(&AuthenticatorConfig{
+		StateFn:        func() *state.StateStore { return store },
+		Logger:         testlog.HCLogger(t),
+		GetLeaderACLFn: func() string { return uuid.Generate() },
+		AclsEnabled:    true,
+		VerifyTLS:      true,
+		Region:         "global",
+		Encrypter:      newTestEncrypter(),
+	})
+
+	token := mock.ACLToken()
+	must.NoError(t, store.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLToken{token}))
+
+	ident, err := auth.ResolveIdentity(token.SecretID)
+	must.NoError(t, err)
+	must.Eq(t, token, ident.ACLToken)
+
+	ident, err = auth.ResolveIdentity("")
+	must.NoError(t, err)
+	must.Eq(t, structs.AnonymousACLToken, ident.ACLToken)
+
+	_, err = auth.ResolveIdentity(uuid.Generate())
+	must.ErrorIs(t, err, structs.ErrTokenNotFound)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	claims := structs.NewIdentityClaimsBuilder(alloc.Job, alloc,
+		task.IdentityHandle(&structs.WorkloadIdentity{Name: "default"}),
+		task.Identity).
+		WithTask(task).
+		Build(time.Now())
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 20,
+		[]*structs.Allocation{alloc}))
+	signed, err := auth.encrypter.(*testEncrypter).signClaim(claims)
+	must.NoError(t, err)
+
+	ident, err = auth.ResolveIdentity(signed)
+	must.NoError(t, err)
+	must.Nil(t, ident.ACLToken)
+	must.Eq(t, alloc.ID, ident.Claims.AllocationID)
+}
+
 func TestResolveClaims(t *testing.T) {
 	ci.Parallel(t)
 
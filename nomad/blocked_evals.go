@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"slices"
 	"sync"
 	"time"
 
@@ -53,6 +54,10 @@ type BlockedEvals struct {
 	// resource constraints.
 	system *systemEvals
 
+	// dependencies is the set of evaluations waiting for the jobs their job
+	// depends on to reach a condition.
+	dependencies *dependencyEvals
+
 	// unblockCh is used to buffer unblocking of evaluations.
 	capacityChangeCh chan *capacityUpdate
 
@@ -108,6 +113,7 @@ func NewBlockedEvals(evalBroker *EvalBroker, logger hclog.Logger) *BlockedEvals
 		captured:         make(map[string]wrappedEval),
 		escaped:          make(map[string]wrappedEval),
 		system:           newSystemEvals(),
+		dependencies:     newDependencyEvals(),
 		jobs:             make(map[structs.NamespacedID]string),
 		unblockIndexes:   make(map[string]unblockEvent),
 		capacityChangeCh: make(chan *capacityUpdate, unblockBuffer),
@@ -179,6 +185,20 @@ func (b *BlockedEvals) processBlock(eval *structs.Evaluation, token string) {
 		return
 	}
 
+	// Evaluations waiting for the dependencies of their job are unblocked when
+	// those jobs change, rather than when capacity becomes available.
+	if len(eval.UnmetDependencies) != 0 {
+		if b.dependencies.MissedUnblock(eval) {
+			b.evalBroker.EnqueueAll(map[*structs.Evaluation]string{eval: token})
+			return
+		}
+
+		b.jobs[structs.NewNamespacedID(eval.JobID, eval.Namespace)] = eval.ID
+		b.stats.Block(eval)
+		b.dependencies.Add(eval, token)
+		return
+	}
+
 	// Check if the eval missed an unblock while it was in the scheduler at an
 	// older index. The scheduler could have been invoked with a snapshot of
 	// state that was prior to additional capacity being added or allocations
@@ -243,8 +263,16 @@ func (b *BlockedEvals) processBlockJobDuplicate(eval *structs.Evaluation) (newCa
 	}
 
 	var dup *structs.Evaluation
-	existingW, ok := b.captured[existingID]
-	if ok {
+	if existingW, ok := b.dependencies.Get(existingID); ok {
+		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
+			b.dependencies.Remove(existingW.eval)
+			dup = existingW.eval
+			b.stats.Unblock(dup)
+		} else {
+			dup = eval
+			newCancelled = true
+		}
+	} else if existingW, ok := b.captured[existingID]; ok {
 		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
 			delete(b.captured, existingID)
 			dup = existingW.eval
@@ -254,7 +282,7 @@ func (b *BlockedEvals) processBlockJobDuplicate(eval *structs.Evaluation) (newCa
 			newCancelled = true
 		}
 	} else {
-		existingW, ok = b.escaped[existingID]
+		existingW, ok := b.escaped[existingID]
 		if !ok {
 			// This is a programming error
 			b.logger.Error("existing blocked evaluation is neither tracked as captured or escaped", "existing_id", existingID)
@@ -376,6 +404,12 @@ func (b *BlockedEvals) Untrack(jobID, namespace string) {
 	}
 
 	// Attempt to delete the evaluation
+	if w, ok := b.dependencies.Get(evalID); ok {
+		delete(b.jobs, nsID)
+		b.dependencies.Remove(w.eval)
+		b.stats.Unblock(w.eval)
+	}
+
 	if w, ok := b.captured[evalID]; ok {
 		delete(b.jobs, nsID)
 		delete(b.captured, evalID)
@@ -518,6 +552,51 @@ func (b *BlockedEvals) UnblockNode(nodeID string, index uint64) {
 	b.evalBroker.EnqueueAll(evals)
 }
 
+// UnblockJobDependents causes any evaluation waiting on the passed job to reach
+// a condition to be enqueued into the eval broker, once satisfied reports that
+// every dependency it is waiting on has been met. It is called whenever the
+// job, its allocations or its deployments change.
+func (b *BlockedEvals) UnblockJobDependents(jobID, namespace string, index uint64,
+	satisfied func(namespace string, dep *structs.JobDependency) bool) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	// Do nothing if not enabled
+	if !b.enabled {
+		return
+	}
+
+	// Store the index in which the unblock happened. We use this on subsequent
+	// block calls in case the evaluation was in the scheduler when the job
+	// changed.
+	nsID := structs.NewNamespacedID(jobID, namespace)
+	b.dependencies.SetUnblockIndex(nsID, index, time.Now().UTC())
+
+	evals := b.dependencies.DependentEvals(nsID)
+	if len(evals) == 0 {
+		return
+	}
+
+	for eval := range evals {
+		// Evaluations are only unblocked once they can roll out the job, so
+		// changes which don't meet the dependencies don't cause churn.
+		if slices.ContainsFunc(eval.UnmetDependencies, func(dep *structs.JobDependency) bool {
+			return !satisfied(eval.Namespace, dep)
+		}) {
+			delete(evals, eval)
+			continue
+		}
+
+		b.dependencies.Remove(eval)
+		delete(b.jobs, structs.NewNamespacedID(eval.JobID, eval.Namespace))
+		b.stats.Unblock(eval)
+	}
+
+	if len(evals) != 0 {
+		b.evalBroker.EnqueueAll(evals)
+	}
+}
+
 // watchCapacity is a long lived function that watches for capacity changes in
 // nodes and unblocks the correct set of evals.
 func (b *BlockedEvals) watchCapacity(stopCh <-chan struct{}, changeCh <-chan *capacityUpdate) {
@@ -702,6 +781,7 @@ func (b *BlockedEvals) Flush() {
 	b.stopCh = make(chan struct{})
 	b.duplicateCh = make(chan struct{}, 1)
 	b.system = newSystemEvals()
+	b.dependencies = newDependencyEvals()
 }
 
 // Stats is used to query the state of the blocked eval tracker.
@@ -788,6 +868,7 @@ func (b *BlockedEvals) pruneUnblockIndexes(cutoff time.Time) {
 			delete(b.unblockIndexes, key)
 		}
 	}
+	b.dependencies.pruneUnblockIndexes(cutoff)
 }
 
 // pruneStats is used to prune any zero value stats that are excessively old.
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dependencyEvals are the blocked evaluations of jobs waiting for the jobs they
+// depend on to reach a condition. They are unblocked when one of those jobs
+// changes, rather than when capacity becomes available.
+type dependencyEvals struct {
+	// byDependency maps a job to the set of evalIDs waiting on it
+	byDependency map[structs.NamespacedID]map[string]struct{}
+
+	// evals maps evalIDs to an eval and token
+	evals map[string]*wrappedEval
+
+	// unblockIndexes maps a job to the index and time at which it last
+	// changed. This is used to check if an evaluation could have been
+	// unblocked between the time it was in the scheduler and the time it is
+	// being blocked.
+	unblockIndexes map[structs.NamespacedID]unblockEvent
+}
+
+func newDependencyEvals() *dependencyEvals {
+	return &dependencyEvals{
+		byDependency:   map[structs.NamespacedID]map[string]struct{}{},
+		evals:          map[string]*wrappedEval{},
+		unblockIndexes: map[structs.NamespacedID]unblockEvent{},
+	}
+}
+
+func (d *dependencyEvals) Add(eval *structs.Evaluation, token string) {
+	d.evals[eval.ID] = &wrappedEval{eval: eval, token: token}
+	for _, dep := range eval.UnmetDependencies {
+		jobID := structs.NewNamespacedID(dep.JobID, eval.Namespace)
+		if _, ok := d.byDependency[jobID]; !ok {
+			d.byDependency[jobID] = make(map[string]struct{})
+		}
+		d.byDependency[jobID][eval.ID] = struct{}{}
+	}
+}
+
+func (d *dependencyEvals) Get(evalID string) (*wrappedEval, bool) {
+	w, ok := d.evals[evalID]
+	return w, ok
+}
+
+func (d *dependencyEvals) Remove(eval *structs.Evaluation) {
+	for _, dep := range eval.UnmetDependencies {
+		jobID := structs.NewNamespacedID(dep.JobID, eval.Namespace)
+		delete(d.byDependency[jobID], eval.ID)
+		if len(d.byDependency[jobID]) == 0 {
+			delete(d.byDependency, jobID)
+		}
+	}
+	delete(d.evals, eval.ID)
+}
+
+// DependentEvals returns the evaluations waiting on the passed job.
+func (d *dependencyEvals) DependentEvals(jobID structs.NamespacedID) map[*structs.Evaluation]string {
+	out := map[*structs.Evaluation]string{}
+	for evalID := range d.byDependency[jobID] {
+		if w, ok := d.Get(evalID); ok {
+			out[w.eval] = w.token
+		}
+	}
+	return out
+}
+
+// SetUnblockIndex records the index at which the passed job changed.
+func (d *dependencyEvals) SetUnblockIndex(jobID structs.NamespacedID, index uint64, now time.Time) {
+	d.unblockIndexes[jobID] = unblockEvent{index, now}
+}
+
+// MissedUnblock returns whether one of the jobs the evaluation is waiting on
+// changed after the snapshot the evaluation was processed with.
+func (d *dependencyEvals) MissedUnblock(eval *structs.Evaluation) bool {
+	for _, dep := range eval.UnmetDependencies {
+		u, ok := d.unblockIndexes[structs.NewNamespacedID(dep.JobID, eval.Namespace)]
+		if ok && eval.SnapshotIndex < u.index {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneUnblockIndexes removes the unblock indexes recorded before cutoff.
+func (d *dependencyEvals) pruneUnblockIndexes(cutoff time.Time) {
+	for jobID, u := range d.unblockIndexes {
+		if u.timestamp.Before(cutoff) {
+			delete(d.unblockIndexes, jobID)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -760,3 +761,102 @@ func TestBlockedEvals_SystemDisableFlush(t *testing.T) {
 	require.Empty(blocked.system.byJob)
 	require.Empty(blocked.system.byNode)
 }
+
+func TestBlockedEvals_UnblockJobDependents(t *testing.T) {
+	ci.Parallel(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// Create an eval waiting on two jobs and add it to the blocked tracker.
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.SnapshotIndex = 1000
+	e.UnmetDependencies = []*structs.JobDependency{
+		{JobID: "migrate", Condition: structs.JobDependencyConditionComplete},
+		{JobID: "db", Condition: structs.JobDependencyConditionHealthy},
+	}
+	blocked.Block(e)
+	must.Eq(t, 1, blocked.Stats().TotalBlocked)
+
+	met := map[string]bool{}
+	satisfied := func(namespace string, dep *structs.JobDependency) bool {
+		return namespace == e.Namespace && met[dep.JobID]
+	}
+
+	// Capacity changes and other jobs changing don't unblock the eval
+	met["other"], met["migrate"], met["db"] = true, true, true
+	blocked.Unblock("v1:123", 1001)
+	blocked.UnblockJobDependents("other", e.Namespace, 1002, satisfied)
+	blocked.UnblockJobDependents("migrate", "other-namespace", 1003, satisfied)
+	must.Eq(t, 1, blocked.Stats().TotalBlocked)
+	must.Eq(t, 0, broker.Stats().TotalReady)
+
+	// A job it depends on changing doesn't unblock it while any of its
+	// dependencies is unmet
+	met["migrate"] = false
+	blocked.UnblockJobDependents("db", e.Namespace, 1004, satisfied)
+	must.Eq(t, 1, blocked.Stats().TotalBlocked)
+	must.Eq(t, 0, broker.Stats().TotalReady)
+
+	// The last dependency being met unblocks it
+	met["migrate"] = true
+	blocked.UnblockJobDependents("migrate", e.Namespace, 1005, satisfied)
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+	must.MapEmpty(t, blocked.dependencies.byDependency)
+	must.MapEmpty(t, blocked.dependencies.evals)
+}
+
+func TestBlockedEvals_Block_ImmediateUnblock_JobDependents(t *testing.T) {
+	ci.Parallel(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// The job changed after the eval was processed by the scheduler
+	blocked.UnblockJobDependents("migrate", structs.DefaultNamespace, 1000, satisfiedJobDependency)
+
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.SnapshotIndex = 900
+	e.UnmetDependencies = []*structs.JobDependency{
+		{JobID: "migrate", Condition: structs.JobDependencyConditionComplete},
+	}
+	blocked.Block(e)
+
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+}
+
+func TestBlockedEvals_Untrack_JobDependents(t *testing.T) {
+	ci.Parallel(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.SnapshotIndex = 1000
+	e.UnmetDependencies = []*structs.JobDependency{
+		{JobID: "migrate", Condition: structs.JobDependencyConditionComplete},
+	}
+	blocked.Block(e)
+
+	// A newer eval for the same job replaces the existing one
+	e2 := e.Copy()
+	e2.ID = uuid.Generate()
+	e2.SnapshotIndex = 1001
+	blocked.Block(e2)
+	must.Eq(t, 1, blocked.Stats().TotalBlocked)
+	dups := blocked.GetDuplicates(0)
+	must.Len(t, 1, dups)
+	must.Eq(t, e.ID, dups[0].ID)
+
+	blocked.Untrack(e.JobID, e.Namespace)
+	must.Eq(t, 0, blocked.Stats().TotalBlocked)
+	must.MapEmpty(t, blocked.dependencies.evals)
+
+	blocked.UnblockJobDependents("migrate", e.Namespace, 1002, satisfiedJobDependency)
+	must.Eq(t, 0, broker.Stats().TotalReady)
+}
+
+// satisfiedJobDependency reports every job dependency as met.
+func satisfiedJobDependency(string, *structs.JobDependency) bool {
+	return true
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -77,6 +79,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobVariableVersionsGC:
+		return c.variableVersionsGC()
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -116,6 +120,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.rootKeyGC(eval, time.Now()); err != nil {
 		return err
 	}
+	if err := c.variableVersionsGC(); err != nil {
+		return err
+	}
 
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
@@ -672,6 +679,89 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string, batchSize
 	return requests
 }
 
+// variableVersionsGC is used to garbage collect previous versions of variables
+// beyond the retention configured for their namespace and path. Versions are
+// pruned as they are written, so this only finds versions left behind when
+// the retention is lowered or the namespace is deleted.
+func (c *CoreScheduler) variableVersionsGC() error {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.VariableVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	// The iterator is ordered by namespace and then path, so each variable's
+	// versions are contiguous.
+	var gcVersions []*structs.VariableVersionKey
+	var current []*structs.VariableEncrypted
+
+	collect := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		retain, err := c.snap.VariableRetainVersions(ws, current[0].Namespace, current[0].Path)
+		if err != nil {
+			return err
+		}
+		sort.Slice(current, func(i, j int) bool {
+			return current[i].ModifyIndex < current[j].ModifyIndex
+		})
+		for i := 0; i < len(current)-retain; i++ {
+			gcVersions = append(gcVersions, &structs.VariableVersionKey{
+				Namespace: current[i].Namespace,
+				Path:      current[i].Path,
+				Version:   current[i].ModifyIndex,
+			})
+		}
+		current = current[:0]
+		return nil
+	}
+
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		v := raw.(*structs.VariableEncrypted)
+		if len(current) > 0 &&
+			(current[0].Namespace != v.Namespace || current[0].Path != v.Path) {
+			if err := collect(); err != nil {
+				return err
+			}
+		}
+		current = append(current, v)
+	}
+	if err := collect(); err != nil {
+		return err
+	}
+
+	// Fast-path the nothing case
+	if len(gcVersions) == 0 {
+		return nil
+	}
+	c.logger.Debug("variable versions GC found eligible versions", "versions", len(gcVersions))
+	return c.variableVersionsReap(gcVersions)
+}
+
+// variableVersionsReap contacts the leader and issues a reap on the passed
+// variable versions.
+func (c *CoreScheduler) variableVersionsReap(versions []*structs.VariableVersionKey) error {
+	for batch := range slices.Chunk(versions, structs.MaxUUIDsPerWriteRequest) {
+		req := &structs.VariableVersionsDeleteRequest{
+			Versions: batch,
+			WriteRequest: structs.WriteRequest{
+				Region: c.srv.config.Region,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("Variables.ReapVersions", req, &resp); err != nil {
+			c.logger.Error("variable versions reap failed", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime, cutoffTime time.Time) bool {
@@ -1242,6 +1332,17 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 			return err
 		}
 
+		// Previous versions of variables are encrypted with the key too, and
+		// the key can't be removed until none are
+		versionIter, err := c.snap.GetVariableVersionsByKeyID(ws, wrappedKeys.KeyID)
+		if err != nil {
+			return err
+		}
+		err = c.rotateVariableVersions(versionIter, eval)
+		if err != nil {
+			return err
+		}
+
 		rootKey, err := c.srv.encrypter.GetKey(wrappedKeys.KeyID)
 		if err != nil {
 			return fmt.Errorf("rotated key does not exist in keyring: %w", err)
@@ -1356,6 +1457,63 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 	return nil
 }
 
+// variableVersionsRekeyBatchSize is the number of previous versions of
+// variables re-encrypted per Raft write. Each version may hold up to the
+// maximum size of a variable, so batches are kept small.
+const variableVersionsRekeyBatchSize = 100
+
+// rotateVariableVersions runs over an iterator of previous versions of
+// variables and re-encrypts them with the currently active key, in batches.
+// Versions are immutable, so unlike variables they can't conflict with
+// concurrent writes.
+func (c *CoreScheduler) rotateVariableVersions(iter memdb.ResultIterator, eval *structs.Evaluation) error {
+	var batch []*structs.VariableEncrypted
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		req := &structs.VariableVersionsRekeyRequest{
+			Versions: batch,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: eval.LeaderACL,
+			},
+		}
+		batch = nil
+		return c.srv.RPC("Variables.RekeyVersions", req, &structs.GenericResponse{})
+	}
+
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		version := raw.(*structs.VariableEncrypted)
+		cleartext, err := c.srv.encrypter.Decrypt(version.Data, version.KeyID)
+		if err != nil {
+			return err
+		}
+		data, keyID, err := c.srv.encrypter.Encrypt(cleartext)
+		if err != nil {
+			return err
+		}
+
+		rekeyed := version.Copy()
+		rekeyed.Data = data
+		rekeyed.KeyID = keyID
+		batch = append(batch, &rekeyed)
+
+		if len(batch) == variableVersionsRekeyBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
 // getCutoffTime returns a time.Time of the latest object that should be GCd
 func (c *CoreScheduler) getCutoffTime(configThreshold time.Duration) time.Time {
 	return time.Now().UTC().Add(-1 * configThreshold)
@@ -1863,6 +1863,64 @@ func TestCoreScheduler_DeploymentGC(t *testing.T) {
 	must.NotNil(t, out3)
 }
 
+func TestCoreScheduler_VariableVersionsGC(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// Write several versions of two variables
+	index := uint64(1000)
+	for _, path := range []string{"app/a", "app/b"} {
+		for i := 0; i < 4; i++ {
+			index++
+			sv := mock.VariableEncrypted()
+			sv.Namespace = ns.Name
+			sv.Path = path
+			sv.Data = []byte(fmt.Sprintf("%s-%d", path, i))
+			resp := store.VarSet(index, &structs.VarApplyStateRequest{
+				Op:  structs.VarOpSet,
+				Var: sv,
+			})
+			must.NoError(t, resp.Error)
+		}
+	}
+
+	versions, err := store.GetVariableVersions(nil, ns.Name, "app/a")
+	must.NoError(t, err)
+	must.Len(t, 3, versions)
+
+	// Lower the retention, which only takes effect on the next write or GC
+	ns = ns.Copy()
+	ns.VariablesConfiguration.RetainVersions = 1
+	ns.VariablesConfiguration.PathRetainVersions = map[string]int{"app/b": 0}
+	must.NoError(t, store.UpsertNamespaces(2000, []*structs.Namespace{ns}))
+
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	gc := s1.coreJobEval(structs.CoreJobVariableVersionsGC, 2001)
+	must.NoError(t, core.Process(gc))
+
+	versions, err = store.GetVariableVersions(nil, ns.Name, "app/a")
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, "app/a-2", string(versions[0].Data))
+
+	versions, err = store.GetVariableVersions(nil, ns.Name, "app/b")
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+}
+
 func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	ci.Parallel(t)
 	for _, withAcl := range []bool{false, true} {
@@ -2846,6 +2904,76 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 	), must.Sprint("variable rekey should be complete"))
 }
 
+func TestCoreScheduler_VariablesRekey_Versions(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 1
+	})
+	defer cleanup()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	store := srv.fsm.State()
+	key0, err := store.GetActiveRootKey(nil)
+	must.NoError(t, err)
+	must.NotNil(t, key0)
+
+	ns, err := store.NamespaceByName(nil, structs.DefaultNamespace)
+	must.NoError(t, err)
+	ns = ns.Copy()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{RetainVersions: 5}
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// Write a variable twice so a previous version is encrypted with the
+	// original key
+	for _, value := range []string{"v1", "v2"} {
+		sv := mock.Variable()
+		sv.Items = structs.VariableItems{"key": value}
+		req := &structs.VariablesApplyRequest{
+			Op:           structs.VarOpSet,
+			Var:          sv,
+			WriteRequest: structs.WriteRequest{Region: srv.config.Region},
+		}
+		must.NoError(t, srv.RPC("Variables.Apply", req, &structs.VariablesApplyResponse{}))
+	}
+	versions, err := store.GetVariableVersions(nil, structs.DefaultNamespace, mock.Variable().Path)
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, key0.KeyID, versions[0].KeyID)
+
+	rotateReq := &structs.KeyringRotateRootKeyRequest{
+		Full:         true,
+		WriteRequest: structs.WriteRequest{Region: srv.config.Region},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	must.NoError(t, srv.RPC("Keyring.Rotate", rotateReq, &rotateResp))
+	newKeyID := rotateResp.Key.KeyID
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.Timeout(5*time.Second),
+		wait.Gap(100*time.Millisecond),
+		wait.BoolFunc(func() bool {
+			originalKey, _ := store.RootKeyByID(nil, key0.KeyID)
+			return originalKey.IsInactive()
+		}),
+	), must.Sprint("variable rekey should be complete"))
+
+	// The previous version is re-encrypted with the new key, so the original
+	// key is no longer in use
+	versions, err = store.GetVariableVersions(nil, structs.DefaultNamespace, mock.Variable().Path)
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, newKeyID, versions[0].KeyID)
+
+	cleartext, err := srv.encrypter.Decrypt(versions[0].Data, versions[0].KeyID)
+	must.NoError(t, err)
+	must.Eq(t, `{"key":"v1"}`, string(cleartext))
+
+	inUse, err := store.IsRootKeyInUse(key0.KeyID)
+	must.NoError(t, err)
+	must.False(t, inUse)
+}
+
 func TestCoreScheduler_FailLoop(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// deadlineWatcherMinInterval is the minimum time between two checks of
+	// the job deadlines. It coalesces the many job changes of a busy cluster
+	// into a single check.
+	deadlineWatcherMinInterval = time.Second
+
+	// deadlineWatcherRetryInterval is the time to wait before checking the
+	// job deadlines again after failing to stop a job.
+	deadlineWatcherRetryInterval = 5 * time.Second
+)
+
+// DeadlineStopper is used to stop jobs which exceeded their active deadline.
+type DeadlineStopper interface {
+	// StopDeadlineExceededJob stops the job by deregistering it.
+	StopDeadlineExceededJob(job *structs.Job) error
+}
+
+// DeadlineWatcher enforces the active_deadline of batch jobs. It stops the
+// pending and running jobs which have run for longer than their deadline
+// since they became active, that is since their first submission or their
+// release from the dispatch queue. It only runs on the leader.
+type DeadlineWatcher struct {
+	enabled bool
+	logger  log.Logger
+	stopper DeadlineStopper
+
+	// state is the state store the jobs are read from.
+	state *state.StateStore
+
+	// minInterval is the minimum time between two checks of the deadlines.
+	minInterval time.Duration
+
+	// ctx and exitFn are used to cancel the watcher
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewDeadlineWatcher returns a deadline watcher which stops jobs via the
+// passed stopper once enabled.
+func NewDeadlineWatcher(logger log.Logger, stopper DeadlineStopper) *DeadlineWatcher {
+	return &DeadlineWatcher{
+		logger:      logger.Named("deadline_watcher"),
+		stopper:     stopper,
+		minInterval: deadlineWatcherMinInterval,
+	}
+}
+
+// SetEnabled is used to control if the deadline watcher is enabled. The
+// watcher should only be enabled on the active leader. When being enabled the
+// state is passed in as it is no longer valid once a leader election has taken
+// place.
+func (w *DeadlineWatcher) SetEnabled(enabled bool, state *state.StateStore) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	wasEnabled := w.enabled
+	w.enabled = enabled
+	if state != nil {
+		w.state = state
+	}
+
+	if enabled && !wasEnabled {
+		w.ctx, w.exitFn = context.WithCancel(context.Background())
+		go w.run(w.ctx, w.state)
+	} else if !enabled && wasEnabled {
+		w.exitFn()
+	}
+}
+
+// run stops the jobs which exceeded their deadline whenever the jobs change
+// or the nearest deadline is reached, until the context is canceled.
+func (w *DeadlineWatcher) run(ctx context.Context, store *state.StateStore) {
+	index := uint64(1)
+	for {
+		next, err := w.stopExceeded(store, time.Now())
+		if err != nil {
+			w.logger.Error("failed to stop jobs which exceeded their active deadline", "error", err)
+			next = time.Now().Add(deadlineWatcherRetryInterval)
+		}
+
+		// Block until the jobs change or the nearest deadline is reached
+		queryCtx, cancel := ctx, context.CancelFunc(func() {})
+		if !next.IsZero() {
+			queryCtx, cancel = context.WithDeadline(ctx, next)
+		}
+		_, idx, err := store.BlockingQuery(deadlineWatcherIndex, index, queryCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			index = idx
+		} else if err != context.DeadlineExceeded {
+			w.logger.Error("failed to watch jobs", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.minInterval):
+		}
+	}
+}
+
+// deadlineWatcherIndex blocks on changes to the jobs, which hold their
+// deadline, submission time and status.
+func deadlineWatcherIndex(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	if _, err := store.Jobs(ws, state.SortDefault); err != nil {
+		return nil, 0, err
+	}
+
+	index, err := store.Index("jobs")
+	if err != nil {
+		return nil, 0, err
+	}
+	return nil, index, nil
+}
+
+// stopExceeded stops the jobs which exceeded their deadline at the passed
+// time, and returns the nearest deadline of the remaining jobs or a zero time
+// if none has a deadline.
+func (w *DeadlineWatcher) stopExceeded(store *state.StateStore, now time.Time) (time.Time, error) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		return time.Time{}, err
+	}
+	iter, err := snap.Jobs(nil, state.SortDefault)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		deadline, ok := jobDeadline(job)
+		if !ok {
+			continue
+		}
+
+		if now.Before(deadline) {
+			if next.IsZero() || deadline.Before(next) {
+				next = deadline
+			}
+			continue
+		}
+
+		w.logger.Info("stopping job which exceeded its active deadline",
+			"namespace", job.Namespace, "job_id", job.ID, "active_deadline", job.ActiveDeadline)
+		if err := w.stopper.StopDeadlineExceededJob(job); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return next, nil
+}
+
+// jobDeadline returns the time at which the job exceeds its active deadline,
+// or false if the deadline does not apply to the job. Periodic and
+// parameterized jobs are templates, so the deadline applies to the jobs they
+// launch instead.
+func jobDeadline(job *structs.Job) (time.Time, bool) {
+	if job.ActiveDeadline <= 0 || job.Stop || job.IsPeriodic() || job.IsParameterized() {
+		return time.Time{}, false
+	}
+
+	switch job.Status {
+	case structs.JobStatusPending, structs.JobStatusRunning:
+	default:
+		return time.Time{}, false
+	}
+
+	start := job.ActiveSince
+	if start == 0 {
+		start = job.SubmitTime
+	}
+	return time.Unix(0, start).Add(job.ActiveDeadline), true
+}
+
+// StopDeadlineExceededJob is used to stop a job which exceeded its active
+// deadline by deregistering it via Raft.
+func (s *Server) StopDeadlineExceededJob(job *structs.Job) error {
+	now := time.Now().UTC().UnixNano()
+	req := structs.JobDeregisterRequest{
+		JobID:      job.ID,
+		SubmitTime: now,
+		Eval: &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   job.Namespace,
+			Priority:    job.Priority,
+			Type:        job.Type,
+			TriggeredBy: structs.EvalTriggerJobDeregister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now,
+			ModifyTime:  now,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.config.Region,
+			Namespace: job.Namespace,
+		},
+	}
+	if _, _, err := s.raftApply(structs.JobDeregisterRequestType, req); err != nil {
+		return fmt.Errorf("failed to stop job %q: %v", job.ID, err)
+	}
+	return nil
+}
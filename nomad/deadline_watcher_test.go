@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// testDeadlineStopper records the stopped jobs.
+type testDeadlineStopper struct {
+	jobs []string
+	l    sync.Mutex
+}
+
+func (s *testDeadlineStopper) StopDeadlineExceededJob(job *structs.Job) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.jobs = append(s.jobs, job.ID)
+	return nil
+}
+
+func TestDeadlineWatcher_StopExceeded(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	stopper := &testDeadlineStopper{}
+	w := NewDeadlineWatcher(testlog.HCLogger(t), stopper)
+
+	now := time.Now()
+	job := func(index uint64, submitted time.Time, deadline time.Duration) *structs.Job {
+		job := mock.BatchJob()
+		job.ActiveDeadline = deadline
+		job.SubmitTime = submitted.UnixNano()
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, job))
+		return job
+	}
+
+	exceeded := job(1000, now.Add(-2*time.Hour), time.Hour)
+	pending := job(1001, now.Add(-30*time.Minute), time.Hour)
+	job(1002, now.Add(-2*time.Hour), 0)
+
+	// Stopped jobs and periodic jobs are ignored
+	stopped := job(1003, now.Add(-2*time.Hour), time.Hour).Copy()
+	stopped.Stop = true
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1004, nil, stopped))
+	periodic := mock.PeriodicJob()
+	periodic.ActiveDeadline = time.Hour
+	periodic.SubmitTime = now.Add(-2 * time.Hour).UnixNano()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1005, nil, periodic))
+
+	next, err := w.stopExceeded(store, now)
+	must.NoError(t, err)
+	must.Eq(t, []string{exceeded.ID}, stopper.jobs)
+	must.Eq(t, time.Unix(0, pending.SubmitTime).Add(time.Hour), next)
+}
+
+func TestDeadlineWatcher_ActiveSince(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	stopper := &testDeadlineStopper{}
+	w := NewDeadlineWatcher(testlog.HCLogger(t), stopper)
+
+	now := time.Now()
+	submitted := now.Add(-2 * time.Hour)
+
+	// Updating a running job doesn't extend its deadline
+	updated := mock.BatchJob()
+	updated.ActiveDeadline = time.Hour
+	updated.SubmitTime = submitted.UnixNano()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, updated))
+	updated = updated.Copy()
+	updated.Priority++
+	updated.SubmitTime = now.UnixNano()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, updated))
+
+	// A queued dispatched job is measured from its release
+	queued := mock.BatchJob()
+	queued.ActiveDeadline = time.Hour
+	queued.SubmitTime = submitted.UnixNano()
+	queued.DispatchQueued = true
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1002, nil, queued))
+	released := now.Add(-30 * time.Minute).UnixNano()
+	must.NoError(t, store.ReleaseDispatchedJobs(structs.MsgTypeTestSetup, 1003,
+		&structs.JobDispatchReleaseRequest{Evals: []*structs.Evaluation{{
+			ID:          uuid.Generate(),
+			Namespace:   queued.Namespace,
+			JobID:       queued.ID,
+			Type:        queued.Type,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  released,
+		}}}))
+
+	next, err := w.stopExceeded(store, now)
+	must.NoError(t, err)
+	must.Eq(t, []string{updated.ID}, stopper.jobs)
+	must.Eq(t, time.Unix(0, released).Add(time.Hour), next)
+
+	// Starting a stopped job again resets its deadline
+	stopped := updated.Copy()
+	stopped.Stop = true
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1004, nil, stopped))
+	restarted := updated.Copy()
+	restarted.SubmitTime = now.UnixNano()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1005, nil, restarted))
+
+	out, err := store.JobByID(nil, restarted.Namespace, restarted.ID)
+	must.NoError(t, err)
+	must.Eq(t, now.UnixNano(), out.ActiveSince)
+}
+
+func TestDeadlineWatcher_Leader(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.BatchJob()
+	job.ActiveDeadline = 200 * time.Millisecond
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, s1.RPC("Job.Register", req, &resp))
+
+	// The leader stops the job once its deadline is exceeded
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+			must.NoError(t, err)
+			return out != nil && out.Stop
+		}),
+		wait.Timeout(10*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+}
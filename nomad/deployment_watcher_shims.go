@@ -53,6 +53,11 @@ func (d *deploymentWatcherRaftShim) UpdateDeploymentAllocHealth(req *structs.App
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
 
+func (d *deploymentWatcherRaftShim) UpdateDeploymentAnalysis(req *structs.DeploymentAnalysisUpdateRequest) (uint64, error) {
+	fsmErrIntf, index, raftErr := d.apply(structs.DeploymentAnalysisUpdateRequestType, req)
+	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
+}
+
 func (d *deploymentWatcherRaftShim) UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error) {
 	fsmErrIntf, index, raftErr := d.apply(structs.AllocUpdateDesiredTransitionRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
@@ -0,0 +1,370 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// analysisQueryTimeout is the timeout of each query of a canary analysis.
+	analysisQueryTimeout = 10 * time.Second
+
+	// analysisMaxResponseSize is the maximum size of the response to a query
+	// of a canary analysis.
+	analysisMaxResponseSize = 1 << 20
+)
+
+// analysisClient is the HTTP client used to query the metrics of canary
+// analyses.
+var analysisClient = &http.Client{Timeout: analysisQueryTimeout}
+
+// nextAnalysis returns the time the next canary analysis run of the deployment
+// is due, or the zero time if no run is pending. The analysis of a task group
+// is pending while its canaries are all healthy and haven't been promoted, and
+// the first run happens an interval after that point.
+func (w *deploymentWatcher) nextAnalysis(now time.Time) time.Time {
+	d := w.getDeployment()
+
+	var next time.Time
+	for name, dstate := range d.TaskGroups {
+		analysis := w.groupAnalysis(name)
+		if analysis == nil || d.Status != structs.DeploymentStatusRunning ||
+			dstate.DesiredCanaries == 0 || dstate.Promoted ||
+			dstate.HealthyAllocs < dstate.DesiredCanaries {
+			delete(w.analysisNext, name)
+			continue
+		}
+
+		at, ok := w.analysisNext[name]
+		if !ok {
+			// Resume from the last run recorded in the state, such as after
+			// a leader election, so the interval is respected.
+			start := now
+			if dstate.Analysis != nil && !dstate.Analysis.LastRun.IsZero() {
+				start = dstate.Analysis.LastRun
+			}
+			at = start.Add(analysis.Interval)
+			w.analysisNext[name] = at
+		}
+
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// groupAnalysis returns the canary analysis of the task group, if any.
+func (w *deploymentWatcher) groupAnalysis(name string) *structs.CanaryAnalysis {
+	tg := w.j.LookupTaskGroup(name)
+	if tg == nil || tg.Update == nil {
+		return nil
+	}
+	return tg.Update.Analysis
+}
+
+// analysisRun is a canary analysis of a task group which is due to run.
+type analysisRun struct {
+	name     string
+	analysis *structs.CanaryAnalysis
+	dstate   *structs.DeploymentState
+}
+
+// analysisResult is the outcome of the canary analysis runs started together.
+type analysisResult struct {
+	// fail marks whether the deployment should fail because the failure
+	// limit of an analysis has been exceeded, and rollback whether it should
+	// be rolled back.
+	fail, rollback bool
+
+	err error
+}
+
+// startAnalyses starts the canary analyses which are due in the background,
+// as their queries can take a while, and returns whether any was started. The
+// result of the runs is sent on resultCh once they all completed.
+func (w *deploymentWatcher) startAnalyses(now time.Time, resultCh chan<- *analysisResult) (bool, error) {
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return false, err
+	}
+
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil {
+		return false, err
+	}
+	if d == nil {
+		return false, fmt.Errorf("deployment id not found: %q", w.deploymentID)
+	}
+
+	var runs []*analysisRun
+	for name, at := range w.analysisNext {
+		if at.After(now) {
+			continue
+		}
+		dstate, ok := d.TaskGroups[name]
+		analysis := w.groupAnalysis(name)
+		if !ok || analysis == nil {
+			continue
+		}
+		w.analysisNext[name] = now.Add(analysis.Interval)
+		runs = append(runs, &analysisRun{name: name, analysis: analysis, dstate: dstate})
+	}
+	if len(runs) == 0 {
+		return false, nil
+	}
+
+	allocs, err := snap.AllocsByJob(nil, w.j.Namespace, w.j.ID, false)
+	if err != nil {
+		return false, err
+	}
+
+	go func() {
+		result := w.runAnalyses(now, runs, allocs)
+		select {
+		case resultCh <- result:
+		case <-w.ctx.Done():
+		}
+	}()
+	return true, nil
+}
+
+// runAnalyses runs the given canary analyses and records their results.
+func (w *deploymentWatcher) runAnalyses(now time.Time, runs []*analysisRun, allocs []*structs.Allocation) *analysisResult {
+	result := new(analysisResult)
+	ran := false
+	for _, run := range runs {
+		analysis := w.runAnalysis(run.name, run.analysis, run.dstate, allocs, now)
+		if _, err := w.upsertDeploymentAnalysis(&structs.DeploymentAnalysisUpdateRequest{
+			DeploymentID: w.deploymentID,
+			TaskGroup:    run.name,
+			Analysis:     analysis,
+		}); err != nil {
+			result.err = err
+			return result
+		}
+		ran = true
+
+		w.logger.Debug("canary analysis run", "task_group", run.name,
+			"passed", analysis.Passed(), "passed_runs", analysis.PassedRuns, "failed_runs", analysis.FailedRuns)
+
+		if analysis.FailedRuns > run.analysis.FailureLimit {
+			result.fail = true
+			result.rollback = result.rollback || run.dstate.AutoRevert
+		}
+	}
+
+	// Refresh the tracked deployment so the recorded results gate promotion.
+	if ran {
+		snap, err := w.state.Snapshot()
+		if err != nil {
+			result.err = err
+			return result
+		}
+		d, err := snap.DeploymentByID(nil, w.deploymentID)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		if d != nil {
+			w.updateDeployment(d)
+		}
+	}
+
+	return result
+}
+
+// runAnalysis runs the canary analysis of a task group once and returns its
+// new state.
+func (w *deploymentWatcher) runAnalysis(name string, analysis *structs.CanaryAnalysis,
+	dstate *structs.DeploymentState, allocs []*structs.Allocation, now time.Time) *structs.DeploymentAnalysis {
+
+	canaries := make(map[string]struct{}, len(dstate.PlacedCanaries))
+	for _, id := range dstate.PlacedCanaries {
+		canaries[id] = struct{}{}
+	}
+
+	var stable []string
+	for _, alloc := range allocs {
+		if alloc.TaskGroup != name || alloc.TerminalStatus() ||
+			alloc.ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+		if _, ok := canaries[alloc.ID]; ok {
+			continue
+		}
+		stable = append(stable, alloc.ID)
+	}
+
+	data := func(ids []string) *structs.AnalysisQueryData {
+		return &structs.AnalysisQueryData{
+			AllocIDs:  strings.Join(ids, "|"),
+			Namespace: w.j.Namespace,
+			JobID:     w.j.ID,
+			TaskGroup: name,
+		}
+	}
+	canaryData, stableData := data(dstate.PlacedCanaries), data(stable)
+
+	result := dstate.Analysis.Copy()
+	if result == nil {
+		result = &structs.DeploymentAnalysis{}
+	}
+	result.LastRun = now
+	result.Metrics = make([]*structs.AnalysisMetricResult, 0, len(analysis.Metrics))
+
+	for _, metric := range analysis.Metrics {
+		mr := &structs.AnalysisMetricResult{Name: metric.Name}
+		result.Metrics = append(result.Metrics, mr)
+
+		canary, err := w.queryMetric(analysis, metric, canaryData)
+		if err != nil {
+			mr.Error = fmt.Sprintf("failed to query canaries: %v", err)
+			continue
+		}
+		mr.Canary = canary
+
+		// The ratio can only be checked against stable allocations.
+		if metric.MaxRatio != nil && len(stable) != 0 {
+			stableValue, err := w.queryMetric(analysis, metric, stableData)
+			if err != nil {
+				mr.Error = fmt.Sprintf("failed to query stable allocations: %v", err)
+				continue
+			}
+			mr.Stable = pointer.Of(stableValue)
+		}
+
+		mr.Passed = metric.Check(mr.Canary, mr.Stable)
+	}
+
+	if result.Passed() {
+		result.PassedRuns++
+	} else {
+		result.FailedRuns++
+	}
+	return result
+}
+
+// queryMetric executes the query template of the metric with the given data
+// and returns the single value the Prometheus-compatible API at the address of
+// the analysis returns for it. Only the addresses allowed by the configuration
+// of the server are queried.
+func (w *deploymentWatcher) queryMetric(analysis *structs.CanaryAnalysis, metric *structs.AnalysisMetric,
+	data *structs.AnalysisQueryData) (float64, error) {
+
+	if !analysis.AddressAllowed(w.analysisAddresses) {
+		return 0, fmt.Errorf("address %q is not allowed by the server configuration", analysis.Address)
+	}
+
+	tmpl, err := metric.Template()
+	if err != nil {
+		return 0, err
+	}
+	var query strings.Builder
+	if err := tmpl.Execute(&query, data); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, analysisQueryTimeout)
+	defer cancel()
+
+	u := strings.TrimSuffix(analysis.Address, "/") + "/api/v1/query?" +
+		url.Values{"query": []string{query.String()}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := analysisClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, analysisMaxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	return parseQueryResponse(resp.StatusCode, body)
+}
+
+// queryResponse is the response of the query endpoint of the Prometheus HTTP
+// API.
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// parseQueryResponse returns the single value of a response of the query
+// endpoint. Only scalars and vectors of a single sample are supported.
+func parseQueryResponse(status int, body []byte) (float64, error) {
+	var resp queryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		if status != http.StatusOK {
+			return 0, fmt.Errorf("unexpected response code %d", status)
+		}
+		return 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if resp.Status != "success" {
+		if resp.Error != "" {
+			return 0, errors.New(resp.Error)
+		}
+		return 0, fmt.Errorf("unexpected response code %d", status)
+	}
+
+	var value []any
+	switch resp.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(resp.Data.Result, &value); err != nil {
+			return 0, fmt.Errorf("failed to decode scalar: %v", err)
+		}
+	case "vector":
+		var samples []struct {
+			Value []any `json:"value"`
+		}
+		if err := json.Unmarshal(resp.Data.Result, &samples); err != nil {
+			return 0, fmt.Errorf("failed to decode vector: %v", err)
+		}
+		if len(samples) != 1 {
+			return 0, fmt.Errorf("query returned %d series, expected 1", len(samples))
+		}
+		value = samples[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", resp.Data.ResultType)
+	}
+
+	if len(value) != 2 {
+		return 0, errors.New("malformed sample")
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, errors.New("malformed sample")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) {
+		return 0, errors.New("query returned NaN")
+	}
+	return f, nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// testAnalysisServer returns a stub of the Prometheus query API which returns
+// the canary value for queries matching the canary and the stable value for
+// any other query.
+func testAnalysisServer(t *testing.T, canaryID string, canary, stable float64) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := stable
+		if strings.Contains(r.URL.Query().Get("query"), canaryID) {
+			value = canary
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"%v"]}]}}`, value)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testAnalysisDeployment upserts a job with a canary analysis comparing the
+// error rate of its canary and stable allocations, and a running deployment
+// whose canary is healthy.
+func testAnalysisDeployment(t *testing.T, w *Watcher, m *mockBackend, canary, stable float64) (*structs.Job, *structs.Deployment) {
+	now := time.Now()
+
+	j := mock.Job()
+	j.TaskGroups[0].Count = 1
+	upd := structs.DefaultUpdateStrategy.Copy()
+	upd.Canary = 1
+	upd.AutoPromote = true
+	upd.ProgressDeadline = time.Minute
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups = map[string]*structs.DeploymentState{
+		"web": {
+			AutoPromote:      true,
+			ProgressDeadline: upd.ProgressDeadline,
+			DesiredCanaries:  1,
+			DesiredTotal:     1,
+		},
+	}
+
+	stableAlloc := mock.Alloc()
+	stableAlloc.JobID = j.ID
+	stableAlloc.ClientStatus = structs.AllocClientStatusRunning
+
+	canaryAlloc := mock.Alloc()
+	canaryAlloc.JobID = j.ID
+	canaryAlloc.DeploymentID = d.ID
+	canaryAlloc.CreateTime = now.UnixNano()
+	canaryAlloc.ModifyTime = now.UnixNano()
+	canaryAlloc.ClientStatus = structs.AllocClientStatusRunning
+	canaryAlloc.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	d.TaskGroups["web"].PlacedCanaries = []string{canaryAlloc.ID}
+
+	srv := testAnalysisServer(t, canaryAlloc.ID, canary, stable)
+	w.analysisAddresses = []string{srv.URL}
+	upd.Analysis = &structs.CanaryAnalysis{
+		Address:  srv.URL,
+		Interval: 50 * time.Millisecond,
+		Runs:     2,
+		Metrics: []*structs.AnalysisMetric{{
+			Name:     "error-rate",
+			Query:    `sum(rate(errors{alloc_id=~"{{.AllocIDs}}"}[1m]))`,
+			Max:      pointer.Of(0.1),
+			MaxRatio: pointer.Of(1.5),
+		}},
+	}
+	j.TaskGroups[0].Update = upd
+
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+	stableAlloc.Job, canaryAlloc.Job = j, j
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(),
+		[]*structs.Allocation{stableAlloc, canaryAlloc}))
+	must.NoError(t, m.state.UpdateDeploymentAllocHealth(structs.MsgTypeTestSetup, m.nextIndex(),
+		&structs.ApplyDeploymentAllocHealthRequest{
+			DeploymentAllocHealthRequest: structs.DeploymentAllocHealthRequest{
+				DeploymentID:         d.ID,
+				HealthyAllocationIDs: []string{canaryAlloc.ID},
+			},
+			Timestamp: now,
+		}))
+	return j, d
+}
+
+func TestWatcher_CanaryAnalysis_Promote(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+	_, d := testAnalysisDeployment(t, w, m, 0.05, 0.04)
+
+	w.SetEnabled(true, m.state)
+	waitForWatchers(t, w, 1)
+
+	// The canaries are only promoted once both runs have passed
+	must.Wait(t, wait.InitialSuccess(wait.ErrorFunc(func() error {
+		d, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return err
+		}
+		dstate := d.TaskGroups["web"]
+		if !dstate.Promoted {
+			return fmt.Errorf("expected task group to be promoted")
+		}
+		if dstate.Analysis == nil || dstate.Analysis.PassedRuns != 2 || dstate.Analysis.FailedRuns != 0 {
+			return fmt.Errorf("expected 2 passed runs: %#v", dstate.Analysis)
+		}
+		return nil
+	}), wait.Gap(10*time.Millisecond), wait.Timeout(5*time.Second)))
+
+	d, err := m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.DeploymentStatusRunning, d.Status)
+
+	result := d.TaskGroups["web"].Analysis.Metrics
+	must.Len(t, 1, result)
+	must.Eq(t, "error-rate", result[0].Name)
+	must.Eq(t, 0.05, result[0].Canary)
+	must.Eq(t, pointer.Of(0.04), result[0].Stable)
+	must.True(t, result[0].Passed)
+}
+
+func TestWatcher_CanaryAnalysis_Fail(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// The canaries are below the max but above the max ratio
+	_, d := testAnalysisDeployment(t, w, m, 0.08, 0.02)
+
+	w.SetEnabled(true, m.state)
+	waitForWatchers(t, w, 1)
+
+	must.Wait(t, wait.InitialSuccess(wait.ErrorFunc(func() error {
+		d, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return err
+		}
+		if d.Status != structs.DeploymentStatusFailed {
+			return fmt.Errorf("expected deployment to fail: %q", d.Status)
+		}
+		return nil
+	}), wait.Gap(10*time.Millisecond), wait.Timeout(5*time.Second)))
+
+	d, err := m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.DeploymentStatusDescriptionFailedAnalysis, d.StatusDescription)
+
+	dstate := d.TaskGroups["web"]
+	must.False(t, dstate.Promoted)
+	must.Eq(t, 1, dstate.Analysis.FailedRuns)
+	must.False(t, dstate.Analysis.Metrics[0].Passed)
+}
+
+func TestWatcher_CanaryAnalysis_AddressNotAllowed(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+	_, d := testAnalysisDeployment(t, w, m, 0.05, 0.04)
+
+	// The address of the analysis isn't allowed by the server anymore, so
+	// the queries aren't sent and the run fails
+	w.analysisAddresses = []string{"http://prometheus.example:9090"}
+
+	w.SetEnabled(true, m.state)
+	waitForWatchers(t, w, 1)
+
+	must.Wait(t, wait.InitialSuccess(wait.ErrorFunc(func() error {
+		d, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return err
+		}
+		if d.Status != structs.DeploymentStatusFailed {
+			return fmt.Errorf("expected deployment to fail: %q", d.Status)
+		}
+		return nil
+	}), wait.Gap(10*time.Millisecond), wait.Timeout(5*time.Second)))
+
+	d, err := m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	result := d.TaskGroups["web"].Analysis.Metrics
+	must.Len(t, 1, result)
+	must.StrContains(t, result[0].Error, "is not allowed by the server configuration")
+}
+
+func TestParseQueryResponse(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+		exp    float64
+		expErr string
+	}{
+		{
+			name:   "vector",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"0.5"]}]}}`,
+			exp:    0.5,
+		},
+		{
+			name:   "scalar",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"scalar","result":[1,"2"]}}`,
+			exp:    2,
+		},
+		{
+			name:   "empty vector",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			expErr: "query returned 0 series, expected 1",
+		},
+		{
+			name:   "NaN",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"scalar","result":[1,"NaN"]}}`,
+			expErr: "query returned NaN",
+		},
+		{
+			name:   "matrix",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			expErr: `unsupported result type "matrix"`,
+		},
+		{
+			name:   "query error",
+			status: http.StatusBadRequest,
+			body:   `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			expErr: "parse error",
+		},
+		{
+			name:   "not json",
+			status: http.StatusBadGateway,
+			body:   `bad gateway`,
+			expErr: "unexpected response code 502",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := parseQueryResponse(tc.status, []byte(tc.body))
+			if tc.expErr != "" {
+				must.EqError(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, value)
+		})
+	}
+}
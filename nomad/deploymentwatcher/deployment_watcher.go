@@ -53,6 +53,10 @@ type deploymentTriggers interface {
 	// upsertDeploymentAllocHealth is used to set the health of allocations in a
 	// deployment
 	upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error)
+
+	// upsertDeploymentAnalysis is used to record the result of a canary
+	// analysis run
+	upsertDeploymentAnalysis(req *structs.DeploymentAnalysisUpdateRequest) (uint64, error)
 }
 
 // deploymentWatcher is used to watch a single deployment and trigger the
@@ -101,6 +105,14 @@ type deploymentWatcher struct {
 	// by holding the lock or using the setter and getter methods.
 	latestEval uint64
 
+	// analysisNext is the time the next canary analysis run of each task
+	// group is due. It is only accessed by the watch loop.
+	analysisNext map[string]time.Time
+
+	// analysisAddresses are the addresses the canary analyses are allowed to
+	// query.
+	analysisAddresses []string
+
 	logger log.Logger
 	ctx    context.Context
 	exitFn context.CancelFunc
@@ -112,7 +124,7 @@ type deploymentWatcher struct {
 func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 	logger log.Logger, state *state.StateStore, d *structs.Deployment,
 	j *structs.Job, triggers deploymentTriggers,
-	deploymentRPC DeploymentRPC, jobRPC JobRPC) *deploymentWatcher {
+	deploymentRPC DeploymentRPC, jobRPC JobRPC, analysisAddresses []string) *deploymentWatcher {
 
 	ctx, exitFn := context.WithCancel(parent)
 	w := &deploymentWatcher{
@@ -125,6 +137,8 @@ func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 		deploymentTriggers: triggers,
 		DeploymentRPC:      deploymentRPC,
 		JobRPC:             jobRPC,
+		analysisNext:       make(map[string]time.Time),
+		analysisAddresses:  analysisAddresses,
 		logger:             logger.With("deployment_id", d.ID, "job", j.NamespacedID()),
 		ctx:                ctx,
 		exitFn:             exitFn,
@@ -288,7 +302,7 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 
 	// AutoPromote iff every task group with canaries is marked auto_promote and is healthy. The whole
 	// job version has been incremented, so we promote together. See also AutoRevert
-	for _, dstate := range d.TaskGroups {
+	for name, dstate := range d.TaskGroups {
 
 		// skip auto promote canary validation if the task group has no canaries
 		// to prevent auto promote hanging on mixed canary/non-canary taskgroup deploys
@@ -312,6 +326,13 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 		if healthyCanaries != dstate.DesiredCanaries {
 			return nil
 		}
+
+		// Groups with a canary analysis are only promoted once enough runs
+		// have passed
+		if analysis := w.groupAnalysis(name); analysis != nil &&
+			(dstate.Analysis == nil || dstate.Analysis.PassedRuns < analysis.Runs) {
+			return nil
+		}
 	}
 
 	// Send the request
@@ -433,10 +454,23 @@ func (w *deploymentWatcher) watch() {
 	allocsCh := w.getAllocsCh(allocIndex)
 	var updates *allocUpdates
 
-	rollback, deadlineHit := false, false
+	rollback, deadlineHit, analysisFailed := false, false, false
+
+	// The canary analyses run in the background so their queries don't delay
+	// the handling of the deployment. Only one set of runs is in flight at a
+	// time.
+	analysisResultCh := make(chan *analysisResult, 1)
+	analysisRunning := false
 
 FAIL:
 	for {
+		// The timer of the next canary analysis run is recreated on every
+		// iteration because the analyses become due as canaries turn healthy.
+		var analysisCh <-chan time.Time
+		if next := w.nextAnalysis(time.Now()); !next.IsZero() && !analysisRunning {
+			analysisCh = time.After(time.Until(next))
+		}
+
 		select {
 		case <-w.ctx.Done():
 			// This is the successful case, and we stop the loop
@@ -466,6 +500,47 @@ FAIL:
 				w.logger.Error("multiregion deployment error", "error", err)
 			}
 			break FAIL
+		case now := <-analysisCh:
+			started, err := w.startAnalyses(now, analysisResultCh)
+			if err != nil {
+				if w.ctx.Err() != nil {
+					return
+				}
+				w.logger.Error("failed to start canary analysis", "error", err)
+				continue
+			}
+			analysisRunning = started
+		case result := <-analysisResultCh:
+			analysisRunning = false
+			if err := result.err; err != nil {
+				if w.ctx.Err() != nil {
+					return
+				}
+				w.logger.Error("failed to run canary analysis", "error", err)
+				continue
+			}
+			if result.fail {
+				w.logger.Debug("canary analysis failed", "rollback", result.rollback)
+				rollback, analysisFailed = result.rollback, true
+				err := w.nextRegion(structs.DeploymentStatusFailed)
+				if err != nil {
+					w.logger.Error("multiregion deployment error", "error", err)
+				}
+				break FAIL
+			}
+
+			// A passing run may allow this canary deployment to be promoted
+			allocs, _, err := w.getAllocs(0)
+			if err != nil {
+				if w.ctx.Err() != nil {
+					return
+				}
+				w.logger.Error("failed to retrieve allocations", "error", err)
+				continue
+			}
+			if err := w.autoPromoteDeployment(allocs); err != nil {
+				w.logger.Error("failed to auto promote deployment", "error", err)
+			}
 		case <-w.deploymentUpdateCh:
 			// Get the updated deployment and check if we should change the
 			// deadline timer
@@ -554,7 +629,9 @@ FAIL:
 
 	// Change the deployments status to failed
 	desc := structs.DeploymentStatusDescriptionFailedAllocations
-	if deadlineHit {
+	if analysisFailed {
+		desc = structs.DeploymentStatusDescriptionFailedAnalysis
+	} else if deadlineHit {
 		desc = structs.DeploymentStatusDescriptionProgressDeadline
 	}
 
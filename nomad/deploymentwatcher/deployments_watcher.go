@@ -52,6 +52,10 @@ type DeploymentRaftEndpoints interface {
 	// deployment
 	UpdateDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error)
 
+	// UpdateDeploymentAnalysis is used to record the result of a canary
+	// analysis run of a task group in a deployment
+	UpdateDeploymentAnalysis(req *structs.DeploymentAnalysisUpdateRequest) (uint64, error)
+
 	// UpdateAllocDesiredTransition is used to update the desired transition
 	// for allocations.
 	UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error)
@@ -84,6 +88,10 @@ type Watcher struct {
 	// server interface for Job RPCs
 	jobRPC JobRPC
 
+	// analysisAddresses are the addresses the canary analyses of deployments
+	// are allowed to query
+	analysisAddresses []string
+
 	// watchers is the set of active watchers, one per deployment
 	watchers map[string]*deploymentWatcher
 
@@ -103,6 +111,7 @@ type Watcher struct {
 func NewDeploymentsWatcher(logger log.Logger,
 	raft DeploymentRaftEndpoints,
 	deploymentRPC DeploymentRPC, jobRPC JobRPC,
+	analysisAddresses []string,
 	stateQueriesPerSecond float64,
 	updateBatchDuration time.Duration,
 ) *Watcher {
@@ -111,6 +120,7 @@ func NewDeploymentsWatcher(logger log.Logger,
 		raft:                raft,
 		deploymentRPC:       deploymentRPC,
 		jobRPC:              jobRPC,
+		analysisAddresses:   analysisAddresses,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
 		updateBatchDuration: updateBatchDuration,
 		logger:              logger.Named("deployments_watcher"),
@@ -301,7 +311,7 @@ func (w *Watcher) addLocked(d *structs.Deployment) (*deploymentWatcher, error) {
 	}
 
 	watcher := newDeploymentWatcher(w.ctx, w.queryLimiter, w.logger, w.state, d, job,
-		w, w.deploymentRPC, w.jobRPC)
+		w, w.deploymentRPC, w.jobRPC, w.analysisAddresses)
 	w.watchers[d.ID] = watcher
 	return watcher, nil
 }
@@ -482,3 +492,8 @@ func (w *Watcher) upsertDeploymentPromotion(req *structs.ApplyDeploymentPromoteR
 func (w *Watcher) upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error) {
 	return w.raft.UpdateDeploymentAllocHealth(req)
 }
+
+// upsertDeploymentAnalysis commits the given canary analysis result to Raft
+func (w *Watcher) upsertDeploymentAnalysis(req *structs.DeploymentAnalysisUpdateRequest) (uint64, error) {
+	return w.raft.UpdateDeploymentAnalysis(req)
+}
@@ -23,7 +23,7 @@ import (
 
 func testDeploymentWatcher(t *testing.T, qps float64, batchDur time.Duration) (*Watcher, *mockBackend) {
 	m := newMockBackend(t)
-	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, nil, nil, qps, batchDur)
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, nil, nil, nil, qps, batchDur)
 	return w, m
 }
 
@@ -399,6 +399,136 @@ func TestWatcher_PromoteDeployment_UnhealthyCanaries(t *testing.T) {
 	must.False(t, d.TaskGroups["web"].Promoted)
 }
 
+// Test promoting the canary deployment of a system job
+func TestWatcher_PromoteDeployment_SystemJob(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a system job, canary alloc, and a deployment
+	j := mock.SystemJob()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	j, err := m.state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+
+	d := structs.NewDeployment(j, 50, time.Now().UnixNano())
+	a := mock.SystemAlloc()
+	a.Job = j
+	a.JobID = j.ID
+	a.DeploymentID = d.ID
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: pointer.Of(true),
+		Canary:  true,
+	}
+	d.TaskGroups[a.TaskGroup] = &structs.DeploymentState{
+		DesiredCanaries: 1,
+		DesiredTotal:    3,
+		PlacedCanaries:  []string{a.ID},
+	}
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a}))
+
+	w.SetEnabled(true, m.state)
+	waitForWatchers(t, w, 1)
+
+	// manually promote
+	req := &structs.DeploymentPromoteRequest{
+		DeploymentID: d.ID,
+		All:          true,
+	}
+	var resp structs.DeploymentUpdateResponse
+	must.NoError(t, w.PromoteDeployment(req, &resp))
+	must.Eq(t, 1, watchersCount(w), must.Sprint("watcher should still be active"))
+
+	d, err = m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.True(t, d.TaskGroups[a.TaskGroup].Promoted)
+	must.Eq(t, structs.DeploymentStatusRunning, d.Status)
+}
+
+// Test that an unhealthy canary of a system job reverts the job to its first
+// version, which was marked stable by its own deployment
+func TestWatcher_SetAllocHealth_Unhealthy_Rollback_SystemJob(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a system job and the successful deployment of its first version
+	j := mock.SystemJob()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.AutoRevert = true
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	j, err := m.state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+
+	d1 := structs.NewDeployment(j, 50, time.Now().UnixNano())
+	d1.TaskGroups["web"] = &structs.DeploymentState{AutoRevert: true, DesiredTotal: 1}
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d1))
+	must.NoError(t, m.state.UpdateDeploymentStatus(structs.MsgTypeTestSetup, m.nextIndex(),
+		&structs.DeploymentStatusUpdateRequest{
+			DeploymentUpdate: &structs.DeploymentStatusUpdate{
+				DeploymentID:      d1.ID,
+				Status:            structs.DeploymentStatusSuccessful,
+				StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+			},
+		}))
+
+	out, err := m.state.JobByIDAndVersion(nil, j.Namespace, j.ID, 0)
+	must.NoError(t, err)
+	must.True(t, out.Stable, must.Sprint("first version should be stable"))
+
+	// Update the job and start the canary deployment of its new version
+	j2 := j.Copy()
+	j2.Stable = false
+	j2.Meta["foo"] = "bar"
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j2))
+	j2, err = m.state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+
+	d2 := structs.NewDeployment(j2, 50, time.Now().UnixNano())
+	a := mock.SystemAlloc()
+	a.Job = j2
+	a.JobID = j2.ID
+	a.DeploymentID = d2.ID
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	d2.TaskGroups[a.TaskGroup] = &structs.DeploymentState{
+		AutoRevert:      true,
+		DesiredCanaries: 1,
+		DesiredTotal:    1,
+		PlacedCanaries:  []string{a.ID},
+	}
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d2))
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a}))
+
+	w.SetEnabled(true, m.state)
+	waitForWatchers(t, w, 1)
+
+	// manually set the canary unhealthy
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:           d2.ID,
+		UnhealthyAllocationIDs: []string{a.ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	must.NoError(t, w.SetAllocHealth(req, &resp))
+
+	waitForWatchers(t, w, 0)
+
+	d2, err = m.state.DeploymentByID(nil, d2.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.DeploymentStatusFailed, d2.Status)
+	must.Eq(t, structs.DeploymentStatusDescriptionRollback(
+		structs.DeploymentStatusDescriptionFailedAllocations, 0), d2.StatusDescription)
+
+	// The job is reverted to the spec of its first version
+	out, err = m.state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+	must.Eq(t, 2, out.Version)
+	must.MapNotContainsKey(t, out.Meta, "foo")
+}
+
 func TestWatcher_AutoPromoteDeployment(t *testing.T) {
 	ci.Parallel(t)
 	w, m := defaultTestDeploymentWatcher(t)
@@ -79,3 +79,9 @@ func (m *mockBackend) UpdateDeploymentAllocHealth(req *structs.ApplyDeploymentAl
 	i := m.nextIndex()
 	return i, m.state.UpdateDeploymentAllocHealth(structs.MsgTypeTestSetup, i, req)
 }
+
+func (m *mockBackend) UpdateDeploymentAnalysis(req *structs.DeploymentAnalysisUpdateRequest) (uint64, error) {
+	m.trackCall("UpdateDeploymentAnalysis")
+	i := m.nextIndex()
+	return i, m.state.UpdateDeploymentAnalysis(structs.MsgTypeTestSetup, i, req)
+}
@@ -0,0 +1,381 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dispatchQueueMinInterval is the minimum time between two checks of the
+// queued dispatched jobs. It coalesces the many job summary changes of a busy
+// cluster into a single check.
+const dispatchQueueMinInterval = time.Second
+
+// DispatchReleaser is used to release queued dispatched jobs.
+type DispatchReleaser interface {
+	// ReleaseDispatchedJobs clears the queued flag of the jobs of the passed
+	// evaluations and creates the evaluations.
+	ReleaseDispatchedJobs(evals []*structs.Evaluation) (uint64, error)
+}
+
+// DispatchQueue enforces the max_concurrent limit of parameterized jobs. Jobs
+// dispatched beyond the limit are persisted as queued children without an
+// evaluation, and the queue releases them by priority and then in dispatch
+// order as the running children of their parent complete. It only runs on the
+// leader.
+type DispatchQueue struct {
+	enabled  bool
+	logger   log.Logger
+	releaser DispatchReleaser
+
+	// state is the state store the jobs and job summaries are read from.
+	state *state.StateStore
+
+	// minInterval is the minimum time between two checks of the queued jobs.
+	minInterval time.Duration
+
+	// parents is the set of parameterized jobs with queued children, which
+	// are the only jobs the queue watches.
+	parents map[structs.NamespacedID]struct{}
+
+	// parentLocks serializes the admission of the jobs dispatched from each
+	// parameterized job with their release, so that the limit is never
+	// exceeded.
+	parentLocks map[structs.NamespacedID]*parentLock
+
+	// trackCh is signaled when a parent is added to the watched parents.
+	trackCh chan struct{}
+
+	// ctx and exitFn are used to cancel the queue
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// parentLock is the lock of a parameterized job, which is removed once no
+// dispatch or release holds or waits on it.
+type parentLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewDispatchQueue returns a dispatch queue which releases jobs via the
+// passed releaser once enabled.
+func NewDispatchQueue(logger log.Logger, releaser DispatchReleaser) *DispatchQueue {
+	return &DispatchQueue{
+		logger:      logger.Named("dispatch_queue"),
+		releaser:    releaser,
+		minInterval: dispatchQueueMinInterval,
+		parents:     make(map[structs.NamespacedID]struct{}),
+		parentLocks: make(map[structs.NamespacedID]*parentLock),
+		trackCh:     make(chan struct{}, 1),
+	}
+}
+
+// SetEnabled is used to control if the dispatch queue is enabled. The queue
+// should only be enabled on the active leader. When being enabled the state is
+// passed in as it is no longer valid once a leader election has taken place.
+func (d *DispatchQueue) SetEnabled(enabled bool, state *state.StateStore) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	wasEnabled := d.enabled
+	d.enabled = enabled
+	if state != nil {
+		d.state = state
+	}
+
+	if enabled && !wasEnabled {
+		d.ctx, d.exitFn = context.WithCancel(context.Background())
+		d.parents = make(map[structs.NamespacedID]struct{})
+		go d.run(d.ctx, d.state)
+	} else if !enabled && wasEnabled {
+		d.exitFn()
+	}
+}
+
+// Admit determines whether a job dispatched from the passed parameterized job
+// must be queued. The returned function must be called once the dispatched
+// job has been committed, and until then no other job of the same parent is
+// admitted or released. Once some children are queued, new dispatches are
+// queued behind them even if there is room, so that jobs are released in
+// order.
+func (d *DispatchQueue) Admit(store *state.StateStore, parent *structs.Job) (bool, func(), error) {
+	limit := dispatchLimit(parent)
+	if limit == 0 {
+		return false, func() {}, nil
+	}
+
+	id := parent.NamespacedID()
+	unlock := d.lockParent(id)
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		unlock()
+		return false, nil, err
+	}
+	active, queued, err := dispatchedChildren(snap, parent)
+	if err != nil {
+		unlock()
+		return false, nil, err
+	}
+
+	queue := len(queued) > 0 || active >= limit
+	if !queue {
+		return false, unlock, nil
+	}
+
+	// Watch the parent once the queued job is committed
+	return true, func() {
+		d.track(id)
+		unlock()
+	}, nil
+}
+
+// lockParent locks the parameterized job and returns the function unlocking
+// it.
+func (d *DispatchQueue) lockParent(id structs.NamespacedID) func() {
+	d.l.Lock()
+	lock, ok := d.parentLocks[id]
+	if !ok {
+		lock = &parentLock{}
+		d.parentLocks[id] = lock
+	}
+	lock.refs++
+	d.l.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		d.l.Lock()
+		defer d.l.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(d.parentLocks, id)
+		}
+	}
+}
+
+// track adds the parameterized job to the watched parents.
+func (d *DispatchQueue) track(id structs.NamespacedID) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if _, ok := d.parents[id]; ok {
+		return
+	}
+	d.parents[id] = struct{}{}
+	select {
+	case d.trackCh <- struct{}{}:
+	default:
+	}
+}
+
+// untrack removes the parameterized job from the watched parents.
+func (d *DispatchQueue) untrack(id structs.NamespacedID) {
+	d.l.Lock()
+	defer d.l.Unlock()
+	delete(d.parents, id)
+}
+
+// trackedParents returns the watched parents.
+func (d *DispatchQueue) trackedParents() []structs.NamespacedID {
+	d.l.Lock()
+	defer d.l.Unlock()
+	return slices.Collect(maps.Keys(d.parents))
+}
+
+// run releases queued jobs whenever the job summaries or the jobs of the
+// watched parents change, until the context is canceled.
+func (d *DispatchQueue) run(ctx context.Context, store *state.StateStore) {
+	// Find the parents queued children were dispatched from before this
+	// server became the leader
+	if err := d.trackQueued(store); err != nil {
+		d.logger.Error("failed to find queued dispatched jobs", "error", err)
+	}
+
+	for {
+		select {
+		case <-d.trackCh:
+		default:
+		}
+
+		ws := memdb.NewWatchSet()
+		ws.Add(d.trackCh)
+		ws.Add(store.AbandonCh())
+		if err := d.release(store, ws); err != nil {
+			// Retry the release after the min interval
+			d.logger.Error("failed to release queued dispatched jobs", "error", err)
+		} else if err := ws.WatchCtx(ctx); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.minInterval):
+		}
+	}
+}
+
+// trackQueued watches every parameterized job with queued children.
+func (d *DispatchQueue) trackQueued(store *state.StateStore) error {
+	iter, err := store.JobSummaries(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		summary := raw.(*structs.JobSummary)
+		if summary.Children != nil && summary.Children.Queued > 0 {
+			d.track(structs.NewNamespacedID(summary.JobID, summary.Namespace))
+		}
+	}
+	return nil
+}
+
+// release creates the evaluations of the queued jobs of every watched parent
+// which has room below its limit, and adds the job summaries and jobs of the
+// parents to the watch set. Parents without queued children are no longer
+// watched.
+func (d *DispatchQueue) release(store *state.StateStore, ws memdb.WatchSet) error {
+	for _, id := range d.trackedParents() {
+		if err := d.releaseParent(store, ws, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseParent creates the evaluations of the queued jobs of the parent
+// which fit below its limit.
+func (d *DispatchQueue) releaseParent(store *state.StateStore, ws memdb.WatchSet, id structs.NamespacedID) error {
+	unlock := d.lockParent(id)
+	defer unlock()
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		return err
+	}
+	if _, err := snap.JobSummaryByID(ws, id.Namespace, id.ID); err != nil {
+		return err
+	}
+	parent, err := snap.JobByID(ws, id.Namespace, id.ID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		d.untrack(id)
+		return nil
+	}
+
+	active, queued, err := dispatchedChildren(snap, parent)
+	if err != nil {
+		return err
+	}
+
+	// Release every queued job if the limit was removed from the parent
+	n := len(queued)
+	if limit := dispatchLimit(parent); limit > 0 {
+		n = min(n, max(limit-active, 0))
+	}
+	if n == len(queued) {
+		d.untrack(id)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	evals := make([]*structs.Evaluation, 0, n)
+	for _, child := range queued[:n] {
+		evals = append(evals, &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      child.Namespace,
+			Priority:       child.Priority,
+			Type:           child.Type,
+			TriggeredBy:    structs.EvalTriggerJobRegister,
+			JobID:          child.ID,
+			JobModifyIndex: child.JobModifyIndex,
+			Status:         structs.EvalStatusPending,
+			CreateTime:     now,
+			ModifyTime:     now,
+		})
+	}
+
+	d.logger.Debug("releasing queued dispatched jobs", "namespace", id.Namespace,
+		"job_id", id.ID, "count", len(evals))
+	if _, err := d.releaser.ReleaseDispatchedJobs(evals); err != nil {
+		d.track(id)
+		return err
+	}
+	return nil
+}
+
+// dispatchLimit returns the max_concurrent limit of the parameterized job, or
+// zero if its dispatched jobs are not limited.
+func dispatchLimit(parent *structs.Job) int {
+	if parent.ParameterizedJob == nil {
+		return 0
+	}
+	return parent.ParameterizedJob.MaxConcurrent
+}
+
+// dispatchedChildren returns the number of pending or running children of the
+// parameterized job, and its queued children in the order they are released:
+// by descending priority and then by dispatch order.
+func dispatchedChildren(snap *state.StateSnapshot, parent *structs.Job) (int, []*structs.Job, error) {
+	iter, err := snap.JobsByIDPrefix(nil, parent.Namespace, parent.ID+structs.DispatchLaunchSuffix, state.SortDefault)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	active := 0
+	var queued []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		child := raw.(*structs.Job)
+		if child.ParentID != parent.ID {
+			continue
+		}
+
+		switch child.Status {
+		case structs.JobStatusQueued:
+			queued = append(queued, child)
+		case structs.JobStatusPending, structs.JobStatusRunning:
+			active++
+		}
+	}
+
+	sort.SliceStable(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].CreateIndex < queued[j].CreateIndex
+	})
+
+	return active, queued, nil
+}
+
+// ReleaseDispatchedJobs is used to release queued dispatched jobs by clearing
+// their queued flag and creating their evaluations via Raft.
+func (s *Server) ReleaseDispatchedJobs(evals []*structs.Evaluation) (uint64, error) {
+	req := &structs.JobDispatchReleaseRequest{
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: s.config.Region},
+	}
+	_, index, err := s.raftApply(structs.JobDispatchReleaseRequestType, req)
+	return index, err
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testDispatchReleaser records the released evaluations.
+type testDispatchReleaser struct {
+	evals []*structs.Evaluation
+	l     sync.Mutex
+}
+
+func (r *testDispatchReleaser) ReleaseDispatchedJobs(evals []*structs.Evaluation) (uint64, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.evals = append(r.evals, evals...)
+	return 0, nil
+}
+
+func TestDispatchQueue_Release(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	releaser := &testDispatchReleaser{}
+	d := NewDispatchQueue(testlog.HCLogger(t), releaser)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{MaxConcurrent: 2}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, parent))
+
+	// Nothing is queued while the parent has room
+	queued, done, err := d.Admit(store, parent)
+	must.NoError(t, err)
+	must.False(t, queued)
+	done()
+
+	child := func(index uint64, priority int, dispatchQueued bool) *structs.Job {
+		job := mock.BatchJob()
+		job.ID = structs.DispatchedID(parent.ID, "", time.Now())
+		job.ParentID = parent.ID
+		job.Status = ""
+		job.Priority = priority
+		job.Dispatched = true
+		job.DispatchQueued = dispatchQueued
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, job))
+		return job
+	}
+
+	// One pending child and three queued children
+	child(1001, 50, false)
+	fifo := child(1002, 50, true)
+	child(1003, 50, true)
+	urgent := child(1004, 70, true)
+
+	// New dispatches are queued behind the queued children
+	queued, done, err = d.Admit(store, parent)
+	must.NoError(t, err)
+	must.True(t, queued)
+	done()
+
+	// Only the room left below the limit is released, by priority first
+	must.NoError(t, d.release(store, nil))
+	must.Len(t, 1, releaser.evals)
+	must.Eq(t, urgent.ID, releaser.evals[0].JobID)
+	must.Eq(t, structs.EvalTriggerJobRegister, releaser.evals[0].TriggeredBy)
+
+	// Removing the limit releases the remaining queued children in order
+	releaser.evals = nil
+	parent = parent.Copy()
+	parent.ParameterizedJob.MaxConcurrent = 0
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1005, nil, parent))
+
+	must.NoError(t, d.release(store, nil))
+	must.Len(t, 3, releaser.evals)
+	must.Eq(t, urgent.ID, releaser.evals[0].JobID)
+	must.Eq(t, fifo.ID, releaser.evals[1].JobID)
+
+	// Parents without queued children left aren't watched anymore
+	must.MapEmpty(t, d.parents)
+}
+
+func TestDispatchQueue_AdmitPerParent(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	d := NewDispatchQueue(testlog.HCLogger(t), &testDispatchReleaser{})
+
+	parents := []*structs.Job{mock.BatchJob(), mock.BatchJob()}
+	for i, parent := range parents {
+		parent.ParameterizedJob = &structs.ParameterizedJobConfig{MaxConcurrent: 1}
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, uint64(1000+i), nil, parent))
+	}
+
+	// A dispatch which hasn't been committed yet holds its parent only
+	_, done, err := d.Admit(store, parents[0])
+	must.NoError(t, err)
+
+	admitted := make(chan struct{})
+	go func() {
+		_, done, err := d.Admit(store, parents[1])
+		must.NoError(t, err)
+		done()
+		close(admitted)
+	}()
+	select {
+	case <-admitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatch of another parent was blocked")
+	}
+
+	admitted = make(chan struct{})
+	go func() {
+		_, done, err := d.Admit(store, parents[0])
+		must.NoError(t, err)
+		done()
+		close(admitted)
+	}()
+	select {
+	case <-admitted:
+		t.Fatal("dispatch of the same parent wasn't blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	done()
+	select {
+	case <-admitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatch wasn't admitted once the parent was released")
+	}
+	must.MapEmpty(t, d.parentLocks)
+}
@@ -8,15 +8,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
 	metrics "github.com/hashicorp/go-metrics/compat"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/broker"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/delayheap"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -44,7 +48,9 @@ var (
 // created, due to a change in a job specification or a node, we put it into the
 // broker. The broker sorts by evaluations by priority and scheduler type. This
 // allows us to dequeue the highest priority work first, while also allowing sub-schedulers
-// to only dequeue work they know how to handle. The broker is designed to be entirely
+// to only dequeue work they know how to handle. With the fair share dequeue policy,
+// the broker instead takes turns dequeuing the work of each namespace, so a single
+// namespace can't starve the others. The broker is designed to be entirely
 // in-memory and is managed by the leader node.
 //
 // The broker must provide at-least-once delivery semantics. It relies on explicit
@@ -74,8 +80,14 @@ type EvalBroker struct {
 	// now safe for the Eval.Ack RPC to cancel in batches
 	cancelable []*structs.Evaluation
 
-	// ready tracks the ready jobs by scheduler in a priority queue
-	ready map[string]ReadyEvaluations
+	// ready tracks the ready jobs by scheduler in a queue ordered by the
+	// dequeue policy
+	ready map[string]readyQueue
+
+	// dequeuePolicy and namespaceWeights determine the order in which ready
+	// evaluations are dequeued
+	dequeuePolicy    structs.EvalDequeuePolicy
+	namespaceWeights map[string]int
 
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
@@ -157,7 +169,8 @@ func NewEvalBroker(ctx context.Context, timeout, initialNackDelay, subsequentNac
 		jobEvals:             make(map[structs.NamespacedID]string),
 		pending:              make(map[structs.NamespacedID]PendingEvaluations),
 		cancelable:           make([]*structs.Evaluation, 0, structs.MaxUUIDsPerWriteRequest),
-		ready:                make(map[string]ReadyEvaluations),
+		ready:                make(map[string]readyQueue),
+		dequeuePolicy:        structs.EvalDequeuePolicyPriority,
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
@@ -170,6 +183,7 @@ func NewEvalBroker(ctx context.Context, timeout, initialNackDelay, subsequentNac
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 
 	return b, nil
@@ -210,8 +224,47 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	b.enabledNotifier.Notify("eval broker enabled status changed to " + strconv.FormatBool(enabled))
 }
 
+// SetDequeuePolicy sets the order in which ready evaluations are dequeued. The
+// namespace weights are only used by the fair share policy. Evaluations which
+// are already ready are requeued according to the new policy.
+func (b *EvalBroker) SetDequeuePolicy(policy structs.EvalDequeuePolicy, namespaceWeights map[string]int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if policy == "" {
+		policy = structs.EvalDequeuePolicyPriority
+	}
+	if policy == b.dequeuePolicy && maps.Equal(namespaceWeights, b.namespaceWeights) {
+		return
+	}
+	b.dequeuePolicy = policy
+	b.namespaceWeights = maps.Clone(namespaceWeights)
+
+	for sched, oldQueue := range b.ready {
+		readyQueue := b.newReadyQueue()
+		for _, eval := range oldQueue.Evals() {
+			readyQueue.Push(eval)
+		}
+		b.ready[sched] = readyQueue
+	}
+}
+
+// newReadyQueue returns an empty ready queue for the dequeue policy. It must
+// be called with the lock held.
+func (b *EvalBroker) newReadyQueue() readyQueue {
+	if b.dequeuePolicy == structs.EvalDequeuePolicyFairShare {
+		return newFairShareReadyQueue(b.namespaceWeights)
+	}
+	return &priorityReadyQueue{evals: make(ReadyEvaluations, 0, 16)}
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
+	_, span := tracing.Continue(eval.TraceContext, "eval_broker.enqueue",
+		trace.WithAttributes(tracing.EvalAttributes(eval)...),
+	)
+	defer span.End()
+
 	b.l.Lock()
 	defer b.l.Unlock()
 	b.processEnqueue(eval, "", true)
@@ -351,15 +404,15 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, sched string, track
 	// Find the next ready eval by scheduler class
 	readyQueue, ok := b.ready[sched]
 	if !ok {
-		readyQueue = make([]*structs.Evaluation, 0, 16)
+		readyQueue = b.newReadyQueue()
+		b.ready[sched] = readyQueue
 		if _, ok := b.waiting[sched]; !ok {
 			b.waiting[sched] = make(chan struct{}, 1)
 		}
 	}
 
-	// Push onto the heap
-	heap.Push(&readyQueue, eval)
-	b.ready[sched] = readyQueue
+	// Push onto the queue
+	readyQueue.Push(eval)
 
 	// Update the stats
 	b.stats.TotalReady += 1
@@ -369,6 +422,12 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, sched string, track
 		b.stats.ByScheduler[sched] = bySched
 	}
 	bySched.Ready += 1
+	byNamespace, ok := b.stats.ByNamespace[eval.Namespace]
+	if !ok {
+		byNamespace = &NamespaceStats{}
+		b.stats.ByNamespace[eval.Namespace] = byNamespace
+	}
+	byNamespace.Ready += 1
 
 	// Unblock any pending dequeues
 	select {
@@ -411,6 +470,16 @@ SCAN:
 				{Name: "eval_type", Value: eval.Type},
 				{Name: "triggered_by", Value: eval.TriggeredBy},
 			})
+			metrics.MeasureSinceWithLabels([]string{"nomad", "broker", "namespace", "wait_time"}, t, []metrics.Label{
+				{Name: "namespace", Value: eval.Namespace},
+			})
+
+			// The span covers the time the eval waited to be dequeued
+			_, span := tracing.Continue(eval.TraceContext, "eval_broker.dequeue",
+				trace.WithTimestamp(t),
+				trace.WithAttributes(tracing.EvalAttributes(eval)...),
+			)
+			span.End()
 		}
 		b.l.Unlock()
 		return eval, token, nil
@@ -491,10 +560,7 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 // dequeueForSched is used to dequeue the next work item for a given scheduler.
 // This assumes locks are held and that this scheduler has work
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
-	readyQueue := b.ready[sched]
-	raw := heap.Pop(&readyQueue)
-	b.ready[sched] = readyQueue
-	eval := raw.(*structs.Evaluation)
+	eval := b.ready[sched].Pop()
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -520,6 +586,7 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	bySched := b.stats.ByScheduler[sched]
 	bySched.Ready -= 1
 	bySched.Unacked += 1
+	b.stats.ByNamespace[eval.Namespace].Ready -= 1
 
 	return eval, token, nil
 }
@@ -855,11 +922,12 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalCancelable = 0
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.pending = make(map[structs.NamespacedID]PendingEvaluations)
 	b.cancelable = make([]*structs.Evaluation, 0, structs.MaxUUIDsPerWriteRequest)
-	b.ready = make(map[string]ReadyEvaluations)
+	b.ready = make(map[string]readyQueue)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
@@ -944,6 +1012,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats := new(BrokerStats)
 	stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	stats.ByScheduler = make(map[string]*SchedulerStats)
+	stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	b.l.RLock()
 	defer b.l.RUnlock()
@@ -962,6 +1031,10 @@ func (b *EvalBroker) Stats() *BrokerStats {
 		subStatCopy := *subStat
 		stats.ByScheduler[sched] = &subStatCopy
 	}
+	for namespace, subStat := range b.stats.ByNamespace {
+		subStatCopy := *subStat
+		stats.ByNamespace[namespace] = &subStatCopy
+	}
 	return stats
 }
 
@@ -1012,6 +1085,11 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
 			}
+			for namespace, namespaceStats := range stats.ByNamespace {
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "ready"},
+					float32(namespaceStats.Ready),
+					[]metrics.Label{{Name: "namespace", Value: namespace}})
+			}
 
 		case <-stopCh:
 			return
@@ -1028,6 +1106,7 @@ type BrokerStats struct {
 	TotalCancelable int
 	DelayedEvals    map[string]*structs.Evaluation
 	ByScheduler     map[string]*SchedulerStats
+	ByNamespace     map[string]*NamespaceStats
 }
 
 // SchedulerStats returns the stats per scheduler
@@ -1036,6 +1115,140 @@ type SchedulerStats struct {
 	Unacked int
 }
 
+// NamespaceStats returns the stats per namespace
+type NamespaceStats struct {
+	Ready int
+}
+
+// readyQueue is a queue of the ready evaluations of a scheduler, whose order
+// is determined by the dequeue policy of the broker.
+type readyQueue interface {
+	// Push adds an evaluation to the queue.
+	Push(*structs.Evaluation)
+
+	// Pop removes and returns the next evaluation. The queue must not be
+	// empty.
+	Pop() *structs.Evaluation
+
+	// Peek returns the next evaluation, or nil if the queue is empty.
+	Peek() *structs.Evaluation
+
+	// Evals returns all the evaluations in the queue.
+	Evals() []*structs.Evaluation
+}
+
+// priorityReadyQueue dequeues the evaluations with the highest priority
+// first, and then in the order they were created.
+type priorityReadyQueue struct {
+	evals ReadyEvaluations
+}
+
+func (q *priorityReadyQueue) Push(eval *structs.Evaluation) {
+	heap.Push(&q.evals, eval)
+}
+
+func (q *priorityReadyQueue) Pop() *structs.Evaluation {
+	return heap.Pop(&q.evals).(*structs.Evaluation)
+}
+
+func (q *priorityReadyQueue) Peek() *structs.Evaluation {
+	return q.evals.Peek()
+}
+
+func (q *priorityReadyQueue) Evals() []*structs.Evaluation {
+	return q.evals
+}
+
+// fairShareReadyQueue takes turns dequeuing the evaluations of each namespace
+// with queued evaluations, in a weighted round-robin. Each turn dequeues as
+// many evaluations as the weight of the namespace, so a namespace with many
+// queued evaluations can't starve the others. The evaluations of a namespace
+// are dequeued by priority, and then in the order they were created.
+type fairShareReadyQueue struct {
+	weights map[string]int
+
+	// queues are the evaluations of each namespace with queued evaluations.
+	queues map[string]*ReadyEvaluations
+
+	// order is the order in which the namespaces take turns, and next is the
+	// index of the namespace whose turn it is.
+	order []string
+	next  int
+
+	// credit is the number of evaluations the namespace whose turn it is may
+	// still dequeue before the next namespace's turn. It is zero when the
+	// turn hasn't started.
+	credit int
+}
+
+func newFairShareReadyQueue(weights map[string]int) *fairShareReadyQueue {
+	return &fairShareReadyQueue{
+		weights: weights,
+		queues:  make(map[string]*ReadyEvaluations),
+	}
+}
+
+// weight returns the number of evaluations of the namespace dequeued in each
+// turn.
+func (q *fairShareReadyQueue) weight(namespace string) int {
+	if weight, ok := q.weights[namespace]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+func (q *fairShareReadyQueue) Push(eval *structs.Evaluation) {
+	queue, ok := q.queues[eval.Namespace]
+	if !ok {
+		queue = &ReadyEvaluations{}
+		q.queues[eval.Namespace] = queue
+		q.order = append(q.order, eval.Namespace)
+	}
+	heap.Push(queue, eval)
+}
+
+func (q *fairShareReadyQueue) Pop() *structs.Evaluation {
+	namespace := q.order[q.next]
+	if q.credit == 0 {
+		q.credit = q.weight(namespace)
+	}
+
+	queue := q.queues[namespace]
+	eval := heap.Pop(queue).(*structs.Evaluation)
+	q.credit--
+
+	switch {
+	case queue.Len() == 0:
+		// The namespace leaves the rotation until it has queued evaluations
+		// again, and the turn passes to the namespace which now has its index.
+		delete(q.queues, namespace)
+		q.order = slices.Delete(q.order, q.next, q.next+1)
+		if q.next >= len(q.order) {
+			q.next = 0
+		}
+		q.credit = 0
+	case q.credit == 0:
+		q.next = (q.next + 1) % len(q.order)
+	}
+	return eval
+}
+
+func (q *fairShareReadyQueue) Peek() *structs.Evaluation {
+	if len(q.order) == 0 {
+		return nil
+	}
+	queue := *q.queues[q.order[q.next]]
+	return queue[0]
+}
+
+func (q *fairShareReadyQueue) Evals() []*structs.Evaluation {
+	var evals []*structs.Evaluation
+	for _, namespace := range q.order {
+		evals = append(evals, *q.queues[namespace]...)
+	}
+	return evals
+}
+
 // Len is for the sorting interface
 func (r ReadyEvaluations) Len() int {
 	return len(r)
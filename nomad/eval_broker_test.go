@@ -436,6 +436,7 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		stats := b.Stats()
 		stats.DelayedEvals = nil
 		stats.ByScheduler = nil
+		stats.ByNamespace = nil
 		return *stats
 	}
 
@@ -752,6 +753,146 @@ func TestEvalBroker_Dequeue_Fairness(t *testing.T) {
 	}
 }
 
+// Ensure the fair share policy takes turns between namespaces
+func TestEvalBroker_Dequeue_FairShare(t *testing.T) {
+	ci.Parallel(t)
+
+	// enqueue queues evaluations of the namespaces in the given order
+	enqueue := func(b *EvalBroker, namespaces ...string) {
+		for i, namespace := range namespaces {
+			eval := mock.Eval()
+			eval.Namespace = namespace
+			eval.CreateIndex = uint64(i + 1)
+			b.Enqueue(eval)
+		}
+	}
+
+	// dequeue returns the namespaces of the next n dequeued evaluations
+	dequeue := func(t *testing.T, b *EvalBroker, n int) []string {
+		var namespaces []string
+		for range n {
+			out, _, err := b.Dequeue(defaultSched, time.Second)
+			must.NoError(t, err)
+			must.NotNil(t, out)
+			namespaces = append(namespaces, out.Namespace)
+		}
+		return namespaces
+	}
+
+	t.Run("priority", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+
+		enqueue(b, "busy", "busy", "busy", "busy", "other", "other")
+		must.Eq(t, []string{"busy", "busy", "busy", "busy", "other", "other"}, dequeue(t, b, 6))
+	})
+
+	t.Run("fair share", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetDequeuePolicy(structs.EvalDequeuePolicyFairShare, nil)
+
+		enqueue(b, "busy", "busy", "busy", "busy", "other", "other")
+		must.Eq(t, []string{"busy", "other", "busy", "other", "busy", "busy"}, dequeue(t, b, 6))
+	})
+
+	t.Run("namespace weights", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetDequeuePolicy(structs.EvalDequeuePolicyFairShare, map[string]int{"busy": 2})
+
+		enqueue(b, "busy", "busy", "busy", "busy", "busy", "other", "other", "third")
+		must.Eq(t, []string{"busy", "busy", "other", "third", "busy", "busy", "other", "busy"}, dequeue(t, b, 8))
+	})
+
+	t.Run("priority within namespace", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetDequeuePolicy(structs.EvalDequeuePolicyFairShare, nil)
+
+		low := mock.Eval()
+		low.Namespace = "busy"
+		low.Priority = 10
+		b.Enqueue(low)
+		high := mock.Eval()
+		high.Namespace = "busy"
+		high.Priority = 90
+		b.Enqueue(high)
+
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		must.NoError(t, err)
+		must.Eq(t, high, out)
+	})
+
+	t.Run("policy change requeues ready evals", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+
+		enqueue(b, "busy", "busy", "busy", "other")
+		b.SetDequeuePolicy(structs.EvalDequeuePolicyFairShare, nil)
+		must.Eq(t, []string{"busy", "other", "busy", "busy"}, dequeue(t, b, 4))
+
+		stats := b.Stats()
+		must.Eq(t, 0, stats.TotalReady)
+		must.Eq(t, 4, stats.TotalUnacked)
+		must.Eq(t, 0, stats.ByNamespace["busy"].Ready)
+		must.Eq(t, 0, stats.ByNamespace["other"].Ready)
+	})
+}
+
+// Ensure the fair share policy keeps the ack, nack and delivery limit
+// semantics
+func TestEvalBroker_FairShare_DeliveryLimit(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+	b.SetDequeuePolicy(structs.EvalDequeuePolicyFairShare, nil)
+
+	eval := mock.Eval()
+	eval.Namespace = "busy"
+	b.Enqueue(eval)
+
+	other := mock.Eval()
+	other.Namespace = "other"
+	b.Enqueue(other)
+
+	stats := b.Stats()
+	must.Eq(t, 1, stats.ByNamespace["busy"].Ready)
+	must.Eq(t, 1, stats.ByNamespace["other"].Ready)
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, eval, out)
+
+	out, otherToken, err := b.Dequeue(defaultSched, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, other, out)
+	must.NoError(t, b.Ack(other.ID, otherToken))
+
+	// Nack the evaluation until it reaches the delivery limit
+	for i := 1; i < 3; i++ {
+		must.NoError(t, b.Nack(eval.ID, token))
+		out, token, err = b.Dequeue(defaultSched, time.Second)
+		must.NoError(t, err)
+		must.Eq(t, eval, out)
+	}
+	must.NoError(t, b.Nack(eval.ID, token))
+
+	stats = b.Stats()
+	must.Eq(t, 1, stats.ByScheduler[failedQueue].Ready)
+	must.Eq(t, 1, stats.ByNamespace["busy"].Ready)
+
+	out, token, err = b.Dequeue([]string{failedQueue}, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, eval, out)
+	must.NoError(t, b.Ack(eval.ID, token))
+
+	stats = b.Stats()
+	must.Eq(t, 0, stats.TotalReady)
+	must.Eq(t, 0, stats.TotalUnacked)
+	must.Eq(t, 0, stats.ByNamespace["busy"].Ready)
+}
+
 // Ensure we get unblocked
 func TestEvalBroker_Dequeue_Blocked(t *testing.T) {
 	ci.Parallel(t)
@@ -1584,6 +1725,7 @@ func TestEvalBroker_IntegrationTest(t *testing.T) {
 		stats := srv.evalBroker.Stats()
 		stats.DelayedEvals = nil
 		stats.ByScheduler = nil
+		stats.ByNamespace = nil
 		return *stats
 	}
 
@@ -6,12 +6,14 @@ package nomad
 import (
 	"context"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-msgpack/v2/codec"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/auth"
 	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -69,6 +71,13 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
+	// Hold the resolved ACL so that events whose permissions depend on the
+	// event itself can be filtered. It is refreshed whenever the subscription
+	// is re-authenticated after an ACL change.
+	var subACL atomic.Pointer[acl.ACL]
+	subACL.Store(resolvedACL)
+	claim := auth.IdentityToACLClaim(args.GetIdentity(), e.srv.State())
+
 	// Generate the subscription request
 	subReq := &stream.SubscribeRequest{
 		Token:  args.AuthToken,
@@ -86,8 +95,14 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 				return err
 			}
 			_, err = e.validateACL(args.Namespace, args.Topics, resolvedACL)
+			if err == nil {
+				subACL.Store(resolvedACL)
+			}
 			return err
 		},
+		AllowEvent: func(event structs.Event) bool {
+			return allowEvent(event, subACL.Load(), claim)
+		},
 	}
 
 	// Get the servers broker and subscribe
@@ -297,6 +312,12 @@ func validateNsOp(namespace string, topics map[structs.Topic][]string, aclObj *a
 			if ok := aclObj.AllowOperatorRead(); !ok {
 				return structs.ErrPermissionDenied
 			}
+		case structs.TopicVariable:
+			// Variable capabilities are set per path, so only require some
+			// access to the namespace here and filter each event by its path.
+			if ok := aclObj.AllowVariableSearch(namespace); !ok {
+				return structs.ErrPermissionDenied
+			}
 		default: // including TopicAll
 			if ok := aclObj.IsManagement(); !ok {
 				return structs.ErrPermissionDenied
@@ -307,3 +328,17 @@ func validateNsOp(namespace string, topics map[structs.Topic][]string, aclObj *a
 	return nil
 
 }
+
+// allowEvent checks the permissions which can only be determined from the
+// event itself, once the subscription has been validated by validateNsOp.
+func allowEvent(event structs.Event, aclObj *acl.ACL, claim *acl.ACLClaim) bool {
+	switch payload := event.Payload.(type) {
+	case *structs.VariableEvent:
+		// Events carry only metadata, so the list capability is sufficient in
+		// the same way as for the variables list RPC.
+		return aclObj.AllowVariableOperation(payload.Variable.Namespace,
+			payload.Variable.Path, acl.PolicyList, claim)
+	default:
+		return true
+	}
+}
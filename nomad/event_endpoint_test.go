@@ -469,6 +469,28 @@ func TestEventStream_validateNsOp(t *testing.T) {
 			Management:  false,
 			ExpectedErr: structs.ErrPermissionDenied,
 		},
+		{
+			Name: "read variables - correct policy and ns",
+			Topics: map[structs.Topic][]string{
+				structs.TopicVariable: {"*"},
+			},
+			Policy: mock.NamespacePolicyWithVariables("foo", "", nil,
+				map[string][]string{"app/*": {acl.VariablesCapabilityList}}),
+			Namespace:   "foo",
+			Management:  false,
+			ExpectedErr: nil,
+		},
+		{
+			Name: "read variables - incorrect policy or ns",
+			Topics: map[structs.Topic][]string{
+				structs.TopicVariable: {"*"},
+			},
+			Policy: mock.NamespacePolicyWithVariables("foo", "", nil,
+				map[string][]string{"app/*": {acl.VariablesCapabilityList}}),
+			Namespace:   "bar",
+			Management:  false,
+			ExpectedErr: structs.ErrPermissionDenied,
+		},
 	}
 
 	for _, tc := range cases {
@@ -486,6 +508,36 @@ func TestEventStream_validateNsOp(t *testing.T) {
 	}
 }
 
+func TestEventStream_allowEvent(t *testing.T) {
+	ci.Parallel(t)
+
+	p, err := acl.Parse(mock.NamespacePolicyWithVariables("foo", "", nil,
+		map[string][]string{"app/*": {acl.VariablesCapabilityList}}))
+	must.NoError(t, err)
+	testACL, err := acl.NewACL(false, []*acl.Policy{p})
+	must.NoError(t, err)
+
+	varEvent := func(ns, path string) structs.Event {
+		return structs.Event{
+			Topic:     structs.TopicVariable,
+			Namespace: ns,
+			Payload: structs.NewVariableEvent(&structs.VariableEncrypted{
+				VariableMetadata: structs.VariableMetadata{Namespace: ns, Path: path},
+			}),
+		}
+	}
+
+	must.True(t, allowEvent(varEvent("foo", "app/config"), testACL, nil))
+	must.False(t, allowEvent(varEvent("foo", "other/config"), testACL, nil))
+	must.False(t, allowEvent(varEvent("bar", "app/config"), testACL, nil))
+
+	// events for other topics are not affected
+	must.True(t, allowEvent(structs.Event{
+		Topic:   structs.TopicJob,
+		Payload: &structs.JobEvent{Job: mock.Job()},
+	}, testACL, nil))
+}
+
 func TestEventStream_validateACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -59,6 +59,7 @@ const (
 	JobSubmissionSnapshot                SnapshotType = 29
 	RootKeySnapshot                      SnapshotType = 30
 	HostVolumeSnapshot                   SnapshotType = 31
+	VariableVersionsSnapshot             SnapshotType = 32
 
 	// TimeTableSnapshot
 	// Deprecated: Nomad no longer supports TimeTable snapshots since 1.9.2
@@ -110,6 +111,7 @@ var snapshotTypeStrings = map[SnapshotType]string{
 	JobSubmissionSnapshot:                "JobSubmission",
 	RootKeySnapshot:                      "WrappedRootKeys",
 	HostVolumeSnapshot:                   "HostVolumeSnapshot",
+	VariableVersionsSnapshot:             "VariableVersions",
 	NamespaceSnapshot:                    "Namespace",
 }
 
@@ -299,6 +301,12 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeploymentPromotion(msgType, buf[1:], log.Index)
 	case structs.DeploymentAllocHealthRequestType:
 		return n.applyDeploymentAllocHealth(msgType, buf[1:], log.Index)
+	case structs.DeploymentAnalysisUpdateRequestType:
+		return n.applyDeploymentAnalysisUpdate(msgType, buf[1:], log.Index)
+	case structs.PeriodicLaunchDecisionRequestType:
+		return n.applyPeriodicLaunchDecision(msgType, buf[1:], log.Index)
+	case structs.JobDispatchReleaseRequestType:
+		return n.applyJobDispatchRelease(msgType, buf[1:], log.Index)
 	case structs.DeploymentDeleteRequestType:
 		return n.applyDeploymentDelete(buf[1:], log.Index)
 	case structs.JobStabilityRequestType:
@@ -397,6 +405,10 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyHostVolumeDelete(msgType, buf[1:], log.Index)
 	case structs.TaskGroupHostVolumeClaimDeleteRequestType:
 		return n.applyTaskGroupHostVolumeClaimDelete(buf[1:], log.Index)
+	case structs.VariableVersionsDeleteRequestType:
+		return n.applyVariableVersionsDelete(buf[1:], log.Index)
+	case structs.VariableVersionsRekeyRequestType:
+		return n.applyVariableVersionsRekey(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -907,7 +919,7 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	} else if eval.ShouldBlock() {
 		n.blockedEvals.Block(eval)
 	} else if eval.Status == structs.EvalStatusComplete &&
-		len(eval.FailedTGAllocs) == 0 {
+		len(eval.FailedTGAllocs) == 0 && len(eval.UnmetDependencies) == 0 {
 		// If we have a successful evaluation for a node, untrack any
 		// blocked evaluation
 		n.blockedEvals.Untrack(eval.JobID, eval.Namespace)
@@ -957,12 +969,14 @@ func (n *nomadFSM) applyAllocClientUpdate(msgType structs.MessageType, buf []byt
 	ws := memdb.NewWatchSet()
 
 	followupEvalsToCancel := []string{}
+	jobs := map[structs.NamespacedID]struct{}{}
 
 	// Updating the allocs with the job id and task group name
 	for _, alloc := range req.Alloc {
 		if existing, _ := n.state.AllocByID(ws, alloc.ID); existing != nil {
 			alloc.JobID = existing.JobID
 			alloc.TaskGroup = existing.TaskGroup
+			jobs[structs.NewNamespacedID(existing.JobID, existing.Namespace)] = struct{}{}
 
 			// a reconnecting alloc has a followup eval which will be stuck in
 			// pending, blocking new evals for failure of this alloc. The
@@ -1013,6 +1027,12 @@ func (n *nomadFSM) applyAllocClientUpdate(msgType structs.MessageType, buf []byt
 		}
 	}
 
+	// Unblock evals waiting for the jobs of the allocations to complete or
+	// become healthy.
+	for jobID := range jobs {
+		n.blockedEvals.UnblockJobDependents(jobID.ID, jobID.Namespace, index, n.jobDependencySatisfied)
+	}
+
 	// It's possible that allocs on different nodes were marked unknown in the
 	// same eval and therefore have the same FollowupEvalID. If only one of
 	// those allocs reconnects, we need to ensure we keep around the waiting
@@ -1107,6 +1127,8 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(msgType structs.MessageType, buf
 		return err
 	}
 
+	n.unblockDeploymentDependents(req.DeploymentUpdate.DeploymentID, index)
+
 	n.handleUpsertedEval(req.Eval)
 	return nil
 }
@@ -1128,6 +1150,68 @@ func (n *nomadFSM) applyDeploymentPromotion(msgType structs.MessageType, buf []b
 	return nil
 }
 
+// applyDeploymentAnalysisUpdate is used to record the canary analysis of a
+// task group in a deployment
+func (n *nomadFSM) applyDeploymentAnalysisUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_deployment_analysis_update"}, time.Now())
+	var req structs.DeploymentAnalysisUpdateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateDeploymentAnalysis(msgType, index, &req); err != nil {
+		n.logger.Error("UpdateDeploymentAnalysis failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyPeriodicLaunchDecision is used to record the last decision of the
+// concurrency policy of a periodic job
+func (n *nomadFSM) applyPeriodicLaunchDecision(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_periodic_launch_decision"}, time.Now())
+	var req structs.PeriodicLaunchDecisionRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertPeriodicLaunchDecision(msgType, index, &req); err != nil {
+		n.logger.Error("UpsertPeriodicLaunchDecision failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyJobDispatchRelease is used to release dispatched jobs that were queued
+// by their parent's max_concurrent limit and enqueue their evaluations.
+func (n *nomadFSM) applyJobDispatchRelease(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_dispatch_release"}, time.Now())
+	var req structs.JobDispatchReleaseRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ReleaseDispatchedJobs(msgType, index, &req); err != nil {
+		n.logger.Error("ReleaseDispatchedJobs failed", "error", err)
+		return err
+	}
+
+	// Only enqueue the evaluations of jobs that were actually released. Jobs
+	// stopped or purged while queued are skipped by the state store.
+	for _, eval := range req.Evals {
+		existing, err := n.state.EvalByID(nil, eval.ID)
+		if err != nil {
+			n.logger.Error("looking up released evaluation failed", "eval_id", eval.ID, "error", err)
+			return err
+		}
+		n.handleUpsertedEval(existing)
+	}
+
+	return nil
+}
+
 // applyDeploymentAllocHealth is used to set the health of allocations as part
 // of a deployment
 func (n *nomadFSM) applyDeploymentAllocHealth(msgType structs.MessageType, buf []byte, index uint64) interface{} {
@@ -1142,10 +1226,35 @@ func (n *nomadFSM) applyDeploymentAllocHealth(msgType structs.MessageType, buf [
 		return err
 	}
 
+	n.unblockDeploymentDependents(req.DeploymentID, index)
+
 	n.handleUpsertedEval(req.Eval)
 	return nil
 }
 
+// unblockDeploymentDependents unblocks any evaluation waiting on the job of the
+// passed deployment.
+func (n *nomadFSM) unblockDeploymentDependents(deploymentID string, index uint64) {
+	deployment, err := n.state.DeploymentByID(nil, deploymentID)
+	if err != nil || deployment == nil {
+		return
+	}
+	n.blockedEvals.UnblockJobDependents(deployment.JobID, deployment.Namespace, index, n.jobDependencySatisfied)
+}
+
+// jobDependencySatisfied returns whether the job depended on has reached the
+// condition of the dependency. Errors are treated as satisfied so that the
+// scheduler checks the dependencies again rather than the evaluation staying
+// blocked.
+func (n *nomadFSM) jobDependencySatisfied(namespace string, dep *structs.JobDependency) bool {
+	satisfied, err := scheduler.JobDependencySatisfied(n.state, namespace, dep)
+	if err != nil {
+		n.logger.Error("failed to check job dependency", "job_id", dep.JobID, "namespace", namespace, "error", err)
+		return true
+	}
+	return satisfied
+}
+
 // applyDeploymentDelete is used to delete a set of deployments
 func (n *nomadFSM) applyDeploymentDelete(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_deployment_delete"}, time.Now())
@@ -1810,6 +1919,16 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case VariableVersionsSnapshot:
+			version := new(structs.VariableEncrypted)
+			if err := dec.Decode(version); err != nil {
+				return err
+			}
+
+			if err := restore.VariableVersionsRestore(version); err != nil {
+				return err
+			}
+
 		case VariablesQuotaSnapshot:
 			quota := new(structs.VariablesQuota)
 			if err := dec.Decode(quota); err != nil {
@@ -2441,6 +2560,36 @@ func (n *nomadFSM) applyTaskGroupHostVolumeClaimDelete(buf []byte, index uint64)
 	return nil
 }
 
+func (n *nomadFSM) applyVariableVersionsDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_versions_delete"}, time.Now())
+
+	var req structs.VariableVersionsDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVariableVersions(index, req.Versions); err != nil {
+		n.logger.Error("DeleteVariableVersions failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyVariableVersionsRekey(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_versions_rekey"}, time.Now())
+
+	var req structs.VariableVersionsRekeyRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.RekeyVariableVersions(index, req.Versions); err != nil {
+		n.logger.Error("RekeyVariableVersions failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "persist"}, time.Now())
 	// Register the nodes
@@ -2542,6 +2691,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistVariableVersions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistVariablesQuotas(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -3130,6 +3283,29 @@ func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistVariableVersions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.VariableVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := versions.Next()
+		if raw == nil {
+			break
+		}
+		version := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariableVersionsSnapshot)})
+		if err := encoder.Encode(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistVariablesQuotas(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
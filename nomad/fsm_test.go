@@ -3333,6 +3333,45 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 	require.ElementsMatch(t, restoredSVs, svs)
 }
 
+func TestFSM_SnapshotRestore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	// Update a variable a few times so that previous versions are retained.
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	for i := uint64(11); i < 14; i++ {
+		update := sv.Copy()
+		update.Data = []byte(fmt.Sprintf("data-%d", i))
+		setResp := testState.VarSet(i, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &update,
+		})
+		must.NoError(t, setResp.Error)
+	}
+
+	versions, err := testState.GetVariableVersions(nil, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	restoredVersions, err := restoredState.GetVariableVersions(nil, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, versions, restoredVersions)
+}
+
 func TestFSM_ApplyACLRolesUpsert(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
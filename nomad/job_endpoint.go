@@ -206,6 +206,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Check the namespaces the colocation selectors refer to
+	if ok, err := allowColocationNamespaces(aclObj, j.srv.State(), args.Job); err != nil {
+		return err
+	} else if !ok {
+		return structs.ErrPermissionDenied
+	}
+
 	// Lookup the job
 	snap, err := j.srv.State().Snapshot()
 	if err != nil {
@@ -329,15 +336,16 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 
 		eval = &structs.Evaluation{
-			ID:          uuid.Generate(),
-			Namespace:   args.RequestNamespace(),
-			Priority:    evalPriority,
-			Type:        args.Job.Type,
-			TriggeredBy: structs.EvalTriggerJobRegister,
-			JobID:       args.Job.ID,
-			Status:      structs.EvalStatusPending,
-			CreateTime:  now,
-			ModifyTime:  now,
+			ID:           uuid.Generate(),
+			Namespace:    args.RequestNamespace(),
+			Priority:     evalPriority,
+			Type:         args.Job.Type,
+			TriggeredBy:  structs.EvalTriggerJobRegister,
+			JobID:        args.Job.ID,
+			Status:       structs.EvalStatusPending,
+			TraceContext: args.TraceContext(),
+			CreateTime:   now,
+			ModifyTime:   now,
 		}
 		reply.EvalID = eval.ID
 	}
@@ -741,6 +749,8 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		return fmt.Errorf("can't evaluate periodic job")
 	} else if job.IsParameterized() {
 		return fmt.Errorf("can't evaluate parameterized job")
+	} else if job.DispatchQueued && !job.Stop {
+		return fmt.Errorf("can't evaluate queued dispatched job")
 	}
 
 	forceRescheduleAllocs := make(map[string]*structs.DesiredTransition)
@@ -776,6 +786,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		JobID:          job.ID,
 		JobModifyIndex: job.ModifyIndex,
 		Status:         structs.EvalStatusPending,
+		TraceContext:   args.TraceContext(),
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
@@ -862,15 +873,16 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 		}
 
 		eval = &structs.Evaluation{
-			ID:          uuid.Generate(),
-			Namespace:   args.RequestNamespace(),
-			Priority:    priority,
-			Type:        structs.JobTypeService,
-			TriggeredBy: structs.EvalTriggerJobDeregister,
-			JobID:       args.JobID,
-			Status:      structs.EvalStatusPending,
-			CreateTime:  now,
-			ModifyTime:  now,
+			ID:           uuid.Generate(),
+			Namespace:    args.RequestNamespace(),
+			Priority:     priority,
+			Type:         structs.JobTypeService,
+			TriggeredBy:  structs.EvalTriggerJobDeregister,
+			JobID:        args.JobID,
+			Status:       structs.EvalStatusPending,
+			TraceContext: args.TraceContext(),
+			CreateTime:   now,
+			ModifyTime:   now,
 		}
 		reply.EvalID = eval.ID
 	}
@@ -1104,6 +1116,7 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 				JobID:          args.JobID,
 				JobModifyIndex: reply.JobModifyIndex,
 				Status:         structs.EvalStatusPending,
+				TraceContext:   args.TraceContext(),
 				CreateTime:     now,
 				ModifyTime:     now,
 			}
@@ -1399,6 +1412,37 @@ func registrationsAreAllowed(aclObj *acl.ACL, state *state.StateStore) (bool, er
 	return false, nil
 }
 
+// allowColocationNamespaces returns whether the ACL allows reading the jobs of
+// the namespaces that the colocation selectors of the job select allocations
+// in, since placing the job reveals where those allocations are running. A
+// selector for every namespace requires access to every namespace.
+func allowColocationNamespaces(aclObj *acl.ACL, state *state.StateStore, job *structs.Job) (bool, error) {
+	if aclObj.IsManagement() {
+		return true, nil
+	}
+
+	for _, ns := range job.ColocationNamespaces() {
+		if ns != structs.ColocationSelectorAnyNamespace {
+			if !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+				return false, nil
+			}
+			continue
+		}
+
+		iter, err := state.Namespaces(nil)
+		if err != nil {
+			return false, err
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			name := raw.(*structs.Namespace).Name
+			if name != job.Namespace && !aclObj.AllowNsOp(name, acl.NamespaceCapabilityReadJob) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
 // List is used to list the jobs registered in the system
 func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse) error {
 	authErr := j.srv.Authenticate(j.ctx, args)
@@ -1804,6 +1848,12 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 				return structs.ErrPermissionDenied
 			}
 		}
+		// Check the namespaces the colocation selectors refer to
+		if ok, err := allowColocationNamespaces(aclObj, j.srv.State(), args.Job); err != nil {
+			return err
+		} else if !ok {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Acquire a snapshot of the state
@@ -2107,6 +2157,18 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	// Compress the payload
 	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
 
+	// Queue the job instead of evaluating it if the parameterized job is at
+	// its max_concurrent limit. The dispatch queue is held until the job is
+	// committed so that concurrent dispatches can't exceed the limit.
+	if !dispatchJob.IsPeriodic() && parameterizedJob.ParameterizedJob.MaxConcurrent > 0 {
+		queued, done, err := j.srv.dispatchQueue.Admit(j.srv.State(), parameterizedJob)
+		if err != nil {
+			return err
+		}
+		defer done()
+		dispatchJob.DispatchQueued = queued
+	}
+
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
 		WriteRequest: args.WriteRequest,
@@ -2122,9 +2184,10 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	reply.JobCreateIndex = jobCreateIndex
 	reply.DispatchedJobID = dispatchJob.ID
 	reply.Index = jobCreateIndex
+	reply.Queued = dispatchJob.DispatchQueued
 
-	// If the job is periodic, we don't create an eval.
-	if !dispatchJob.IsPeriodic() {
+	// If the job is periodic or queued, we don't create an eval.
+	if !dispatchJob.IsPeriodic() && !dispatchJob.DispatchQueued {
 		// Create a new evaluation
 		now := time.Now().UnixNano()
 		eval := &structs.Evaluation{
@@ -2136,6 +2199,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 			JobID:          dispatchJob.ID,
 			JobModifyIndex: jobCreateIndex,
 			Status:         structs.EvalStatusPending,
+			TraceContext:   args.TraceContext(),
 			CreateTime:     now,
 			ModifyTime:     now,
 		}
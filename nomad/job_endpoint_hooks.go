@@ -502,6 +502,11 @@ func (v *jobValidate) Validate(job *structs.Job) (warnings []error, err error) {
 	okForIdentity := v.isEligibleForMultiIdentity()
 
 	for _, tg := range job.TaskGroups {
+		if tg.Update != nil && tg.Update.Analysis != nil &&
+			!tg.Update.Analysis.AddressAllowed(v.srv.config.CanaryAnalysisAddresses) {
+			multierror.Append(validationErrors, fmt.Errorf("task group %s: canary analysis address %q is not allowed by the server configuration", tg.Name, tg.Update.Analysis.Address))
+		}
+
 		for _, s := range tg.Services {
 			serviceErrs := v.validateServiceIdentity(
 				s, fmt.Sprintf("task group %s", tg.Name), okForIdentity)
@@ -1159,6 +1159,90 @@ func TestJobEndpoint_Register_Payload(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_CanaryAnalysisAddress(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.CanaryAnalysisAddresses = []string{"http://prometheus.example:9090"}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(address string) error {
+		job := mock.Job()
+		job.Update = structs.UpdateStrategy{}
+		job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+		job.TaskGroups[0].Update.Canary = 1
+		job.TaskGroups[0].Update.Analysis = &structs.CanaryAnalysis{
+			Address:  address,
+			Interval: structs.CanaryAnalysisDefaultInterval,
+			Runs:     structs.CanaryAnalysisDefaultRuns,
+			Metrics: []*structs.AnalysisMetric{{
+				Name:  "errors",
+				Query: "errors",
+				Max:   pointer.Of(1.0),
+			}},
+		}
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	must.NoError(t, register("http://prometheus.example:9090/"))
+
+	// Addresses which aren't allowed by the server can't be queried
+	err := register("http://169.254.169.254")
+	must.ErrorContains(t, err, `canary analysis address "http://169.254.169.254" is not allowed`)
+}
+
+func TestJobEndpoint_Register_TraceContext(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	carrier := map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	req.SetTraceContext(carrier)
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// The eval carries the trace context to the broker and scheduler
+	eval, err := s1.fsm.State().EvalByID(nil, resp.EvalID)
+	must.NoError(t, err)
+	must.Eq(t, carrier, eval.TraceContext)
+
+	// The trace context of the RPC isn't written to the raft log
+	var log raft.Log
+	must.NoError(t, s1.raftInmem.GetLog(resp.JobModifyIndex, &log))
+	must.Eq(t, structs.JobRegisterRequestType, structs.MessageType(log.Data[0]))
+	var applied structs.JobRegisterRequest
+	must.NoError(t, structs.Decode(log.Data[1:], &applied))
+	must.Nil(t, applied.TraceContext())
+	must.Eq(t, carrier, applied.Eval.TraceContext)
+}
+
 func TestJobEndpoint_Register_Existing(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2145,6 +2229,117 @@ func TestJobEndpoint_Register_ACL_Namespace(t *testing.T) {
 	assert.NotNil(out, "expected job")
 }
 
+// TestJobEndpoint_Register_ACL_ColocationNamespace asserts that registering or
+// planning a job whose colocation selectors refer to other namespaces requires
+// read-job on those namespaces.
+func TestJobEndpoint_Register_ACL_ColocationNamespace(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := mock.Namespace()
+	ns.Name = "other"
+	must.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	submitToken := mock.CreatePolicyAndToken(t, s1.State(), 1001, "colocation-submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil)).SecretID
+	readOtherToken := mock.CreatePolicyAndToken(t, s1.State(), 1002, "colocation-read-other",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil)+
+			mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityReadJob})).SecretID
+
+	cases := []struct {
+		name     string
+		selector string
+		token    string
+		denied   bool
+	}{
+		{
+			name:     "own namespace",
+			selector: "job=api",
+			token:    submitToken,
+		},
+		{
+			name:     "own namespace by name",
+			selector: "job=api,namespace=default",
+			token:    submitToken,
+		},
+		{
+			name:     "other namespace without read-job",
+			selector: "job=api,namespace=other",
+			token:    submitToken,
+			denied:   true,
+		},
+		{
+			name:     "every namespace without read-job",
+			selector: "job=api,namespace=*",
+			token:    submitToken,
+			denied:   true,
+		},
+		{
+			name:     "other namespace with read-job",
+			selector: "job=api,namespace=other",
+			token:    readOtherToken,
+		},
+		{
+			name:     "every namespace with read-job",
+			selector: "job=api,namespace=*",
+			token:    readOtherToken,
+		},
+		{
+			name:     "management token",
+			selector: "job=api,namespace=*",
+			token:    root.SecretID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.TaskGroups[0].Affinities = []*structs.Affinity{{
+				Operand: structs.ConstraintColocatedWith,
+				RTarget: tc.selector,
+				Weight:  50,
+			}}
+
+			planReq := &structs.JobPlanRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: job.Namespace,
+					AuthToken: tc.token,
+				},
+			}
+			var planResp structs.JobPlanResponse
+			err := msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+			if tc.denied {
+				must.EqError(t, err, structs.ErrPermissionDenied.Error())
+			} else {
+				must.NoError(t, err)
+			}
+
+			req := &structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: job.Namespace,
+					AuthToken: tc.token,
+				},
+			}
+			var resp structs.JobRegisterResponse
+			err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+			if tc.denied {
+				must.EqError(t, err, structs.ErrPermissionDenied.Error())
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestJobRegister_ACL_RejectedBySchedulerConfig(t *testing.T) {
 	ci.Parallel(t)
 	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
@@ -3792,9 +3987,11 @@ func TestJobEndpoint_GetJob(t *testing.T) {
 		}
 	}
 
-	// Clear the submit times
+	// Clear the submit and active times
 	j.SubmitTime = 0
 	resp2.Job.SubmitTime = 0
+	j.ActiveSince = 0
+	resp2.Job.ActiveSince = 0
 
 	if !reflect.DeepEqual(j, resp2.Job) {
 		t.Fatalf("bad: %#v %#v", job, resp2.Job)
@@ -7046,6 +7243,102 @@ func TestJobEndpoint_Dispatch_JobChildrenSummary(t *testing.T) {
 	require.Equal(t, structs.JobStatusDead, dispatchedStatus())
 }
 
+func TestJobEndpoint_Dispatch_MaxConcurrent(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+
+	state := s1.fsm.State()
+
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	parameterizedJob := mock.BatchJob()
+	parameterizedJob.ParameterizedJob = &structs.ParameterizedJobConfig{
+		MaxConcurrent: 1,
+	}
+
+	regReq := &structs.JobRegisterRequest{
+		Job: parameterizedJob,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: parameterizedJob.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	jobChildren := func() *structs.JobChildrenSummary {
+		summary, err := state.JobSummaryByID(nil, parameterizedJob.Namespace, parameterizedJob.ID)
+		must.NoError(t, err)
+		return summary.Children
+	}
+
+	dispatch := func() *structs.JobDispatchResponse {
+		dispatchReq := &structs.JobDispatchRequest{
+			JobID: parameterizedJob.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: parameterizedJob.Namespace,
+			},
+		}
+		var dispatchResp structs.JobDispatchResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", dispatchReq, &dispatchResp))
+		return &dispatchResp
+	}
+
+	// The first dispatch is below the limit and evaluated
+	first := dispatch()
+	must.False(t, first.Queued)
+	must.NotEq(t, "", first.EvalID)
+	must.Eq(t, &structs.JobChildrenSummary{Pending: 1}, jobChildren())
+
+	// The second dispatch is queued without an evaluation
+	second := dispatch()
+	must.True(t, second.Queued)
+	must.Eq(t, "", second.EvalID)
+	must.Eq(t, &structs.JobChildrenSummary{Queued: 1, Pending: 1}, jobChildren())
+
+	queuedJob, err := state.JobByID(nil, parameterizedJob.Namespace, second.DispatchedJobID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusQueued, queuedJob.Status)
+	must.True(t, queuedJob.DispatchQueued)
+
+	evals, err := state.EvalsByJob(nil, parameterizedJob.Namespace, second.DispatchedJobID)
+	must.NoError(t, err)
+	must.SliceEmpty(t, evals)
+
+	// Completing the first child releases the queued one
+	eval, err := state.EvalByID(nil, first.EvalID)
+	must.NoError(t, err)
+	eval = eval.Copy()
+	eval.Status = structs.EvalStatusComplete
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, second.Index+1, []*structs.Evaluation{eval}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		children := jobChildren()
+		if children.Queued != 0 || children.Pending != 1 || children.Dead != 1 {
+			return false, fmt.Errorf("unexpected children summary: %#v", children)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	releasedJob, err := state.JobByID(nil, parameterizedJob.Namespace, second.DispatchedJobID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusPending, releasedJob.Status)
+	must.False(t, releasedJob.DispatchQueued)
+
+	evals, err = state.EvalsByJob(nil, parameterizedJob.Namespace, second.DispatchedJobID)
+	must.NoError(t, err)
+	must.Len(t, 1, evals)
+	must.Eq(t, structs.EvalTriggerJobRegister, evals[0].TriggeredBy)
+}
+
 func TestJobEndpoint_Dispatch_ACL_RejectedBySchedulerConfig(t *testing.T) {
 	ci.Parallel(t)
 	s1, root, cleanupS1 := TestACLServer(t, nil)
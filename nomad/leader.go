@@ -399,6 +399,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the NodeDrainer
 	s.nodeDrainer.SetEnabled(true, s.State())
 
+	// Enable the rebalancer
+	s.rebalancer.SetEnabled(true, s.State())
+
 	// Enable the volume watcher, since we are now the leader
 	s.volumeWatcher.SetEnabled(true, s.State(), s.getLeaderAcl())
 
@@ -413,6 +416,12 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the periodic dispatcher, since we are now the leader.
 	s.periodicDispatcher.SetEnabled(true)
 
+	// Enable the dispatch queue, since we are now the leader.
+	s.dispatchQueue.SetEnabled(true, s.State())
+
+	// Enable the deadline watcher, since we are now the leader.
+	s.deadlineWatcher.SetEnabled(true, s.State())
+
 	// Activate RPC now that local FSM caught up with Raft (as evident by Barrier call success)
 	// and all leader related components (e.g. broker queue) are enabled.
 	// Auxiliary processes (e.g. background, bookkeeping, and cleanup tasks can start after)
@@ -450,6 +459,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Periodically save snapshots of the Raft state, if configured
+	go s.runSnapshotAgent(stopCh)
+
 	// Populate the variable lock TTL timers, so we can start tracking renewals
 	// and expirations.
 	if err := s.restoreLockTTLTimers(); err != nil {
@@ -851,8 +863,22 @@ func (s *Server) restorePeriodicDispatcher() error {
 			continue
 		}
 
-		// We do not need to force run the job since it isn't active.
-		if !job.IsPeriodicActive() {
+		// Restore the launch deferred by the queue concurrency policy, which
+		// is launched once the previous instance finishes.
+		if job.IsPeriodicActive() && job.Periodic.Concurrency() == structs.PeriodicConcurrencyQueue {
+			launch, err := s.fsm.State().PeriodicLaunchByID(ws, job.Namespace, job.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get periodic launch: %v", err)
+			}
+			if launch != nil && !launch.Queued.IsZero() {
+				s.periodicDispatcher.RestoreQueued(job, launch.Queued)
+			}
+		}
+
+		// We do not need to force run the job since it isn't active. Jobs
+		// with a catch-up policy are caught up by the periodic dispatcher
+		// instead.
+		if !job.IsPeriodicActive() || job.Periodic.CatchesUp() {
 			continue
 		}
 
@@ -905,9 +931,10 @@ func (s *Server) restorePeriodicDispatcher() error {
 
 // cronJobOverlapAllowed checks if the job allows for overlap and if there are already
 // instances of the job running in order to determine if a new evaluation needs to
-// be created upon periodic dispatcher restore
+// be created upon periodic dispatcher restore. Only the allow concurrency policy
+// permits a launch while instances are running.
 func (s *Server) cronJobOverlapAllowed(job *structs.Job) (bool, error) {
-	if job.Periodic.ProhibitOverlap {
+	if job.Periodic.Concurrency() != structs.PeriodicConcurrencyAllow {
 		running, err := s.periodicDispatcher.dispatcher.RunningChildren(job)
 		if err != nil {
 			return false, fmt.Errorf("failed to determine if periodic job has running children %q error %q", job.NamespacedID(), err)
@@ -941,6 +968,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	variableVersionsGC := time.NewTicker(s.config.VariableVersionsGCInterval)
+	defer variableVersionsGC.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -994,6 +1023,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-variableVersionsGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariableVersionsGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -1403,12 +1436,21 @@ func (s *Server) revokeLeadership() error {
 	// Disable the periodic dispatcher, since it is only useful as a leader
 	s.periodicDispatcher.SetEnabled(false)
 
+	// Disable the dispatch queue, since it is only useful as a leader
+	s.dispatchQueue.SetEnabled(false, nil)
+
+	// Disable the deadline watcher, since it is only useful as a leader
+	s.deadlineWatcher.SetEnabled(false, nil)
+
 	// Disable the deployment watcher as it is only useful as a leader.
 	s.deploymentWatcher.SetEnabled(false, nil)
 
 	// Disable the node drainer
 	s.nodeDrainer.SetEnabled(false, nil)
 
+	// Disable the rebalancer
+	s.rebalancer.SetEnabled(false, nil)
+
 	// Disable the volume watcher
 	s.volumeWatcher.SetEnabled(false, nil, "")
 
@@ -2743,12 +2785,14 @@ func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfi
 	// The scheduler config can only be persisted to Raft once quorum has been
 	// established. If this is a fresh cluster, we need to use the default
 	// scheduler config, otherwise we can use the persisted object.
-	switch schedConfig {
-	case nil:
-		enableBrokers = !s.config.DefaultSchedulerConfig.PauseEvalBroker
-	default:
-		enableBrokers = !schedConfig.PauseEvalBroker
+	if schedConfig == nil {
+		schedConfig = &s.config.DefaultSchedulerConfig
 	}
+	enableBrokers = !schedConfig.PauseEvalBroker
+
+	// Set the dequeue policy before enabling the evalBroker, so restored
+	// evaluations are queued according to it.
+	s.evalBroker.SetDequeuePolicy(schedConfig.EffectiveEvalDequeuePolicy(), schedConfig.NamespaceWeights)
 
 	// If the evalBroker status is changing, set the new state.
 	if enableBrokers != s.evalBroker.Enabled() {
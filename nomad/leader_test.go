@@ -442,6 +442,63 @@ func TestLeader_PeriodicDispatcher_Restore_NoEvals(t *testing.T) {
 	}
 }
 
+func TestLeader_PeriodicDispatcher_Restore_Queued(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Inject a periodic job with the queue concurrency policy and a launch
+	// queued by the previous leader.
+	job := testPeriodicJob(time.Now().Add(time.Hour))
+	job.Periodic.ConcurrencyPolicy = structs.PeriodicConcurrencyQueue
+	req := structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	_, _, err := s1.raftApply(structs.JobRegisterRequestType, req)
+	must.NoError(t, err)
+
+	queued := time.Now().Add(-time.Minute).Round(time.Second)
+	must.NoError(t, s1.RecordDecision(job, &structs.PeriodicLaunchDecision{
+		Decision: structs.PeriodicDecisionQueued,
+		Launch:   queued,
+		Time:     time.Now().UTC(),
+	}))
+
+	// Flush the periodic dispatcher, dropping the queued launch it tracks.
+	s1.periodicDispatcher.SetEnabled(false)
+	s1.periodicDispatcher.SetEnabled(true)
+	s1.restorePeriodicDispatcher()
+
+	// The queued launch is launched as no previous instance is running.
+	testutil.WaitForResult(func() (bool, error) {
+		last, err := s1.fsm.State().PeriodicLaunchByID(nil, job.Namespace, job.ID)
+		if err != nil {
+			return false, err
+		}
+		if !last.Queued.IsZero() {
+			return false, fmt.Errorf("launch still queued: %v", last.Queued)
+		}
+		child, err := s1.fsm.State().JobByID(nil, job.Namespace,
+			s1.periodicDispatcher.derivedJobID(job, queued))
+		if err != nil {
+			return false, err
+		}
+		if child == nil {
+			return false, fmt.Errorf("queued launch not launched")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
 type mockJobEvalDispatcher struct {
 	forceEvalCalled, children bool
 	evalToReturn              *structs.Evaluation
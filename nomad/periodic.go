@@ -30,6 +30,15 @@ type PeriodicDispatch struct {
 	tracked map[structs.NamespacedID]*structs.Job
 	heap    *periodicHeap
 
+	// catchUp is the set of tracked jobs whose missed launches are pending to
+	// be caught up, with the time they are caught up until.
+	catchUp map[structs.NamespacedID]time.Time
+
+	// queued is the set of tracked jobs with a launch deferred by the queue
+	// concurrency policy, with the deferred launch time. The deferred launches
+	// are also recorded in the state so they are restored by the next leader.
+	queued map[structs.NamespacedID]time.Time
+
 	updateCh chan struct{}
 	stopFn   context.CancelFunc
 	logger   log.Logger
@@ -45,8 +54,28 @@ type JobEvalDispatcher interface {
 
 	// RunningChildren returns whether the passed job has any running children.
 	RunningChildren(job *structs.Job) (bool, error)
+
+	// LastLaunch returns the last launch time of the passed job, or the zero
+	// time if none is recorded.
+	LastLaunch(job *structs.Job) (time.Time, error)
+
+	// StopChildren stops the running children of the passed job.
+	StopChildren(job *structs.Job) error
+
+	// RecordDecision records the decision taken by the concurrency policy of
+	// the passed job.
+	RecordDecision(job *structs.Job, decision *structs.PeriodicLaunchDecision) error
+
+	// WatchChildren returns a channel which is closed once the children of
+	// any of the passed jobs change, or once the context is canceled.
+	WatchChildren(ctx context.Context, jobs []*structs.Job) (<-chan struct{}, error)
 }
 
+// periodicQueueRetryInterval is how long to wait before checking the launches
+// deferred by the queue concurrency policy again when the children of their
+// jobs can't be watched.
+const periodicQueueRetryInterval = 5 * time.Second
+
 // DispatchJob creates an evaluation for the passed job and commits both the
 // evaluation and the job to the raft log. It returns the eval.
 func (s *Server) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
@@ -90,43 +119,110 @@ func (s *Server) RunningChildren(job *structs.Job) (bool, error) {
 		return false, err
 	}
 
+	children, err := runningChildren(snap, job, true)
+	if err != nil {
+		return false, err
+	}
+	return len(children) != 0, nil
+}
+
+// StopChildren stops the running children of the passed job by deregistering
+// them.
+func (s *Server) StopChildren(job *structs.Job) error {
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	children, err := runningChildren(snap, job, false)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		now := time.Now().UTC().UnixNano()
+		req := structs.JobDeregisterRequest{
+			JobID:      child.ID,
+			SubmitTime: now,
+			Eval: &structs.Evaluation{
+				ID:          uuid.Generate(),
+				Namespace:   child.Namespace,
+				Priority:    child.Priority,
+				Type:        child.Type,
+				TriggeredBy: structs.EvalTriggerJobDeregister,
+				JobID:       child.ID,
+				Status:      structs.EvalStatusPending,
+				CreateTime:  now,
+				ModifyTime:  now,
+			},
+			WriteRequest: structs.WriteRequest{
+				Namespace: child.Namespace,
+			},
+		}
+		if _, _, err := s.raftApply(structs.JobDeregisterRequestType, req); err != nil {
+			return fmt.Errorf("failed to stop child job %q: %v", child.ID, err)
+		}
+	}
+	return nil
+}
+
+// runningChildren returns the children of the passed job which have active
+// evaluations or running allocations. If first is set, it returns as soon as
+// one is found.
+func runningChildren(snap *state.StateSnapshot, job *structs.Job, first bool) ([]*structs.Job, error) {
 	ws := memdb.NewWatchSet()
 	prefix := fmt.Sprintf("%s%s", job.ID, structs.PeriodicLaunchSuffix)
 	iter, err := snap.JobsByIDPrefix(ws, job.Namespace, prefix, state.SortDefault)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	var child *structs.Job
+	var running []*structs.Job
 	for i := iter.Next(); i != nil; i = iter.Next() {
-		child = i.(*structs.Job)
+		child := i.(*structs.Job)
 
 		// Ensure the job is actually a child.
 		if child.ParentID != job.ID {
 			continue
 		}
 
-		// Get the childs evaluations.
-		evals, err := snap.EvalsByJob(ws, child.Namespace, child.ID)
+		active, err := childActive(snap, ws, child)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
-
-		// Check if any of the evals are active or have running allocations.
-		for _, eval := range evals {
-			if !eval.TerminalStatus() {
-				return true, nil
+		if active {
+			running = append(running, child)
+			if first {
+				break
 			}
+		}
+	}
 
-			allocs, err := snap.AllocsByEval(ws, eval.ID)
-			if err != nil {
-				return false, err
-			}
+	return running, nil
+}
 
-			for _, alloc := range allocs {
-				if !alloc.TerminalStatus() {
-					return true, nil
-				}
+// childActive checks whether any of the evaluations of the passed child job
+// are active or have running allocations.
+func childActive(snap *state.StateSnapshot, ws memdb.WatchSet, child *structs.Job) (bool, error) {
+	// Get the childs evaluations.
+	evals, err := snap.EvalsByJob(ws, child.Namespace, child.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, eval := range evals {
+		if !eval.TerminalStatus() {
+			return true, nil
+		}
+
+		allocs, err := snap.AllocsByEval(ws, eval.ID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, alloc := range allocs {
+			if !alloc.TerminalStatus() {
+				return true, nil
 			}
 		}
 	}
@@ -135,6 +231,56 @@ func (s *Server) RunningChildren(job *structs.Job) (bool, error) {
 	return false, nil
 }
 
+// LastLaunch returns the last launch time of the passed job recorded in the
+// periodic launch table. If the job was never launched, it is the time the job
+// was registered.
+func (s *Server) LastLaunch(job *structs.Job) (time.Time, error) {
+	launch, err := s.fsm.State().PeriodicLaunchByID(nil, job.Namespace, job.ID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if launch == nil {
+		return time.Time{}, nil
+	}
+	return launch.Launch, nil
+}
+
+// RecordDecision commits the decision taken by the concurrency policy of the
+// passed job to the raft log.
+func (s *Server) RecordDecision(job *structs.Job, decision *structs.PeriodicLaunchDecision) error {
+	req := structs.PeriodicLaunchDecisionRequest{
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Decision:  decision,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	_, _, err := s.raftApply(structs.PeriodicLaunchDecisionRequestType, req)
+	return err
+}
+
+// WatchChildren returns a channel which is closed once the summary of any of
+// the passed jobs changes, which happens whenever the status of one of their
+// children changes, or once the context is canceled.
+func (s *Server) WatchChildren(ctx context.Context, jobs []*structs.Job) (<-chan struct{}, error) {
+	state := s.fsm.State()
+	ws := memdb.NewWatchSet()
+	ws.Add(state.AbandonCh())
+	for _, job := range jobs {
+		if _, err := state.JobSummaryByID(ws, job.Namespace, job.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	ch := make(chan struct{})
+	go func() {
+		defer close(ch)
+		_ = ws.WatchCtx(ctx)
+	}()
+	return ch, nil
+}
+
 // NewPeriodicDispatch returns a periodic dispatcher that is used to track and
 // launch periodic jobs.
 func NewPeriodicDispatch(logger log.Logger, dispatcher JobEvalDispatcher) *PeriodicDispatch {
@@ -142,6 +288,8 @@ func NewPeriodicDispatch(logger log.Logger, dispatcher JobEvalDispatcher) *Perio
 		dispatcher: dispatcher,
 		tracked:    make(map[structs.NamespacedID]*structs.Job),
 		heap:       NewPeriodicHeap(),
+		catchUp:    make(map[structs.NamespacedID]time.Time),
+		queued:     make(map[structs.NamespacedID]time.Time),
 		updateCh:   make(chan struct{}, 1),
 		logger:     logger.Named("periodic"),
 	}
@@ -214,7 +362,8 @@ func (p *PeriodicDispatch) Add(job *structs.Job) error {
 
 	// Add or update the job.
 	p.tracked[tuple] = job
-	next, err := job.Periodic.Next(time.Now().In(job.Periodic.GetLocation()))
+	now := time.Now().In(job.Periodic.GetLocation())
+	next, err := job.Periodic.Next(now)
 	if err != nil {
 		return fmt.Errorf("failed adding job %s: %v", job.NamespacedID(), err)
 	}
@@ -228,6 +377,12 @@ func (p *PeriodicDispatch) Add(job *structs.Job) error {
 			return fmt.Errorf("failed to add job %v: %v", job.ID, err)
 		}
 		p.logger.Debug("registered periodic job", "job", job.NamespacedID())
+
+		// The job was just enabled or this server just became the leader,
+		// so launches may have been missed since the last one.
+		if job.Periodic.CatchesUp() {
+			p.catchUp[tuple] = now
+		}
 	}
 
 	// Signal an update.
@@ -264,6 +419,8 @@ func (p *PeriodicDispatch) removeLocked(jobID structs.NamespacedID) error {
 	}
 
 	delete(p.tracked, jobID)
+	delete(p.catchUp, jobID)
+	delete(p.queued, jobID)
 	if err := p.heap.Remove(job); err != nil {
 		return fmt.Errorf("failed to remove tracked job %q (%s): %v", jobID.ID, jobID.Namespace, err)
 	}
@@ -315,6 +472,14 @@ func (p *PeriodicDispatch) shouldRun() bool {
 func (p *PeriodicDispatch) run(ctx context.Context, updateCh <-chan struct{}) {
 	var launchCh <-chan time.Time
 	for p.shouldRun() {
+		// The children of the jobs with a queued launch are watched before
+		// being checked so that no change is missed.
+		queueCtx, cancelQueue := context.WithCancel(ctx)
+		queueCh := p.watchQueued(queueCtx)
+
+		p.runCatchUps()
+		p.runQueued()
+
 		job, launch := p.nextLaunch()
 		if launch.IsZero() {
 			launchCh = nil
@@ -326,12 +491,63 @@ func (p *PeriodicDispatch) run(ctx context.Context, updateCh <-chan struct{}) {
 
 		select {
 		case <-ctx.Done():
+			cancelQueue()
 			return
 		case <-updateCh:
-			continue
+		case <-queueCh:
 		case <-launchCh:
 			p.dispatch(job, launch)
 		}
+		cancelQueue()
+	}
+}
+
+// watchQueued returns a channel which is closed once the children of any job
+// with a queued launch change, or a nil channel if no launch is queued.
+func (p *PeriodicDispatch) watchQueued(ctx context.Context) <-chan struct{} {
+	p.l.RLock()
+	jobs := make([]*structs.Job, 0, len(p.queued))
+	for tuple := range p.queued {
+		if job, ok := p.tracked[tuple]; ok {
+			jobs = append(jobs, job)
+		}
+	}
+	p.l.RUnlock()
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	ch, err := p.dispatcher.WatchChildren(ctx, jobs)
+	if err != nil {
+		p.logger.Error("failed to watch children of periodic jobs with a queued launch", "error", err)
+		retry := make(chan struct{})
+		time.AfterFunc(periodicQueueRetryInterval, func() { close(retry) })
+		return retry
+	}
+	return ch
+}
+
+// RestoreQueued restores the launch of the job deferred by the queue
+// concurrency policy, as recorded in the state by the previous leader. It is a
+// no-op if the job isn't tracked or already has a queued launch.
+func (p *PeriodicDispatch) RestoreQueued(job *structs.Job, launch time.Time) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	tuple := job.NamespacedID()
+	if _, ok := p.tracked[tuple]; !ok {
+		return
+	}
+	if _, ok := p.queued[tuple]; ok {
+		return
+	}
+	p.queued[tuple] = launch
+	p.logger.Debug("restored queued launch of periodic job", "job", tuple, "launch_time", launch)
+
+	// Signal an update.
+	select {
+	case p.updateCh <- struct{}{}:
+	default:
 	}
 }
 
@@ -347,26 +563,197 @@ func (p *PeriodicDispatch) dispatch(job *structs.Job, launchTime time.Time) {
 		p.logger.Error("failed to update next launch of periodic job", "job", job.NamespacedID(), "error", err)
 	}
 
-	// If the job prohibits overlapping and there are running children, we skip
-	// the launch.
-	if job.Periodic.ProhibitOverlap {
+	p.l.Unlock()
+
+	launch, err := p.checkConcurrency(job, launchTime)
+	if err != nil {
+		p.logger.Error("failed to apply concurrency policy of periodic job", "job", job.NamespacedID(), "error", err)
+		return
+	}
+	if !launch {
+		return
+	}
+
+	p.logger.Debug(" launching job", "job", job.NamespacedID(), "launch_time", launchTime)
+	p.createEval(job, launchTime)
+}
+
+// checkConcurrency applies the concurrency policy of the job to the passed
+// launch and returns whether the job should be launched. The decisions taken
+// because previous instances of the job are running are recorded. This should
+// not be called with the lock held.
+func (p *PeriodicDispatch) checkConcurrency(job *structs.Job, launchTime time.Time) (bool, error) {
+	policy := job.Periodic.Concurrency()
+	if policy == structs.PeriodicConcurrencyAllow {
+		return true, nil
+	}
+
+	running, err := p.dispatcher.RunningChildren(job)
+	if err != nil {
+		return false, fmt.Errorf("failed to determine if periodic job has running children: %v", err)
+	}
+	if !running {
+		return true, nil
+	}
+
+	switch policy {
+	case structs.PeriodicConcurrencyReplace:
+		if err := p.dispatcher.StopChildren(job); err != nil {
+			return false, err
+		}
+		p.logger.Debug("stopped running instances of periodic job to replace them", "job", job.NamespacedID())
+		p.recordDecision(job, launchTime, structs.PeriodicDecisionReplaced,
+			"Stopped the running previous instance of the job")
+		return true, nil
+
+	case structs.PeriodicConcurrencyQueue:
+		tuple := job.NamespacedID()
+		p.l.Lock()
+		queued, ok := p.queued[tuple]
+		if !ok {
+			p.queued[tuple] = launchTime
+		}
+		p.l.Unlock()
+
+		if ok {
+			p.logger.Debug("skipping launch of periodic job because a launch is already queued", "job", tuple)
+			p.recordDecision(job, launchTime, structs.PeriodicDecisionSkipped,
+				fmt.Sprintf("Launch at %s is already queued", queued.UTC().Format(time.RFC3339)))
+			return false, nil
+		}
+
+		p.logger.Debug("queued launch of periodic job until running instance finishes", "job", tuple)
+		p.recordDecision(job, launchTime, structs.PeriodicDecisionQueued,
+			"Launch queued until the previous instance of the job finishes")
+		return false, nil
+
+	default:
+		p.logger.Debug("skipping launch of periodic job because job prohibits overlap", "job", job.NamespacedID())
+		p.recordDecision(job, launchTime, structs.PeriodicDecisionSkipped,
+			"Previous instance of the job is still running")
+		return false, nil
+	}
+}
+
+// recordDecision records a decision taken by the concurrency policy of the job.
+// Failures are only logged as they must not prevent the launch. This should not
+// be called with the lock held.
+func (p *PeriodicDispatch) recordDecision(job *structs.Job, launchTime time.Time, decision, desc string) {
+	err := p.dispatcher.RecordDecision(job, &structs.PeriodicLaunchDecision{
+		Decision:    decision,
+		Description: desc,
+		Launch:      launchTime.UTC(),
+		Time:        time.Now().UTC(),
+	})
+	if err != nil {
+		p.logger.Error("failed to record concurrency decision of periodic job", "job", job.NamespacedID(), "decision", decision, "error", err)
+	}
+}
+
+// hasQueued returns whether any launch is deferred by the queue concurrency
+// policy.
+func (p *PeriodicDispatch) hasQueued() bool {
+	p.l.RLock()
+	defer p.l.RUnlock()
+	return len(p.queued) != 0
+}
+
+// runQueued launches the queued launches of the jobs whose previous instances
+// finished running.
+func (p *PeriodicDispatch) runQueued() {
+	p.l.Lock()
+	if len(p.queued) == 0 {
+		p.l.Unlock()
+		return
+	}
+	pending := make(map[*structs.Job]time.Time, len(p.queued))
+	for tuple, launch := range p.queued {
+		if job, ok := p.tracked[tuple]; ok {
+			pending[job] = launch
+		} else {
+			delete(p.queued, tuple)
+		}
+	}
+	p.l.Unlock()
+
+	for job, launch := range pending {
 		running, err := p.dispatcher.RunningChildren(job)
 		if err != nil {
 			p.logger.Error("failed to determine if periodic job has running children", "job", job.NamespacedID(), "error", err)
-			p.l.Unlock()
-			return
+			continue
 		}
-
 		if running {
-			p.logger.Debug("skipping launch of periodic job because job prohibits overlap", "job", job.NamespacedID())
-			p.l.Unlock()
-			return
+			continue
+		}
+
+		// The job may have been removed or queued again meanwhile
+		tuple := job.NamespacedID()
+		p.l.Lock()
+		current, ok := p.queued[tuple]
+		stillQueued := ok && current.Equal(launch)
+		if stillQueued {
+			delete(p.queued, tuple)
+		}
+		p.l.Unlock()
+		if !stillQueued {
+			continue
 		}
+
+		// The dequeued decision clears the queued launch from the state
+		// before the launch, so a new leader never launches it twice.
+		p.logger.Debug("launching queued launch of periodic job", "job", tuple, "launch_time", launch)
+		p.recordDecision(job, launch, structs.PeriodicDecisionDequeued,
+			"Previous instance of the job finished running")
+		p.createEval(job, launch)
 	}
+}
 
-	p.logger.Debug(" launching job", "job", job.NamespacedID(), "launch_time", launchTime)
+// runCatchUps launches the missed launches of the jobs pending to be caught up
+// according to their catch-up policy.
+func (p *PeriodicDispatch) runCatchUps() {
+	p.l.Lock()
+	if len(p.catchUp) == 0 {
+		p.l.Unlock()
+		return
+	}
+	pending := make(map[*structs.Job]time.Time, len(p.catchUp))
+	for tuple, until := range p.catchUp {
+		if job, ok := p.tracked[tuple]; ok {
+			pending[job] = until
+		}
+	}
+	p.catchUp = make(map[structs.NamespacedID]time.Time)
 	p.l.Unlock()
-	p.createEval(job, launchTime)
+
+	for job, until := range pending {
+		last, err := p.dispatcher.LastLaunch(job)
+		if err != nil {
+			p.logger.Error("failed to determine last launch of periodic job", "job", job.NamespacedID(), "error", err)
+			continue
+		}
+
+		missed, err := job.Periodic.MissedLaunches(last.In(job.Periodic.GetLocation()), until)
+		if err != nil {
+			p.logger.Error("failed to determine missed launches of periodic job", "job", job.NamespacedID(), "error", err)
+			continue
+		}
+
+		for _, launch := range missed {
+			ok, err := p.checkConcurrency(job, launch)
+			if err != nil {
+				p.logger.Error("failed to apply concurrency policy of periodic job", "job", job.NamespacedID(), "error", err)
+				break
+			}
+			if !ok {
+				break
+			}
+
+			p.logger.Debug("catching up missed launch of periodic job", "job", job.NamespacedID(), "launch_time", launch)
+			if _, err := p.createCatchUpEval(job, launch); err != nil {
+				break
+			}
+		}
+	}
 }
 
 // nextLaunch returns the next job to launch and when it should be launched. If
@@ -405,6 +792,28 @@ func (p *PeriodicDispatch) createEval(periodicJob *structs.Job, time time.Time)
 	return eval, nil
 }
 
+// createCatchUpEval instantiates a job for a missed launch of the passed
+// periodic job and submits an evaluation for it. The derived job's meta records
+// the missed launch time. This should not be called with the lock held.
+func (p *PeriodicDispatch) createCatchUpEval(periodicJob *structs.Job, launch time.Time) (*structs.Evaluation, error) {
+	derived, err := p.deriveJob(periodicJob, launch)
+	if err != nil {
+		return nil, err
+	}
+	if derived.Meta == nil {
+		derived.Meta = make(map[string]string, 1)
+	}
+	derived.Meta[structs.PeriodicCatchUpMetaKey] = launch.UTC().Format(time.RFC3339)
+
+	eval, err := p.dispatcher.DispatchJob(derived)
+	if err != nil {
+		p.logger.Error("failed to dispatch job", "job", periodicJob.NamespacedID(), "error", err)
+		return nil, err
+	}
+
+	return eval, nil
+}
+
 // deriveJob instantiates a new job based on the passed periodic job and the
 // launch time.
 func (p *PeriodicDispatch) deriveJob(periodicJob *structs.Job, time time.Time) (
@@ -462,6 +871,8 @@ func (p *PeriodicDispatch) flush() {
 	p.updateCh = make(chan struct{}, 1)
 	p.tracked = make(map[structs.NamespacedID]*structs.Job)
 	p.heap = NewPeriodicHeap()
+	p.catchUp = make(map[structs.NamespacedID]time.Time)
+	p.queued = make(map[structs.NamespacedID]time.Time)
 	p.stopFn = nil
 }
 
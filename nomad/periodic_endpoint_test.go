@@ -5,6 +5,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -198,3 +200,52 @@ func TestPeriodicEndpoint_Force_NonPeriodic(t *testing.T) {
 		t.Fatalf("Force on non-periodic job should err")
 	}
 }
+
+func TestPeriodicEndpoint_GetLaunch(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create and insert a periodic job and its launch.
+	job := mock.PeriodicJob()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+	must.NoError(t, state.UpsertPeriodicLaunch(101, &structs.PeriodicLaunch{
+		ID:        job.ID,
+		Namespace: job.Namespace,
+		Launch:    time.Now(),
+	}))
+
+	decision := &structs.PeriodicLaunchDecision{
+		Decision:    structs.PeriodicDecisionSkipped,
+		Description: "Previous instance of the job is still running",
+		Launch:      time.Now().UTC(),
+		Time:        time.Now().UTC(),
+	}
+	must.NoError(t, s1.RecordDecision(job, decision))
+
+	req := &structs.PeriodicLaunchRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.PeriodicLaunchResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Periodic.GetLaunch", req, &resp))
+	must.NotNil(t, resp.Launch)
+	must.Eq(t, resp.Launch.ModifyIndex, resp.Index)
+	must.Eq(t, decision.Decision, resp.Launch.Decision.Decision)
+	must.Eq(t, decision.Description, resp.Launch.Decision.Description)
+
+	// An unknown job has no launch
+	req.JobID = "unknown"
+	resp = structs.PeriodicLaunchResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Periodic.GetLaunch", req, &resp))
+	must.Nil(t, resp.Launch)
+}
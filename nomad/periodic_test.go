@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -26,11 +27,30 @@ import (
 
 type MockJobEvalDispatcher struct {
 	Jobs map[structs.NamespacedID]*structs.Job
+
+	// lastLaunch is the last launch time returned for any job.
+	lastLaunch time.Time
+
+	// decisions are the recorded decisions of the concurrency policies.
+	decisions []*structs.PeriodicLaunchDecision
+
+	// changeCh is closed and replaced whenever the jobs change.
+	changeCh chan struct{}
+
 	lock sync.Mutex
 }
 
 func NewMockJobEvalDispatcher() *MockJobEvalDispatcher {
-	return &MockJobEvalDispatcher{Jobs: make(map[structs.NamespacedID]*structs.Job)}
+	return &MockJobEvalDispatcher{
+		Jobs:     make(map[structs.NamespacedID]*structs.Job),
+		changeCh: make(chan struct{}),
+	}
+}
+
+// notifyChange notifies the watchers of the jobs. The lock must be held.
+func (m *MockJobEvalDispatcher) notifyChange() {
+	close(m.changeCh)
+	m.changeCh = make(chan struct{})
 }
 
 func (m *MockJobEvalDispatcher) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
@@ -41,6 +61,7 @@ func (m *MockJobEvalDispatcher) DispatchJob(job *structs.Job) (*structs.Evaluati
 		Namespace: job.Namespace,
 	}
 	m.Jobs[tuple] = job
+	m.notifyChange()
 	return nil, nil
 }
 
@@ -48,13 +69,65 @@ func (m *MockJobEvalDispatcher) RunningChildren(parent *structs.Job) (bool, erro
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	for _, job := range m.Jobs {
-		if job.ParentID == parent.ID && job.Namespace == parent.Namespace {
+		if job.ParentID == parent.ID && job.Namespace == parent.Namespace && !job.Stop {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+func (m *MockJobEvalDispatcher) LastLaunch(_ *structs.Job) (time.Time, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.lastLaunch, nil
+}
+
+func (m *MockJobEvalDispatcher) StopChildren(parent *structs.Job) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, job := range m.Jobs {
+		if job.ParentID == parent.ID && job.Namespace == parent.Namespace {
+			job.Stop = true
+		}
+	}
+	m.notifyChange()
+	return nil
+}
+
+func (m *MockJobEvalDispatcher) RecordDecision(_ *structs.Job, decision *structs.PeriodicLaunchDecision) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.decisions = append(m.decisions, decision)
+	return nil
+}
+
+func (m *MockJobEvalDispatcher) WatchChildren(ctx context.Context, _ []*structs.Job) (<-chan struct{}, error) {
+	m.lock.Lock()
+	changeCh := m.changeCh
+	m.lock.Unlock()
+
+	ch := make(chan struct{})
+	go func() {
+		defer close(ch)
+		select {
+		case <-changeCh:
+		case <-ctx.Done():
+		}
+	}()
+	return ch, nil
+}
+
+// recordedDecisions returns the recorded decisions in order.
+func (m *MockJobEvalDispatcher) recordedDecisions() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	decisions := make([]string, len(m.decisions))
+	for i, d := range m.decisions {
+		decisions[i] = d.Decision
+	}
+	return decisions
+}
+
 // LaunchTimes returns the launch times of child jobs in sorted order.
 func (m *MockJobEvalDispatcher) LaunchTimes(p *PeriodicDispatch, namespace, parentID string) ([]time.Time, error) {
 	m.lock.Lock()
@@ -428,6 +501,182 @@ func TestPeriodicDispatch_Run_DisallowOverlaps(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_Run_ConcurrencyPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name         string
+		policy       string
+		expLaunches  int
+		expDecisions []string
+		expStopped   bool
+	}{
+		{
+			name:         "forbid",
+			policy:       structs.PeriodicConcurrencyForbid,
+			expLaunches:  1,
+			expDecisions: []string{structs.PeriodicDecisionSkipped, structs.PeriodicDecisionSkipped},
+		},
+		{
+			name:        "replace",
+			policy:      structs.PeriodicConcurrencyReplace,
+			expLaunches: 3,
+			expDecisions: []string{
+				structs.PeriodicDecisionReplaced,
+				structs.PeriodicDecisionReplaced,
+			},
+			expStopped: true,
+		},
+		{
+			// The third launch is skipped as the second one is queued
+			name:        "queue",
+			policy:      structs.PeriodicConcurrencyQueue,
+			expLaunches: 1,
+			expDecisions: []string{
+				structs.PeriodicDecisionQueued,
+				structs.PeriodicDecisionSkipped,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ci.Parallel(t)
+			p, m := testPeriodicDispatcher(t)
+
+			now := time.Now().Round(1 * time.Second)
+			launches := []time.Time{
+				now.Add(1 * time.Second),
+				now.Add(2 * time.Second),
+				now.Add(3 * time.Second),
+			}
+			job := testPeriodicJob(launches...)
+			job.Periodic.ConcurrencyPolicy = tc.policy
+			must.NoError(t, p.Add(job))
+
+			time.Sleep(4 * time.Second)
+
+			times, err := m.LaunchTimes(p, job.Namespace, job.ID)
+			must.NoError(t, err)
+			must.Eq(t, launches[:tc.expLaunches], times)
+			must.Eq(t, tc.expDecisions, m.recordedDecisions())
+
+			// Only the last instance is left running when replacing
+			for _, child := range m.dispatchedJobs(job) {
+				launch, err := p.LaunchTime(child.ID)
+				must.NoError(t, err)
+				must.Eq(t, tc.expStopped && !launch.Equal(launches[2]), child.Stop)
+			}
+		})
+	}
+}
+
+func TestPeriodicDispatch_Run_ConcurrencyQueue(t *testing.T) {
+	ci.Parallel(t)
+	p, m := testPeriodicDispatcher(t)
+
+	launch1 := time.Now().Round(1 * time.Second).Add(1 * time.Second)
+	launch2 := time.Now().Round(1 * time.Second).Add(2 * time.Second)
+	job := testPeriodicJob(launch1, launch2)
+	job.Periodic.ConcurrencyPolicy = structs.PeriodicConcurrencyQueue
+	must.NoError(t, p.Add(job))
+
+	time.Sleep(3 * time.Second)
+	must.Eq(t, []string{structs.PeriodicDecisionQueued}, m.recordedDecisions())
+
+	// The queued launch runs once the first instance finishes
+	must.NoError(t, m.StopChildren(job))
+	testutil.WaitForResult(func() (bool, error) {
+		times, err := m.LaunchTimes(p, job.Namespace, job.ID)
+		if err != nil {
+			return false, err
+		}
+		if !reflect.DeepEqual(times, []time.Time{launch1, launch2}) {
+			return false, fmt.Errorf("got launches %v", times)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	must.Eq(t, []string{
+		structs.PeriodicDecisionQueued,
+		structs.PeriodicDecisionDequeued,
+	}, m.recordedDecisions())
+	must.False(t, p.hasQueued())
+}
+
+func TestPeriodicDispatch_CatchUp(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().Round(time.Second)
+	launches := []time.Time{
+		now.Add(-3 * time.Hour),
+		now.Add(-2 * time.Hour),
+		now.Add(-1 * time.Hour),
+		now.Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name     string
+		policy   string
+		expected []time.Time
+	}{
+		{
+			name:   "skip",
+			policy: structs.PeriodicCatchUpSkip,
+		},
+		{
+			name:     "latest",
+			policy:   structs.PeriodicCatchUpLatest,
+			expected: []time.Time{launches[2]},
+		},
+		{
+			// The first missed launch is older than the starting deadline.
+			name:     "all",
+			policy:   structs.PeriodicCatchUpAll,
+			expected: []time.Time{launches[1], launches[2]},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, m := testPeriodicDispatcher(t)
+			m.lastLaunch = now.Add(-4 * time.Hour)
+
+			job := testPeriodicJob(launches...)
+			job.Periodic.CatchUp = tc.policy
+			job.Periodic.StartingDeadline = 150 * time.Minute
+			must.NoError(t, p.Add(job))
+
+			if len(tc.expected) == 0 {
+				time.Sleep(100 * time.Millisecond)
+				must.SliceEmpty(t, m.dispatchedJobs(job))
+				return
+			}
+
+			testutil.WaitForResult(func() (bool, error) {
+				times, err := m.LaunchTimes(p, job.Namespace, job.ID)
+				if err != nil {
+					return false, err
+				}
+				if !reflect.DeepEqual(times, tc.expected) {
+					return false, fmt.Errorf("got launches %v; want %v", times, tc.expected)
+				}
+				return true, nil
+			}, func(err error) {
+				t.Fatal(err)
+			})
+
+			for _, derived := range m.dispatchedJobs(job) {
+				launch, err := p.LaunchTime(derived.ID)
+				must.NoError(t, err)
+				must.Eq(t, launch.UTC().Format(time.RFC3339), derived.Meta[structs.PeriodicCatchUpMetaKey])
+			}
+		})
+	}
+}
+
 func TestPeriodicDispatch_Run_Multiple(t *testing.T) {
 	ci.Parallel(t)
 	p, m := testPeriodicDispatcher(t)
@@ -773,6 +1022,39 @@ func TestPeriodicDispatch_RunningChildren_ActiveAllocs(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_StopChildren(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert periodic job and a child with a pending eval.
+	state := s1.fsm.State()
+	job := mock.PeriodicJob()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	childjob := deriveChildJob(job)
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, childjob))
+
+	eval := mock.Eval()
+	eval.JobID = childjob.ID
+	eval.Status = structs.EvalStatusPending
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	must.NoError(t, s1.StopChildren(job))
+
+	out, err := state.JobByID(nil, childjob.Namespace, childjob.ID)
+	must.NoError(t, err)
+	must.True(t, out.Stop)
+
+	evals, err := state.EvalsByJob(nil, childjob.Namespace, childjob.ID)
+	must.NoError(t, err)
+	must.Len(t, 2, evals)
+}
+
 // TestPeriodicDispatch_JobEmptyStatus asserts that dispatched
 // job will always has an empty status
 func TestPeriodicDispatch_JobEmptyStatus(t *testing.T) {
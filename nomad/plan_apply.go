@@ -13,10 +13,13 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	metrics "github.com/hashicorp/go-metrics/compat"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // planner is used to manage the submitted allocation plans that are waiting
@@ -124,6 +127,7 @@ func (p *planner) planApply() {
 		if err != nil {
 			return
 		}
+		pending.startSpan("plan.queue", trace.WithTimestamp(pending.enqueueTime)).End()
 
 		// If last plan has completed get a new snapshot
 		select {
@@ -162,7 +166,9 @@ func (p *planner) planApply() {
 		}
 
 		// Evaluate the plan
+		span := pending.startSpan("plan.evaluate")
 		result, err := evaluatePlan(pool, snap, pending.plan, p.srv.logger)
+		tracing.End(span, err)
 		if err != nil {
 			p.srv.logger.Error("failed to evaluate plan", "error", err)
 			pending.respond(nil, err)
@@ -197,16 +203,18 @@ func (p *planner) planApply() {
 		}
 
 		// Dispatch the Raft transaction for the plan
+		span = pending.startSpan("plan.apply")
 		future, err := p.applyPlan(pending.plan, result, snap)
 		if err != nil {
 			p.srv.logger.Error("failed to submit plan", "error", err)
+			tracing.End(span, err)
 			pending.respond(nil, err)
 			continue
 		}
 
 		// Respond to the plan in async; receive plan's committed index via chan
 		planIndexCh = make(chan uint64, 1)
-		go p.asyncPlanWait(planIndexCh, future, result, pending)
+		go p.asyncPlanWait(planIndexCh, future, result, pending, span)
 	}
 }
 
@@ -441,13 +449,14 @@ func signAllocIdentities(signer claimSigner, job *structs.Job, allocations []*st
 // commit the plan's index will be sent on the chan. On error the chan will be
 // closed.
 func (p *planner) asyncPlanWait(indexCh chan<- uint64, future raft.ApplyFuture,
-	result *structs.PlanResult, pending *pendingPlan) {
+	result *structs.PlanResult, pending *pendingPlan, span trace.Span) {
 	defer metrics.MeasureSince([]string{"nomad", "plan", "apply"}, time.Now())
 	defer close(indexCh)
 
 	// Wait for the plan to apply
 	if err := future.Error(); err != nil {
 		p.srv.logger.Error("failed to apply plan", "error", err)
+		tracing.End(span, err)
 		pending.respond(nil, err)
 		return
 	}
@@ -455,6 +464,8 @@ func (p *planner) asyncPlanWait(indexCh chan<- uint64, future raft.ApplyFuture,
 	// Respond to the plan
 	index := future.Index()
 	result.AllocIndex = index
+	span.SetAttributes(attribute.Int64("nomad.raft.index", int64(index)))
+	span.End()
 
 	// If this is a partial plan application, we need to ensure the scheduler
 	// at least has visibility into any placements it made to avoid double placement.
@@ -541,6 +552,10 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 	partialCommit := false
 	rejectedNodes := make(map[string]struct{}, 0)
 
+	// Plans placing gang scheduled task groups are applied atomically, since
+	// rejecting the portion of a single node would partially place a gang.
+	allAtOnce := plan.RequiresAllAtOnce()
+
 	// handleResult is used to process the result of evaluateNodePlan
 	handleResult := func(nodeID string, fit bool, reason string, err error) (cancel bool) {
 		// Evaluate the plan for this node
@@ -571,7 +586,7 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 
 			// If we require all-at-once scheduling, there is no point
 			// to continue the evaluation, as we've already failed.
-			if plan.AllAtOnce {
+			if allAtOnce {
 				result.NodeUpdate = nil
 				result.NodeAllocation = nil
 				result.DeploymentUpdates = nil
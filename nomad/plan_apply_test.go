@@ -757,6 +757,50 @@ func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Partial_Gang(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	node2 := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+	snap, err := state.Snapshot()
+	must.NoError(t, err)
+
+	job := mock.Job()
+	job.TaskGroups[0].Gang = "training"
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc2 := mock.Alloc() // Ensure alloc2 does not fit
+	alloc2.Job = job
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc},
+			node2.ID: {alloc2},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.NotNil(t, result)
+	must.MapLen(t, 0, result.NodeAllocation)
+	must.Eq(t, 1001, result.RefreshIndex)
+
+	// Without a gang the portion of the node that fits is applied
+	job.TaskGroups[0].Gang = ""
+	result, err = evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.MapLen(t, 1, result.NodeAllocation)
+	must.Len(t, 1, result.NodeAllocation[node.ID])
+	must.Eq(t, alloc.ID, result.NodeAllocation[node.ID][0].ID)
+}
+
 func TestPlanApply_EvalNodePlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
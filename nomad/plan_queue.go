@@ -11,7 +11,10 @@ import (
 
 	metrics "github.com/hashicorp/go-metrics/compat"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -66,6 +69,14 @@ func (p *pendingPlan) Wait() (*structs.PlanResult, error) {
 	return p.result, err
 }
 
+// startSpan starts a span for a stage of applying the plan, which is recorded
+// as part of the trace of the scheduler that submitted it.
+func (p *pendingPlan) startSpan(name string, opts ...trace.SpanStartOption) trace.Span {
+	opts = append(opts, trace.WithAttributes(attribute.String("nomad.eval.id", p.plan.EvalID)))
+	_, span := tracing.Continue(p.plan.TraceContext, name, opts...)
+	return span
+}
+
 // respond is used to set the response and error for the future
 func (p *pendingPlan) respond(result *structs.PlanResult, err error) {
 	p.result = result
@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package rebalancer
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+// DefaultCheckInterval is how often the rebalancer checks the placement of
+// the jobs which opted into being rebalanced.
+const DefaultCheckInterval = time.Minute
+
+// RaftApplier contains methods for applying the rebalancer's decisions via
+// Raft.
+type RaftApplier interface {
+	// AllocUpdateDesiredTransition is used to mark allocations for migration
+	// and create the evaluations which migrate them.
+	AllocUpdateDesiredTransition(allocs map[string]*structs.DesiredTransition, evals []*structs.Evaluation) (uint64, error)
+}
+
+// Rebalancer periodically scores the placement of the running allocations of
+// the service jobs with a rebalance policy, and marks for migration those
+// which would score significantly better on another node. It only runs on the
+// leader.
+//
+// The number of allocations moved is bounded by the max disruption of each
+// job's rebalance policy and by the max parallel of each task group's migrate
+// block, which is applied the same way as when draining nodes. Moves are
+// derived from the allocations in state, so they are kept across leader
+// elections.
+type Rebalancer struct {
+	enabled bool
+	logger  log.Logger
+	raft    RaftApplier
+
+	// checkInterval is how often the jobs are checked.
+	checkInterval time.Duration
+
+	// state is the state store the jobs and allocations are read from.
+	state *state.StateStore
+
+	// ctx and exitFn are used to cancel the rebalancer
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewRebalancer returns a rebalancer which checks jobs at the given interval
+// once enabled.
+func NewRebalancer(logger log.Logger, raft RaftApplier, checkInterval time.Duration) *Rebalancer {
+	return &Rebalancer{
+		logger:        logger.Named("rebalancer"),
+		raft:          raft,
+		checkInterval: checkInterval,
+	}
+}
+
+// SetEnabled is used to control if the rebalancer is enabled. The rebalancer
+// should only be enabled on the active leader. When being enabled the state is
+// passed in as it is no longer valid once a leader election has taken place.
+func (r *Rebalancer) SetEnabled(enabled bool, state *state.StateStore) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	wasEnabled := r.enabled
+	r.enabled = enabled
+	if state != nil {
+		r.state = state
+	}
+
+	if enabled && !wasEnabled {
+		r.ctx, r.exitFn = context.WithCancel(context.Background())
+		go r.run(r.ctx)
+	} else if !enabled && wasEnabled {
+		r.exitFn()
+	}
+}
+
+// run checks the jobs every check interval until the context is canceled.
+func (r *Rebalancer) run(ctx context.Context) {
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.rebalance(time.Now()); err != nil {
+				r.logger.Error("failed to rebalance jobs", "error", err)
+			}
+		}
+	}
+}
+
+// rebalance marks the allocations of every job with a rebalance policy which
+// would score better on another node for migration.
+func (r *Rebalancer) rebalance(now time.Time) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	snap, err := r.state.Snapshot()
+	if err != nil {
+		return err
+	}
+	iter, err := snap.Jobs(nil, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	transitions := map[string]*structs.DesiredTransition{}
+	moved := map[structs.NamespacedID]int{}
+	var evals []*structs.Evaluation
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.Rebalance == nil || job.Type != structs.JobTypeService || job.Stopped() {
+			continue
+		}
+
+		allocs, err := r.rebalanceJob(snap, job, now)
+		if err != nil {
+			r.logger.Error("failed to rebalance job", "namespace", job.Namespace,
+				"job_id", job.ID, "error", err)
+			continue
+		}
+		if len(allocs) == 0 {
+			continue
+		}
+
+		for _, alloc := range allocs {
+			transitions[alloc.ID] = &structs.DesiredTransition{
+				Migrate: pointer.Of(true),
+			}
+		}
+		moved[job.NamespacedID()] = len(allocs)
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   job.Namespace,
+			Priority:    job.Priority,
+			Type:        job.Type,
+			TriggeredBy: structs.EvalTriggerRebalance,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now.UTC().UnixNano(),
+			ModifyTime:  now.UTC().UnixNano(),
+		})
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+	if _, err := r.raft.AllocUpdateDesiredTransition(transitions, evals); err != nil {
+		return err
+	}
+
+	for id, n := range moved {
+		r.logger.Info("migrating allocations to rebalance job",
+			"namespace", id.Namespace, "job_id", id.ID, "allocs", n)
+	}
+	return nil
+}
+
+// rebalanceJob returns the allocations of the job to migrate, which are those
+// that would improve their score the most, within the limits of the job's
+// rebalance policy and its task groups' migrate blocks.
+func (r *Rebalancer) rebalanceJob(snap *state.StateSnapshot, job *structs.Job, now time.Time) ([]*structs.Allocation, error) {
+	policy := job.Rebalance
+
+	// Don't interfere with rollouts.
+	deployment, err := snap.LatestDeploymentByJobID(nil, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment != nil && deployment.Active() {
+		return nil, nil
+	}
+
+	allocs, err := snap.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := recentMoves(snap, allocs, policy.Interval, now)
+	if err != nil {
+		return nil, err
+	}
+	budget := policy.MaxDisruption - recent
+	if budget <= 0 {
+		return nil, nil
+	}
+
+	// Determine how many allocations of each task group can be moved. As
+	// when draining, a task group keeps at least its count minus its migrate
+	// max parallel of healthy allocations.
+	movable := make(map[string]int, len(job.TaskGroups))
+	healthy := make(map[string]int, len(job.TaskGroups))
+	var running []*structs.Allocation
+	for _, alloc := range allocs {
+		if alloc.Job == nil || alloc.Job.Version != job.Version || alloc.TerminalStatus() {
+			continue
+		}
+		if alloc.DeploymentStatus.IsHealthy() && !alloc.DesiredTransition.ShouldMigrate() {
+			healthy[alloc.TaskGroup]++
+			if alloc.ClientStatus == structs.AllocClientStatusRunning {
+				running = append(running, alloc)
+			}
+		}
+	}
+	for _, tg := range job.TaskGroups {
+		if tg.Migrate == nil {
+			continue
+		}
+		movable[tg.Name] = healthy[tg.Name] - (tg.Count - tg.Migrate.MaxParallel)
+	}
+	running = slices.DeleteFunc(running, func(alloc *structs.Allocation) bool {
+		return movable[alloc.TaskGroup] <= 0
+	})
+	if len(running) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := scheduler.RebalanceCandidates(r.logger, snap, job, running, policy.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*structs.Allocation
+	for _, c := range candidates {
+		if len(out) == budget {
+			break
+		}
+		if movable[c.Alloc.TaskGroup] <= 0 {
+			continue
+		}
+		movable[c.Alloc.TaskGroup]--
+		out = append(out, c.Alloc)
+		r.logger.Debug("allocation would score better on another node",
+			"alloc_id", c.Alloc.ID, "node_id", c.Alloc.NodeID, "score", c.CurrentScore,
+			"best_node_id", c.NodeID, "best_score", c.BestScore)
+	}
+	return out, nil
+}
+
+// recentMoves returns the number of allocations of the job moved within the
+// interval. Allocations marked for migration which haven't been replaced yet
+// are moving, as are the replacements placed by the rebalancer's evaluations
+// within the interval.
+func recentMoves(snap *state.StateSnapshot, allocs []*structs.Allocation, interval time.Duration, now time.Time) (int, error) {
+	rebalanceEvals := map[string]bool{}
+	n := 0
+	for _, alloc := range allocs {
+		if alloc.DesiredTransition.ShouldMigrate() && alloc.NextAllocation == "" && !alloc.ServerTerminalStatus() {
+			n++
+			continue
+		}
+		if alloc.PreviousAllocation == "" || now.Sub(time.Unix(0, alloc.CreateTime)) >= interval {
+			continue
+		}
+
+		rebalance, ok := rebalanceEvals[alloc.EvalID]
+		if !ok {
+			eval, err := snap.EvalByID(nil, alloc.EvalID)
+			if err != nil {
+				return 0, err
+			}
+			rebalance = eval != nil && eval.TriggeredBy == structs.EvalTriggerRebalance
+			rebalanceEvals[alloc.EvalID] = rebalance
+		}
+		if rebalance {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package rebalancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testRaft applies the rebalancer's decisions to a state store.
+type testRaft struct {
+	state *state.StateStore
+	index uint64
+	evals []*structs.Evaluation
+}
+
+func (r *testRaft) AllocUpdateDesiredTransition(allocs map[string]*structs.DesiredTransition, evals []*structs.Evaluation) (uint64, error) {
+	r.index++
+	r.evals = append(r.evals, evals...)
+	return r.index, r.state.UpdateAllocsDesiredTransitions(structs.MsgTypeTestSetup, r.index, allocs, evals)
+}
+
+// testRebalancer returns a rebalancer of a state store with three nodes and a
+// job with the rebalance policy whose three allocations are all running on the
+// first node.
+func testRebalancer(t *testing.T, maxParallel int, policy *structs.RebalancePolicy) (*Rebalancer, *testRaft, *structs.Job) {
+	store := state.TestStateStore(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Migrate.MaxParallel = maxParallel
+	job.Rebalance = policy
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	var allocs []*structs.Allocation
+	for i := range 3 {
+		alloc := mock.Alloc()
+		alloc.ID = uuid.Generate()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[0].ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		alloc.AllocatedResources.Shared.Ports = nil
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 300, allocs))
+
+	raft := &testRaft{state: store, index: 1000}
+	r := NewRebalancer(testlog.HCLogger(t), raft, time.Hour)
+	r.state = store
+	return r, raft, job
+}
+
+// testPolicy returns a rebalance policy with the given max disruption.
+func testPolicy(maxDisruption int) *structs.RebalancePolicy {
+	return &structs.RebalancePolicy{
+		Threshold:     0.2,
+		MaxDisruption: maxDisruption,
+		Interval:      10 * time.Minute,
+	}
+}
+
+// migrating returns the number of allocations of the job marked for migration.
+func migrating(t *testing.T, store *state.StateStore, job *structs.Job) int {
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	n := 0
+	for _, alloc := range allocs {
+		if alloc.DesiredTransition.ShouldMigrate() {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRebalancer_MigrateMaxParallel(t *testing.T) {
+	ci.Parallel(t)
+
+	r, raft, job := testRebalancer(t, 1, testPolicy(3))
+	now := time.Now()
+
+	must.NoError(t, r.rebalance(now))
+	must.Eq(t, 1, migrating(t, raft.state, job))
+	must.Len(t, 1, raft.evals)
+	must.Eq(t, structs.EvalTriggerRebalance, raft.evals[0].TriggeredBy)
+	must.Eq(t, job.ID, raft.evals[0].JobID)
+
+	// No other allocation is moved until the migrating one is replaced.
+	must.NoError(t, r.rebalance(now.Add(time.Minute)))
+	must.Eq(t, 1, migrating(t, raft.state, job))
+	must.Len(t, 1, raft.evals)
+}
+
+// replaceMigrating replaces the allocations of the job marked for migration
+// as the scheduler would, placing the replacements with the given eval at the
+// given time.
+func replaceMigrating(t *testing.T, raft *testRaft, job *structs.Job, evalID string, now time.Time) {
+	allocs, err := raft.state.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+
+	var updates []*structs.Allocation
+	for _, alloc := range allocs {
+		if !alloc.DesiredTransition.ShouldMigrate() || alloc.NextAllocation != "" {
+			continue
+		}
+		replacement := alloc.Copy()
+		replacement.ID = uuid.Generate()
+		replacement.EvalID = evalID
+		replacement.PreviousAllocation = alloc.ID
+		replacement.DesiredTransition = structs.DesiredTransition{}
+		replacement.ClientStatus = structs.AllocClientStatusPending
+		replacement.DeploymentStatus = nil
+		replacement.CreateTime = now.UnixNano()
+
+		stopped := alloc.Copy()
+		stopped.DesiredStatus = structs.AllocDesiredStatusStop
+		stopped.NextAllocation = replacement.ID
+		updates = append(updates, stopped, replacement)
+	}
+
+	raft.index++
+	must.NoError(t, raft.state.UpsertAllocs(structs.MsgTypeTestSetup, raft.index, updates))
+}
+
+func TestRebalancer_MaxDisruption(t *testing.T) {
+	ci.Parallel(t)
+
+	r, raft, job := testRebalancer(t, 3, testPolicy(2))
+	now := time.Now()
+
+	must.NoError(t, r.rebalance(now))
+	must.Eq(t, 2, migrating(t, raft.state, job))
+	must.Len(t, 1, raft.evals)
+
+	// Moves count towards the max disruption until they are replaced.
+	must.NoError(t, r.rebalance(now.Add(20*time.Minute)))
+	must.Len(t, 1, raft.evals)
+
+	// Replacements count towards the max disruption until the interval
+	// passes.
+	replaceMigrating(t, raft, job, raft.evals[0].ID, now.Add(20*time.Minute))
+	must.NoError(t, r.rebalance(now.Add(21*time.Minute)))
+	must.Len(t, 1, raft.evals)
+
+	must.NoError(t, r.rebalance(now.Add(30*time.Minute)))
+	must.Len(t, 2, raft.evals)
+}
+
+func TestRebalancer_LeaderElection(t *testing.T) {
+	ci.Parallel(t)
+
+	r, raft, job := testRebalancer(t, 3, testPolicy(2))
+	now := time.Now()
+
+	must.NoError(t, r.rebalance(now))
+	must.Eq(t, 2, migrating(t, raft.state, job))
+
+	// A newly elected leader doesn't move more allocations within the
+	// interval.
+	replaceMigrating(t, raft, job, raft.evals[0].ID, now)
+	leader := NewRebalancer(testlog.HCLogger(t), raft, time.Hour)
+	leader.state = raft.state
+	must.NoError(t, leader.rebalance(now.Add(time.Minute)))
+	must.Len(t, 1, raft.evals)
+}
+
+func TestRebalancer_Skipped(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("no rebalance policy", func(t *testing.T) {
+		r, raft, job := testRebalancer(t, 1, nil)
+
+		must.NoError(t, r.rebalance(time.Now()))
+		must.Zero(t, migrating(t, raft.state, job))
+	})
+
+	t.Run("active deployment", func(t *testing.T) {
+		r, raft, job := testRebalancer(t, 1, testPolicy(1))
+		d := mock.Deployment()
+		d.JobID = job.ID
+		d.JobVersion = job.Version
+		must.NoError(t, raft.state.UpsertDeployment(400, d))
+
+		must.NoError(t, r.rebalance(time.Now()))
+		must.Zero(t, migrating(t, raft.state, job))
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		policy := testPolicy(1)
+		policy.Threshold = 1
+		r, raft, job := testRebalancer(t, 1, policy)
+
+		must.NoError(t, r.rebalance(time.Now()))
+		must.Zero(t, migrating(t, raft.state, job))
+	})
+}
@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// rebalancerShim implements the rebalancer.RaftApplier interface required by
+// the Rebalancer.
+type rebalancerShim struct {
+	s *Server
+}
+
+func (r rebalancerShim) AllocUpdateDesiredTransition(allocs map[string]*structs.DesiredTransition, evals []*structs.Evaluation) (uint64, error) {
+	args := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs:       allocs,
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: r.s.config.Region},
+	}
+	_, index, err := r.s.raftApply(structs.AllocUpdateDesiredTransitionRequestType, args)
+	return index, err
+}
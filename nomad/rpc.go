@@ -24,11 +24,14 @@ import (
 	"github.com/hashicorp/go-msgpack/v2/codec"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/yamux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -592,7 +595,9 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 	if region != r.srv.config.Region {
 		// Mark that we are forwarding the RPC
 		info.SetForwarded()
+		span := traceForward(method, info, attribute.String("nomad.region", region))
 		err := r.forwardRegion(region, method, args, reply)
+		tracing.End(span, err)
 		return true, err
 	}
 
@@ -613,10 +618,26 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// forward to leader
 	info.SetForwarded()
+	span := traceForward(method, info, attribute.String("nomad.server", remoteServer.Name))
 	err = r.forwardLeader(remoteServer, method, args, reply)
+	tracing.End(span, err)
 	return true, err
 }
 
+// traceForward starts a span for forwarding an RPC that is being traced, and
+// propagates the span with the RPC so that the work of the server it is
+// forwarded to is recorded as part of it.
+func traceForward(method string, info structs.RPCInfo, attrs ...attribute.KeyValue) trace.Span {
+	ctx, span := tracing.Continue(info.TraceContext(), "rpc.forward "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("rpc.method", method))...),
+	)
+	if span.SpanContext().IsValid() {
+		info.SetTraceContext(tracing.Inject(ctx))
+	}
+	return span
+}
+
 // getLeaderForRPC returns the server info of the currently known leader, or
 // nil if this server is the current leader.  If the local server is the leader
 // it blocks until it is ready to handle consistent RPC invocations.  If leader
@@ -796,6 +817,17 @@ func (r *rpcHandler) streamingRpcImpl(conn net.Conn, method string) (net.Conn, e
 
 // raftApplyFuture is used to encode a message, run it through raft, and return the Raft future.
 func (s *Server) raftApplyFuture(t structs.MessageType, msg interface{}) (raft.ApplyFuture, error) {
+	// The trace context of the RPC is only meaningful while handling it, so
+	// it isn't written to the raft log. Evaluations are written with their
+	// own trace context, which is needed to continue the trace once they
+	// are enqueued from the FSM.
+	if info, ok := msg.(structs.RPCInfo); ok {
+		if carrier := info.TraceContext(); carrier != nil {
+			info.SetTraceContext(nil)
+			defer info.SetTraceContext(carrier)
+		}
+	}
+
 	buf, err := structs.Encode(t, msg)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to encode request: %v", err)
@@ -816,7 +848,17 @@ type raftApplyFn func(t structs.MessageType, msg interface{}) (interface{}, uint
 // raftApply is used to encode a message, run it through raft, and return the
 // FSM response along with any errors. If the FSM.Apply response is an error it
 // will be returned as the error return value with a nil response.
-func (s *Server) raftApply(t structs.MessageType, msg any) (any, uint64, error) {
+func (s *Server) raftApply(t structs.MessageType, msg any) (resp any, index uint64, err error) {
+	if info, ok := msg.(structs.RPCInfo); ok {
+		_, span := tracing.Continue(info.TraceContext(), "raft.apply",
+			trace.WithAttributes(attribute.Int("nomad.raft.message_type", int(t))),
+		)
+		defer func() {
+			span.SetAttributes(attribute.Int64("nomad.raft.index", int64(index)))
+			tracing.End(span, err)
+		}()
+	}
+
 	future, err := s.raftApplyFuture(t, msg)
 	if err != nil {
 		return nil, 0, err
@@ -824,7 +866,7 @@ func (s *Server) raftApply(t structs.MessageType, msg any) (any, uint64, error)
 	if err := future.Error(); err != nil {
 		return nil, 0, err
 	}
-	resp := future.Response()
+	resp = future.Response()
 	if err, ok := resp.(error); ok && err != nil {
 		return nil, future.Index(), err
 	}
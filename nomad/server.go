@@ -44,6 +44,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/lock"
+	"github.com/hashicorp/nomad/nomad/rebalancer"
 	"github.com/hashicorp/nomad/nomad/reporting"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -225,6 +226,10 @@ type Server struct {
 	// volumeWatcher is used to release volume claims
 	volumeWatcher *volumewatcher.Watcher
 
+	// rebalancer is used to migrate allocations which would score better on
+	// other nodes.
+	rebalancer *rebalancer.Rebalancer
+
 	// volumeControllerFutures is a map of plugin IDs to pending controller RPCs. If
 	// no RPC is pending for a given plugin, this may be nil.
 	volumeControllerFutures map[string]context.Context
@@ -243,6 +248,14 @@ type Server struct {
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
+	// dispatchQueue is used to enforce the max_concurrent limit of
+	// parameterized jobs and release their queued dispatched jobs.
+	dispatchQueue *DispatchQueue
+
+	// deadlineWatcher is used to stop jobs which exceeded their active
+	// deadline.
+	deadlineWatcher *DeadlineWatcher
+
 	// planner is used to mange the submitted allocation plans that are waiting
 	// to be accessed by the leader
 	*planner
@@ -405,6 +418,13 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
 
+	// Create the dispatch queue for limiting dispatched jobs.
+	s.dispatchQueue = NewDispatchQueue(s.logger, s)
+
+	// Create the deadline watcher for stopping jobs which exceeded their
+	// active deadline.
+	s.deadlineWatcher = NewDeadlineWatcher(s.logger, s)
+
 	// Initialize the stats fetcher that autopilot will use.
 	s.statsFetcher = NewStatsFetcher(s.logger, s.connPool, s.config.Region)
 
@@ -515,6 +535,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 	// Setup the node drainer.
 	s.setupNodeDrainer()
 
+	// Setup the rebalancer.
+	s.rebalancer = rebalancer.NewRebalancer(s.logger, rebalancerShim{s}, rebalancer.DefaultCheckInterval)
+
 	// Setup the enterprise state
 	if err := s.setupEnterprise(config); err != nil {
 		return nil, err
@@ -1152,6 +1175,7 @@ func (s *Server) setupDeploymentWatcher() error {
 		raftShim,
 		NewDeploymentEndpoint(s, nil),
 		NewJobEndpoints(s, nil),
+		s.config.CanaryAnalysisAddresses,
 		s.config.DeploymentQueryRateLimit,
 		deploymentwatcher.CrossDeploymentUpdateBatchDuration,
 	)
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	metrics "github.com/hashicorp/go-metrics/compat"
+	"github.com/hashicorp/go-msgpack/v2/codec"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// snapshotAgentFilePrefix and snapshotAgentFileSuffix surround the time
+	// and Raft index of the snapshots written by the snapshot agent. Files in
+	// the snapshot directory without them are never removed.
+	snapshotAgentFilePrefix = "nomad-snapshot-"
+	snapshotAgentFileSuffix = ".snap"
+
+	// snapshotAgentTimeFormat is the format of the time in the name of
+	// snapshot files. It sorts lexically in time order.
+	snapshotAgentTimeFormat = "20060102T150405Z"
+)
+
+// SnapshotAgentConfig configures the snapshot agent, which periodically saves
+// snapshots of the Raft state to a local directory while this server is the
+// leader.
+type SnapshotAgentConfig struct {
+	// Enabled controls if the snapshot agent runs.
+	Enabled bool
+
+	// Interval is the time between snapshots.
+	Interval time.Duration
+
+	// Path is the directory snapshots are written to.
+	Path string
+
+	// Retain is the number of snapshots to keep. Older snapshots are deleted
+	// after a new snapshot is saved. Zero keeps all snapshots.
+	Retain int
+
+	// RetainAge is how long snapshots are kept for. Zero keeps snapshots
+	// regardless of their age.
+	RetainAge time.Duration
+
+	// Redact removes the key material of the keyring from snapshots before
+	// they are written, as the "nomad operator snapshot redact" command does.
+	Redact bool
+}
+
+// Copy returns a copy of the configuration.
+func (c *SnapshotAgentConfig) Copy() *SnapshotAgentConfig {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	return &nc
+}
+
+// runSnapshotAgent periodically saves snapshots of the Raft state and prunes
+// those which are no longer retained, until stopCh is closed.
+func (s *Server) runSnapshotAgent(stopCh chan struct{}) {
+	cfg := s.config.SnapshotAgentConfig
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+	logger := s.logger.Named("snapshot_agent")
+
+	// Leadership may have moved back to this server since it last saved a
+	// snapshot, so pick up the schedule where it left off.
+	wait := cfg.Interval
+	if last, err := lastAgentSnapshotTime(cfg.Path); err != nil {
+		logger.Warn("failed to find previous snapshots", "error", err)
+	} else if !last.IsZero() {
+		wait = max(0, cfg.Interval-time.Since(last))
+	}
+
+	timer, stop := helper.NewSafeTimer(wait)
+	defer stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		path, size, err := saveAgentSnapshot(logger, s.raft, cfg, start)
+		if err != nil {
+			logger.Error("failed to save snapshot", "error", err)
+			metrics.IncrCounter([]string{"nomad", "snapshot_agent", "failure"}, 1)
+		} else {
+			logger.Info("saved snapshot", "path", path, "size", size)
+			metrics.IncrCounter([]string{"nomad", "snapshot_agent", "success"}, 1)
+			metrics.SetGauge([]string{"nomad", "snapshot_agent", "size"}, float32(size))
+			metrics.MeasureSince([]string{"nomad", "snapshot_agent", "save"}, start)
+
+			if err := pruneAgentSnapshots(logger, cfg, time.Now()); err != nil {
+				logger.Error("failed to remove expired snapshots", "error", err)
+			}
+		}
+
+		timer.Reset(cfg.Interval)
+	}
+}
+
+// saveAgentSnapshot takes a snapshot of the Raft state and writes it to the
+// configured directory, returning the path and size of the written file.
+func saveAgentSnapshot(logger hclog.Logger, r *raft.Raft, cfg *SnapshotAgentConfig, now time.Time) (string, int64, error) {
+	// Redaction streams the state into the snapshot, so it doesn't hold a
+	// copy of the state in memory.
+	var snap *snapshot.Snapshot
+	var err error
+	if cfg.Redact {
+		snap, err = snapshot.NewFiltered(logger, r, redactSnapshotState)
+	} else {
+		snap, err = snapshot.New(logger, r)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	defer snap.Close()
+
+	if err := os.MkdirAll(cfg.Path, 0o700); err != nil {
+		return "", 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Write to a temporary file first so a partially written snapshot is
+	// never mistaken for a complete one.
+	name := fmt.Sprintf("%s%s-%d%s", snapshotAgentFilePrefix,
+		now.UTC().Format(snapshotAgentTimeFormat), snap.Index(), snapshotAgentFileSuffix)
+	path := filepath.Join(cfg.Path, name)
+	tmp, err := os.CreateTemp(cfg.Path, name+".tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, snap)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("failed to move snapshot file: %w", err)
+	}
+	return path, size, nil
+}
+
+// agentSnapshot is a snapshot file written by the snapshot agent.
+type agentSnapshot struct {
+	name  string
+	time  time.Time
+	index uint64
+}
+
+// listAgentSnapshots returns the snapshots in dir written by the snapshot
+// agent, oldest first.
+func listAgentSnapshots(dir string) ([]agentSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snaps []agentSnapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasPrefix(name, snapshotAgentFilePrefix) ||
+			!strings.HasSuffix(name, snapshotAgentFileSuffix) {
+			continue
+		}
+
+		// The name is the prefix, time, index and suffix
+		parts := strings.Split(strings.TrimSuffix(
+			strings.TrimPrefix(name, snapshotAgentFilePrefix), snapshotAgentFileSuffix), "-")
+		if len(parts) != 2 {
+			continue
+		}
+		t, err := time.Parse(snapshotAgentTimeFormat, parts[0])
+		if err != nil {
+			continue
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, agentSnapshot{name: name, time: t, index: index})
+	}
+
+	sort.Slice(snaps, func(i, j int) bool {
+		if snaps[i].time.Equal(snaps[j].time) {
+			return snaps[i].name < snaps[j].name
+		}
+		return snaps[i].time.Before(snaps[j].time)
+	})
+	return snaps, nil
+}
+
+// lastAgentSnapshotTime returns the time of the newest snapshot in dir written
+// by the snapshot agent, or the zero time if there is none.
+func lastAgentSnapshotTime(dir string) (time.Time, error) {
+	snaps, err := listAgentSnapshots(dir)
+	if err != nil || len(snaps) == 0 {
+		return time.Time{}, err
+	}
+	return snaps[len(snaps)-1].time, nil
+}
+
+// pruneAgentSnapshots removes the snapshots written by the snapshot agent
+// which exceed the retained count or age. The newest snapshot is always kept.
+func pruneAgentSnapshots(logger hclog.Logger, cfg *SnapshotAgentConfig, now time.Time) error {
+	snaps, err := listAgentSnapshots(cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	var mErr error
+	for i, snap := range snaps {
+		newer := len(snaps) - i - 1
+		if newer == 0 {
+			break
+		}
+		expired := (cfg.Retain > 0 && newer >= cfg.Retain) ||
+			(cfg.RetainAge > 0 && now.Sub(snap.time) > cfg.RetainAge)
+		if !expired {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(cfg.Path, snap.name)); err != nil && !os.IsNotExist(err) {
+			mErr = err
+			continue
+		}
+		logger.Debug("removed expired snapshot", "name", snap.name)
+	}
+	return mErr
+}
+
+// RedactSnapshot removes the key material of the keyring from the snapshot
+// archive read from in, as the "nomad operator snapshot redact" command does.
+// You must arrange to call Close() on the returned snapshot.
+func RedactSnapshot(logger hclog.Logger, in io.Reader) (*snapshot.Snapshot, error) {
+	snap, err := snapshot.Filter(logger, in, redactSnapshotState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redacted snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// redactSnapshotState copies the FSM state encoded by nomadSnapshot.Persist
+// from r to w, removing the key material of the root keys. The state is
+// streamed rather than restored, and records other than root keys are copied
+// without being decoded into their types.
+func redactSnapshotState(w io.Writer, r io.Reader) error {
+	dec := codec.NewDecoder(r, structs.MsgpackHandle)
+	enc := codec.NewEncoder(w, structs.MsgpackHandle)
+
+	var header SnapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+	if err := enc.Encode(&header); err != nil {
+		return err
+	}
+
+	msgType := make([]byte, 1)
+	for {
+		// Read the message type
+		if _, err := io.ReadFull(r, msgType); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, err := w.Write(msgType); err != nil {
+			return err
+		}
+
+		if SnapshotType(msgType[0]) != RootKeySnapshot {
+			var raw codec.Raw
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			if _, err := w.Write(raw); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rootKey := new(structs.RootKey)
+		if err := dec.Decode(rootKey); err != nil {
+			return err
+		}
+		if len(rootKey.WrappedKeys) > 0 {
+			rootKey.KeyID = rootKey.KeyID + " [REDACTED]"
+			rootKey.WrappedKeys = nil
+		}
+		if err := enc.Encode(rootKey); err != nil {
+			return fmt.Errorf("failed to re-encode redacted key: %w", err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestSnapshotAgent_Save(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.DevMode = false
+		c.DataDir = filepath.Join(dir, "server")
+		c.SnapshotAgentConfig = &SnapshotAgentConfig{
+			Enabled:  true,
+			Interval: 100 * time.Millisecond,
+			Path:     filepath.Join(dir, "snapshots"),
+			Retain:   2,
+			Redact:   true,
+		}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, s1.Region())
+	snapDir := s1.config.SnapshotAgentConfig.Path
+	keyringIndex := s1.raft.AppliedIndex()
+
+	// Snapshots are saved and pruned down to the retained count, and the
+	// newest includes the keyring
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			snaps, err := listAgentSnapshots(snapDir)
+			if err != nil {
+				return err
+			}
+			if len(snaps) != 2 {
+				return fmt.Errorf("expected 2 snapshots, found %d", len(snaps))
+			}
+			if snaps[1].index < keyringIndex {
+				return fmt.Errorf("newest snapshot is from index %d", snaps[1].index)
+			}
+			return nil
+		}),
+		wait.Timeout(10*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+
+	snaps, err := listAgentSnapshots(snapDir)
+	must.NoError(t, err)
+	must.SliceNotEmpty(t, snaps)
+
+	f, err := os.Open(filepath.Join(snapDir, snaps[len(snaps)-1].name))
+	must.NoError(t, err)
+	defer f.Close()
+	_, err = snapshot.Verify(f)
+	must.NoError(t, err)
+
+	// The key material of the keyring is removed
+	_, err = f.Seek(0, 0)
+	must.NoError(t, err)
+	fsm := testFSM(t)
+	r, w := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := snapshot.CopySnapshot(f, w)
+		errCh <- err
+	}()
+	must.NoError(t, fsm.Restore(r))
+	must.NoError(t, <-errCh)
+	iter, err := fsm.State().RootKeys(nil)
+	must.NoError(t, err)
+	var keys int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		key := raw.(*structs.RootKey)
+		if len(key.WrappedKeys) > 0 {
+			must.Unreachable(t, must.Sprintf("key %s was not redacted", key.KeyID))
+		}
+		keys++
+	}
+	must.Positive(t, keys)
+}
+
+func TestSnapshotAgent_RedactState(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	store := fsm.State()
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	job := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+	key := structs.NewRootKey(structs.NewRootKeyMeta())
+	key.WrappedKeys = []*structs.WrappedKey{{Provider: "aead"}}
+	must.NoError(t, store.UpsertRootKey(1002, key, false))
+
+	snap, err := fsm.Snapshot()
+	must.NoError(t, err)
+	defer snap.Release()
+	state := new(bytes.Buffer)
+	must.NoError(t, snap.Persist(&MockSink{state, false}))
+
+	redacted := new(bytes.Buffer)
+	must.NoError(t, redactSnapshotState(redacted, state))
+
+	restored := testFSM(t)
+	must.NoError(t, restored.Restore(io.NopCloser(redacted)))
+
+	// The records other than root keys are copied as is
+	out, err := restored.State().NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, node.Name, out.Name)
+	must.Eq(t, node.Attributes, out.Attributes)
+	outJob, err := restored.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, outJob)
+	must.Eq(t, job.TaskGroups[0].Tasks[0].Config, outJob.TaskGroups[0].Tasks[0].Config)
+
+	// The key material of the root keys is removed
+	outKey, err := restored.State().RootKeyByID(nil, key.KeyID+" [REDACTED]")
+	must.NoError(t, err)
+	must.NotNil(t, outKey)
+	must.SliceEmpty(t, outKey.WrappedKeys)
+	outKey, err = restored.State().RootKeyByID(nil, key.KeyID)
+	must.NoError(t, err)
+	must.Nil(t, outKey)
+}
+
+func TestSnapshotAgent_Prune(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	names := []string{
+		"nomad-snapshot-20240101T120000Z-10.snap",
+		"nomad-snapshot-20240105T120000Z-20.snap",
+		"nomad-snapshot-20240108T120000Z-30.snap",
+		"nomad-snapshot-20240109T120000Z-40.snap",
+		"nomad-snapshot-20240110T110000Z-50.snap",
+	}
+	unrelated := []string{
+		"nomad-snapshot-20240101T120000Z-10.snap.tmp123",
+		"nomad-snapshot-latest.snap",
+		"backup.snap",
+	}
+	for _, name := range append(names, unrelated...) {
+		must.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	remaining := func() []string {
+		entries, err := os.ReadDir(dir)
+		must.NoError(t, err)
+		var out []string
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), snapshotAgentFileSuffix) &&
+				strings.HasPrefix(entry.Name(), snapshotAgentFilePrefix+"2024") {
+				out = append(out, entry.Name())
+			}
+		}
+		return out
+	}
+
+	logger := hclog.NewNullLogger()
+
+	last, err := lastAgentSnapshotTime(dir)
+	must.NoError(t, err)
+	must.Eq(t, time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC), last)
+
+	// Snapshots older than the retained age are removed
+	cfg := &SnapshotAgentConfig{Path: dir, RetainAge: 7 * 24 * time.Hour}
+	must.NoError(t, pruneAgentSnapshots(logger, cfg, now))
+	must.Eq(t, names[1:], remaining())
+
+	// Only the retained number of snapshots are kept
+	cfg = &SnapshotAgentConfig{Path: dir, Retain: 2}
+	must.NoError(t, pruneAgentSnapshots(logger, cfg, now))
+	must.Eq(t, names[3:], remaining())
+
+	// The newest snapshot is kept even when it has expired
+	cfg = &SnapshotAgentConfig{Path: dir, RetainAge: time.Minute}
+	must.NoError(t, pruneAgentSnapshots(logger, cfg, now))
+	must.Eq(t, names[4:], remaining())
+
+	// Files which weren't written by the snapshot agent are never removed
+	for _, name := range unrelated {
+		must.FileExists(t, filepath.Join(dir, name))
+	}
+}
@@ -20,6 +20,8 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.NodeUpdateStatusRequestType:                  structs.TypeNodeEvent,
 	structs.JobDeregisterRequestType:                     structs.TypeJobDeregistered,
 	structs.JobBatchDeregisterRequestType:                structs.TypeJobBatchDeregistered,
+	structs.PeriodicLaunchDecisionRequestType:            structs.TypePeriodicLaunchDecision,
+	structs.JobDispatchReleaseRequestType:                structs.TypeJobDispatchReleased,
 	structs.AllocUpdateDesiredTransitionRequestType:      structs.TypeAllocationUpdateDesiredStatus,
 	structs.NodeUpdateEligibilityRequestType:             structs.TypeNodeDrain,
 	structs.NodeUpdateDrainRequestType:                   structs.TypeNodeDrain,
@@ -27,6 +29,7 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.DeploymentStatusUpdateRequestType:            structs.TypeDeploymentUpdate,
 	structs.DeploymentPromoteRequestType:                 structs.TypeDeploymentPromotion,
 	structs.DeploymentAllocHealthRequestType:             structs.TypeDeploymentAllocHealth,
+	structs.DeploymentAnalysisUpdateRequestType:          structs.TypeDeploymentUpdate,
 	structs.ApplyPlanResultsRequestType:                  structs.TypePlanResult,
 	structs.ACLTokenDeleteRequestType:                    structs.TypeACLTokenDeleted,
 	structs.ACLTokenUpsertRequestType:                    structs.TypeACLTokenUpserted,
@@ -46,6 +49,7 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.CSIVolumeRegisterRequestType:                 structs.TypeCSIVolumeRegistered,
 	structs.CSIVolumeDeregisterRequestType:               structs.TypeCSIVolumeDeregistered,
 	structs.CSIVolumeClaimRequestType:                    structs.TypeCSIVolumeClaim,
+	structs.VarApplyStateRequestType:                     structs.TypeVariableUpserted,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -56,6 +60,9 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 			return nil
 		}
 	}
+	if changes.EventType != "" {
+		eventType = changes.EventType
+	}
 
 	var events []structs.Event
 	for _, change := range changes.Changes {
@@ -241,6 +248,18 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Plugin: before,
 				},
 			}, true
+		case TableVariables:
+			before, ok := change.Before.(*structs.VariableEncrypted)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicVariable,
+				Key:        before.Path,
+				FilterKeys: []string{before.Path},
+				Namespace:  before.Namespace,
+				Payload:    structs.NewVariableEvent(before),
+			}, true
 		default:
 			return enterpriseEventFromChangeDeleted(change)
 		}
@@ -389,6 +408,19 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				NodePool: after,
 			},
 		}, true
+	case "periodic_launch":
+		after, ok := change.After.(*structs.PeriodicLaunch)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicJob,
+			Key:       after.ID,
+			Namespace: after.Namespace,
+			Payload: &structs.PeriodicLaunchEvent{
+				PeriodicLaunch: after,
+			},
+		}, true
 	case "deployment":
 		after, ok := change.After.(*structs.Deployment)
 		if !ok {
@@ -472,6 +504,18 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Plugin: after,
 			},
 		}, true
+	case TableVariables:
+		after, ok := change.After.(*structs.VariableEncrypted)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicVariable,
+			Key:        after.Path,
+			FilterKeys: []string{after.Path},
+			Namespace:  after.Namespace,
+			Payload:    structs.NewVariableEvent(after),
+		}, true
 	default:
 		return enterpriseEventFromChange(change)
 	}
@@ -144,6 +144,44 @@ func TestEventsFromChanges_DeploymentUpdate(t *testing.T) {
 	must.SliceContains(t, got.FilterKeys, j.ID)
 }
 
+func TestEventsFromChanges_PeriodicLaunchDecision(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// setup
+	j := mock.PeriodicJob()
+	must.NoError(t, s.UpsertPeriodicLaunch(10, &structs.PeriodicLaunch{
+		ID:        j.ID,
+		Namespace: j.Namespace,
+		Launch:    time.Now(),
+	}))
+
+	msgType := structs.PeriodicLaunchDecisionRequestType
+	req := &structs.PeriodicLaunchDecisionRequest{
+		Namespace: j.Namespace,
+		JobID:     j.ID,
+		Decision: &structs.PeriodicLaunchDecision{
+			Decision: structs.PeriodicDecisionQueued,
+			Launch:   time.Now().UTC(),
+			Time:     time.Now().UTC(),
+		},
+	}
+	must.NoError(t, s.UpsertPeriodicLaunchDecision(msgType, 100, req))
+
+	events := WaitForEvents(t, s, 100, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	got := events[0]
+	must.Eq(t, structs.TopicJob, got.Topic)
+	must.Eq(t, structs.TypePeriodicLaunchDecision, got.Type)
+	must.Eq(t, j.ID, got.Key)
+	must.Eq(t, j.Namespace, got.Namespace)
+
+	pe := got.Payload.(*structs.PeriodicLaunchEvent)
+	must.Eq(t, structs.PeriodicDecisionQueued, pe.PeriodicLaunch.Decision.Decision)
+}
+
 func TestEventsFromChanges_DeploymentPromotion(t *testing.T) {
 	ci.Parallel(t)
 	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
@@ -1390,3 +1428,78 @@ func testNodeID() string {
 func testNodeIDTwo() string {
 	return "694ff31d-8c59-4030-ac83-e15692560c8d"
 }
+
+func Test_eventsFromChanges_Variable(t *testing.T) {
+	ci.Parallel(t)
+	testState := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer testState.StopEventBroker()
+
+	sv := mock.VariableEncrypted()
+
+	// Write the variable and ensure the event carries only its metadata.
+	setVar := sv.Copy()
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &setVar,
+	})
+	must.True(t, resp.IsOk())
+
+	events := WaitForEvents(t, testState, 10, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TopicVariable, events[0].Topic)
+	must.Eq(t, structs.TypeVariableUpserted, events[0].Type)
+	must.Eq(t, sv.Path, events[0].Key)
+	must.Eq(t, sv.Namespace, events[0].Namespace)
+
+	payload, ok := events[0].Payload.(*structs.VariableEvent)
+	must.True(t, ok)
+	must.Eq(t, sv.Path, payload.Variable.Path)
+	must.Eq(t, 10, payload.Variable.ModifyIndex)
+
+	// Acquire a lock and ensure the lock ID is not published.
+	lockVar := sv.Copy()
+	lockVar.Lock = &structs.VariableLock{
+		ID:        uuid.Generate(),
+		TTL:       15 * time.Second,
+		LockDelay: 15 * time.Second,
+	}
+	resp = testState.VarLockAcquire(20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpLockAcquire,
+		Var: &lockVar,
+	})
+	must.True(t, resp.IsOk())
+
+	events = WaitForEvents(t, testState, 20, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TypeVariableLockAcquired, events[0].Type)
+	payload = events[0].Payload.(*structs.VariableEvent)
+	must.NotNil(t, payload.Variable.Lock)
+	must.Eq(t, "", payload.Variable.Lock.ID)
+	must.Eq(t, 15*time.Second, payload.Variable.Lock.TTL)
+
+	// Release the lock without a lock ID, as the leader does when the TTL
+	// expires.
+	expired := sv.Copy()
+	resp = testState.VarLockRelease(30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpLockRelease,
+		Var: &expired,
+	})
+	must.True(t, resp.IsOk())
+
+	events = WaitForEvents(t, testState, 30, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TypeVariableLockExpired, events[0].Type)
+
+	// Delete the variable.
+	deleteVar := sv.Copy()
+	resp = testState.VarDelete(40, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: &deleteVar,
+	})
+	must.True(t, resp.IsOk())
+
+	events = WaitForEvents(t, testState, 40, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TypeVariableDeleted, events[0].Type)
+	must.Eq(t, sv.Path, events[0].Key)
+}
@@ -20,6 +20,7 @@ const (
 	TableServiceRegistrations     = "service_registrations"
 	TableVariables                = "variables"
 	TableVariablesQuotas          = "variables_quota"
+	TableVariableVersions         = "variable_versions"
 	TableRootKeys                 = "root_keys"
 	TableACLRoles                 = "acl_roles"
 	TableACLAuthMethods           = "acl_auth_methods"
@@ -98,6 +99,7 @@ func init() {
 		serviceRegistrationsTableSchema,
 		variablesTableSchema,
 		variablesQuotasTableSchema,
+		variableVersionsTableSchema,
 		wrappedRootKeySchema,
 		aclRolesTableSchema,
 		aclAuthMethodsTableSchema,
@@ -1468,6 +1470,40 @@ func variablesTableSchema() *memdb.TableSchema {
 	}
 }
 
+// variableVersionsTableSchema returns the MemDB schema for the previous
+// versions of Nomad variables. Versions are identified by the ModifyIndex the
+// variable had when it was superseded.
+func variableVersionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariableVersions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+						&memdb.UintFieldIndex{
+							Field: "ModifyIndex",
+						},
+					},
+				},
+			},
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Indexer:      &variableKeyIDFieldIndexer{},
+			},
+		},
+	}
+}
+
 type variableKeyIDFieldIndexer struct{}
 
 // FromArgs implements go-memdb/Indexer and is used to build an exact
@@ -24,6 +24,11 @@ type Changes struct {
 	Index   uint64
 	Changes memdb.Changes
 	MsgType structs.MessageType
+
+	// EventType optionally overrides the event type derived from MsgType. It
+	// is used where a single message type covers several operations, such as
+	// variables.
+	EventType string
 }
 
 // changeTrackerDB is a thin wrapper around memdb.DB which enables TrackChanges on
@@ -88,6 +93,15 @@ func (c *changeTrackerDB) WriteTxnMsgT(msgType structs.MessageType, idx uint64)
 	return t
 }
 
+// WriteTxnEvent returns a wrapped write transaction in the same way as
+// WriteTxnMsgT, but the events published on commit will use the given event
+// type rather than the one mapped from the message type.
+func (c *changeTrackerDB) WriteTxnEvent(msgType structs.MessageType, eventType string, idx uint64) *txn {
+	t := c.WriteTxnMsgT(msgType, idx)
+	t.eventType = eventType
+	return t
+}
+
 func (c *changeTrackerDB) publish(changes Changes) (*structs.Events, error) {
 	readOnlyTx := c.memdb.Txn(false)
 	defer readOnlyTx.Abort()
@@ -123,6 +137,9 @@ type txn struct {
 	// msgType is used to inform event sourcing which type of event to create
 	msgType structs.MessageType
 
+	// eventType optionally overrides the event type derived from msgType
+	eventType string
+
 	*memdb.Txn
 	// Index in raft where the write is occurring. The value is zero for a
 	// read-only, or WriteTxnRestore transaction.
@@ -144,9 +161,10 @@ func (tx *txn) Commit() error {
 	// to publish.
 	if tx.publish != nil {
 		changes := Changes{
-			Index:   tx.Index,
-			Changes: tx.Txn.Changes(),
-			MsgType: tx.MsgType(),
+			Index:     tx.Index,
+			Changes:   tx.Txn.Changes(),
+			MsgType:   tx.MsgType(),
+			EventType: tx.eventType,
 		}
 		_, err := tx.publish(changes)
 		if err != nil {
@@ -1781,6 +1781,18 @@ func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job
 		return fmt.Errorf("job lookup failed: %v", err)
 	}
 
+	// The active deadline is measured from the time the job became active,
+	// which updates of an active job don't change. Queued dispatched jobs
+	// become active once released.
+	switch {
+	case job.DispatchQueued:
+		job.ActiveSince = 0
+	case existing != nil && jobActive(existing.(*structs.Job)):
+		job.ActiveSince = existing.(*structs.Job).ActiveSince
+	default:
+		job.ActiveSince = job.SubmitTime
+	}
+
 	// Setup the indexes correctly
 	if existing != nil {
 		job.CreateIndex = existing.(*structs.Job).CreateIndex
@@ -1904,6 +1916,10 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 
 				modified := false
 				switch job.Status {
+				case structs.JobStatusQueued:
+					pSummary.Children.Queued--
+					pSummary.Children.Dead++
+					modified = true
 				case structs.JobStatusPending:
 					pSummary.Children.Pending--
 					pSummary.Children.Dead++
@@ -3264,10 +3280,17 @@ func (s *StateStore) UpsertPeriodicLaunch(index uint64, launch *structs.Periodic
 		return fmt.Errorf("periodic launch lookup failed: %v", err)
 	}
 
-	// Setup the indexes correctly
+	// Setup the indexes correctly and keep the last decision and queued
+	// launch of the concurrency policy
 	if existing != nil {
 		launch.CreateIndex = existing.(*structs.PeriodicLaunch).CreateIndex
 		launch.ModifyIndex = index
+		if launch.Decision == nil {
+			launch.Decision = existing.(*structs.PeriodicLaunch).Decision
+		}
+		if launch.Queued.IsZero() {
+			launch.Queued = existing.(*structs.PeriodicLaunch).Queued
+		}
 	} else {
 		launch.CreateIndex = index
 		launch.ModifyIndex = index
@@ -3284,6 +3307,41 @@ func (s *StateStore) UpsertPeriodicLaunch(index uint64, launch *structs.Periodic
 	return txn.Commit()
 }
 
+// UpsertPeriodicLaunchDecision is used to record the last decision of the
+// concurrency policy of a periodic job. The launch deferred by the queue
+// policy is recorded until it is dequeued.
+func (s *StateStore) UpsertPeriodicLaunchDecision(msgType structs.MessageType, index uint64, req *structs.PeriodicLaunchDecisionRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First("periodic_launch", "id", req.Namespace, req.JobID)
+	if err != nil {
+		return fmt.Errorf("periodic launch lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("periodic launch for job %q in namespace %q not found", req.JobID, req.Namespace)
+	}
+
+	launch := *existing.(*structs.PeriodicLaunch)
+	launch.Decision = req.Decision.Copy()
+	launch.ModifyIndex = index
+	switch req.Decision.Decision {
+	case structs.PeriodicDecisionQueued:
+		launch.Queued = req.Decision.Launch
+	case structs.PeriodicDecisionDequeued:
+		launch.Queued = time.Time{}
+	}
+
+	if err := txn.Insert("periodic_launch", &launch); err != nil {
+		return fmt.Errorf("launch insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"periodic_launch", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // DeletePeriodicLaunch is used to delete the periodic launch
 func (s *StateStore) DeletePeriodicLaunch(index uint64, namespace, jobID string) error {
 	txn := s.db.WriteTxn(index)
@@ -3352,6 +3410,71 @@ func (s *StateStore) PeriodicLaunches(ws memdb.WatchSet) (memdb.ResultIterator,
 	return iter, nil
 }
 
+// ReleaseDispatchedJobs is used to release dispatched jobs that were queued
+// by their parent's max_concurrent limit. The queued flag of each job is
+// cleared and its evaluation is created in the same transaction, so the job
+// moves from queued to pending.
+func (s *StateStore) ReleaseDispatchedJobs(msgType structs.MessageType, index uint64, req *structs.JobDispatchReleaseRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	jobs := make(map[structs.NamespacedID]string, len(req.Evals))
+	for _, eval := range req.Evals {
+		existing, err := txn.First("jobs", "id", eval.Namespace, eval.JobID)
+		if err != nil {
+			return fmt.Errorf("job lookup failed: %v", err)
+		}
+
+		// The job may have been stopped, purged or released already
+		if existing == nil {
+			continue
+		}
+		job := existing.(*structs.Job)
+		if !job.DispatchQueued || job.Stop {
+			continue
+		}
+
+		job = job.Copy()
+		job.DispatchQueued = false
+		job.ActiveSince = eval.CreateTime
+		job.ModifyIndex = index
+
+		if err := txn.Insert("jobs", job); err != nil {
+			return fmt.Errorf("job insert failed: %v", err)
+		}
+		if err := txn.Insert("job_version", job); err != nil {
+			return fmt.Errorf("failed to insert job into job_version table: %v", err)
+		}
+
+		if err := s.nestedUpsertEval(txn, index, eval); err != nil {
+			return err
+		}
+
+		jobs[structs.NamespacedID{ID: job.ID, Namespace: job.Namespace}] = ""
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"job_version", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	// Set the job's status, moving it from queued to pending
+	if err := s.setJobStatuses(index, txn, jobs, false); err != nil {
+		return fmt.Errorf("setting job status failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// jobActive returns whether the job became active and hasn't been stopped or
+// finished since.
+func jobActive(job *structs.Job) bool {
+	return job.ActiveSince != 0 && !job.Stopped() && !job.DispatchQueued &&
+		job.Status != structs.JobStatusDead
+}
+
 // UpsertEvals is used to upsert a set of evaluations
 func (s *StateStore) UpsertEvals(msgType structs.MessageType, index uint64, evals []*structs.Evaluation) error {
 	txn := s.db.WriteTxnMsgT(msgType, index)
@@ -4709,6 +4832,39 @@ func (s *StateStore) UpdateDeploymentStatus(msgType structs.MessageType, index u
 	return txn.Commit()
 }
 
+// UpdateDeploymentAnalysis is used to record the state of the canary analysis
+// of a task group in a deployment.
+func (s *StateStore) UpdateDeploymentAnalysis(msgType structs.MessageType, index uint64, req *structs.DeploymentAnalysisUpdateRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	deployment, err := s.deploymentByIDImpl(nil, req.DeploymentID, txn)
+	if err != nil {
+		return err
+	} else if deployment == nil {
+		return fmt.Errorf("Deployment ID %q couldn't be updated as it does not exist", req.DeploymentID)
+	} else if !deployment.Active() {
+		return fmt.Errorf("Deployment %q has terminal status %q:", deployment.ID, deployment.Status)
+	}
+
+	copy := deployment.Copy()
+	dstate, ok := copy.TaskGroups[req.TaskGroup]
+	if !ok {
+		return fmt.Errorf("Deployment %q has no task group %q", deployment.ID, req.TaskGroup)
+	}
+	dstate.Analysis = req.Analysis.Copy()
+	copy.ModifyIndex = index
+
+	if err := txn.Insert("deployment", copy); err != nil {
+		return err
+	}
+	if err := txn.Insert("index", &IndexEntry{"deployment", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // updateDeploymentStatusImpl is used to make deployment status updates
 func (s *StateStore) updateDeploymentStatusImpl(index uint64, u *structs.DeploymentStatusUpdate, txn *txn) error {
 	// Retrieve deployment
@@ -5239,6 +5395,8 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			children := parentMap[job.ID]
 			for _, childJob := range children {
 				switch childJob.Status {
+				case structs.JobStatusQueued:
+					summary.Children.Queued++
 				case structs.JobStatusPending:
 					summary.Children.Pending++
 				case structs.JobStatusDead:
@@ -5437,6 +5595,8 @@ func (s *StateStore) setJobSummary(txn *txn, updated *structs.Job, index uint64,
 		// Decrement old status
 		if oldStatus != "" {
 			switch oldStatus {
+			case structs.JobStatusQueued:
+				children.Queued--
 			case structs.JobStatusPending:
 				children.Pending--
 			case structs.JobStatusRunning:
@@ -5450,6 +5610,8 @@ func (s *StateStore) setJobSummary(txn *txn, updated *structs.Job, index uint64,
 
 		// Increment new status
 		switch newStatus {
+		case structs.JobStatusQueued:
+			children.Queued++
 		case structs.JobStatusPending:
 			children.Pending++
 		case structs.JobStatusRunning:
@@ -5486,6 +5648,12 @@ func (s *StateStore) getJobStatus(txn *txn, job *structs.Job, evalDelete bool) (
 		return structs.JobStatusRunning, nil
 	}
 
+	// Dispatched jobs held back by their parent's max_concurrent limit have
+	// no evaluations until they are released.
+	if job.DispatchQueued && !job.Stop {
+		return structs.JobStatusQueued, nil
+	}
+
 	allocs, err := txn.Get("allocs", "job", job.Namespace, job.ID)
 	if err != nil {
 		return "", err
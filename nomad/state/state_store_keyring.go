@@ -168,7 +168,8 @@ func (s *StateStore) GetActiveRootKey(ws memdb.WatchSet) (*structs.RootKey, erro
 }
 
 // IsRootKeyInUse determines whether a key has been used to sign a workload
-// identity for a live allocation or encrypt any variables
+// identity for a live allocation or encrypt any variables, including the
+// previous versions of variables
 func (s *StateStore) IsRootKeyInUse(keyID string) (bool, error) {
 	txn := s.db.ReadTxn()
 
@@ -190,5 +191,14 @@ func (s *StateStore) IsRootKeyInUse(keyID string) (bool, error) {
 		return true, nil
 	}
 
+	iter, err = txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return false, err
+	}
+	version := iter.Next()
+	if version != nil {
+		return true, nil
+	}
+
 	return false, nil
 }
@@ -215,6 +215,15 @@ func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) err
 	return nil
 }
 
+// VariableVersionsRestore is used to restore a single previous version of a
+// variable into the variable_versions table.
+func (r *StateRestore) VariableVersionsRestore(version *structs.VariableEncrypted) error {
+	if err := r.txn.Insert(TableVariableVersions, version); err != nil {
+		return fmt.Errorf("variable version insert failed: %v", err)
+	}
+	return nil
+}
+
 // VariablesQuotaRestore is used to restore a single variable quota into the
 // variables_quota table.
 func (r *StateRestore) VariablesQuotaRestore(quota *structs.VariablesQuota) error {
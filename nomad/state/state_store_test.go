@@ -3404,6 +3404,93 @@ func TestStateStore_UpdateUpsertPeriodicLaunch(t *testing.T) {
 	must.False(t, watchFired(ws), must.Sprint("watch should not have fired"))
 }
 
+func TestStateStore_UpsertPeriodicLaunchDecision(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.Job()
+	req := &structs.PeriodicLaunchDecisionRequest{
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Decision: &structs.PeriodicLaunchDecision{
+			Decision:    structs.PeriodicDecisionSkipped,
+			Description: "Previous instance of the job is still running",
+			Launch:      time.Now().UTC(),
+			Time:        time.Now().UTC(),
+		},
+	}
+
+	// The launch must exist
+	err := state.UpsertPeriodicLaunchDecision(structs.MsgTypeTestSetup, 999, req)
+	must.ErrorContains(t, err, "not found")
+
+	launch := &structs.PeriodicLaunch{
+		ID:        job.ID,
+		Namespace: job.Namespace,
+		Launch:    time.Now(),
+	}
+	must.NoError(t, state.UpsertPeriodicLaunch(1000, launch))
+
+	ws := memdb.NewWatchSet()
+	_, err = state.PeriodicLaunchByID(ws, job.Namespace, job.ID)
+	must.NoError(t, err)
+
+	must.NoError(t, state.UpsertPeriodicLaunchDecision(structs.MsgTypeTestSetup, 1001, req))
+	must.True(t, watchFired(ws), must.Sprint("expected watch to fire"))
+
+	out, err := state.PeriodicLaunchByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, out.CreateIndex)
+	must.Eq(t, 1001, out.ModifyIndex)
+	must.Eq(t, launch.Launch, out.Launch)
+	must.Eq(t, req.Decision, out.Decision)
+
+	// The decision is kept when the launch is updated
+	launch2 := &structs.PeriodicLaunch{
+		ID:        job.ID,
+		Namespace: job.Namespace,
+		Launch:    launch.Launch.Add(1 * time.Second),
+	}
+	must.NoError(t, state.UpsertPeriodicLaunch(1002, launch2))
+
+	out, err = state.PeriodicLaunchByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, launch2.Launch, out.Launch)
+	must.Eq(t, req.Decision, out.Decision)
+	must.True(t, out.Queued.IsZero())
+
+	// A queued launch is recorded until it is dequeued
+	queued := launch.Launch.Add(2 * time.Second)
+	req.Decision = &structs.PeriodicLaunchDecision{
+		Decision: structs.PeriodicDecisionQueued,
+		Launch:   queued,
+		Time:     time.Now().UTC(),
+	}
+	must.NoError(t, state.UpsertPeriodicLaunchDecision(structs.MsgTypeTestSetup, 1003, req))
+
+	launch3 := &structs.PeriodicLaunch{
+		ID:        job.ID,
+		Namespace: job.Namespace,
+		Launch:    launch.Launch.Add(3 * time.Second),
+	}
+	must.NoError(t, state.UpsertPeriodicLaunch(1004, launch3))
+
+	out, err = state.PeriodicLaunchByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, queued, out.Queued)
+
+	req.Decision = &structs.PeriodicLaunchDecision{
+		Decision: structs.PeriodicDecisionDequeued,
+		Launch:   queued,
+		Time:     time.Now().UTC(),
+	}
+	must.NoError(t, state.UpsertPeriodicLaunchDecision(structs.MsgTypeTestSetup, 1005, req))
+
+	out, err = state.PeriodicLaunchByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.True(t, out.Queued.IsZero())
+}
+
 func TestStateStore_DeletePeriodicLaunch(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4375,6 +4462,76 @@ func TestStateStore_UpsertEvals_Eval_ChildJob(t *testing.T) {
 	must.False(t, watchFired(ws), must.Sprint("watch should not have fired"))
 }
 
+func TestStateStore_ReleaseDispatchedJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{MaxConcurrent: 1}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 998, nil, parent))
+
+	child := mock.BatchJob()
+	child.Status = ""
+	child.ParentID = parent.ID
+	child.Dispatched = true
+	child.DispatchQueued = true
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, nil, child))
+
+	// A stopped queued job is not released
+	stopped := mock.BatchJob()
+	stopped.Status = ""
+	stopped.ParentID = parent.ID
+	stopped.Dispatched = true
+	stopped.DispatchQueued = true
+	stopped.Stop = true
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, stopped))
+
+	out, err := state.JobByID(nil, child.Namespace, child.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusQueued, out.Status)
+
+	summary, err := state.JobSummaryByID(nil, parent.Namespace, parent.ID)
+	must.NoError(t, err)
+	must.Eq(t, &structs.JobChildrenSummary{Queued: 1, Dead: 1}, summary.Children)
+
+	eval := mock.Eval()
+	eval.Type = structs.JobTypeBatch
+	eval.JobID = child.ID
+	eval.JobModifyIndex = child.JobModifyIndex
+	stoppedEval := mock.Eval()
+	stoppedEval.Type = structs.JobTypeBatch
+	stoppedEval.JobID = stopped.ID
+
+	ws := memdb.NewWatchSet()
+	_, err = state.JobSummaryByID(ws, parent.Namespace, parent.ID)
+	must.NoError(t, err)
+
+	req := &structs.JobDispatchReleaseRequest{
+		Evals: []*structs.Evaluation{eval, stoppedEval},
+	}
+	must.NoError(t, state.ReleaseDispatchedJobs(structs.MsgTypeTestSetup, 1001, req))
+	must.True(t, watchFired(ws), must.Sprint("expected watch to fire"))
+
+	out, err = state.JobByID(nil, child.Namespace, child.ID)
+	must.NoError(t, err)
+	must.False(t, out.DispatchQueued)
+	must.Eq(t, structs.JobStatusPending, out.Status)
+	must.Eq(t, 1001, out.ModifyIndex)
+
+	evalOut, err := state.EvalByID(nil, eval.ID)
+	must.NoError(t, err)
+	must.NotNil(t, evalOut)
+
+	evalOut, err = state.EvalByID(nil, stoppedEval.ID)
+	must.NoError(t, err)
+	must.Nil(t, evalOut)
+
+	summary, err = state.JobSummaryByID(nil, parent.Namespace, parent.ID)
+	must.NoError(t, err)
+	must.Eq(t, &structs.JobChildrenSummary{Pending: 1, Dead: 1}, summary.Children)
+}
+
 func TestStateStore_DeleteEval_Eval(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariableVersions queries all the previous versions of all variables and is
+// used only for snapshot/restore and garbage collection.
+func (s *StateStore) VariableVersions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariableVersions returns the previous versions of the variable at the
+// given namespace and path, newest first. Versions are retained even after the
+// variable itself is deleted.
+func (s *StateStore) GetVariableVersions(
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+	return variableVersionsTxn(txn, ws, namespace, path)
+}
+
+// GetVariableVersion returns a single previous version of the variable at the
+// given namespace and path, identified by the ModifyIndex it had when it was
+// superseded.
+func (s *StateStore) GetVariableVersion(
+	ws memdb.WatchSet, namespace, path string, version uint64) (*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariableVersions, indexID, namespace, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return nil, nil
+	}
+
+	return raw.(*structs.VariableEncrypted), nil
+}
+
+func variableVersionsTxn(
+	txn ReadTxn, ws memdb.WatchSet, namespace, path string) ([]*structs.VariableEncrypted, error) {
+
+	iter, err := txn.Get(TableVariableVersions, indexID+"_prefix", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var versions []*structs.VariableEncrypted
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Ensure the path is an exact match
+		version := raw.(*structs.VariableEncrypted)
+		if version.Path != path {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	// Sort in reverse order so that the newest version is first
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ModifyIndex > versions[j].ModifyIndex
+	})
+
+	return versions, nil
+}
+
+// GetVariableVersionsByKeyID returns an iterator of the previous versions of
+// all variables encrypted with the given root key.
+func (s *StateStore) GetVariableVersionsByKeyID(ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// DeleteVariableVersions is used to garbage collect previous versions of
+// variables. Versions which no longer exist are ignored.
+func (s *StateStore) DeleteVariableVersions(index uint64, keys []*structs.VariableVersionKey) error {
+	txn := s.db.WriteTxnMsgT(structs.VariableVersionsDeleteRequestType, index)
+	defer txn.Abort()
+
+	for _, key := range keys {
+		existing, err := txn.First(TableVariableVersions, indexID, key.Namespace, key.Path, key.Version)
+		if err != nil {
+			return fmt.Errorf("variable version lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+		if err := txn.Delete(TableVariableVersions, existing); err != nil {
+			return fmt.Errorf("variable version deletion failed: %v", err)
+		}
+		size := int64(len(existing.(*structs.VariableEncrypted).Data))
+		if err := updateVariablesQuotaTxn(txn, index, key.Namespace, -size); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariableVersions, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// RekeyVariableVersions is used to replace the data of previous versions of
+// variables with the data re-encrypted with the active root key. Versions
+// which no longer exist or which were already re-encrypted are ignored.
+func (s *StateStore) RekeyVariableVersions(index uint64, versions []*structs.VariableEncrypted) error {
+	txn := s.db.WriteTxnMsgT(structs.VariableVersionsRekeyRequestType, index)
+	defer txn.Abort()
+
+	for _, version := range versions {
+		raw, err := txn.First(TableVariableVersions, indexID,
+			version.Namespace, version.Path, version.ModifyIndex)
+		if err != nil {
+			return fmt.Errorf("variable version lookup failed: %v", err)
+		}
+		if raw == nil {
+			continue
+		}
+		existing := raw.(*structs.VariableEncrypted)
+		if existing.KeyID == version.KeyID {
+			continue
+		}
+
+		// Only the encrypted data changes, as the version is otherwise
+		// immutable
+		updated := existing.Copy()
+		updated.KeyID = version.KeyID
+		updated.Data = version.Data
+		if err := txn.Insert(TableVariableVersions, &updated); err != nil {
+			return fmt.Errorf("failed inserting variable version: %v", err)
+		}
+		change := int64(len(updated.Data) - len(existing.Data))
+		if err := updateVariablesQuotaTxn(txn, index, version.Namespace, change); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariableVersions, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// updateVariablesQuotaTxn applies a change in the size of the variables of
+// the namespace to its quota usage. The quota isn't enforced, as the change is
+// never caused by a write of a user.
+func updateVariablesQuotaTxn(tx WriteTxn, idx uint64, namespace string, change int64) error {
+	if change == 0 {
+		return nil
+	}
+	raw, err := tx.First(TableVariablesQuotas, indexID, namespace)
+	if err != nil {
+		return fmt.Errorf("variable quota lookup failed: %v", err)
+	}
+	if raw == nil {
+		return nil
+	}
+
+	quotaUsed := raw.(*structs.VariablesQuota).Copy()
+	if change > 0 {
+		quotaUsed.Size += change
+	} else {
+		quotaUsed.Size -= min(quotaUsed.Size, -change)
+	}
+	quotaUsed.ModifyIndex = idx
+	if err := tx.Insert(TableVariablesQuotas, quotaUsed); err != nil {
+		return fmt.Errorf("variable quota insert failed: %v", err)
+	}
+	return nil
+}
+
+// VariableRetainVersions returns the number of previous versions retained for
+// the variable at the given namespace and path.
+func (s *StateStore) VariableRetainVersions(ws memdb.WatchSet, namespace, path string) (int, error) {
+	txn := s.db.ReadTxn()
+	return variableRetainVersionsTxn(txn, ws, namespace, path)
+}
+
+func variableRetainVersionsTxn(txn ReadTxn, ws memdb.WatchSet, namespace, path string) (int, error) {
+	watchCh, raw, err := txn.FirstWatch(TableNamespaces, indexID, namespace)
+	if err != nil {
+		return 0, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return 0, nil
+	}
+
+	ns := raw.(*structs.Namespace)
+	return ns.VariablesConfiguration.RetainVersionsForPath(path), nil
+}
+
+// archiveVariableVersionTxn stores the variable being superseded as a previous
+// version, if the namespace retains versions for its path, and removes any
+// versions beyond the retention count. It returns the change in the size of
+// the stored versions, which the caller applies to the quota usage.
+func (s *StateStore) archiveVariableVersionTxn(tx WriteTxn, idx uint64, existing *structs.VariableEncrypted) (int64, error) {
+	retain, err := variableRetainVersionsTxn(tx, nil, existing.Namespace, existing.Path)
+	if err != nil {
+		return 0, err
+	}
+	if retain == 0 {
+		return 0, nil
+	}
+
+	version := existing.Copy()
+	version.Lock = nil
+	if err := tx.Insert(TableVariableVersions, &version); err != nil {
+		return 0, fmt.Errorf("failed inserting variable version: %v", err)
+	}
+	change := int64(len(version.Data))
+
+	versions, err := variableVersionsTxn(tx, nil, existing.Namespace, existing.Path)
+	if err != nil {
+		return 0, err
+	}
+	for i := retain; i < len(versions); i++ {
+		if err := tx.Delete(TableVariableVersions, versions[i]); err != nil {
+			return 0, fmt.Errorf("failed deleting variable version: %v", err)
+		}
+		change -= int64(len(versions[i].Data))
+	}
+
+	if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+		return 0, fmt.Errorf("failed updating variable version index: %v", err)
+	}
+	return change, nil
+}
+
+// retainsVariableVersion returns whether the update to the existing variable
+// retains it as a previous version. Acquiring or releasing a lock and updates
+// which don't change the encrypted data only change the metadata of the
+// variable, so retaining them would fill its history with duplicates.
+func retainsVariableVersion(op structs.VarOp, existing, sv *structs.VariableEncrypted) bool {
+	switch op {
+	case structs.VarOpLockAcquire, structs.VarOpLockRelease:
+		return false
+	}
+	return !bytes.Equal(existing.Data, sv.Data)
+}
+
+// isVariableRekeyTxn returns true if the update to the existing variable is
+// re-encrypting it with a new key because the key it was encrypted with is
+// being rotated out. The contents are unchanged in that case, so there is no
+// new version to retain.
+func isVariableRekeyTxn(tx ReadTxn, existing, sv *structs.VariableEncrypted) (bool, error) {
+	if existing.KeyID == sv.KeyID {
+		return false, nil
+	}
+	raw, err := tx.First(TableRootKeys, indexID, existing.KeyID)
+	if err != nil {
+		return false, fmt.Errorf("root key lookup failed: %v", err)
+	}
+	if raw == nil {
+		return false, nil
+	}
+	return raw.(*structs.RootKey).IsRekeying(), nil
+}
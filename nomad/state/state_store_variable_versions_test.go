@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 2,
+		PathRetainVersions: map[string]int{
+			"keep/none": 0,
+		},
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	setVar := func(idx uint64, path, data string) {
+		t.Helper()
+		sv := mock.VariableEncrypted()
+		sv.Namespace = ns.Name
+		sv.Path = path
+		sv.Data = []byte(data)
+		resp := testState.VarSet(idx, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	setVar(20, "app/config", "v1")
+	versions, err := testState.GetVariableVersions(nil, ns.Name, "app/config")
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+
+	// Each update retains the value it replaces, up to the retention count
+	setVar(21, "app/config", "v2")
+	setVar(22, "app/config", "v3")
+	setVar(23, "app/config", "v4")
+
+	versions, err = testState.GetVariableVersions(nil, ns.Name, "app/config")
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Eq(t, 22, versions[0].ModifyIndex)
+	must.Eq(t, "v3", string(versions[0].Data))
+	must.Eq(t, 21, versions[1].ModifyIndex)
+	must.Eq(t, "v2", string(versions[1].Data))
+
+	index, err := testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, 23, index)
+
+	// Versions of a path sharing a prefix are kept separately
+	setVar(24, "app/config2", "other")
+	setVar(25, "app/config2", "other2")
+	versions, err = testState.GetVariableVersions(nil, ns.Name, "app/config")
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+
+	version, err := testState.GetVariableVersion(nil, ns.Name, "app/config", 22)
+	must.NoError(t, err)
+	must.NotNil(t, version)
+	must.Eq(t, "v3", string(version.Data))
+
+	// Path overrides take precedence over the namespace retention
+	setVar(26, "keep/none/a", "v1")
+	setVar(27, "keep/none/a", "v2")
+	versions, err = testState.GetVariableVersions(nil, ns.Name, "keep/none/a")
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+
+	// Deleting a variable retains its last value
+	resp := testState.VarDelete(28, &structs.VarApplyStateRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: ns.Name,
+				Path:      "app/config",
+			},
+		},
+	})
+	must.NoError(t, resp.Error)
+	versions, err = testState.GetVariableVersions(nil, ns.Name, "app/config")
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Eq(t, 23, versions[0].ModifyIndex)
+	must.Eq(t, "v4", string(versions[0].Data))
+
+	// Versions pin the key they were encrypted with
+	inUse, err := testState.IsRootKeyInUse(versions[0].KeyID)
+	must.NoError(t, err)
+	must.True(t, inUse)
+
+	// Versions can be garbage collected
+	must.NoError(t, testState.DeleteVariableVersions(29, []*structs.VariableVersionKey{
+		{Namespace: ns.Name, Path: "app/config", Version: 23},
+		{Namespace: ns.Name, Path: "app/config", Version: 1000},
+	}))
+	versions, err = testState.GetVariableVersions(nil, ns.Name, "app/config")
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, 22, versions[0].ModifyIndex)
+
+	index, err = testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, 29, index)
+}
+
+func TestStateStore_VariableVersions_Rekey(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	oldKey := structs.NewRootKey(structs.NewRootKeyMeta())
+	must.NoError(t, testState.UpsertRootKey(11, oldKey, false))
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	sv.KeyID = oldKey.KeyID
+	resp := testState.VarSet(20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+	})
+	must.NoError(t, resp.Error)
+
+	// Re-encrypting the variable while its key is being rotated out doesn't
+	// change its contents, so no version is retained
+	must.NoError(t, testState.UpsertRootKey(21, oldKey.MakeRekeying(), false))
+
+	rekeyed := sv.Copy()
+	rekeyed.KeyID = "new-key"
+	rekeyed.Data = []byte("rekeyed")
+	resp = testState.VarSet(22, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &rekeyed,
+	})
+	must.NoError(t, resp.Error)
+
+	versions, err := testState.GetVariableVersions(nil, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+}
+
+func TestStateStore_VariableVersions_Lock(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	sv.Data = []byte("v1")
+	resp := testState.VarSet(20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+	})
+	must.NoError(t, resp.Error)
+
+	versions := func() []*structs.VariableEncrypted {
+		t.Helper()
+		versions, err := testState.GetVariableVersions(nil, ns.Name, sv.Path)
+		must.NoError(t, err)
+		return versions
+	}
+
+	// Acquiring the lock doesn't retain a version
+	locked := sv.Copy()
+	locked.Lock = &structs.VariableLock{ID: "lock-id", TTL: 15 * time.Second}
+	resp = testState.VarLockAcquire(21, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpLockAcquire,
+		Var: &locked,
+	})
+	must.NoError(t, resp.Error)
+	must.Len(t, 0, versions())
+
+	// Updates of the locked variable which don't change its data don't
+	// retain a version either
+	updated := locked.Copy()
+	updated.Lock = &structs.VariableLock{ID: "lock-id", TTL: 30 * time.Second}
+	resp = testState.VarSet(22, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &updated,
+	})
+	must.NoError(t, resp.Error)
+	must.Len(t, 0, versions())
+
+	// Changing the data while holding the lock retains the previous data
+	changed := updated.Copy()
+	changed.Data = []byte("v2")
+	resp = testState.VarSet(23, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &changed,
+	})
+	must.NoError(t, resp.Error)
+	must.Len(t, 1, versions())
+	must.Eq(t, "v1", string(versions()[0].Data))
+
+	// Releasing the lock doesn't retain a version
+	released := changed.Copy()
+	resp = testState.VarLockRelease(24, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpLockRelease,
+		Var: &released,
+	})
+	must.NoError(t, resp.Error)
+	must.Len(t, 1, versions())
+
+	out, err := testState.GetVariable(nil, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Nil(t, out.Lock)
+	must.Eq(t, "v2", string(out.Data))
+}
+
+func TestStateStore_RekeyVariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	for i, data := range []string{"v1", "v2"} {
+		sv := mock.VariableEncrypted()
+		sv.Namespace = ns.Name
+		sv.KeyID = "old-key"
+		sv.Data = []byte(data)
+		resp := testState.VarSet(uint64(20+i), &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	iter, err := testState.GetVariableVersionsByKeyID(nil, "old-key")
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Nil(t, iter.Next())
+
+	rekeyed := raw.(*structs.VariableEncrypted).Copy()
+	rekeyed.KeyID = "new-key"
+	rekeyed.Data = []byte("rekeyed")
+	must.NoError(t, testState.RekeyVariableVersions(30, []*structs.VariableEncrypted{&rekeyed}))
+
+	// Only the encrypted data of the version changes
+	version, err := testState.GetVariableVersion(nil, ns.Name, rekeyed.Path, rekeyed.ModifyIndex)
+	must.NoError(t, err)
+	must.Eq(t, "new-key", version.KeyID)
+	must.Eq(t, "rekeyed", string(version.Data))
+	must.Eq(t, 20, version.ModifyIndex)
+
+	inUse, err := testState.IsRootKeyInUse("old-key")
+	must.NoError(t, err)
+	must.True(t, inUse, must.Sprint("the variable is still encrypted with the old key"))
+
+	// The size of the version counts toward the quota
+	quota, err := testState.VariablesQuotaByNamespace(nil, ns.Name)
+	must.NoError(t, err)
+	must.Eq(t, int64(len("v2")+len("rekeyed")), quota.Size)
+}
+
+func TestStateStore_VariableVersions_Quota(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 1,
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	quotaSize := func() int64 {
+		t.Helper()
+		quota, err := testState.VariablesQuotaByNamespace(nil, ns.Name)
+		must.NoError(t, err)
+		return quota.Size
+	}
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	for i, data := range []string{"a", "bb", "cccc"} {
+		sv := sv.Copy()
+		sv.Data = []byte(data)
+		resp := testState.VarSet(uint64(20+i), &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &sv,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	// The variable and its retained version count, while the pruned version
+	// doesn't
+	must.Eq(t, int64(len("cccc")+len("bb")), quotaSize())
+
+	// Deleting the variable retains it as a version
+	resp := testState.VarDelete(30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: sv,
+	})
+	must.NoError(t, resp.Error)
+	must.Eq(t, int64(len("cccc")), quotaSize())
+
+	// Garbage collecting the versions frees their size
+	must.NoError(t, testState.DeleteVariableVersions(31, []*structs.VariableVersionKey{{
+		Namespace: ns.Name,
+		Path:      sv.Path,
+		Version:   22,
+	}}))
+	must.Eq(t, int64(0), quotaSize())
+}
@@ -122,7 +122,7 @@ func (s *StateStore) GetVariable(
 
 // VarSet is used to store a variable object.
 func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, sv)
 	defer tx.Abort()
 
 	// Perform the actual set.
@@ -141,7 +141,7 @@ func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *struc
 // variable. The ModifyIndex in the provided entry is used to determine if
 // we should write the entry to the state store or not.
 func (s *StateStore) VarSetCAS(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, sv)
 	defer tx.Abort()
 
 	resp := s.varSetCASTxn(tx, idx, sv)
@@ -239,6 +239,20 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 		}
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data) - len(existing.Data))
+
+		rekey, err := isVariableRekeyTxn(tx, existing, sv)
+		if err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		if !rekey && retainsVariableVersion(req.Op, existing, sv) {
+			// Previous versions count toward the quota as they are stored
+			// alongside the variable
+			versionsChange, err := s.archiveVariableVersionTxn(tx, idx, existing)
+			if err != nil {
+				return req.ErrorResponse(idx, err)
+			}
+			quotaChange += versionsChange
+		}
 	} else {
 		sv.CreateIndex = idx
 		sv.ModifyIndex = idx
@@ -300,7 +314,7 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 // VarDelete is used to delete a single variable in the
 // the state store.
 func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, req)
 	defer tx.Abort()
 
 	// Perform the actual delete
@@ -322,7 +336,7 @@ func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *s
 // last observed index for the given variable, then the call is a noop,
 // otherwise a normal delete is invoked.
 func (s *StateStore) VarDeleteCAS(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, req)
 	defer tx.Abort()
 
 	resp := s.svDeleteCASTxn(tx, idx, req)
@@ -413,6 +427,13 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 		return req.ConflictResponse(idx, zeroVal)
 	}
 
+	// The variable is retained as a previous version, which still counts
+	// toward the quota
+	versionsChange, err := s.archiveVariableVersionTxn(tx, idx, sv)
+	if err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+
 	existingQuota, err := tx.First(TableVariablesQuotas, indexID, req.Var.Namespace)
 	if err != nil {
 		return req.ErrorResponse(idx, fmt.Errorf("variable quota lookup failed: %v", err))
@@ -422,7 +443,7 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 	if existingQuota != nil {
 		quotaUsed := existingQuota.(*structs.VariablesQuota)
 		quotaUsed = quotaUsed.Copy()
-		quotaUsed.Size -= min(quotaUsed.Size, int64(len(sv.Data)))
+		quotaUsed.Size -= min(quotaUsed.Size, int64(len(sv.Data))-versionsChange)
 		quotaUsed.ModifyIndex = idx
 		if err := tx.Insert(TableVariablesQuotas, quotaUsed); err != nil {
 			return req.ErrorResponse(idx, fmt.Errorf("variable quota insert failed: %v", err))
@@ -441,6 +462,30 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 	return req.SuccessResponse(idx, nil)
 }
 
+// varWriteTxn returns a write transaction for the given variable request. All
+// variable operations share a single raft message type, so the event type
+// is set from the operation.
+func (s *StateStore) varWriteTxn(idx uint64, req *structs.VarApplyStateRequest) *txn {
+	var eventType string
+	switch req.Op {
+	case structs.VarOpDelete, structs.VarOpDeleteCAS:
+		eventType = structs.TypeVariableDeleted
+	case structs.VarOpLockAcquire:
+		eventType = structs.TypeVariableLockAcquired
+	case structs.VarOpLockRelease:
+		// Releases submitted by the leader when a lock TTL expires are the
+		// only ones which do not carry the lock being released.
+		if req.Var.Lock == nil {
+			eventType = structs.TypeVariableLockExpired
+		} else {
+			eventType = structs.TypeVariableLockReleased
+		}
+	default:
+		eventType = structs.TypeVariableUpserted
+	}
+	return s.db.WriteTxnEvent(structs.VarApplyStateRequestType, eventType, idx)
+}
+
 // WriteTxn is implemented by memdb.Txn to perform write operations.
 type WriteTxn interface {
 	ReadTxn
@@ -486,7 +531,7 @@ func (s *StateStore) VariablesQuotaByNamespace(ws memdb.WatchSet, namespace stri
 // IMPORTANT: this method overwrites the variable, data included.
 func (s *StateStore) VarLockAcquire(idx uint64,
 	req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, req)
 	defer tx.Abort()
 
 	// Try to fetch the variable.
@@ -523,7 +568,7 @@ func (s *StateStore) VarLockAcquire(idx uint64,
 
 func (s *StateStore) VarLockRelease(idx uint64,
 	req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.varWriteTxn(idx, req)
 	defer tx.Abort()
 
 	// Look up the entry in the state store.
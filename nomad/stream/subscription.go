@@ -65,6 +65,13 @@ type SubscribeRequest struct {
 	// associated with the SubscribeRequest has not expired and
 	// has the correct permissions
 	Authenticate func() error
+
+	// AllowEvent is an optional callback that is used to further restrict the
+	// events sent to the subscriber once they have matched the topics, keys
+	// and namespaces. It is used for topics where the ACL check depends on
+	// the event itself, such as variables whose capabilities are set per
+	// path.
+	AllowEvent func(structs.Event) bool
 }
 
 func newSubscription(req *SubscribeRequest, item *bufferItem, unsub func()) *Subscription {
@@ -138,6 +145,10 @@ func filter(req *SubscribeRequest, events []structs.Event) []structs.Event {
 			continue
 		}
 
+		if req.AllowEvent != nil && !req.AllowEvent(event) {
+			continue
+		}
+
 		// *[*] always matches
 		if len(allTopicKeys) == 1 && allTopicKeys[0] == string(structs.TopicAll) {
 			result = append(result, event)
@@ -178,3 +178,25 @@ func TestFilter_FilterKeys(t *testing.T) {
 
 	require.Equal(t, 1, cap(actual))
 }
+
+func TestFilter_AllowEvent(t *testing.T) {
+	ci.Parallel(t)
+
+	event1 := structs.Event{Topic: "Test", Key: "One", Namespace: "foo"}
+	event2 := structs.Event{Topic: "Test", Key: "Two", Namespace: "foo"}
+	events := []structs.Event{event1, event2}
+
+	req := &SubscribeRequest{
+		Topics: map[structs.Topic][]string{
+			"*": {"*"},
+		},
+		Namespaces: []string{"foo"},
+		AllowEvent: func(event structs.Event) bool {
+			return event.Key == "Two"
+		},
+	}
+	actual := filter(req, events)
+	// expect the event rejected by the callback to be filtered out
+	expected := []structs.Event{event2}
+	require.Equal(t, expected, actual)
+}
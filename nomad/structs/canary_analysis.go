@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// CanaryAnalysisDefaultInterval is the default interval between the runs
+	// of a canary analysis.
+	CanaryAnalysisDefaultInterval = time.Minute
+
+	// CanaryAnalysisMinInterval is the minimum interval between the runs of a
+	// canary analysis, which bounds the rate of the queries the leader sends.
+	CanaryAnalysisMinInterval = 10 * time.Second
+
+	// CanaryAnalysisDefaultRuns is the default number of runs of a canary
+	// analysis which must pass before the canaries are automatically promoted.
+	CanaryAnalysisDefaultRuns = 1
+)
+
+// CanaryAnalysis configures the metric queries the deployment watcher runs
+// periodically against the canaries of a deployment and the stable
+// allocations of the task group. The canaries are only auto-promoted once
+// enough runs have passed, and the deployment fails once too many runs have
+// breached the thresholds of the metrics.
+type CanaryAnalysis struct {
+	// Address is the address of the Prometheus-compatible HTTP API the
+	// queries are sent to. It must be one of the addresses allowed by the
+	// configuration of the servers.
+	Address string
+
+	// Interval is the time between each run of the analysis. The first run
+	// happens an Interval after all the canaries are healthy.
+	Interval time.Duration
+
+	// Runs is the number of runs which must pass before the canaries are
+	// auto-promoted.
+	Runs int
+
+	// FailureLimit is the number of failed runs tolerated before the
+	// deployment fails.
+	FailureLimit int
+
+	// Metrics are the metrics compared between the canaries and the stable
+	// allocations.
+	Metrics []*AnalysisMetric
+}
+
+// AnalysisMetric is a metric of a canary analysis. The query is a template
+// executed with AnalysisQueryData, once for the canaries and once for the
+// stable allocations, and must return a single value.
+type AnalysisMetric struct {
+	// Name is the name of the metric.
+	Name string
+
+	// Query is the query template of the metric.
+	Query string
+
+	// Max is the maximum value of the metric for the canaries, if set.
+	Max *float64
+
+	// MaxRatio is the maximum ratio of the value of the metric for the
+	// canaries to its value for the stable allocations, if set.
+	MaxRatio *float64
+}
+
+// AnalysisQueryData is the data the query templates of a canary analysis are
+// executed with.
+type AnalysisQueryData struct {
+	// AllocIDs is a regular expression matching the IDs of the allocations the
+	// query is run for, such as "id1|id2".
+	AllocIDs  string
+	Namespace string
+	JobID     string
+	TaskGroup string
+}
+
+// Copy returns a copy of the canary analysis.
+func (c *CanaryAnalysis) Copy() *CanaryAnalysis {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	nc.Metrics = make([]*AnalysisMetric, 0, len(c.Metrics))
+	for _, m := range c.Metrics {
+		nc.Metrics = append(nc.Metrics, m.Copy())
+	}
+	return &nc
+}
+
+// Copy returns a copy of the analysis metric.
+func (m *AnalysisMetric) Copy() *AnalysisMetric {
+	if m == nil {
+		return nil
+	}
+	nm := *m
+	nm.Max = pointer.Copy(m.Max)
+	nm.MaxRatio = pointer.Copy(m.MaxRatio)
+	return &nm
+}
+
+// Validate returns an error if the canary analysis is invalid.
+func (c *CanaryAnalysis) Validate() error {
+	var mErr []error
+	if c.Address == "" {
+		mErr = append(mErr, errors.New("Address must be set"))
+	}
+	if c.Interval < CanaryAnalysisMinInterval {
+		mErr = append(mErr, fmt.Errorf("Interval must be at least %v", CanaryAnalysisMinInterval))
+	}
+	if c.Runs < 1 {
+		mErr = append(mErr, fmt.Errorf("Runs must be at least 1 but found %d", c.Runs))
+	}
+	if c.FailureLimit < 0 {
+		mErr = append(mErr, fmt.Errorf("Failure limit can not be less than zero: %d < 0", c.FailureLimit))
+	}
+	if len(c.Metrics) == 0 {
+		mErr = append(mErr, errors.New("At least one metric must be set"))
+	}
+
+	names := make(map[string]struct{}, len(c.Metrics))
+	for i, m := range c.Metrics {
+		if m.Name == "" {
+			mErr = append(mErr, fmt.Errorf("Metric %d must have a name", i+1))
+		} else if _, ok := names[m.Name]; ok {
+			mErr = append(mErr, fmt.Errorf("Metric %q is defined more than once", m.Name))
+		}
+		names[m.Name] = struct{}{}
+
+		if err := m.Validate(); err != nil {
+			mErr = append(mErr, fmt.Errorf("Metric %q: %w", m.Name, err))
+		}
+	}
+	return errors.Join(mErr...)
+}
+
+// AddressAllowed returns whether the address of the canary analysis is one of
+// the allowed addresses. Addresses are compared without trailing slashes.
+func (c *CanaryAnalysis) AddressAllowed(allowed []string) bool {
+	address := strings.TrimSuffix(c.Address, "/")
+	return slices.ContainsFunc(allowed, func(a string) bool {
+		return strings.TrimSuffix(a, "/") == address
+	})
+}
+
+// Validate returns an error if the analysis metric is invalid.
+func (m *AnalysisMetric) Validate() error {
+	var mErr []error
+	if strings.TrimSpace(m.Query) == "" {
+		mErr = append(mErr, errors.New("Query must be set"))
+	} else if _, err := m.Template(); err != nil {
+		mErr = append(mErr, fmt.Errorf("Query is not a valid template: %w", err))
+	}
+	if m.Max == nil && m.MaxRatio == nil {
+		mErr = append(mErr, errors.New("Max or max ratio must be set"))
+	}
+	if m.MaxRatio != nil && *m.MaxRatio <= 0 {
+		mErr = append(mErr, fmt.Errorf("Max ratio must be greater than zero: %v", *m.MaxRatio))
+	}
+	return errors.Join(mErr...)
+}
+
+// Template parses the query template of the metric.
+func (m *AnalysisMetric) Template() (*template.Template, error) {
+	return template.New(m.Name).Option("missingkey=error").Parse(m.Query)
+}
+
+// Check returns whether the values of the metric for the canaries and the
+// stable allocations are within its thresholds. The stable value is nil if
+// there are no stable allocations, in which case the max ratio isn't checked.
+func (m *AnalysisMetric) Check(canary float64, stable *float64) bool {
+	if m.Max != nil && canary > *m.Max {
+		return false
+	}
+	if m.MaxRatio != nil && stable != nil && canary > *stable**m.MaxRatio {
+		return false
+	}
+	return true
+}
+
+// DeploymentAnalysis is the state of the canary analysis of a task group in a
+// deployment.
+type DeploymentAnalysis struct {
+	// PassedRuns is the number of runs which passed.
+	PassedRuns int
+
+	// FailedRuns is the number of runs which failed.
+	FailedRuns int
+
+	// LastRun is the time of the last run.
+	LastRun time.Time
+
+	// Metrics are the results of the last run.
+	Metrics []*AnalysisMetricResult
+}
+
+// AnalysisMetricResult is the result of a metric in a run of a canary
+// analysis.
+type AnalysisMetricResult struct {
+	// Name is the name of the metric.
+	Name string
+
+	// Canary is the value of the metric for the canaries.
+	Canary float64
+
+	// Stable is the value of the metric for the stable allocations, if there
+	// are any.
+	Stable *float64
+
+	// Passed marks whether the values are within the thresholds.
+	Passed bool
+
+	// Error is the error which occurred when querying the metric, if any. A
+	// metric which can't be queried fails.
+	Error string
+}
+
+// Copy returns a copy of the deployment analysis.
+func (d *DeploymentAnalysis) Copy() *DeploymentAnalysis {
+	if d == nil {
+		return nil
+	}
+	nd := *d
+	nd.Metrics = make([]*AnalysisMetricResult, 0, len(d.Metrics))
+	for _, m := range d.Metrics {
+		nm := *m
+		nm.Stable = pointer.Copy(m.Stable)
+		nd.Metrics = append(nd.Metrics, &nm)
+	}
+	return &nd
+}
+
+// Passed returns whether every metric of the run passed.
+func (d *DeploymentAnalysis) Passed() bool {
+	return !slices.ContainsFunc(d.Metrics, func(m *AnalysisMetricResult) bool {
+		return !m.Passed
+	})
+}
+
+// DeploymentAnalysisUpdateRequest is used to record the result of a run of the
+// canary analysis of a task group via Raft.
+type DeploymentAnalysisUpdateRequest struct {
+	DeploymentID string
+	TaskGroup    string
+
+	// Analysis is the new state of the canary analysis of the task group.
+	Analysis *DeploymentAnalysis
+
+	WriteRequest
+}
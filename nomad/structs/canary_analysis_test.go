@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestCanaryAnalysis_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		canary   int
+		analysis *CanaryAnalysis
+		expErr   []string
+	}{
+		{
+			name:   "valid",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Address:  "http://127.0.0.1:9090",
+				Interval: CanaryAnalysisDefaultInterval,
+				Runs:     CanaryAnalysisDefaultRuns,
+				Metrics: []*AnalysisMetric{{
+					Name:     "errors",
+					Query:    `sum(rate(errors{alloc_id=~"{{.AllocIDs}}"}[1m]))`,
+					MaxRatio: pointer.Of(1.2),
+				}},
+			},
+		},
+		{
+			name:   "invalid",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Interval:     time.Nanosecond,
+				Runs:         0,
+				FailureLimit: -1,
+			},
+			expErr: []string{
+				"Address must be set",
+				"Interval must be at least 10s",
+				"Runs must be at least 1 but found 0",
+				"Failure limit can not be less than zero",
+				"At least one metric must be set",
+			},
+		},
+		{
+			name:   "invalid metrics",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Address:  "http://127.0.0.1:9090",
+				Interval: CanaryAnalysisDefaultInterval,
+				Runs:     CanaryAnalysisDefaultRuns,
+				Metrics: []*AnalysisMetric{
+					{Name: "errors", Query: "{{.AllocIDs", Max: pointer.Of(1.0)},
+					{Name: "errors", Query: "errors", MaxRatio: pointer.Of(0.0)},
+					{Query: "latency"},
+				},
+			},
+			expErr: []string{
+				`Metric "errors": Query is not a valid template`,
+				`Metric "errors" is defined more than once`,
+				`Metric "errors": Max ratio must be greater than zero`,
+				"Metric 3 must have a name",
+				`Metric "": Max or max ratio must be set`,
+			},
+		},
+		{
+			name:   "no canaries",
+			canary: 0,
+			analysis: &CanaryAnalysis{
+				Address:  "http://127.0.0.1:9090",
+				Interval: CanaryAnalysisDefaultInterval,
+				Runs:     CanaryAnalysisDefaultRuns,
+				Metrics: []*AnalysisMetric{{
+					Name:  "errors",
+					Query: "errors",
+					Max:   pointer.Of(1.0),
+				}},
+			},
+			expErr: []string{"Canary analysis requires a Canary count greater than zero"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := DefaultUpdateStrategy.Copy()
+			u.Canary = tc.canary
+			u.Analysis = tc.analysis
+
+			err := u.Validate()
+			if len(tc.expErr) == 0 {
+				must.NoError(t, err)
+				return
+			}
+			must.Error(t, err)
+			for _, exp := range tc.expErr {
+				must.StrContains(t, err.Error(), exp)
+			}
+		})
+	}
+}
+
+func TestAnalysisMetric_Check(t *testing.T) {
+	ci.Parallel(t)
+
+	m := &AnalysisMetric{Max: pointer.Of(1.0), MaxRatio: pointer.Of(1.5)}
+	must.True(t, m.Check(0.9, pointer.Of(0.8)))
+	must.False(t, m.Check(1.1, pointer.Of(1.0)))
+	must.False(t, m.Check(0.9, pointer.Of(0.5)))
+
+	// The ratio isn't checked without stable allocations
+	must.True(t, m.Check(0.9, nil))
+}
+
+func TestCanaryAnalysis_AddressAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	c := &CanaryAnalysis{Address: "http://prometheus.example:9090/"}
+	must.False(t, c.AddressAllowed(nil))
+	must.False(t, c.AddressAllowed([]string{"http://169.254.169.254"}))
+	must.True(t, c.AddressAllowed([]string{"http://169.254.169.254", "http://prometheus.example:9090"}))
+}
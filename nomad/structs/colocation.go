@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ColocationSelectorAnyNamespace selects allocations in every namespace.
+	ColocationSelectorAnyNamespace = "*"
+
+	colocationSelectorMetaPrefix = "meta."
+)
+
+// ColocationSelector selects the allocations of other jobs that the
+// colocated_with and not_colocated_with operators are evaluated against. It is
+// parsed from the RTarget of a constraint or affinity, a comma separated list
+// of key=value terms such as "job=api,group=web". Every term must match for
+// an allocation to be selected.
+type ColocationSelector struct {
+	// Namespace of the allocations. It defaults to the namespace of the job
+	// being placed, and "*" selects every namespace.
+	Namespace string
+
+	// JobID of the allocations.
+	JobID string
+
+	// TaskGroup of the allocations.
+	TaskGroup string
+
+	// Meta the job of the allocations must have.
+	Meta map[string]string
+}
+
+// ParseColocationSelector parses a selector of the form
+// "job=<id>,group=<name>,namespace=<name>,meta.<key>=<value>". Terms may be
+// given in any order and at least one of job, group or meta is required.
+func ParseColocationSelector(s string) (*ColocationSelector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("selector must not be empty")
+	}
+
+	sel := &ColocationSelector{}
+	set := func(field *string, key, value string) error {
+		if *field != "" {
+			return fmt.Errorf("duplicate %q term", key)
+		}
+		*field = value
+		return nil
+	}
+
+	for _, term := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(term, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("term %q must be of the form key=value", strings.TrimSpace(term))
+		}
+
+		var err error
+		switch {
+		case key == "job":
+			err = set(&sel.JobID, key, value)
+		case key == "group":
+			err = set(&sel.TaskGroup, key, value)
+		case key == "namespace":
+			err = set(&sel.Namespace, key, value)
+		case strings.HasPrefix(key, colocationSelectorMetaPrefix):
+			metaKey := strings.TrimPrefix(key, colocationSelectorMetaPrefix)
+			if metaKey == "" {
+				return nil, fmt.Errorf("term %q is missing a meta key", key)
+			}
+			if _, ok := sel.Meta[metaKey]; ok {
+				return nil, fmt.Errorf("duplicate %q term", key)
+			}
+			if sel.Meta == nil {
+				sel.Meta = make(map[string]string)
+			}
+			sel.Meta[metaKey] = value
+		default:
+			return nil, fmt.Errorf("unknown key %q; must be one of \"job\", \"group\", \"namespace\" or \"meta.<key>\"", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if sel.JobID == "" && sel.TaskGroup == "" && len(sel.Meta) == 0 {
+		return nil, errors.New("selector must include a job, group or meta term")
+	}
+	return sel, nil
+}
+
+// Matches returns whether the allocation is selected. The namespace is the
+// namespace of the job being placed, used when the selector doesn't set one.
+func (s *ColocationSelector) Matches(alloc *Allocation, namespace string) bool {
+	if s.Namespace != "" {
+		namespace = s.Namespace
+	}
+	if namespace != ColocationSelectorAnyNamespace && alloc.Namespace != namespace {
+		return false
+	}
+	if s.JobID != "" && alloc.JobID != s.JobID {
+		return false
+	}
+	if s.TaskGroup != "" && alloc.TaskGroup != s.TaskGroup {
+		return false
+	}
+	if len(s.Meta) > 0 {
+		if alloc.Job == nil {
+			return false
+		}
+		for k, v := range s.Meta {
+			if m, ok := alloc.Job.Meta[k]; !ok || m != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ColocationNamespaces returns the namespaces other than the namespace of the
+// job which the colocation selectors of its constraints and affinities select
+// allocations in. It includes ColocationSelectorAnyNamespace if a selector
+// selects every namespace. Selectors which can't be parsed are ignored, as
+// they are rejected when the job is validated.
+func (j *Job) ColocationNamespaces() []string {
+	var namespaces []string
+	add := func(operand, target string) {
+		if operand != ConstraintColocatedWith && operand != ConstraintNotColocatedWith {
+			return
+		}
+		sel, err := ParseColocationSelector(target)
+		if err != nil || sel.Namespace == "" || sel.Namespace == j.Namespace {
+			return
+		}
+		if !slices.Contains(namespaces, sel.Namespace) {
+			namespaces = append(namespaces, sel.Namespace)
+		}
+	}
+	addConstraints := func(constraints []*Constraint) {
+		for _, c := range constraints {
+			add(c.Operand, c.RTarget)
+		}
+	}
+	addAffinities := func(affinities []*Affinity) {
+		for _, a := range affinities {
+			add(a.Operand, a.RTarget)
+		}
+	}
+
+	addConstraints(j.Constraints)
+	addAffinities(j.Affinities)
+	for _, tg := range j.TaskGroups {
+		addConstraints(tg.Constraints)
+		addAffinities(tg.Affinities)
+		for _, t := range tg.Tasks {
+			addConstraints(t.Constraints)
+			addAffinities(t.Affinities)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestParseColocationSelector(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		input  string
+		exp    *ColocationSelector
+		expErr string
+	}{
+		{
+			name:  "job",
+			input: "job=api",
+			exp:   &ColocationSelector{JobID: "api"},
+		},
+		{
+			name:  "all terms",
+			input: "job=api, group=web ,namespace=*,meta.team=payments",
+			exp: &ColocationSelector{
+				Namespace: "*",
+				JobID:     "api",
+				TaskGroup: "web",
+				Meta:      map[string]string{"team": "payments"},
+			},
+		},
+		{
+			name:   "empty",
+			input:  " ",
+			expErr: "selector must not be empty",
+		},
+		{
+			name:   "missing value",
+			input:  "job=",
+			expErr: `term "job=" must be of the form key=value`,
+		},
+		{
+			name:   "unknown key",
+			input:  "node=web",
+			expErr: `unknown key "node"`,
+		},
+		{
+			name:   "duplicate key",
+			input:  "job=api,job=web",
+			expErr: `duplicate "job" term`,
+		},
+		{
+			name:   "missing meta key",
+			input:  "meta.=foo",
+			expErr: `term "meta." is missing a meta key`,
+		},
+		{
+			name:   "only namespace",
+			input:  "namespace=prod",
+			expErr: "selector must include a job, group or meta term",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sel, err := ParseColocationSelector(tc.input)
+			if tc.expErr != "" {
+				must.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, sel)
+		})
+	}
+}
+
+func TestColocationSelector_Matches(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := &Allocation{
+		Namespace: "prod",
+		JobID:     "api",
+		TaskGroup: "web",
+		Job: &Job{
+			ID:   "api",
+			Meta: map[string]string{"team": "payments"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		selector string
+		exp      bool
+	}{
+		{name: "job", selector: "job=api", exp: true},
+		{name: "other job", selector: "job=cache", exp: false},
+		{name: "job and group", selector: "job=api,group=web", exp: true},
+		{name: "other group", selector: "job=api,group=worker", exp: false},
+		{name: "meta", selector: "meta.team=payments", exp: true},
+		{name: "other meta", selector: "meta.team=search", exp: false},
+		{name: "missing meta", selector: "meta.noisy=true", exp: false},
+		{name: "other namespace", selector: "job=api,namespace=dev", exp: false},
+		{name: "any namespace", selector: "job=api,namespace=*", exp: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sel, err := ParseColocationSelector(tc.selector)
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, sel.Matches(alloc, "prod"))
+		})
+	}
+
+	t.Run("default namespace", func(t *testing.T) {
+		sel, err := ParseColocationSelector("job=api")
+		must.NoError(t, err)
+		must.False(t, sel.Matches(alloc, DefaultNamespace))
+	})
+}
+
+func TestJob_ColocationNamespaces(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{
+		Namespace: "prod",
+		Constraints: []*Constraint{
+			{Operand: ConstraintColocatedWith, RTarget: "job=api,namespace=prod"},
+			{Operand: ConstraintDistinctHosts, RTarget: "namespace=dev"},
+		},
+		TaskGroups: []*TaskGroup{{
+			Affinities: []*Affinity{
+				{Operand: ConstraintNotColocatedWith, RTarget: "job=batch,namespace=dev"},
+			},
+			Tasks: []*Task{{
+				Constraints: []*Constraint{
+					{Operand: ConstraintColocatedWith, RTarget: "job=cache"},
+					{Operand: ConstraintColocatedWith, RTarget: "meta.team=search,namespace=*"},
+					{Operand: ConstraintColocatedWith, RTarget: "job=db,namespace=dev"},
+				},
+			}},
+		}},
+	}
+	must.Eq(t, []string{"dev", ColocationSelectorAnyNamespace}, job.ColocationNamespaces())
+}
@@ -75,7 +75,8 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
 	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "VaultToken"}
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "ActiveSince", "NomadTokenID", "VaultToken",
+		"DispatchQueued"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -129,6 +130,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	depsDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depsDiff != nil {
+		diff.Objects = append(diff.Objects, depsDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -146,6 +158,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// Rebalance diff
+	if rDiff := primitiveObjectDiff(j.Rebalance, other.Rebalance, nil, "Rebalance", contextual); rDiff != nil {
+		diff.Objects = append(diff.Objects, rDiff)
+	}
+
 	// Multiregion diff
 	if mrDiff := multiregionDiff(j.Multiregion, other.Multiregion, contextual); mrDiff != nil {
 		diff.Objects = append(diff.Objects, mrDiff)
@@ -289,6 +306,20 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
+	var oldRestartRules, newRestartRules []*ExitRule
+	if tg.RestartPolicy != nil {
+		oldRestartRules = tg.RestartPolicy.ExitRules
+	}
+	if other.RestartPolicy != nil {
+		newRestartRules = other.RestartPolicy.ExitRules
+	}
+	if eDiffs := exitRulesDiffs(oldRestartRules, newRestartRules, contextual); eDiffs != nil &&
+		(rDiff != nil || !reflect.DeepEqual(oldRestartRules, newRestartRules)) {
+		if rDiff == nil {
+			rDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "RestartPolicy"}
+		}
+		rDiff.Objects = append(rDiff.Objects, eDiffs...)
+	}
 	if rDiff != nil {
 		diff.Objects = append(diff.Objects, rDiff)
 	}
@@ -301,6 +332,20 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 
 	// Reschedule policy diff
 	reschedDiff := primitiveObjectDiff(tg.ReschedulePolicy, other.ReschedulePolicy, nil, "ReschedulePolicy", contextual)
+	var oldRescheduleRules, newRescheduleRules []*ExitRule
+	if tg.ReschedulePolicy != nil {
+		oldRescheduleRules = tg.ReschedulePolicy.ExitRules
+	}
+	if other.ReschedulePolicy != nil {
+		newRescheduleRules = other.ReschedulePolicy.ExitRules
+	}
+	if eDiffs := exitRulesDiffs(oldRescheduleRules, newRescheduleRules, contextual); eDiffs != nil &&
+		(reschedDiff != nil || !reflect.DeepEqual(oldRescheduleRules, newRescheduleRules)) {
+		if reschedDiff == nil {
+			reschedDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "ReschedulePolicy"}
+		}
+		reschedDiff.Objects = append(reschedDiff.Objects, eDiffs...)
+	}
 	if reschedDiff != nil {
 		diff.Objects = append(diff.Objects, reschedDiff)
 	}
@@ -318,7 +363,21 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 
 	// Update diff
 	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual)
+	var oldAnalysis, newAnalysis *CanaryAnalysis
+	if tg.Update != nil {
+		oldAnalysis = tg.Update.Analysis
+	}
+	if other.Update != nil {
+		newAnalysis = other.Update.Analysis
+	}
+	if aDiff := canaryAnalysisDiff(oldAnalysis, newAnalysis, contextual); aDiff != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, aDiff)
+	}
+	if uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
@@ -504,8 +563,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
-	if lDiff != nil {
+	if lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual); lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
 
@@ -1051,6 +1109,41 @@ func checkRestartDiff(old, new *CheckRestart, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two log config objects, including their
+// sinks. If contextual diff is enabled, all fields will be returned, even if no
+// diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "LogConfig"}
+	var oldFlat, newFlat map[string]string
+	var oldSink, newSink *LogSinkConfig
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newFlat = flatmap.Flatten(new, nil, true)
+		newSink = new.Sink
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldFlat = flatmap.Flatten(old, nil, true)
+		oldSink = old.Sink
+	} else {
+		diff.Type = DiffTypeEdited
+		oldFlat = flatmap.Flatten(old, nil, true)
+		newFlat = flatmap.Flatten(new, nil, true)
+		oldSink, newSink = old.Sink, new.Sink
+	}
+
+	diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+
+	// Sink diff
+	if sDiff := primitiveObjectDiff(oldSink, newSink, nil, "Sink", contextual); sDiff != nil {
+		diff.Objects = append(diff.Objects, sDiff)
+	}
+
+	return diff
+}
+
 // connectDiffs returns the diff of two Consul connect objects. If contextual
 // diff is enabled, all fields will be returned, even if no diff occurred.
 func connectDiffs(old, new *ConsulConnect, contextual bool) *ObjectDiff {
@@ -1704,8 +1797,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
-	if lDiff != nil {
+	if lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual); lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
 
@@ -2730,6 +2822,67 @@ func disconectStrategyDiffs(old, new *DisconnectStrategy, contextual bool) *Obje
 	return diff
 }
 
+// canaryAnalysisDiff returns the diff of the canary analysis of an update
+// block, including the fields of its metrics.
+func canaryAnalysisDiff(old, new *CanaryAnalysis, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Analysis"}
+	var oldFlat, newFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newFlat = flatmap.Flatten(new, nil, false)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldFlat = flatmap.Flatten(old, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldFlat = flatmap.Flatten(old, nil, false)
+		newFlat = flatmap.Flatten(new, nil, false)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+
+	return diff
+}
+
+// exitRulesDiffs returns the diffs of the exit rules of a restart or
+// reschedule policy. Rules are ordered, as the first matching rule applies, so
+// they are diffed by position.
+func exitRulesDiffs(old, new []*ExitRule, contextual bool) []*ObjectDiff {
+	var diffs []*ObjectDiff
+	for i := 0; i < max(len(old), len(new)); i++ {
+		diff := &ObjectDiff{Type: DiffTypeNone, Name: "ExitRule"}
+		var oldFlat, newFlat map[string]string
+
+		switch {
+		case i >= len(old):
+			diff.Type = DiffTypeAdded
+			newFlat = flatmap.Flatten(new[i], nil, false)
+		case i >= len(new):
+			diff.Type = DiffTypeDeleted
+			oldFlat = flatmap.Flatten(old[i], nil, false)
+		case reflect.DeepEqual(old[i], new[i]):
+			if !contextual {
+				continue
+			}
+			oldFlat = flatmap.Flatten(old[i], nil, false)
+			newFlat = oldFlat
+		default:
+			diff.Type = DiffTypeEdited
+			oldFlat = flatmap.Flatten(old[i], nil, false)
+			newFlat = flatmap.Flatten(new[i], nil, false)
+		}
+
+		diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
 // networkResourceDiffs diffs a set of NetworkResources. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func networkResourceDiffs(old, new []*NetworkResource, contextual bool) []*ObjectDiff {
@@ -153,6 +153,12 @@ func TestJobDiff(t *testing.T) {
 				Type: DiffTypeDeleted,
 				ID:   "foo",
 				Fields: []*FieldDiff{
+					{
+						Type: DiffTypeDeleted,
+						Name: "ActiveDeadline",
+						Old:  "0",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "AllAtOnce",
@@ -222,6 +228,12 @@ func TestJobDiff(t *testing.T) {
 				Type: DiffTypeAdded,
 				ID:   "foo",
 				Fields: []*FieldDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "ActiveDeadline",
+						Old:  "",
+						New:  "0",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "AllAtOnce",
@@ -552,6 +564,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "foo",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "StartingDeadline",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "TimeZone",
@@ -600,6 +618,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "foo",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "StartingDeadline",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "TimeZone",
@@ -674,6 +698,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "StartingDeadline",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "TimeZone",
@@ -1027,6 +1057,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "CatchUp",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "ConcurrencyPolicy",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Enabled",
@@ -1051,6 +1093,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "foo",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "StartingDeadline",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "TimeZone",
@@ -1246,14 +1294,95 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Dependencies edited
+			Old: &Job{
+				DependsOn: []*JobDependency{
+					{
+						JobID:     "migrate",
+						Condition: JobDependencyConditionComplete,
+					},
+				},
+			},
+			New: &Job{
+				DependsOn: []*JobDependency{
+					{
+						JobID:     "migrate",
+						Condition: JobDependencyConditionComplete,
+					},
+					{
+						JobID:     "db",
+						Condition: JobDependencyConditionHealthy,
+					},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Condition",
+								Old:  "",
+								New:  JobDependencyConditionHealthy,
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "JobID",
+								Old:  "",
+								New:  "db",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Rebalance edited
+			Old: &Job{
+				Rebalance: &RebalancePolicy{
+					Threshold:     0.2,
+					MaxDisruption: 1,
+					Interval:      10 * time.Minute,
+				},
+			},
+			New: &Job{
+				Rebalance: &RebalancePolicy{
+					Threshold:     0.5,
+					MaxDisruption: 1,
+					Interval:      10 * time.Minute,
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Rebalance",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Threshold",
+								Old:  "0.2",
+								New:  "0.5",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Parameterized Job added
 			Old: &Job{},
 			New: &Job{
 				ParameterizedJob: &ParameterizedJobConfig{
-					Payload:      DispatchPayloadRequired,
-					MetaOptional: []string{"foo"},
-					MetaRequired: []string{"bar"},
+					Payload:       DispatchPayloadRequired,
+					MetaOptional:  []string{"foo"},
+					MetaRequired:  []string{"bar"},
+					MaxConcurrent: 10,
 				},
 			},
 			Expected: &JobDiff{
@@ -1263,6 +1392,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxConcurrent",
+								Old:  "",
+								New:  "10",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Payload",
@@ -1304,9 +1439,10 @@ func TestJobDiff(t *testing.T) {
 			// Parameterized Job deleted
 			Old: &Job{
 				ParameterizedJob: &ParameterizedJobConfig{
-					Payload:      DispatchPayloadRequired,
-					MetaOptional: []string{"foo"},
-					MetaRequired: []string{"bar"},
+					Payload:       DispatchPayloadRequired,
+					MetaOptional:  []string{"foo"},
+					MetaRequired:  []string{"bar"},
+					MaxConcurrent: 10,
 				},
 			},
 			New: &Job{},
@@ -1317,6 +1453,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxConcurrent",
+								Old:  "10",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Payload",
@@ -1450,6 +1592,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "MaxConcurrent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Payload",
@@ -2422,6 +2570,81 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "RestartPolicy exit rules edited",
+			Old: &TaskGroup{
+				RestartPolicy: &RestartPolicy{
+					Attempts: 1,
+					Mode:     "fail",
+					ExitRules: []*ExitRule{
+						{ExitCodes: []int{3}, Action: ExitRuleActionFail},
+						{Signals: []int{9}, Action: ExitRuleActionReschedule},
+					},
+				},
+			},
+			New: &TaskGroup{
+				RestartPolicy: &RestartPolicy{
+					Attempts: 1,
+					Mode:     "fail",
+					ExitRules: []*ExitRule{
+						{ExitCodes: []int{3, 4}, Action: ExitRuleActionSucceed},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "RestartPolicy",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "ExitRule",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "Action",
+										Old:  ExitRuleActionFail,
+										New:  ExitRuleActionSucceed,
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "ExitCodes[1]",
+										Old:  "",
+										New:  "4",
+									},
+								},
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "ExitRule",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeDeleted,
+										Name: "Action",
+										Old:  ExitRuleActionReschedule,
+										New:  "",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "ExitCodes",
+										Old:  "nil",
+										New:  "",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Signals[0]",
+										Old:  "9",
+										New:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase: "ReschedulePolicy added",
 			Old:      &TaskGroup{},
@@ -2698,12 +2921,24 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "true",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "BlueGreen",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Canary",
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "CanaryPercent",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "HealthyDeadline",
@@ -2716,6 +2951,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxSurge",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MinHealthyTime",
@@ -2760,12 +3001,24 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "BlueGreen",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Canary",
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "CanaryPercent",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "HealthyDeadline",
@@ -2778,6 +3031,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxSurge",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MinHealthyTime",
@@ -2795,6 +3054,68 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "Update strategy analysis edited",
+			Old: &TaskGroup{
+				Update: &UpdateStrategy{
+					Canary: 1,
+					Analysis: &CanaryAnalysis{
+						Address:  "http://127.0.0.1:9090",
+						Interval: time.Minute,
+						Runs:     1,
+						Metrics: []*AnalysisMetric{{
+							Name:  "errors",
+							Query: "errors",
+							Max:   pointer.Of(1.0),
+						}},
+					},
+				},
+			},
+			New: &TaskGroup{
+				Update: &UpdateStrategy{
+					Canary: 1,
+					Analysis: &CanaryAnalysis{
+						Address:  "http://127.0.0.1:9090",
+						Interval: time.Minute,
+						Runs:     3,
+						Metrics: []*AnalysisMetric{{
+							Name:  "errors",
+							Query: "errors",
+							Max:   pointer.Of(2.0),
+						}},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Update",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Analysis",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "Metrics[0].Max",
+										Old:  "1",
+										New:  "2",
+									},
+									{
+										Type: DiffTypeEdited,
+										Name: "Runs",
+										Old:  "1",
+										New:  "3",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase: "Update strategy edited",
 			Old: &TaskGroup{
@@ -2927,12 +3248,24 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "true",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "BlueGreen",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Canary",
 								Old:  "2",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "CanaryPercent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "HealthCheck",
@@ -2951,6 +3284,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "5",
 								New:  "7",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxSurge",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "MinHealthyTime",
@@ -4565,6 +4904,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "bam",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ActiveDeadline",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Driver",
@@ -4611,6 +4956,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "foo",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "ActiveDeadline",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Driver",
@@ -5862,6 +6213,61 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig sink added",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sink: &LogSinkConfig{
+						Type:       LogSinkTypeSyslog,
+						Address:    "udp://127.0.0.1:514",
+						BufferSize: 1024,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "udp://127.0.0.1:514",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "1024",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "syslog",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:       "LogConfig edited with context",
 			Contextual: true,
@@ -35,6 +35,7 @@ const (
 	TopicCSIVolume      Topic = "CSIVolume"
 	TopicCSIPlugin      Topic = "CSIPlugin"
 	TopicOperator       Topic = "Operator"
+	TopicVariable       Topic = "Variable"
 	TopicAll            Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -54,6 +55,8 @@ const (
 	TypeJobRegistered                 = "JobRegistered"
 	TypeJobDeregistered               = "JobDeregistered"
 	TypeJobBatchDeregistered          = "JobBatchDeregistered"
+	TypePeriodicLaunchDecision        = "PeriodicLaunchDecision"
+	TypeJobDispatchReleased           = "JobDispatchReleased"
 	TypePlanResult                    = "PlanResult"
 	TypeACLTokenDeleted               = "ACLTokenDeleted"
 	TypeACLTokenUpserted              = "ACLTokenUpserted"
@@ -73,6 +76,11 @@ const (
 	TypeCSIVolumeDeregistered         = "CSIVolumeDeregistered"
 	TypeCSIVolumeClaim                = "CSIVolumeClaim"
 	TypeUtilizationSnapshotUpserted   = "UtilizationSnapshotUpserted"
+	TypeVariableUpserted              = "VariableUpserted"
+	TypeVariableDeleted               = "VariableDeleted"
+	TypeVariableLockAcquired          = "VariableLockAcquired"
+	TypeVariableLockReleased          = "VariableLockReleased"
+	TypeVariableLockExpired           = "VariableLockExpired"
 )
 
 // Event represents a change in Nomads state.
@@ -125,6 +133,11 @@ type JobEvent struct {
 	Job *Job
 }
 
+// PeriodicLaunchEvent holds a newly updated PeriodicLaunch.
+type PeriodicLaunchEvent struct {
+	PeriodicLaunch *PeriodicLaunch
+}
+
 // EvaluationEvent holds a newly updated Eval.
 type EvaluationEvent struct {
 	Evaluation *Evaluation
@@ -216,3 +229,20 @@ type CSIVolumeEvent struct {
 type CSIPluginEvent struct {
 	Plugin *CSIPlugin
 }
+
+// VariableEvent holds the metadata of a newly updated or deleted variable to
+// be used as an event in the event stream. The encrypted data and the lock ID
+// are never included, as the event stream is not a way to read variables.
+type VariableEvent struct {
+	Variable *VariableMetadata
+}
+
+// NewVariableEvent takes an encrypted variable and creates a new
+// VariableEvent from a copy of its metadata, with any lock ID removed.
+func NewVariableEvent(v *VariableEncrypted) *VariableEvent {
+	meta := v.VariableMetadata.Copy()
+	if meta.Lock != nil {
+		meta.Lock.ID = ""
+	}
+	return &VariableEvent{Variable: meta}
+}
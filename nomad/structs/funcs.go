@@ -231,21 +231,28 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex, checkDevi
 }
 
 func computeFreePercentage(node *Node, util *ComparableResources) (freePctCpu, freePctRam float64) {
+	nodeCpu, nodeMem := computeNodeAvailable(node)
+
+	// Compute the free percentage
+	freePctCpu = 1 - (float64(util.Flattened.Cpu.CpuShares) / nodeCpu)
+	freePctRam = 1 - (float64(util.Flattened.Memory.MemoryMB) / nodeMem)
+	return freePctCpu, freePctRam
+}
+
+// computeNodeAvailable returns the CPU shares and memory of the node which are
+// available to allocations.
+func computeNodeAvailable(node *Node) (nodeCpu, nodeMem float64) {
 	reserved := node.ReservedResources.Comparable()
 	res := node.NodeResources.Comparable()
 
 	// Determine the node availability
-	nodeCpu := float64(res.Flattened.Cpu.CpuShares)
-	nodeMem := float64(res.Flattened.Memory.MemoryMB)
+	nodeCpu = float64(res.Flattened.Cpu.CpuShares)
+	nodeMem = float64(res.Flattened.Memory.MemoryMB)
 	if reserved != nil {
 		nodeCpu -= float64(reserved.Flattened.Cpu.CpuShares)
 		nodeMem -= float64(reserved.Flattened.Memory.MemoryMB)
 	}
-
-	// Compute the free percentage
-	freePctCpu = 1 - (float64(util.Flattened.Cpu.CpuShares) / nodeCpu)
-	freePctRam = 1 - (float64(util.Flattened.Memory.MemoryMB) / nodeMem)
-	return freePctCpu, freePctRam
+	return nodeCpu, nodeMem
 }
 
 // ScoreFitBinPack computes a fit score to achieve pinbacking behavior.
@@ -293,6 +300,53 @@ func ScoreFitSpread(node *Node, util *ComparableResources) float64 {
 	return score
 }
 
+// ScoreFitWeighted computes a fit score which favors the nodes with the least
+// requested resources. The free percentage of each resource counts towards the
+// score in proportion to its weight, and resources the node doesn't have are
+// ignored.
+// Score is in [0, 18]
+func ScoreFitWeighted(node *Node, util *ComparableResources, weights *SchedulerScoringWeights) float64 {
+	var sum, total float64
+	add := func(weight, freePct float64) {
+		if weight <= 0 {
+			return
+		}
+		sum += weight * math.Max(0, math.Min(1, freePct))
+		total += weight
+	}
+
+	freePctCpu, freePctRam := computeFreePercentage(node, util)
+	add(weights.CPU, freePctCpu)
+	add(weights.Memory, freePctRam)
+
+	if weights.MemoryMax > 0 {
+		_, nodeMem := computeNodeAvailable(node)
+		add(weights.MemoryMax, 1-(float64(util.Flattened.Memory.MemoryMaxMB)/nodeMem))
+	}
+
+	if weights.Devices > 0 && node.NodeResources != nil {
+		var instances, used int
+		for _, d := range node.NodeResources.Devices {
+			for _, instance := range d.Instances {
+				if instance.Healthy {
+					instances++
+				}
+			}
+		}
+		for _, d := range util.Flattened.Devices {
+			used += len(d.DeviceIDs)
+		}
+		if instances > 0 {
+			add(weights.Devices, 1-(float64(used)/float64(instances)))
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return 18.0 * sum / total
+}
+
 func CopySliceConstraints(s []*Constraint) []*Constraint {
 	l := len(s)
 	if l == 0 {
@@ -716,6 +716,95 @@ func TestScoreFitBinPack(t *testing.T) {
 	}
 }
 
+func TestScoreFitWeighted(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{
+		NodeResources: &NodeResources{
+			Processors: NodeProcessorResources{
+				Topology: &numalib.Topology{
+					Distances: numalib.SLIT{[]numalib.Cost{10}},
+					Cores: []numalib.Core{{
+						ID:        0,
+						Grade:     numalib.Performance,
+						BaseSpeed: 4096,
+					}},
+				},
+			},
+			Memory: NodeMemoryResources{
+				MemoryMB: 8192,
+			},
+			Devices: []*NodeDeviceResource{
+				{
+					Vendor: "nvidia",
+					Type:   "gpu",
+					Name:   "1080ti",
+					Instances: []*NodeDevice{
+						{ID: "a", Healthy: true},
+						{ID: "b", Healthy: true},
+						{ID: "c", Healthy: false},
+					},
+				},
+			},
+		},
+	}
+	node.NodeResources.Processors.Topology.SetNodes(idset.From[hw.NodeID]([]hw.NodeID{0}))
+	node.NodeResources.Compatibility()
+
+	util := &ComparableResources{
+		Flattened: AllocatedTaskResources{
+			Cpu:    AllocatedCpuResources{CpuShares: 1024},
+			Memory: AllocatedMemoryResources{MemoryMB: 6144, MemoryMaxMB: 8192},
+			Devices: []*AllocatedDeviceResource{
+				{Vendor: "nvidia", Type: "gpu", Name: "1080ti", DeviceIDs: []string{"a"}},
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		weights *SchedulerScoringWeights
+		score   float64
+	}{
+		{
+			name:    "cpu only",
+			weights: &SchedulerScoringWeights{CPU: 1},
+			score:   18 * 0.75,
+		},
+		{
+			name:    "memory only",
+			weights: &SchedulerScoringWeights{Memory: 2},
+			score:   18 * 0.25,
+		},
+		{
+			name:    "weighted cpu and memory",
+			weights: &SchedulerScoringWeights{CPU: 1, Memory: 3},
+			score:   18 * (0.75 + 3*0.25) / 4,
+		},
+		{
+			name:    "memory max",
+			weights: &SchedulerScoringWeights{MemoryMax: 1},
+			score:   0,
+		},
+		{
+			name:    "healthy devices",
+			weights: &SchedulerScoringWeights{Devices: 1},
+			score:   18 * 0.5,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.score, ScoreFitWeighted(node, util, tc.weights))
+		})
+	}
+
+	// Devices are ignored on nodes without them
+	node.NodeResources.Devices = nil
+	weights := &SchedulerScoringWeights{CPU: 1, Devices: 100}
+	must.Eq(t, 18*0.75, ScoreFitWeighted(node, util, weights))
+}
+
 func TestAllocsFit_MaxNodeAllocs(t *testing.T) {
 	ci.Parallel(t)
 	baseAlloc := &Allocation{
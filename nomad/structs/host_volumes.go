@@ -163,6 +163,9 @@ func (hv *HostVolume) Validate() error {
 		case ConstraintDistinctHosts, ConstraintDistinctProperty:
 			mErr = multierror.Append(mErr, fmt.Errorf(
 				"invalid constraint %s: host volumes of the same name are always on distinct hosts", constraint.Operand))
+		case ConstraintColocatedWith, ConstraintNotColocatedWith:
+			mErr = multierror.Append(mErr, fmt.Errorf(
+				"invalid constraint %s: host volumes are not placed with allocations", constraint.Operand))
 		default:
 		}
 	}
@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// JobDependencyConditionComplete is satisfied once every allocation of
+	// the current version of a batch job has completed successfully.
+	JobDependencyConditionComplete = "complete"
+
+	// JobDependencyConditionDeploymentSuccessful is satisfied once the
+	// deployment of the current version of a job has succeeded.
+	JobDependencyConditionDeploymentSuccessful = "deployment_successful"
+
+	// JobDependencyConditionHealthy is satisfied once every task group of the
+	// current version of a job is running its desired count of allocations,
+	// and allocations which are part of a deployment have been marked healthy.
+	JobDependencyConditionHealthy = "healthy"
+)
+
+// JobDependency is a job which must reach a given condition before a new
+// version of the job depending on it is rolled out. The job must be in the
+// same namespace as the dependent job.
+type JobDependency struct {
+	// JobID is the ID of the job depended on.
+	JobID string
+
+	// Condition is the state the job must reach.
+	Condition string
+}
+
+// Copy returns a copy of the dependency.
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := *d
+	return &nd
+}
+
+// Equal returns whether d and o depend on the same job reaching the same
+// condition.
+func (d *JobDependency) Equal(o *JobDependency) bool {
+	if d == nil || o == nil {
+		return d == o
+	}
+	return d.JobID == o.JobID && d.Condition == o.Condition
+}
+
+func (d *JobDependency) String() string {
+	return fmt.Sprintf("%s (%s)", d.JobID, d.Condition)
+}
+
+// Validate returns an error if the dependency is invalid.
+func (d *JobDependency) Validate() error {
+	var mErr []error
+	if d.JobID == "" {
+		mErr = append(mErr, errors.New("Missing job ID"))
+	}
+	switch d.Condition {
+	case JobDependencyConditionComplete,
+		JobDependencyConditionDeploymentSuccessful,
+		JobDependencyConditionHealthy:
+	case "":
+		mErr = append(mErr, errors.New("Missing condition"))
+	default:
+		mErr = append(mErr, fmt.Errorf("Invalid condition %q, must be one of %q, %q or %q",
+			d.Condition, JobDependencyConditionComplete,
+			JobDependencyConditionDeploymentSuccessful, JobDependencyConditionHealthy))
+	}
+	return errors.Join(mErr...)
+}
+
+// SatisfiedBy returns whether the condition of the dependency has been reached
+// by job, given all of its allocations and its latest deployment. The job,
+// allocations and deployment may be nil if they don't exist.
+func (d *JobDependency) SatisfiedBy(job *Job, allocs []*Allocation, deployment *Deployment) bool {
+	if job == nil || job.Stopped() {
+		return false
+	}
+
+	// Only the current version of the job is considered, as older versions
+	// may have reached the condition before the job was updated.
+	current := make([]*Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.Job != nil && alloc.Job.Version == job.Version &&
+			alloc.Job.CreateIndex == job.CreateIndex {
+			current = append(current, alloc)
+		}
+	}
+
+	switch d.Condition {
+	case JobDependencyConditionComplete:
+		if job.Status != JobStatusDead {
+			return false
+		}
+		found := false
+		for _, alloc := range current {
+			// Allocations which were rescheduled are replaced by one which
+			// may have succeeded.
+			if alloc.NextAllocation != "" {
+				continue
+			}
+			if alloc.ClientStatus != AllocClientStatusComplete {
+				return false
+			}
+			found = true
+		}
+		return found
+
+	case JobDependencyConditionDeploymentSuccessful:
+		return deployment != nil &&
+			deployment.JobCreateIndex == job.CreateIndex &&
+			deployment.JobVersion == job.Version &&
+			deployment.Status == DeploymentStatusSuccessful
+
+	case JobDependencyConditionHealthy:
+		if job.Status != JobStatusRunning {
+			return false
+		}
+
+		// The deployment of the current version marks allocations healthy
+		// once they pass their service checks, so it must have marked enough
+		// of them healthy and not have failed.
+		if deployment != nil && deployment.JobCreateIndex == job.CreateIndex &&
+			deployment.JobVersion == job.Version {
+			switch deployment.Status {
+			case DeploymentStatusFailed, DeploymentStatusCancelled:
+				return false
+			}
+			for _, dstate := range deployment.TaskGroups {
+				if dstate.HealthyAllocs < dstate.DesiredTotal {
+					return false
+				}
+			}
+		}
+
+		healthy := make(map[string]int, len(job.TaskGroups))
+		for _, alloc := range current {
+			if alloc.TerminalStatus() || alloc.ClientStatus != AllocClientStatusRunning {
+				continue
+			}
+			if alloc.DeploymentID != "" && !alloc.DeploymentStatus.IsHealthy() {
+				continue
+			}
+			healthy[alloc.TaskGroup]++
+		}
+		for _, tg := range job.TaskGroups {
+			if healthy[tg.Name] < tg.Count {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// CopySliceJobDependencies returns a copy of the dependencies.
+func CopySliceJobDependencies(s []*JobDependency) []*JobDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// JobDependenciesString returns a human readable list of the dependencies.
+func JobDependenciesString(deps []*JobDependency) string {
+	out := make([]string, len(deps))
+	for i, dep := range deps {
+		out[i] = dep.String()
+	}
+	return strings.Join(out, ", ")
+}
+
+// validateJobDependencies validates the dependencies of job.
+func validateJobDependencies(job *Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+
+	var mErr []error
+	if job.Type != JobTypeService && job.Type != JobTypeBatch {
+		mErr = append(mErr, fmt.Errorf("depends_on can only be used with %q or %q scheduler",
+			JobTypeService, JobTypeBatch))
+	}
+	for idx, dep := range job.DependsOn {
+		if err := dep.Validate(); err != nil {
+			mErr = append(mErr, fmt.Errorf("Dependency %d validation failed: %w", idx+1, err))
+			continue
+		}
+		if dep.JobID == job.ID {
+			mErr = append(mErr, fmt.Errorf("Dependency %d: job cannot depend on itself", idx+1))
+		}
+		if slices.ContainsFunc(job.DependsOn[:idx], dep.Equal) {
+			mErr = append(mErr, fmt.Errorf("Dependency %d: duplicate dependency on %s", idx+1, dep))
+		}
+	}
+	return errors.Join(mErr...)
+}
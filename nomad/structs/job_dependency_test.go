@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDependency_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		job    *Job
+		expErr []string
+	}{
+		{
+			name: "valid",
+			job: &Job{
+				ID:   "api",
+				Type: JobTypeService,
+				DependsOn: []*JobDependency{
+					{JobID: "migrate", Condition: JobDependencyConditionComplete},
+					{JobID: "db", Condition: JobDependencyConditionHealthy},
+				},
+			},
+		},
+		{
+			name: "invalid",
+			job: &Job{
+				ID:   "api",
+				Type: JobTypeService,
+				DependsOn: []*JobDependency{
+					{Condition: JobDependencyConditionComplete},
+					{JobID: "migrate", Condition: "finished"},
+					{JobID: "api", Condition: JobDependencyConditionHealthy},
+					{JobID: "db", Condition: JobDependencyConditionHealthy},
+					{JobID: "db", Condition: JobDependencyConditionHealthy},
+				},
+			},
+			expErr: []string{
+				"Dependency 1 validation failed: Missing job ID",
+				`Dependency 2 validation failed: Invalid condition "finished"`,
+				"Dependency 3: job cannot depend on itself",
+				"Dependency 5: duplicate dependency on db (healthy)",
+			},
+		},
+		{
+			name: "system job",
+			job: &Job{
+				ID:   "api",
+				Type: JobTypeSystem,
+				DependsOn: []*JobDependency{
+					{JobID: "migrate", Condition: JobDependencyConditionComplete},
+				},
+			},
+			expErr: []string{`depends_on can only be used with "service" or "batch" scheduler`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJobDependencies(tc.job)
+			if len(tc.expErr) == 0 {
+				must.NoError(t, err)
+				return
+			}
+			must.Error(t, err)
+			for _, exp := range tc.expErr {
+				must.StrContains(t, err.Error(), exp)
+			}
+		})
+	}
+}
+
+func TestJobDependency_SatisfiedBy(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{
+		ID:          "dep",
+		Version:     2,
+		CreateIndex: 10,
+		TaskGroups:  []*TaskGroup{{Name: "web", Count: 2}},
+	}
+	oldJob := job.Copy()
+	oldJob.Version = 1
+
+	alloc := func(job *Job, clientStatus string, health *bool) *Allocation {
+		a := &Allocation{
+			ID:            uuid.Generate(),
+			Job:           job,
+			TaskGroup:     "web",
+			DesiredStatus: AllocDesiredStatusRun,
+			ClientStatus:  clientStatus,
+		}
+		if health != nil {
+			a.DeploymentID = "d1"
+			a.DeploymentStatus = &AllocDeploymentStatus{Healthy: health}
+		}
+		return a
+	}
+	healthy, unhealthy := true, false
+
+	testCases := []struct {
+		name       string
+		condition  string
+		status     string
+		allocs     []*Allocation
+		deployment *Deployment
+		exp        bool
+	}{
+		{
+			name:      "complete",
+			condition: JobDependencyConditionComplete,
+			status:    JobStatusDead,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusComplete, nil),
+				alloc(oldJob, AllocClientStatusFailed, nil),
+			},
+			exp: true,
+		},
+		{
+			name:      "complete after reschedule",
+			condition: JobDependencyConditionComplete,
+			status:    JobStatusDead,
+			allocs: func() []*Allocation {
+				failed := alloc(job, AllocClientStatusFailed, nil)
+				failed.NextAllocation = "next"
+				return []*Allocation{failed, alloc(job, AllocClientStatusComplete, nil)}
+			}(),
+			exp: true,
+		},
+		{
+			name:      "failed",
+			condition: JobDependencyConditionComplete,
+			status:    JobStatusDead,
+			allocs:    []*Allocation{alloc(job, AllocClientStatusFailed, nil)},
+			exp:       false,
+		},
+		{
+			name:      "only old version complete",
+			condition: JobDependencyConditionComplete,
+			status:    JobStatusDead,
+			allocs:    []*Allocation{alloc(oldJob, AllocClientStatusComplete, nil)},
+			exp:       false,
+		},
+		{
+			name:      "still running",
+			condition: JobDependencyConditionComplete,
+			status:    JobStatusRunning,
+			allocs:    []*Allocation{alloc(job, AllocClientStatusRunning, nil)},
+			exp:       false,
+		},
+		{
+			name:      "deployment successful",
+			condition: JobDependencyConditionDeploymentSuccessful,
+			status:    JobStatusRunning,
+			deployment: &Deployment{
+				JobVersion:     2,
+				JobCreateIndex: 10,
+				Status:         DeploymentStatusSuccessful,
+			},
+			exp: true,
+		},
+		{
+			name:      "deployment of old version successful",
+			condition: JobDependencyConditionDeploymentSuccessful,
+			status:    JobStatusRunning,
+			deployment: &Deployment{
+				JobVersion:     1,
+				JobCreateIndex: 10,
+				Status:         DeploymentStatusSuccessful,
+			},
+			exp: false,
+		},
+		{
+			name:      "deployment running",
+			condition: JobDependencyConditionDeploymentSuccessful,
+			status:    JobStatusRunning,
+			deployment: &Deployment{
+				JobVersion:     2,
+				JobCreateIndex: 10,
+				Status:         DeploymentStatusRunning,
+			},
+			exp: false,
+		},
+		{
+			name:      "healthy",
+			condition: JobDependencyConditionHealthy,
+			status:    JobStatusRunning,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusRunning, &healthy),
+				alloc(job, AllocClientStatusRunning, nil),
+			},
+			exp: true,
+		},
+		{
+			name:      "unhealthy",
+			condition: JobDependencyConditionHealthy,
+			status:    JobStatusRunning,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusRunning, &healthy),
+				alloc(job, AllocClientStatusRunning, &unhealthy),
+			},
+			exp: false,
+		},
+		{
+			name:      "deployment not healthy yet",
+			condition: JobDependencyConditionHealthy,
+			status:    JobStatusRunning,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusRunning, &healthy),
+				alloc(job, AllocClientStatusRunning, &healthy),
+			},
+			deployment: &Deployment{
+				JobVersion:     2,
+				JobCreateIndex: 10,
+				Status:         DeploymentStatusRunning,
+				TaskGroups: map[string]*DeploymentState{
+					"web": {DesiredTotal: 3, HealthyAllocs: 2},
+				},
+			},
+			exp: false,
+		},
+		{
+			name:      "deployment failed",
+			condition: JobDependencyConditionHealthy,
+			status:    JobStatusRunning,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusRunning, &healthy),
+				alloc(job, AllocClientStatusRunning, &healthy),
+			},
+			deployment: &Deployment{
+				JobVersion:     2,
+				JobCreateIndex: 10,
+				Status:         DeploymentStatusFailed,
+				TaskGroups: map[string]*DeploymentState{
+					"web": {DesiredTotal: 2, HealthyAllocs: 2},
+				},
+			},
+			exp: false,
+		},
+		{
+			name:      "not enough allocations",
+			condition: JobDependencyConditionHealthy,
+			status:    JobStatusRunning,
+			allocs: []*Allocation{
+				alloc(job, AllocClientStatusRunning, nil),
+				alloc(job, AllocClientStatusPending, nil),
+			},
+			exp: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := job.Copy()
+			j.Status = tc.status
+			dep := &JobDependency{JobID: j.ID, Condition: tc.condition}
+			must.Eq(t, tc.exp, dep.SatisfiedBy(j, tc.allocs, tc.deployment))
+		})
+	}
+
+	t.Run("missing or stopped job", func(t *testing.T) {
+		dep := &JobDependency{JobID: job.ID, Condition: JobDependencyConditionHealthy}
+		must.False(t, dep.SatisfiedBy(nil, nil, nil))
+
+		stopped := job.Copy()
+		stopped.Status = JobStatusRunning
+		stopped.Stop = true
+		allocs := []*Allocation{
+			alloc(job, AllocClientStatusRunning, nil),
+			alloc(job, AllocClientStatusRunning, nil),
+		}
+		must.False(t, dep.SatisfiedBy(stopped, allocs, nil))
+	})
+}
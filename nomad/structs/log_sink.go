@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// LogSinkTypeSyslog forwards log lines to a syslog server using RFC5424
+	// messages over TCP, UDP or a unix socket.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeUnix writes log lines as newline delimited JSON to a unix
+	// socket on the client.
+	LogSinkTypeUnix = "unix"
+
+	// LogSinkTypeHTTP posts batches of log lines as newline delimited JSON to
+	// an HTTP endpoint.
+	LogSinkTypeHTTP = "http"
+
+	// DefaultLogSinkBufferSize is the default number of log lines buffered
+	// for a sink before lines are dropped.
+	DefaultLogSinkBufferSize = 1024
+)
+
+// LogSinkConfig configures logmon to forward the lines of a task's logs to an
+// external sink. Lines are buffered and dropped when the sink can't keep up,
+// so a slow or unavailable sink never blocks the task.
+type LogSinkConfig struct {
+	// Type is the type of sink, one of "syslog", "unix" or "http".
+	Type string
+
+	// Address is the address of the sink. For syslog it is a URL with a tcp,
+	// udp or unix scheme, for unix it is the path of the socket, and for
+	// http it is the URL of the endpoint. It must be one of the addresses
+	// allowed by the configuration of the client.
+	Address string
+
+	// BufferSize is the number of lines buffered before lines are dropped.
+	BufferSize int
+}
+
+func (l *LogSinkConfig) Equal(o *LogSinkConfig) bool {
+	if l == nil || o == nil {
+		return l == o
+	}
+	return *l == *o
+}
+
+func (l *LogSinkConfig) Copy() *LogSinkConfig {
+	if l == nil {
+		return nil
+	}
+	nl := *l
+	return &nl
+}
+
+// Validate returns an error if the sink type is unknown or its address isn't
+// valid for the type.
+func (l *LogSinkConfig) Validate() error {
+	var mErr []error
+	if l.BufferSize < 0 {
+		mErr = append(mErr, fmt.Errorf("buffer size must be positive; got %d", l.BufferSize))
+	}
+	if l.Address == "" {
+		mErr = append(mErr, errors.New("missing address"))
+		return errors.Join(append(mErr, validateLogSinkType(l.Type))...)
+	}
+
+	switch l.Type {
+	case LogSinkTypeSyslog:
+		u, err := url.Parse(l.Address)
+		if err != nil {
+			mErr = append(mErr, fmt.Errorf("invalid syslog address: %v", err))
+			break
+		}
+		switch u.Scheme {
+		case "tcp", "udp":
+			if _, _, err := net.SplitHostPort(u.Host); err != nil {
+				mErr = append(mErr, fmt.Errorf("invalid syslog address: %v", err))
+			}
+		case "unix":
+			if !filepath.IsAbs(u.Path) {
+				mErr = append(mErr, fmt.Errorf("syslog socket path must be absolute; got %q", u.Path))
+			}
+		default:
+			mErr = append(mErr, fmt.Errorf("syslog address scheme must be one of \"tcp\", \"udp\" or \"unix\"; got %q", u.Scheme))
+		}
+	case LogSinkTypeUnix:
+		if !filepath.IsAbs(l.Address) {
+			mErr = append(mErr, fmt.Errorf("socket path must be absolute; got %q", l.Address))
+		}
+	case LogSinkTypeHTTP:
+		u, err := url.Parse(l.Address)
+		if err != nil {
+			mErr = append(mErr, fmt.Errorf("invalid http address: %v", err))
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			mErr = append(mErr, fmt.Errorf("http address must be an http or https URL; got %q", l.Address))
+		}
+	default:
+		mErr = append(mErr, validateLogSinkType(l.Type))
+	}
+	return errors.Join(mErr...)
+}
+
+// AddressAllowed returns whether the address of the sink is one of the allowed
+// addresses. Addresses are compared without trailing slashes.
+func (l *LogSinkConfig) AddressAllowed(allowed []string) bool {
+	address := strings.TrimSuffix(l.Address, "/")
+	return slices.ContainsFunc(allowed, func(a string) bool {
+		return strings.TrimSuffix(a, "/") == address
+	})
+}
+
+func validateLogSinkType(t string) error {
+	switch t {
+	case LogSinkTypeSyslog, LogSinkTypeUnix, LogSinkTypeHTTP:
+		return nil
+	case "":
+		return errors.New("missing type")
+	default:
+		return fmt.Errorf("type must be one of %q, %q or %q; got %q",
+			LogSinkTypeSyslog, LogSinkTypeUnix, LogSinkTypeHTTP, t)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestLogSinkConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		sink   *LogSinkConfig
+		expErr string
+	}{
+		{
+			name: "syslog tcp",
+			sink: &LogSinkConfig{Type: LogSinkTypeSyslog, Address: "tcp://10.0.0.1:514"},
+		},
+		{
+			name: "syslog udp",
+			sink: &LogSinkConfig{Type: LogSinkTypeSyslog, Address: "udp://logs.example.com:514"},
+		},
+		{
+			name: "syslog unix",
+			sink: &LogSinkConfig{Type: LogSinkTypeSyslog, Address: "unix:///dev/log"},
+		},
+		{
+			name:   "syslog missing port",
+			sink:   &LogSinkConfig{Type: LogSinkTypeSyslog, Address: "tcp://10.0.0.1"},
+			expErr: "invalid syslog address",
+		},
+		{
+			name:   "syslog invalid scheme",
+			sink:   &LogSinkConfig{Type: LogSinkTypeSyslog, Address: "http://10.0.0.1:514"},
+			expErr: "syslog address scheme must be one of",
+		},
+		{
+			name: "unix",
+			sink: &LogSinkConfig{Type: LogSinkTypeUnix, Address: "/run/logs.sock", BufferSize: 10},
+		},
+		{
+			name:   "unix relative path",
+			sink:   &LogSinkConfig{Type: LogSinkTypeUnix, Address: "logs.sock"},
+			expErr: "socket path must be absolute",
+		},
+		{
+			name: "http",
+			sink: &LogSinkConfig{Type: LogSinkTypeHTTP, Address: "https://logs.example.com/ingest"},
+		},
+		{
+			name:   "http invalid scheme",
+			sink:   &LogSinkConfig{Type: LogSinkTypeHTTP, Address: "tcp://logs.example.com"},
+			expErr: "http address must be an http or https URL",
+		},
+		{
+			name:   "missing type",
+			sink:   &LogSinkConfig{Address: "/run/logs.sock"},
+			expErr: "missing type",
+		},
+		{
+			name:   "unknown type",
+			sink:   &LogSinkConfig{Type: "kafka", Address: "/run/logs.sock"},
+			expErr: `type must be one of "syslog", "unix" or "http"; got "kafka"`,
+		},
+		{
+			name:   "missing address",
+			sink:   &LogSinkConfig{Type: LogSinkTypeHTTP},
+			expErr: "missing address",
+		},
+		{
+			name:   "negative buffer size",
+			sink:   &LogSinkConfig{Type: LogSinkTypeUnix, Address: "/run/logs.sock", BufferSize: -1},
+			expErr: "buffer size must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sink.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+
+	t.Run("logs disabled", func(t *testing.T) {
+		l := DefaultLogConfig()
+		l.Disabled = true
+		l.Sink = &LogSinkConfig{Type: LogSinkTypeUnix, Address: "/run/logs.sock"}
+		must.ErrorContains(t, l.Validate(nil), "log sink cannot be used when logs are disabled")
+	})
+}
+
+func TestLogSinkConfig_AddressAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed := []string{"/run/nomad/logs.sock", "http://logs.example:8080/ingest/"}
+
+	must.True(t, (&LogSinkConfig{Type: LogSinkTypeUnix, Address: "/run/nomad/logs.sock"}).AddressAllowed(allowed))
+	must.True(t, (&LogSinkConfig{Type: LogSinkTypeHTTP, Address: "http://logs.example:8080/ingest"}).AddressAllowed(allowed))
+	must.False(t, (&LogSinkConfig{Type: LogSinkTypeUnix, Address: "/var/run/docker.sock"}).AddressAllowed(allowed))
+	must.False(t, (&LogSinkConfig{Type: LogSinkTypeHTTP, Address: "http://169.254.169.254/latest"}).AddressAllowed(allowed))
+	must.False(t, (&LogSinkConfig{Type: LogSinkTypeUnix, Address: "/run/nomad/logs.sock"}).AddressAllowed(nil))
+}
@@ -3,6 +3,15 @@
 
 package structs
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
 // NamespaceVaultConfiguration stores configuration about permissions to Vault
 // clusters for a namespace, for use with Nomad Enterprise.
 type NamespaceVaultConfiguration struct {
@@ -46,3 +55,70 @@ type NamespaceConsulConfiguration struct {
 	// This field cannot be used with Allowed.
 	Denied []string
 }
+
+// NamespaceVariablesConfiguration stores configuration about how variables
+// in the namespace are stored.
+type NamespaceVariablesConfiguration struct {
+	// RetainVersions is the number of previous versions of each variable in
+	// the namespace that are kept in the state store after the variable is
+	// updated or deleted. A value of 0 disables variable versioning.
+	RetainVersions int
+
+	// PathRetainVersions overrides RetainVersions for variables under a given
+	// path prefix. When more than one prefix matches a variable path, the
+	// longest prefix wins.
+	PathRetainVersions map[string]int
+}
+
+// Copy returns a deep copy of the variables configuration.
+func (n *NamespaceVariablesConfiguration) Copy() *NamespaceVariablesConfiguration {
+	if n == nil {
+		return nil
+	}
+	nv := new(NamespaceVariablesConfiguration)
+	*nv = *n
+	nv.PathRetainVersions = maps.Clone(n.PathRetainVersions)
+	return nv
+}
+
+// Validate returns an error if the variables configuration is invalid.
+func (n *NamespaceVariablesConfiguration) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if n.RetainVersions < 0 || n.RetainVersions > maxVariableRetainVersions {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf(
+			"retain_versions must be between 0 and %d", maxVariableRetainVersions))
+	}
+	for prefix, retain := range n.PathRetainVersions {
+		if prefix == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("path prefix must not be empty"))
+		}
+		if retain < 0 || retain > maxVariableRetainVersions {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"retain_versions for path %q must be between 0 and %d", prefix, maxVariableRetainVersions))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// RetainVersionsForPath returns the number of previous versions to keep for
+// the variable at path. It is safe to call on a nil configuration, which
+// disables versioning.
+func (n *NamespaceVariablesConfiguration) RetainVersionsForPath(path string) int {
+	if n == nil {
+		return 0
+	}
+
+	retain := n.RetainVersions
+	longest := -1
+	for prefix, r := range n.PathRetainVersions {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			retain = r
+		}
+	}
+	return retain
+}
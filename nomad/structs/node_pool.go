@@ -162,6 +162,10 @@ func (n *NodePool) SetHash() []byte {
 				_, _ = hash.Write([]byte("memory_oversubscription_disabled"))
 			}
 		}
+
+		if weights := n.SchedulerConfiguration.ScoringWeights; weights != nil {
+			_, _ = hash.Write([]byte(weights.String()))
+		}
 	}
 
 	// sort keys to ensure hash stability when meta is stored later
@@ -198,6 +202,11 @@ type NodePoolSchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription
 	// is enabled. If not defined, the global cluster configuration is used.
 	MemoryOversubscriptionEnabled *bool `hcl:"memory_oversubscription_enabled"`
+
+	// ScoringWeights are the weights of each resource used by the weighted
+	// scheduler algorithm. If not defined, the global cluster configuration
+	// is used.
+	ScoringWeights *SchedulerScoringWeights `hcl:"scoring_weights"`
 }
 
 // Copy returns a deep copy of the node pool scheduler configuration.
@@ -212,6 +221,7 @@ func (n *NodePoolSchedulerConfiguration) Copy() *NodePoolSchedulerConfiguration
 	if n.MemoryOversubscriptionEnabled != nil {
 		nc.MemoryOversubscriptionEnabled = pointer.Of(*n.MemoryOversubscriptionEnabled)
 	}
+	nc.ScoringWeights = n.ScoringWeights.Copy()
 
 	return nc
 }
@@ -6,6 +6,7 @@ package structs
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/netip"
 	"time"
 
@@ -209,6 +210,88 @@ const (
 	// SchedulerAlgorithmSpread indicates that the scheduler should spread
 	// allocations as evenly as possible over the available hardware.
 	SchedulerAlgorithmSpread SchedulerAlgorithm = "spread"
+
+	// SchedulerAlgorithmWeighted indicates that the scheduler should place
+	// allocations on the nodes with the least requested resources, weighting
+	// each resource by the SchedulerConfiguration ScoringWeights.
+	SchedulerAlgorithmWeighted SchedulerAlgorithm = "weighted"
+)
+
+// SchedulerScoringWeights are the relative weights given to each resource
+// when the weighted scheduler algorithm scores nodes. A resource with a zero
+// weight doesn't affect scores.
+type SchedulerScoringWeights struct {
+	// CPU is the weight of the CPU shares.
+	CPU float64 `hcl:"cpu"`
+
+	// Memory is the weight of the reserved memory.
+	Memory float64 `hcl:"memory"`
+
+	// MemoryMax is the weight of the maximum memory tasks may use when memory
+	// oversubscription is enabled.
+	MemoryMax float64 `hcl:"memory_max"`
+
+	// Devices is the weight of the number of device instances. It only
+	// affects the scores of nodes with devices.
+	Devices float64 `hcl:"devices"`
+}
+
+// DefaultSchedulerScoringWeights returns the weights used by the weighted
+// scheduler algorithm when none are configured.
+func DefaultSchedulerScoringWeights() *SchedulerScoringWeights {
+	return &SchedulerScoringWeights{
+		CPU:    1,
+		Memory: 1,
+	}
+}
+
+func (w *SchedulerScoringWeights) Copy() *SchedulerScoringWeights {
+	if w == nil {
+		return nil
+	}
+
+	nw := *w
+	return &nw
+}
+
+func (w *SchedulerScoringWeights) Validate() error {
+	if w == nil {
+		return nil
+	}
+
+	if w.CPU < 0 || w.Memory < 0 || w.MemoryMax < 0 || w.Devices < 0 {
+		return errors.New("scoring weights must not be negative")
+	}
+	if w.CPU+w.Memory+w.MemoryMax+w.Devices == 0 {
+		return errors.New("at least one scoring weight must be greater than zero")
+	}
+	return nil
+}
+
+// String returns the weights in the form "cpu=1, memory=2".
+func (w *SchedulerScoringWeights) String() string {
+	if w == nil {
+		return ""
+	}
+	return fmt.Sprintf("cpu=%g, memory=%g, memory_max=%g, devices=%g",
+		w.CPU, w.Memory, w.MemoryMax, w.Devices)
+}
+
+// EvalDequeuePolicy is an enum string that encapsulates the valid options for
+// a SchedulerConfiguration EvalDequeuePolicy.
+type EvalDequeuePolicy string
+
+const (
+	// EvalDequeuePolicyPriority indicates that the eval broker should dequeue
+	// the evaluations with the highest priority first, and then in the order
+	// they were created.
+	EvalDequeuePolicyPriority EvalDequeuePolicy = "priority"
+
+	// EvalDequeuePolicyFairShare indicates that the eval broker should take
+	// turns dequeuing the evaluations of each namespace, dequeuing as many
+	// evaluations of a namespace in a row as its weight. The evaluations of
+	// each namespace are dequeued by priority.
+	EvalDequeuePolicyFairShare EvalDequeuePolicy = "fair_share"
 )
 
 // SchedulerConfiguration is the config for controlling scheduler behavior
@@ -216,6 +299,11 @@ type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
 	SchedulerAlgorithm SchedulerAlgorithm `hcl:"scheduler_algorithm"`
 
+	// ScoringWeights are the weights of each resource used by the weighted
+	// scheduler algorithm. If not defined, CPU and memory are weighted
+	// equally.
+	ScoringWeights *SchedulerScoringWeights `hcl:"scoring_weights"`
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig `hcl:"preemption_config"`
@@ -233,6 +321,15 @@ type SchedulerConfiguration struct {
 	// during leadership transitions.
 	PauseEvalBroker bool `hcl:"pause_eval_broker"`
 
+	// EvalDequeuePolicy is the order in which the eval broker on the cluster
+	// leader dequeues the ready evaluations of each scheduler type.
+	EvalDequeuePolicy EvalDequeuePolicy `hcl:"eval_dequeue_policy"`
+
+	// NamespaceWeights are the number of evaluations of each namespace
+	// dequeued in a row by the fair share eval dequeue policy. Namespaces
+	// which aren't listed have a weight of 1.
+	NamespaceWeights map[string]int `hcl:"namespace_weights"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -244,6 +341,8 @@ func (s *SchedulerConfiguration) Copy() *SchedulerConfiguration {
 	}
 
 	ns := *s
+	ns.ScoringWeights = s.ScoringWeights.Copy()
+	ns.NamespaceWeights = maps.Clone(s.NamespaceWeights)
 	return &ns
 }
 
@@ -255,6 +354,26 @@ func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm() SchedulerAlgorith
 	return s.SchedulerAlgorithm
 }
 
+// EffectiveScoringWeights returns the weights used by the weighted scheduler
+// algorithm.
+func (s *SchedulerConfiguration) EffectiveScoringWeights() *SchedulerScoringWeights {
+	if s == nil || s.ScoringWeights == nil {
+		return DefaultSchedulerScoringWeights()
+	}
+
+	return s.ScoringWeights
+}
+
+// EffectiveEvalDequeuePolicy returns the order in which the eval broker
+// dequeues evaluations.
+func (s *SchedulerConfiguration) EffectiveEvalDequeuePolicy() EvalDequeuePolicy {
+	if s == nil || s.EvalDequeuePolicy == "" {
+		return EvalDequeuePolicyPriority
+	}
+
+	return s.EvalDequeuePolicy
+}
+
 // WithNodePool returns a new SchedulerConfiguration with the node pool
 // scheduler configuration applied.
 func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfiguration {
@@ -271,6 +390,9 @@ func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfigur
 	if poolConfig.MemoryOversubscriptionEnabled != nil {
 		schedConfig.MemoryOversubscriptionEnabled = *poolConfig.MemoryOversubscriptionEnabled
 	}
+	if poolConfig.ScoringWeights != nil {
+		schedConfig.ScoringWeights = poolConfig.ScoringWeights.Copy()
+	}
 
 	return schedConfig
 }
@@ -287,11 +409,27 @@ func (s *SchedulerConfiguration) Validate() error {
 	}
 
 	switch s.SchedulerAlgorithm {
-	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread, SchedulerAlgorithmWeighted:
 	default:
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
+	if err := s.ScoringWeights.Validate(); err != nil {
+		return fmt.Errorf("invalid scoring weights: %w", err)
+	}
+
+	switch s.EvalDequeuePolicy {
+	case "", EvalDequeuePolicyPriority, EvalDequeuePolicyFairShare:
+	default:
+		return fmt.Errorf("invalid eval dequeue policy: %v", s.EvalDequeuePolicy)
+	}
+
+	for namespace, weight := range s.NamespaceWeights {
+		if weight < 1 {
+			return fmt.Errorf("invalid weight for namespace %q: must be at least 1 but found %d", namespace, weight)
+		}
+	}
+
 	return nil
 }
 
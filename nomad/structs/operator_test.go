@@ -72,6 +72,22 @@ func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
 				SchedulerAlgorithm: SchedulerAlgorithmSpread,
 			},
 		},
+		{
+			name: "pool with scoring weights overwrites config",
+			schedConfig: &SchedulerConfiguration{
+				SchedulerAlgorithm: SchedulerAlgorithmWeighted,
+				ScoringWeights:     &SchedulerScoringWeights{CPU: 1},
+			},
+			pool: &NodePool{
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					ScoringWeights: &SchedulerScoringWeights{Memory: 2},
+				},
+			},
+			expected: &SchedulerConfiguration{
+				SchedulerAlgorithm: SchedulerAlgorithmWeighted,
+				ScoringWeights:     &SchedulerScoringWeights{Memory: 2},
+			},
+		},
 		{
 			name: "pool without memory oversubscription does not modify config",
 			schedConfig: &SchedulerConfiguration{
@@ -106,3 +122,77 @@ func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedulerConfiguration_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		config *SchedulerConfiguration
+		expErr string
+	}{
+		{
+			name:   "weighted without weights",
+			config: &SchedulerConfiguration{SchedulerAlgorithm: SchedulerAlgorithmWeighted},
+		},
+		{
+			name: "weighted with weights",
+			config: &SchedulerConfiguration{
+				SchedulerAlgorithm: SchedulerAlgorithmWeighted,
+				ScoringWeights:     &SchedulerScoringWeights{Memory: 3, Devices: 1},
+			},
+		},
+		{
+			name:   "invalid algorithm",
+			config: &SchedulerConfiguration{SchedulerAlgorithm: "random"},
+			expErr: "invalid scheduler algorithm",
+		},
+		{
+			name: "negative weight",
+			config: &SchedulerConfiguration{
+				SchedulerAlgorithm: SchedulerAlgorithmWeighted,
+				ScoringWeights:     &SchedulerScoringWeights{CPU: 1, Memory: -1},
+			},
+			expErr: "must not be negative",
+		},
+		{
+			name: "zero weights",
+			config: &SchedulerConfiguration{
+				SchedulerAlgorithm: SchedulerAlgorithmWeighted,
+				ScoringWeights:     &SchedulerScoringWeights{},
+			},
+			expErr: "at least one scoring weight",
+		},
+		{
+			name: "fair share with weights",
+			config: &SchedulerConfiguration{
+				EvalDequeuePolicy: EvalDequeuePolicyFairShare,
+				NamespaceWeights:  map[string]int{"default": 1, "prod": 3},
+			},
+		},
+		{
+			name:   "invalid eval dequeue policy",
+			config: &SchedulerConfiguration{EvalDequeuePolicy: "random"},
+			expErr: "invalid eval dequeue policy",
+		},
+		{
+			name: "zero namespace weight",
+			config: &SchedulerConfiguration{
+				EvalDequeuePolicy: EvalDequeuePolicyFairShare,
+				NamespaceWeights:  map[string]int{"prod": 0},
+			},
+			expErr: `invalid weight for namespace "prod"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// RebalanceDefaultThreshold is the default minimum improvement of the
+	// normalized placement score an allocation must gain to be moved.
+	RebalanceDefaultThreshold = 0.2
+
+	// RebalanceDefaultMaxDisruption is the default number of allocations of a
+	// job which may be moved within each rebalance interval.
+	RebalanceDefaultMaxDisruption = 1
+
+	// RebalanceDefaultInterval is the default interval the max disruption of
+	// a rebalance policy applies to.
+	RebalanceDefaultInterval = 10 * time.Minute
+)
+
+// RebalancePolicy opts a service job into being rebalanced by the leader,
+// which periodically scores the current placement of its running allocations
+// and migrates those which would score significantly better on another node.
+type RebalancePolicy struct {
+	// Threshold is the minimum improvement of the normalized placement score
+	// an allocation must gain to be moved.
+	Threshold float64
+
+	// MaxDisruption is the maximum number of allocations of the job which
+	// are moved within each Interval.
+	MaxDisruption int
+
+	// Interval is the period MaxDisruption applies to.
+	Interval time.Duration
+}
+
+// Copy returns a copy of the rebalance policy.
+func (r *RebalancePolicy) Copy() *RebalancePolicy {
+	if r == nil {
+		return nil
+	}
+	nr := *r
+	return &nr
+}
+
+// Validate returns an error if the rebalance policy is invalid.
+func (r *RebalancePolicy) Validate() error {
+	var mErr []error
+	if r.Threshold <= 0 || r.Threshold > 1 {
+		mErr = append(mErr, fmt.Errorf("Threshold must be greater than 0 and at most 1 but found %v", r.Threshold))
+	}
+	if r.MaxDisruption < 1 {
+		mErr = append(mErr, fmt.Errorf("MaxDisruption must be at least 1 but found %d", r.MaxDisruption))
+	}
+	if r.Interval <= 0 {
+		mErr = append(mErr, errors.New("Interval must be a positive value"))
+	}
+	return errors.Join(mErr...)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestRebalancePolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		jobType string
+		policy  *RebalancePolicy
+		expErr  []string
+	}{
+		{
+			name:    "valid",
+			jobType: JobTypeService,
+			policy: &RebalancePolicy{
+				Threshold:     RebalanceDefaultThreshold,
+				MaxDisruption: RebalanceDefaultMaxDisruption,
+				Interval:      RebalanceDefaultInterval,
+			},
+		},
+		{
+			name:    "invalid",
+			jobType: JobTypeService,
+			policy: &RebalancePolicy{
+				Threshold:     1.5,
+				MaxDisruption: 0,
+				Interval:      -time.Minute,
+			},
+			expErr: []string{
+				"Threshold must be greater than 0 and at most 1 but found 1.5",
+				"MaxDisruption must be at least 1 but found 0",
+				"Interval must be a positive value",
+			},
+		},
+		{
+			name:    "batch job",
+			jobType: JobTypeBatch,
+			policy: &RebalancePolicy{
+				Threshold:     RebalanceDefaultThreshold,
+				MaxDisruption: RebalanceDefaultMaxDisruption,
+				Interval:      RebalanceDefaultInterval,
+			},
+			expErr: []string{`Rebalance can only be used with "service" scheduler`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testJob()
+			job.Type = tc.jobType
+			job.Rebalance = tc.policy
+
+			err := job.Validate()
+			if len(tc.expErr) == 0 {
+				must.NoError(t, err)
+				return
+			}
+			must.Error(t, err)
+			for _, exp := range tc.expErr {
+				must.StrContains(t, err.Error(), exp)
+			}
+		})
+	}
+}
@@ -347,7 +347,7 @@ func (sc *ServiceCheck) validateCommon(allowableTypes []string) error {
 
 // validate a Service's ServiceCheck in the context of the Nomad provider.
 func (sc *ServiceCheck) validateNomad() error {
-	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP}
+	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP, ServiceCheckGRPC}
 	if err := sc.validateCommon(allowable); err != nil {
 		return err
 	}
@@ -398,9 +398,13 @@ func (sc *ServiceCheck) validateNomad() error {
 		return errors.New("failures_before_warning may only be set for Consul service checks")
 	}
 
-	// tls_server_name is consul only
+	// tls_server_name only applies to checks using tls
 	if sc.TLSServerName != "" {
-		return errors.New("tls_server_name may only be set for Consul service checks")
+		usesTLS := (sc.Type == ServiceCheckHTTP && sc.Protocol == "https") ||
+			(sc.Type == ServiceCheckGRPC && sc.GRPCUseTLS)
+		if !usesTLS {
+			return errors.New("tls_server_name may only be set for https checks or grpc checks using tls")
+		}
 	}
 
 	return nil
@@ -255,8 +255,18 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 		sc   *ServiceCheck
 		exp  string
 	}{
-		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http`},
-		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http`},
+		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http, grpc`},
+		{
+			name: "grpc",
+			sc: &ServiceCheck{
+				Type:          ServiceCheckGRPC,
+				GRPCService:   "foo.Bar",
+				GRPCUseTLS:    true,
+				TLSSkipVerify: true,
+				Interval:      3 * time.Second,
+				Timeout:       1 * time.Second,
+			},
+		},
 		{
 			name: "expose",
 			sc: &ServiceCheck{
@@ -392,7 +402,38 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 				Path:          "/health",
 				TLSServerName: "foo",
 			},
-			exp: `tls_server_name may only be set for Consul service checks`,
+			exp: `tls_server_name may only be set for https checks or grpc checks using tls`,
+		},
+		{
+			name: "https with tls_server_name",
+			sc: &ServiceCheck{
+				Type:          ServiceCheckHTTP,
+				Interval:      3 * time.Second,
+				Timeout:       1 * time.Second,
+				Path:          "/health",
+				Protocol:      "https",
+				TLSServerName: "foo",
+			},
+		},
+		{
+			name: "grpc with tls_server_name",
+			sc: &ServiceCheck{
+				Type:          ServiceCheckGRPC,
+				Interval:      3 * time.Second,
+				Timeout:       1 * time.Second,
+				GRPCUseTLS:    true,
+				TLSServerName: "foo",
+			},
+		},
+		{
+			name: "grpc without tls with tls_server_name",
+			sc: &ServiceCheck{
+				Type:          ServiceCheckGRPC,
+				Interval:      3 * time.Second,
+				Timeout:       1 * time.Second,
+				TLSServerName: "foo",
+			},
+			exp: `tls_server_name may only be set for https checks or grpc checks using tls`,
 		},
 	}
 
@@ -2232,7 +2273,7 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New(`invalid check type (""), must be one of tcp, http`),
+				errors.New(`invalid check type (""), must be one of tcp, http, grpc`),
 			},
 			name: "bad nomad check",
 		},
@@ -137,6 +137,11 @@ const (
 	HostVolumeRegisterRequestType             MessageType = 75
 	HostVolumeDeleteRequestType               MessageType = 76
 	TaskGroupHostVolumeClaimDeleteRequestType MessageType = 77
+	VariableVersionsDeleteRequestType         MessageType = 78
+	DeploymentAnalysisUpdateRequestType       MessageType = 79
+	PeriodicLaunchDecisionRequestType         MessageType = 80
+	JobDispatchReleaseRequestType             MessageType = 81
+	VariableVersionsRekeyRequestType          MessageType = 82
 
 	// NOTE: MessageTypes are shared between CE and ENT. If you need to add a
 	// new type, check that ENT is not already using that value.
@@ -253,6 +258,10 @@ type RPCInfo interface {
 	// so Callers should readback TimeToBlock. E.g. you cannot set time to block at all on WriteRequests
 	// and it cannot exceed MaxBlockingRPCQueryTime
 	SetTimeToBlock(t time.Duration)
+	// TraceContext returns the propagated context of the trace the RPC is
+	// part of, which is nil if the RPC isn't being traced.
+	TraceContext() map[string]string
+	SetTraceContext(map[string]string)
 }
 
 // InternalRpcInfo allows adding internal RPC metadata to an RPC. This struct
@@ -260,6 +269,11 @@ type RPCInfo interface {
 type InternalRpcInfo struct {
 	// Forwarded marks whether the RPC has been forwarded.
 	Forwarded bool
+
+	// Trace carries the context of the trace the RPC is part of, so that the
+	// work done by the servers handling it is recorded in the same trace. It
+	// isn't written to the raft log.
+	Trace map[string]string
 }
 
 // IsForwarded returns whether the RPC is forwarded from another server.
@@ -272,6 +286,16 @@ func (i *InternalRpcInfo) SetForwarded() {
 	i.Forwarded = true
 }
 
+// TraceContext returns the propagated context of the trace the RPC is part of.
+func (i *InternalRpcInfo) TraceContext() map[string]string {
+	return i.Trace
+}
+
+// SetTraceContext sets the propagated context of the trace the RPC is part of.
+func (i *InternalRpcInfo) SetTraceContext(carrier map[string]string) {
+	i.Trace = carrier
+}
+
 // QueryOptions is used to specify various flags for read queries
 type QueryOptions struct {
 	// The target region for this query
@@ -1229,6 +1253,12 @@ type PeriodicForceRequest struct {
 	WriteRequest
 }
 
+// PeriodicLaunchRequest is used to get the last launch of a periodic job.
+type PeriodicLaunchRequest struct {
+	JobID string
+	QueryOptions
+}
+
 // ServerMembersResponse has the list of servers in a cluster
 type ServerMembersResponse struct {
 	ServerName   string
@@ -1611,6 +1641,10 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+
+	// Queued is set when the dispatched job was held back by the parent's
+	// max_concurrent limit. No evaluation is created until it is released.
+	Queued bool
 	WriteMeta
 }
 
@@ -1773,6 +1807,12 @@ type PeriodicForceResponse struct {
 	WriteMeta
 }
 
+// PeriodicLaunchResponse is used to return the last launch of a periodic job
+type PeriodicLaunchResponse struct {
+	Launch *PeriodicLaunch
+	QueryMeta
+}
+
 // DeploymentUpdateResponse is used to respond to a deployment change. The
 // response will include the modify index of the deployment as well as details
 // of any triggered evaluation.
@@ -3177,7 +3217,8 @@ func (r *RequestedDevice) Validate() error {
 	for idx, constr := range r.Constraints {
 		// Ensure that the constraint doesn't use an operand we do not allow
 		switch constr.Operand {
-		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+		case ConstraintDistinctHosts, ConstraintDistinctProperty,
+			ConstraintColocatedWith, ConstraintNotColocatedWith:
 			outer := fmt.Errorf("Constraint %d validation failed: using unsupported operand %q", idx+1, constr.Operand)
 			_ = multierror.Append(&mErr, outer)
 		default:
@@ -3188,9 +3229,15 @@ func (r *RequestedDevice) Validate() error {
 		}
 	}
 	for idx, affinity := range r.Affinities {
-		if err := affinity.Validate(); err != nil {
-			outer := fmt.Errorf("Affinity %d validation failed: %s", idx+1, err)
+		switch affinity.Operand {
+		case ConstraintColocatedWith, ConstraintNotColocatedWith:
+			outer := fmt.Errorf("Affinity %d validation failed: using unsupported operand %q", idx+1, affinity.Operand)
 			_ = multierror.Append(&mErr, outer)
+		default:
+			if err := affinity.Validate(); err != nil {
+				outer := fmt.Errorf("Affinity %d validation failed: %s", idx+1, err)
+				_ = multierror.Append(&mErr, outer)
+			}
 		}
 	}
 
@@ -4338,6 +4385,7 @@ const (
 	JobStatusPending = "pending" // Pending means the job is waiting on scheduling
 	JobStatusRunning = "running" // Running means the job has non-terminal allocations
 	JobStatusDead    = "dead"    // Dead means all evaluation's and allocations are terminal
+	JobStatusQueued  = "queued"  // Queued means a dispatched job is waiting on its parent's max_concurrent limit
 )
 
 const (
@@ -4495,6 +4543,10 @@ type Job struct {
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// DependsOn is the set of jobs which must reach a given condition before
+	// a new version of this job is rolled out.
+	DependsOn []*JobDependency
+
 	// TaskGroups are the collections of task groups that this job needs
 	// to run. Each task group is an atomic unit of scheduling and placement.
 	TaskGroups []*TaskGroup
@@ -4512,10 +4564,24 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// Rebalance opts a service job into having its running allocations
+	// moved by the leader to nodes they would score better on.
+	Rebalance *RebalancePolicy
+
+	// ActiveDeadline is the maximum duration a batch job may run, measured
+	// from ActiveSince. The leader stops the job once it is exceeded. A zero
+	// value disables the deadline.
+	ActiveDeadline time.Duration
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
 
+	// DispatchQueued is set on a dispatched job that is waiting on the
+	// parent's max_concurrent limit. It is cleared by the leader when the job
+	// is released and evaluated.
+	DispatchQueued bool
+
 	// DispatchIdempotencyToken is optionally used to ensure that a dispatched job does not have any
 	// non-terminal siblings which have the same token value.
 	DispatchIdempotencyToken string
@@ -4559,6 +4625,12 @@ type Job struct {
 	// UnixNano in UTC
 	SubmitTime int64
 
+	// ActiveSince is the time at which the job became active as UnixNano in
+	// UTC, which the active deadline is measured from. It is set when the job
+	// is first submitted or when a queued dispatched job is released, and is
+	// kept by updates of the job so they don't extend its deadline.
+	ActiveSince int64
+
 	// Raft Indexes
 	CreateIndex uint64
 	// ModifyIndex is the index at which any state of the job last changed
@@ -4720,6 +4792,10 @@ func (j *Job) Canonicalize() {
 		j.Spreads = nil
 	}
 
+	if len(j.DependsOn) == 0 {
+		j.DependsOn = nil
+	}
+
 	// Ensure the job is in a namespace.
 	if j.Namespace == "" {
 		j.Namespace = DefaultNamespace
@@ -4757,6 +4833,7 @@ func (j *Job) Copy() *Job {
 	nj.Datacenters = slices.Clone(j.Datacenters)
 	nj.Constraints = CopySliceConstraints(j.Constraints)
 	nj.Affinities = CopySliceAffinities(j.Affinities)
+	nj.DependsOn = CopySliceJobDependencies(j.DependsOn)
 	nj.Multiregion = j.Multiregion.Copy()
 	nj.UI = j.UI.Copy()
 	nj.VersionTag = j.VersionTag.Copy()
@@ -4772,6 +4849,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = j.Periodic.Copy()
 	nj.Meta = maps.Clone(j.Meta)
 	nj.ParameterizedJob = j.ParameterizedJob.Copy()
+	nj.Rebalance = j.Rebalance.Copy()
 	return nj
 }
 
@@ -4850,6 +4928,10 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if err := validateJobDependencies(j); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	const MaxDescriptionCharacters = 1000
 	if j.UI != nil {
 		if len(j.UI.Description) > MaxDescriptionCharacters {
@@ -4924,6 +5006,26 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.ActiveDeadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("ActiveDeadline must be a positive value"))
+	} else if j.ActiveDeadline > 0 && j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf(
+			"Active deadline can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch,
+		))
+	}
+
+	if j.Rebalance != nil {
+		if j.Type != JobTypeService {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Rebalance can only be used with %q scheduler", JobTypeService,
+			))
+		}
+
+		if err := j.Rebalance.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Rebalance validation failed: %v", err))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4945,7 +5047,7 @@ func (j *Job) Warnings() error {
 			hasAutoPromote = hasAutoPromote || u.AutoPromote
 
 			// Having no canaries implies auto-promotion since there are no canaries to promote.
-			allAutoPromote = allAutoPromote && (u.Canary == 0 || u.AutoPromote)
+			allAutoPromote = allAutoPromote && (!u.HasCanaries() || u.AutoPromote)
 		}
 	}
 
@@ -4977,6 +5079,24 @@ func (j *Job) LookupTaskGroup(name string) *TaskGroup {
 	return nil
 }
 
+// Gangs returns the names of the task groups of each gang of the job.
+func (j *Job) Gangs() map[string][]string {
+	if j == nil {
+		return nil
+	}
+	var gangs map[string][]string
+	for _, tg := range j.TaskGroups {
+		if tg.Gang == "" {
+			continue
+		}
+		if gangs == nil {
+			gangs = make(map[string][]string)
+		}
+		gangs[tg.Gang] = append(gangs[tg.Gang], tg.Name)
+	}
+	return gangs
+}
+
 // CombinedTaskMeta takes a TaskGroup and Task name and returns the combined
 // meta data for the task. When joining Job, Group and Task Meta, the precedence
 // is by deepest scope (Task > Group > Job).
@@ -5224,6 +5344,7 @@ func (j *Job) SpecChanged(new *Job) bool {
 	c.ModifyIndex = j.ModifyIndex
 	c.JobModifyIndex = j.JobModifyIndex
 	c.SubmitTime = j.SubmitTime
+	c.ActiveSince = j.ActiveSince
 
 	// cgbaker: FINISH: probably need some consideration of scaling policy ID here
 
@@ -5294,6 +5415,7 @@ func (js *JobSummary) Copy() *JobSummary {
 
 // JobChildrenSummary contains the summary of children job statuses
 type JobChildrenSummary struct {
+	Queued  int64
 	Pending int64
 	Running int64
 	Dead    int64
@@ -5362,6 +5484,12 @@ type UpdateStrategy struct {
 	// MaxParallel is how many updates can be done in parallel
 	MaxParallel int
 
+	// MaxSurge is how many of the parallel updates place the new allocation
+	// before stopping the allocation it replaces, which is only stopped once
+	// the new allocation is healthy. The task group runs up to MaxSurge
+	// allocations above its count during a rolling update.
+	MaxSurge int
+
 	// HealthCheck specifies the mechanism in which allocations are marked
 	// healthy or unhealthy as part of a deployment.
 	HealthCheck string
@@ -5394,6 +5522,22 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// CanaryPercent is the percentage of the eligible nodes on which canaries
+	// are deployed when a change to the task group is detected. It may only be
+	// used by system and sysbatch jobs, where canaries replace the existing
+	// allocation of the node they are placed on.
+	CanaryPercent int
+
+	// BlueGreen declares that a change to the task group should be deployed
+	// by placing a full set of canaries next to the existing allocations.
+	// Once the canaries are promoted, all the existing allocations are
+	// stopped at once. It may only be used by service jobs.
+	BlueGreen bool
+
+	// Analysis configures the metric queries which gate the promotion of the
+	// canaries and may fail the deployment.
+	Analysis *CanaryAnalysis
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -5403,6 +5547,7 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	c := new(UpdateStrategy)
 	*c = *u
+	c.Analysis = u.Analysis.Copy()
 	return c
 }
 
@@ -5421,12 +5566,35 @@ func (u *UpdateStrategy) Validate() error {
 	if u.MaxParallel < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Max parallel can not be less than zero: %d < 0", u.MaxParallel))
 	}
+	if u.MaxSurge < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max surge can not be less than zero: %d < 0", u.MaxSurge))
+	}
 	if u.Canary < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Canary count can not be less than zero: %d < 0", u.Canary))
 	}
-	if u.Canary == 0 && u.AutoPromote {
+	if u.CanaryPercent < 0 || u.CanaryPercent > 100 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary percent must be between 0 and 100: %d", u.CanaryPercent))
+	}
+	if u.Canary != 0 && u.CanaryPercent != 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary count and canary percent can not both be set"))
+	}
+	if u.BlueGreen && (u.Canary != 0 || u.CanaryPercent != 0) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary count and canary percent can not be set with blue/green deployments"))
+	}
+	if u.MaxSurge != 0 && u.HasCanaries() {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max surge can not be set with canary deployments"))
+	}
+	if !u.HasCanaries() && u.AutoPromote {
 		_ = multierror.Append(&mErr, fmt.Errorf("Auto Promote requires a Canary count greater than zero"))
 	}
+	if u.Analysis != nil {
+		if !u.HasCanaries() {
+			_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis requires a Canary count greater than zero"))
+		}
+		if err := u.Analysis.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis: %w", err))
+		}
+	}
 	if u.MinHealthyTime < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Minimum healthy time may not be less than zero: %v", u.MinHealthyTime))
 	}
@@ -5463,6 +5631,37 @@ func (u *UpdateStrategy) IsEmpty() bool {
 	return u.MaxParallel == 0
 }
 
+// HasCanaries returns whether the update strategy deploys canaries.
+func (u *UpdateStrategy) HasCanaries() bool {
+	return u != nil && (u.Canary > 0 || u.CanaryPercent > 0 || u.BlueGreen)
+}
+
+// CanaryCount returns the number of canaries to deploy for a service task
+// group with the given count. Blue/green deployments deploy as many canaries
+// as the count.
+func (u *UpdateStrategy) CanaryCount(count int) int {
+	if u == nil {
+		return 0
+	}
+	if u.BlueGreen {
+		return count
+	}
+	return u.Canary
+}
+
+// DesiredCanaries returns the number of canaries to deploy for a system or
+// sysbatch task group running on the given number of nodes. The canary
+// percentage is rounded up so that at least one canary is placed.
+func (u *UpdateStrategy) DesiredCanaries(nodes int) int {
+	if u == nil || nodes <= 0 {
+		return 0
+	}
+	if u.CanaryPercent > 0 {
+		return (nodes*u.CanaryPercent + 99) / 100
+	}
+	return min(u.Canary, nodes)
+}
+
 // Rolling returns if a rolling strategy should be used.
 // TODO(alexdadgar): Remove once no longer used by the scheduler.
 func (u *UpdateStrategy) Rolling() bool {
@@ -5551,6 +5750,10 @@ type Namespace struct {
 	VaultConfiguration  *NamespaceVaultConfiguration
 	ConsulConfiguration *NamespaceConsulConfiguration
 
+	// VariablesConfiguration is the namespace configuration for storing
+	// variables, such as how many previous versions to retain.
+	VariablesConfiguration *NamespaceVariablesConfiguration
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5637,6 +5840,16 @@ func (n *Namespace) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid consul configuration: %v", e))
 	}
 
+	err = n.VariablesConfiguration.Validate()
+	switch e := err.(type) {
+	case *multierror.Error:
+		for _, vErr := range e.Errors {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid variables configuration: %v", vErr))
+		}
+	case error:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid variables configuration: %v", e))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5696,6 +5909,15 @@ func (n *Namespace) SetHash() []byte {
 		}
 	}
 
+	if n.VariablesConfiguration != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.VariablesConfiguration.RetainVersions)))
+		prefixes := slices.Sorted(maps.Keys(n.VariablesConfiguration.PathRetainVersions))
+		for _, prefix := range prefixes {
+			_, _ = hash.Write([]byte(prefix))
+			_, _ = hash.Write([]byte(strconv.Itoa(n.VariablesConfiguration.PathRetainVersions[prefix])))
+		}
+	}
+
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
 	for k := range n.Meta {
@@ -5747,6 +5969,7 @@ func (n *Namespace) Copy() *Namespace {
 		nc.Allowed = slices.Clone(n.ConsulConfiguration.Allowed)
 		nc.Denied = slices.Clone(n.ConsulConfiguration.Denied)
 	}
+	nc.VariablesConfiguration = n.VariablesConfiguration.Copy()
 
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
@@ -5812,6 +6035,36 @@ const (
 	// PeriodicSpecTest is only used by unit tests. It is a sorted, comma
 	// separated list of unix timestamps at which to launch.
 	PeriodicSpecTest = "_internal_test"
+
+	// PeriodicCatchUpSkip skips the launches missed while the periodic job
+	// wasn't tracked by a leader or was disabled.
+	PeriodicCatchUpSkip = "skip"
+
+	// PeriodicCatchUpLatest runs the latest missed launch.
+	PeriodicCatchUpLatest = "latest"
+
+	// PeriodicCatchUpAll runs every missed launch, in order.
+	PeriodicCatchUpAll = "all"
+
+	// PeriodicCatchUpMetaKey is the meta key set on the jobs derived by
+	// catch-up launches. Its value is the missed launch time.
+	PeriodicCatchUpMetaKey = "nomad_periodic_catch_up"
+
+	// PeriodicConcurrencyAllow launches the job even if a previous instance
+	// is still running.
+	PeriodicConcurrencyAllow = "allow"
+
+	// PeriodicConcurrencyForbid skips the launch while a previous instance is
+	// running.
+	PeriodicConcurrencyForbid = "forbid"
+
+	// PeriodicConcurrencyReplace stops the running previous instances and
+	// launches the job.
+	PeriodicConcurrencyReplace = "replace"
+
+	// PeriodicConcurrencyQueue defers the launch until the running previous
+	// instances have finished. At most one launch is pending at a time.
+	PeriodicConcurrencyQueue = "queue"
 )
 
 // Periodic defines the interval a job should be run at.
@@ -5830,9 +6083,15 @@ type PeriodicConfig struct {
 	// SpecType defines the format of the spec.
 	SpecType string
 
-	// ProhibitOverlap enforces that spawned jobs do not run in parallel.
+	// ProhibitOverlap enforces that spawned jobs do not run in parallel. It
+	// is the same as the PeriodicConcurrencyForbid policy.
 	ProhibitOverlap bool
 
+	// ConcurrencyPolicy is the policy for launches while a previous instance
+	// of the job is running. An empty policy is the same as
+	// PeriodicConcurrencyAllow, unless ProhibitOverlap is set.
+	ConcurrencyPolicy string
+
 	// TimeZone is the user specified string that determines the time zone to
 	// launch against. The time zones must be specified from IANA Time Zone
 	// database, such as "America/New_York".
@@ -5840,6 +6099,15 @@ type PeriodicConfig struct {
 	// Reference: https://www.iana.org/time-zones
 	TimeZone string
 
+	// CatchUp is the policy for the launches missed while the job wasn't
+	// tracked by a leader or was disabled. An empty policy is the same as
+	// PeriodicCatchUpSkip.
+	CatchUp string
+
+	// StartingDeadline is how late a missed launch may be run by the catch-up
+	// policy.
+	StartingDeadline time.Duration
+
 	// location is the time zone to evaluate the launch time against
 	location *time.Location
 }
@@ -5894,6 +6162,32 @@ func (p *PeriodicConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Unknown periodic specification type %q", p.SpecType))
 	}
 
+	switch p.CatchUp {
+	case "", PeriodicCatchUpSkip:
+	case PeriodicCatchUpLatest, PeriodicCatchUpAll:
+		if p.StartingDeadline <= 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Starting deadline must be set with catch up policy %q", p.CatchUp))
+		}
+		if p.CatchUp == PeriodicCatchUpAll && p.Concurrency() != PeriodicConcurrencyAllow {
+			_ = multierror.Append(&mErr, fmt.Errorf("Catch up policy %q can only be used with concurrency policy %q", p.CatchUp, PeriodicConcurrencyAllow))
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown catch up policy %q", p.CatchUp))
+	}
+	if p.StartingDeadline < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Starting deadline can not be negative"))
+	}
+
+	switch p.ConcurrencyPolicy {
+	case "", PeriodicConcurrencyForbid:
+	case PeriodicConcurrencyAllow, PeriodicConcurrencyReplace, PeriodicConcurrencyQueue:
+		if p.ProhibitOverlap {
+			_ = multierror.Append(&mErr, fmt.Errorf("Concurrency policy %q can not be used with prohibit_overlap", p.ConcurrencyPolicy))
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown concurrency policy %q", p.ConcurrencyPolicy))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5976,6 +6270,55 @@ func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	return time.Time{}, nil
 }
 
+// CatchesUp returns whether missed launches are run by the catch-up policy.
+func (p *PeriodicConfig) CatchesUp() bool {
+	return p.CatchUp == PeriodicCatchUpLatest || p.CatchUp == PeriodicCatchUpAll
+}
+
+// Concurrency returns the effective concurrency policy of the job.
+func (p *PeriodicConfig) Concurrency() string {
+	switch {
+	case p.ConcurrencyPolicy != "":
+		return p.ConcurrencyPolicy
+	case p.ProhibitOverlap:
+		return PeriodicConcurrencyForbid
+	default:
+		return PeriodicConcurrencyAllow
+	}
+}
+
+// MissedLaunches returns the launches the catch-up policy runs for the
+// launches missed after the last launch and up to now, in order. Launches
+// older than the starting deadline are never run.
+func (p *PeriodicConfig) MissedLaunches(last, now time.Time) ([]time.Time, error) {
+	if !p.CatchesUp() || last.IsZero() {
+		return nil, nil
+	}
+
+	from := last
+	if deadline := now.Add(-p.StartingDeadline); from.Before(deadline) {
+		from = deadline
+	}
+
+	var missed []time.Time
+	for {
+		next, err := p.Next(from)
+		if err != nil {
+			return nil, err
+		}
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		missed = append(missed, next)
+		from = next
+	}
+
+	if p.CatchUp == PeriodicCatchUpLatest && len(missed) > 1 {
+		missed = missed[len(missed)-1:]
+	}
+	return missed, nil
+}
+
 // GetLocation returns the location to use for determining the time zone to run
 // the periodic job against.
 func (p *PeriodicConfig) GetLocation() *time.Location {
@@ -5999,11 +6342,77 @@ type PeriodicLaunch struct {
 	Namespace string    // Namespace of the periodic job
 	Launch    time.Time // The last launch time.
 
+	// Decision is the last decision taken by the concurrency policy of the
+	// job.
+	Decision *PeriodicLaunchDecision
+
+	// Queued is the launch deferred by the queue concurrency policy until
+	// the previous instance finishes, or the zero time if none is.
+	Queued time.Time
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+const (
+	// PeriodicDecisionSkipped is recorded when a launch was skipped because
+	// a previous instance was running.
+	PeriodicDecisionSkipped = "skipped"
+
+	// PeriodicDecisionReplaced is recorded when the running previous
+	// instances were stopped to launch the job.
+	PeriodicDecisionReplaced = "replaced"
+
+	// PeriodicDecisionQueued is recorded when a launch was deferred until the
+	// running previous instances finish.
+	PeriodicDecisionQueued = "queued"
+
+	// PeriodicDecisionDequeued is recorded when a deferred launch was run.
+	PeriodicDecisionDequeued = "dequeued"
+)
+
+// PeriodicLaunchDecision is a decision taken by the concurrency policy of a
+// periodic job for one of its launches.
+type PeriodicLaunchDecision struct {
+	// Decision is one of the PeriodicDecision* values.
+	Decision string
+
+	// Description is a human readable description of the decision.
+	Description string
+
+	// Launch is the launch time the decision was taken for.
+	Launch time.Time
+
+	// Time is the time the decision was taken at.
+	Time time.Time
+}
+
+func (d *PeriodicLaunchDecision) Copy() *PeriodicLaunchDecision {
+	if d == nil {
+		return nil
+	}
+	nd := new(PeriodicLaunchDecision)
+	*nd = *d
+	return nd
+}
+
+// PeriodicLaunchDecisionRequest is used to record a decision of the
+// concurrency policy of a periodic job.
+type PeriodicLaunchDecisionRequest struct {
+	Namespace string
+	JobID     string
+	Decision  *PeriodicLaunchDecision
+	WriteRequest
+}
+
+// JobDispatchReleaseRequest is used by the leader to release queued
+// dispatched jobs and create their evaluations.
+type JobDispatchReleaseRequest struct {
+	Evals []*Evaluation
+	WriteRequest
+}
+
 const (
 	DispatchPayloadForbidden = "forbidden"
 	DispatchPayloadOptional  = "optional"
@@ -6024,6 +6433,12 @@ type ParameterizedJobConfig struct {
 
 	// MetaOptional is metadata keys that may be specified by the dispatcher
 	MetaOptional []string
+
+	// MaxConcurrent is the maximum number of dispatched children that may be
+	// pending or running at once. Dispatches beyond the limit are persisted
+	// as queued children and released as running children complete. Zero
+	// means unlimited.
+	MaxConcurrent int
 }
 
 func (d *ParameterizedJobConfig) Validate() error {
@@ -6040,6 +6455,10 @@ func (d *ParameterizedJobConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Required and optional meta keys should be disjoint. Following keys exist in both: %v", offending))
 	}
 
+	if d.MaxConcurrent < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max concurrent must be non-negative: %d", d.MaxConcurrent))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -6192,6 +6611,115 @@ const (
 	ReasonWithinPolicy = "Restart within policy"
 )
 
+const (
+	// ExitRuleActionRestart restarts the task according to the restart
+	// policy, even if it exited successfully.
+	ExitRuleActionRestart = "restart"
+
+	// ExitRuleActionReschedule fails the task without restarting it, so that
+	// the allocation is rescheduled according to the reschedule policy.
+	ExitRuleActionReschedule = "reschedule"
+
+	// ExitRuleActionFail fails the task without restarting it, and prevents
+	// the allocation from being rescheduled.
+	ExitRuleActionFail = "fail"
+
+	// ExitRuleActionSucceed treats the task as having completed successfully,
+	// so it is neither restarted nor rescheduled.
+	ExitRuleActionSucceed = "succeed"
+)
+
+// ExitRule matches the exit code or the signal of a task which exited, and
+// determines the action taken. Rules are evaluated in order and the first
+// matching rule is used.
+type ExitRule struct {
+	// ExitCodes are the exit codes matched by the rule.
+	ExitCodes []int
+
+	// Signals are the signals matched by the rule, as reported by the task
+	// driver when the task is killed by a signal.
+	Signals []int
+
+	// Action is the action taken when the rule matches.
+	Action string
+}
+
+func (r *ExitRule) Copy() *ExitRule {
+	if r == nil {
+		return nil
+	}
+	nr := new(ExitRule)
+	*nr = *r
+	nr.ExitCodes = slices.Clone(r.ExitCodes)
+	nr.Signals = slices.Clone(r.Signals)
+	return nr
+}
+
+// Matches returns whether the rule matches the exit code and signal of a task.
+// A signal of zero means the task was not killed by a signal.
+func (r *ExitRule) Matches(exitCode, signal int) bool {
+	if slices.Contains(r.ExitCodes, exitCode) {
+		return true
+	}
+	return signal != 0 && slices.Contains(r.Signals, signal)
+}
+
+// String returns a human readable description of the rule.
+func (r *ExitRule) String() string {
+	var matches []string
+	if len(r.ExitCodes) > 0 {
+		matches = append(matches, fmt.Sprintf("exit codes %v", r.ExitCodes))
+	}
+	if len(r.Signals) > 0 {
+		matches = append(matches, fmt.Sprintf("signals %v", r.Signals))
+	}
+	return fmt.Sprintf("%s => %s", strings.Join(matches, " or "), r.Action)
+}
+
+// Validate validates the rule, which may only use the passed actions.
+func (r *ExitRule) Validate(actions ...string) error {
+	var mErr multierror.Error
+	if !slices.Contains(actions, r.Action) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Unsupported exit rule action %q, must be one of %q", r.Action, actions))
+	}
+	if len(r.ExitCodes) == 0 && len(r.Signals) == 0 {
+		_ = multierror.Append(&mErr, errors.New("Exit rule must match at least one exit code or signal"))
+	}
+	for _, code := range r.ExitCodes {
+		if code < 0 || code > 255 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Exit rule exit code must be between 0 and 255 (got %d)", code))
+		}
+	}
+	for _, signal := range r.Signals {
+		if signal <= 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Exit rule signal must be positive (got %d)", signal))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// MatchExitRule returns the first of the rules which matches the exit code and
+// signal of a task, or nil if none matches.
+func MatchExitRule(rules []*ExitRule, exitCode, signal int) *ExitRule {
+	for _, rule := range rules {
+		if rule.Matches(exitCode, signal) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func copyExitRules(rules []*ExitRule) []*ExitRule {
+	if rules == nil {
+		return nil
+	}
+	nrules := make([]*ExitRule, len(rules))
+	for i, rule := range rules {
+		nrules[i] = rule.Copy()
+	}
+	return nrules
+}
+
 // JobScalingEvents contains the scaling events for a given job
 type JobScalingEvents struct {
 	Namespace string
@@ -6500,6 +7028,10 @@ type RestartPolicy struct {
 
 	// RenderTemplates is flag to explicitly render all templates on task restart
 	RenderTemplates bool
+
+	// ExitRules determine the action taken when the task exits with specific
+	// exit codes or signals, instead of treating every failure the same.
+	ExitRules []*ExitRule
 }
 
 func (r *RestartPolicy) Copy() *RestartPolicy {
@@ -6508,6 +7040,7 @@ func (r *RestartPolicy) Copy() *RestartPolicy {
 	}
 	nrp := new(RestartPolicy)
 	*nrp = *r
+	nrp.ExitRules = copyExitRules(r.ExitRules)
 	return nrp
 }
 
@@ -6531,6 +7064,12 @@ func (r *RestartPolicy) Validate() error {
 		_ = multierror.Append(&mErr,
 			fmt.Errorf("Nomad can't restart the TaskGroup %v times in an interval of %v with a delay of %v", r.Attempts, r.Interval, r.Delay))
 	}
+	for i, rule := range r.ExitRules {
+		if err := rule.Validate(ExitRuleActionRestart, ExitRuleActionReschedule,
+			ExitRuleActionFail, ExitRuleActionSucceed); err != nil {
+			_ = multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Exit rule %d:", i+1)))
+		}
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -6573,6 +7112,10 @@ type ReschedulePolicy struct {
 	// Unlimited allows infinite rescheduling attempts. Only allowed when delay is set
 	// between reschedule attempts.
 	Unlimited bool
+
+	// ExitRules determine whether a failed allocation is rescheduled based on
+	// the exit codes or signals of its failed tasks.
+	ExitRules []*ExitRule
 }
 
 func (r *ReschedulePolicy) Copy() *ReschedulePolicy {
@@ -6581,9 +7124,23 @@ func (r *ReschedulePolicy) Copy() *ReschedulePolicy {
 	}
 	nrp := new(ReschedulePolicy)
 	*nrp = *r
+	nrp.ExitRules = copyExitRules(r.ExitRules)
 	return nrp
 }
 
+// MatchExitRule returns the first exit rule which matches the last exit of the
+// task, or nil if none matches.
+func (r *ReschedulePolicy) MatchExitRule(ts *TaskState) *ExitRule {
+	if r == nil {
+		return nil
+	}
+	exited := ts.LastExit()
+	if exited == nil {
+		return nil
+	}
+	return MatchExitRule(r.ExitRules, exited.ExitCode, exited.Signal)
+}
+
 func (r *ReschedulePolicy) Enabled() bool {
 	enabled := r != nil && (r.Attempts > 0 || r.Unlimited)
 	return enabled
@@ -6636,6 +7193,15 @@ func (r *ReschedulePolicy) Validate() error {
 
 	}
 
+	// Tasks are only restarted or completed by the client, so rules of the
+	// reschedule policy can only decide whether a failed allocation is
+	// rescheduled
+	for i, rule := range r.ExitRules {
+		if err := rule.Validate(ExitRuleActionReschedule, ExitRuleActionFail); err != nil {
+			_ = multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Exit rule %d:", i+1)))
+		}
+	}
+
 	// Validate Interval and other delay parameters if attempts are limited
 	if !r.Unlimited {
 		if r.Interval.Nanoseconds() < ReschedulePolicyMinInterval.Nanoseconds() {
@@ -6826,6 +7392,10 @@ type TaskGroup struct {
 	// be scheduled.
 	Count int
 
+	// Gang is the name of the gang the task group belongs to. The allocations
+	// of all the task groups of a gang are placed together or not at all.
+	Gang string
+
 	// Update is used to control the update strategy for this task group
 	Update *UpdateStrategy
 
@@ -6885,6 +7455,12 @@ type TaskGroup struct {
 	// group services in consul and stopping tasks.
 	ShutdownDelay *time.Duration
 
+	// ActiveDeadline is the maximum duration an allocation of the group may
+	// run, measured from its creation. The client kills and fails the tasks
+	// of allocations which exceed it so the reschedule policy applies. A zero
+	// value disables the deadline.
+	ActiveDeadline time.Duration
+
 	// StopAfterClientDisconnect, if set, configures the client to stop the task group
 	// after this duration since the last known good heartbeat
 	// To be deprecated after 1.8.0 infavor of Disconnect.StopOnClientAfter
@@ -7061,6 +7637,16 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr = multierror.Append(mErr, errors.New("Task group count can't be negative"))
 	}
 
+	if tg.Gang != "" && j.Type != JobTypeService && j.Type != JobTypeBatch {
+		mErr = multierror.Append(mErr, fmt.Errorf("Gang can only be used with %q or %q scheduler", JobTypeService, JobTypeBatch))
+	}
+
+	if tg.ActiveDeadline < 0 {
+		mErr = multierror.Append(mErr, errors.New("ActiveDeadline must be a positive value"))
+	} else if tg.ActiveDeadline > 0 && j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+		mErr = multierror.Append(mErr, fmt.Errorf("Active deadline can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
+	}
+
 	if len(tg.Tasks) == 0 {
 		// could be a lone consul gateway inserted by the connect mutator
 		mErr = multierror.Append(mErr, errors.New("Missing tasks for task group"))
@@ -7137,7 +7723,17 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	// Validate the update strategy
 	if u := tg.Update; u != nil {
 		switch j.Type {
-		case JobTypeService, JobTypeSystem:
+		case JobTypeService:
+			if u.CanaryPercent != 0 {
+				mErr = multierror.Append(mErr, fmt.Errorf("Canary percent can only be used with %q or %q scheduler", JobTypeSystem, JobTypeSysBatch))
+			}
+		case JobTypeSystem, JobTypeSysBatch:
+			if u.BlueGreen {
+				mErr = multierror.Append(mErr, fmt.Errorf("Blue/green deployments can only be used with %q scheduler", JobTypeService))
+			}
+			if u.MaxSurge != 0 {
+				mErr = multierror.Append(mErr, fmt.Errorf("Max surge can only be used with %q scheduler", JobTypeService))
+			}
 		default:
 			mErr = multierror.Append(mErr, fmt.Errorf("Job type %q does not allow update block", j.Type))
 		}
@@ -7182,10 +7778,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	}
 
 	// Validate the volume requests
-	var canaries int
-	if tg.Update != nil {
-		canaries = tg.Update.Canary
-	}
+	canaries := tg.Update.CanaryCount(tg.Count)
 	for name, volReq := range tg.Volumes {
 		if err := volReq.Validate(j.Type, tg.Count, canaries); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf(
@@ -7735,6 +8328,10 @@ type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
 	Disabled      bool
+
+	// Sink optionally configures logmon to forward the task's logs to an
+	// external sink as they are written.
+	Sink *LogSinkConfig
 }
 
 func (l *LogConfig) Equal(o *LogConfig) bool {
@@ -7754,6 +8351,10 @@ func (l *LogConfig) Equal(o *LogConfig) bool {
 		return false
 	}
 
+	if !l.Sink.Equal(o.Sink) {
+		return false
+	}
+
 	return true
 }
 
@@ -7765,6 +8366,7 @@ func (l *LogConfig) Copy() *LogConfig {
 		MaxFiles:      l.MaxFiles,
 		MaxFileSizeMB: l.MaxFileSizeMB,
 		Disabled:      l.Disabled,
+		Sink:          l.Sink.Copy(),
 	}
 }
 
@@ -7796,6 +8398,13 @@ func (l *LogConfig) Validate(disk *EphemeralDisk) error {
 					logUsage, disk.SizeMB))
 		}
 	}
+	if l.Sink != nil {
+		if l.Disabled {
+			mErr.Errors = append(mErr.Errors, errors.New("log sink cannot be used when logs are disabled"))
+		} else if err := l.Sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("log sink validation failed: %v", err))
+		}
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -7858,6 +8467,11 @@ type Task struct {
 	// killed and killing it.
 	KillTimeout time.Duration
 
+	// ActiveDeadline is the maximum duration of each run of the task. The
+	// client kills tasks which exceed it and restarts them according to the
+	// restart policy. A zero value disables the deadline.
+	ActiveDeadline time.Duration
+
 	// LogConfig provides configuration for log rotation
 	LogConfig *LogConfig
 
@@ -8148,6 +8762,11 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 	if t.ShutdownDelay < 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 	}
+	if t.ActiveDeadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("ActiveDeadline must be a positive value"))
+	} else if t.ActiveDeadline > 0 && jobType != JobTypeBatch && jobType != JobTypeSysBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Active deadline can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
+	}
 
 	// Validate the resources.
 	if t.Resources == nil {
@@ -9100,6 +9719,34 @@ func (ts *TaskState) Successful() bool {
 	return ts.State == TaskStateDead && !ts.Failed
 }
 
+// DeadlineExceeded returns whether the last run of a failed task was killed
+// for exceeding its active deadline.
+func (ts *TaskState) DeadlineExceeded() bool {
+	if !ts.Failed {
+		return false
+	}
+	for i := len(ts.Events) - 1; i >= 0; i-- {
+		switch ts.Events[i].Type {
+		case TaskDeadlineExceeded:
+			return true
+		case TaskStarted:
+			return false
+		}
+	}
+	return false
+}
+
+// LastExit returns the event of the last exit of the task, or nil if the task
+// never exited.
+func (ts *TaskState) LastExit() *TaskEvent {
+	for i := len(ts.Events) - 1; i >= 0; i-- {
+		if ts.Events[i].Type == TaskTerminated {
+			return ts.Events[i]
+		}
+	}
+	return nil
+}
+
 func (ts *TaskState) Equal(o *TaskState) bool {
 	if ts.State != o.State {
 		return false
@@ -9241,6 +9888,10 @@ const (
 	// TaskRunning indicates a task is running due to a schedule or schedule
 	// override. (Enterprise)
 	TaskRunning = "Running"
+
+	// TaskDeadlineExceeded indicates that the task is being killed because it
+	// exceeded the active deadline of the task or of its group.
+	TaskDeadlineExceeded = "Deadline Exceeded"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -9421,6 +10072,9 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Task exceeded restart policy"
 		}
+		if rule := e.Details["reschedule_exit_rule"]; rule != "" {
+			desc = fmt.Sprintf("%s; reschedule exit rule %q matched", desc, rule)
+		}
 	case TaskSiblingFailed:
 		if e.FailedSibling != "" {
 			desc = fmt.Sprintf("Task's sibling %q failed", e.FailedSibling)
@@ -9454,6 +10108,12 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskDeadlineExceeded:
+		if e.KillReason != "" {
+			desc = e.KillReason
+		} else {
+			desc = "Task exceeded its active deadline"
+		}
 	default:
 		desc = e.Message
 	}
@@ -9598,6 +10258,25 @@ func (e *TaskEvent) SetRestartReason(reason string) *TaskEvent {
 	return e
 }
 
+// SetExitRule records the exit rule of the restart policy which matched the
+// exit of the task.
+func (e *TaskEvent) SetExitRule(rule *ExitRule) *TaskEvent {
+	if rule != nil {
+		e.Details["exit_rule"] = rule.String()
+		e.Details["exit_rule_action"] = rule.Action
+	}
+	return e
+}
+
+// SetRescheduleExitRule records the exit rule of the reschedule policy which
+// matched the exit of the task.
+func (e *TaskEvent) SetRescheduleExitRule(rule *ExitRule) *TaskEvent {
+	if rule != nil {
+		e.Details["reschedule_exit_rule"] = rule.String()
+	}
+	return e
+}
+
 func (e *TaskEvent) SetTaskSignalReason(r string) *TaskEvent {
 	e.TaskSignalReason = r
 	e.Details["task_signal_reason"] = r
@@ -9869,6 +10548,8 @@ const (
 	ConstraintSetContainsAny    = "set_contains_any"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintColocatedWith     = "colocated_with"
+	ConstraintNotColocatedWith  = "not_colocated_with"
 )
 
 // A Constraint is used to restrict placement options.
@@ -9915,6 +10596,11 @@ func (c *Constraint) Validate() error {
 	switch c.Operand {
 	case ConstraintDistinctHosts:
 		requireLtarget = false
+	case ConstraintColocatedWith, ConstraintNotColocatedWith:
+		requireLtarget = false
+		if _, err := ParseColocationSelector(c.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid colocation selector: %v", err))
+		}
 	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains:
 		if c.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Set contains constraint requires an RTarget"))
@@ -10039,8 +10725,17 @@ func (a *Affinity) Validate() error {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing affinity operand"))
 	}
 
+	// requireLtarget specifies whether the affinity requires an LTarget to be
+	// provided.
+	requireLtarget := true
+
 	// Perform additional validation based on operand
 	switch a.Operand {
+	case ConstraintColocatedWith, ConstraintNotColocatedWith:
+		requireLtarget = false
+		if _, err := ParseColocationSelector(a.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid colocation selector: %v", err))
+		}
 	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains:
 		if a.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Set contains operators require an RTarget"))
@@ -10065,8 +10760,8 @@ func (a *Affinity) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Unknown affinity operator %q", a.Operand))
 	}
 
-	// Ensure we have an LTarget
-	if a.LTarget == "" {
+	// Ensure we have an LTarget for the affinities that need one
+	if requireLtarget && a.LTarget == "" {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("No LTarget provided but is required"))
 	}
 
@@ -10099,10 +10794,34 @@ type Spread struct {
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
+	// MaxSkew is the maximum difference allowed between the number of
+	// allocations of the task group on any two values of the attribute. Nodes
+	// whose placement would exceed it are infeasible. Zero disables the limit.
+	MaxSkew int
+
+	// MinDomains is the minimum number of values of the attribute the
+	// allocations are expected to be spread over.
+	MinDomains int
+
+	// InsufficientDomains is the behavior of the max skew when fewer than
+	// MinDomains values of the attribute exist.
+	InsufficientDomains string
+
 	// Memoized string representation
 	str string
 }
 
+const (
+	// SpreadInsufficientDomainsBlock counts the missing values of the
+	// attribute as having no allocations, so each existing value is limited
+	// to MaxSkew allocations until enough values exist. This is the default.
+	SpreadInsufficientDomainsBlock = "block"
+
+	// SpreadInsufficientDomainsIgnore computes the skew between the existing
+	// values of the attribute only.
+	SpreadInsufficientDomainsIgnore = "ignore"
+)
+
 func (s *Spread) Equal(o *Spread) bool {
 	if s == nil || o == nil {
 		return s == o
@@ -10114,6 +10833,12 @@ func (s *Spread) Equal(o *Spread) bool {
 		return false
 	case !slices.EqualFunc(s.SpreadTarget, o.SpreadTarget, func(a, b *SpreadTarget) bool { return a.Equal(b) }):
 		return false
+	case s.MaxSkew != o.MaxSkew:
+		return false
+	case s.MinDomains != o.MinDomains:
+		return false
+	case s.InsufficientDomains != o.InsufficientDomains:
+		return false
 	}
 	return true
 }
@@ -10159,6 +10884,9 @@ func (s *Spread) String() string {
 		return s.str
 	}
 	s.str = fmt.Sprintf("%s %s %v", s.Attribute, s.SpreadTarget, s.Weight)
+	if s.MaxSkew > 0 {
+		s.str += fmt.Sprintf(" max_skew=%d", s.MaxSkew)
+	}
 	return s.str
 }
 
@@ -10189,6 +10917,21 @@ func (s *Spread) Validate() error {
 	if sumPercent > 100 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sum of spread target percentages must not be greater than 100%%; got %d%%", sumPercent))
 	}
+	if s.MaxSkew < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread max skew must not be negative; got %d", s.MaxSkew))
+	}
+	if s.MinDomains < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread min domains must not be negative; got %d", s.MinDomains))
+	}
+	switch s.InsufficientDomains {
+	case "", SpreadInsufficientDomainsBlock, SpreadInsufficientDomainsIgnore:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread insufficient domains must be %q or %q; got %q",
+			SpreadInsufficientDomainsBlock, SpreadInsufficientDomainsIgnore, s.InsufficientDomains))
+	}
+	if s.MaxSkew == 0 && (s.MinDomains != 0 || s.InsufficientDomains != "") {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread min domains and insufficient domains require a max skew"))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -10437,6 +11180,7 @@ const (
 	DeploymentStatusDescriptionNewerJob              = "Cancelled due to newer version of job"
 	DeploymentStatusDescriptionFailedAllocations     = "Failed due to unhealthy allocations"
 	DeploymentStatusDescriptionProgressDeadline      = "Failed due to progress deadline"
+	DeploymentStatusDescriptionFailedAnalysis        = "Failed due to canary analysis"
 	DeploymentStatusDescriptionFailedByUser          = "Deployment marked as failed"
 
 	// used only in multiregion deployments
@@ -10676,6 +11420,11 @@ type DeploymentState struct {
 	// DesiredCanaries is the number of canaries that should be created.
 	DesiredCanaries int
 
+	// DesiredSurge is the number of allocations that may be placed above the
+	// count of the task group, alongside the allocations they replace. It is
+	// copied from the TaskGroup UpdateStrategy in scheduler.reconcile
+	DesiredSurge int
+
 	// DesiredTotal is the total number of allocations that should be created as
 	// part of the deployment.
 	DesiredTotal int
@@ -10688,12 +11437,17 @@ type DeploymentState struct {
 
 	// UnhealthyAllocs are allocations that have been marked as unhealthy.
 	UnhealthyAllocs int
+
+	// Analysis is the state of the canary analysis of the task group, if it
+	// has one.
+	Analysis *DeploymentAnalysis
 }
 
 func (d *DeploymentState) GoString() string {
 	base := fmt.Sprintf("\tDesired Total: %d", d.DesiredTotal)
 	base += fmt.Sprintf("\n\tDesired Canaries: %d", d.DesiredCanaries)
 	base += fmt.Sprintf("\n\tPlaced Canaries: %#v", d.PlacedCanaries)
+	base += fmt.Sprintf("\n\tDesired Surge: %d", d.DesiredSurge)
 	base += fmt.Sprintf("\n\tPromoted: %v", d.Promoted)
 	base += fmt.Sprintf("\n\tPlaced: %d", d.PlacedAllocs)
 	base += fmt.Sprintf("\n\tHealthy: %d", d.HealthyAllocs)
@@ -10707,6 +11461,7 @@ func (d *DeploymentState) Copy() *DeploymentState {
 	c := &DeploymentState{}
 	*c = *d
 	c.PlacedCanaries = slices.Clone(d.PlacedCanaries)
+	c.Analysis = d.Analysis.Copy()
 	return c
 }
 
@@ -10916,6 +11671,10 @@ const (
 	AllocClientStatusUnknown  = "unknown"
 )
 
+// AllocClientDescriptionDeadlineExceeded is the client description of failed
+// allocations whose tasks were killed for exceeding their active deadline.
+const AllocClientDescriptionDeadlineExceeded = "DeadlineExceeded"
+
 // terminalAllocationStatuses lists allocation statutes that we consider
 // terminal
 var terminalAllocationStatuses = []string{
@@ -11272,12 +12031,61 @@ func (a *Allocation) ShouldReschedule(reschedulePolicy *ReschedulePolicy, failTi
 	}
 	switch a.ClientStatus {
 	case AllocClientStatusFailed:
+		if a.ExitRuleAction(reschedulePolicy) == ExitRuleActionFail {
+			return false
+		}
 		return a.RescheduleEligible(reschedulePolicy, failTime)
 	default:
 		return false
 	}
 }
 
+// ExitRuleAction returns the action of the exit rules matched by the failed
+// tasks of the allocation, or an empty string if no rule matched. The exit
+// rules of the restart policy are matched by the client and recorded in the
+// task events, while the exit rules of the passed reschedule policy are
+// matched against the last exit of each failed task. If the failed tasks
+// matched different actions, fail takes precedence over reschedule.
+func (a *Allocation) ExitRuleAction(reschedulePolicy *ReschedulePolicy) string {
+	var actions []string
+	for _, ts := range a.TaskStates {
+		if ts == nil || !ts.Failed {
+			continue
+		}
+
+		var clientAction string
+		for i := len(ts.Events) - 1; i >= 0 && clientAction == ""; i-- {
+			if details := ts.Events[i].Details; details != nil {
+				clientAction = details["exit_rule_action"]
+			}
+		}
+
+		// A client which failed the task by a rule of the restart policy
+		// already decided whether the allocation may be rescheduled
+		if clientAction == ExitRuleActionFail {
+			actions = append(actions, clientAction)
+			continue
+		}
+
+		if reschedulePolicy != nil {
+			if rule := reschedulePolicy.MatchExitRule(ts); rule != nil {
+				actions = append(actions, rule.Action)
+				continue
+			}
+		}
+		if clientAction != "" {
+			actions = append(actions, clientAction)
+		}
+	}
+
+	for _, action := range []string{ExitRuleActionFail, ExitRuleActionReschedule} {
+		if slices.Contains(actions, action) {
+			return action
+		}
+	}
+	return ""
+}
+
 // RescheduleEligible returns if the allocation is eligible to be rescheduled according
 // to its ReschedulePolicy and the current state of its reschedule trackers
 func (a *Allocation) RescheduleEligible(reschedulePolicy *ReschedulePolicy, failTime time.Time) bool {
@@ -11342,6 +12150,12 @@ func (a *Allocation) NextRescheduleTime() (time.Time, bool) {
 		return time.Time{}, false
 	}
 
+	// Exit rules can prevent a failed allocation from being rescheduled
+	if a.ClientStatus == AllocClientStatusFailed &&
+		a.ExitRuleAction(reschedulePolicy) == ExitRuleActionFail {
+		return time.Time{}, false
+	}
+
 	return a.nextRescheduleTime(failTime, reschedulePolicy)
 }
 
@@ -11937,6 +12751,15 @@ type AllocMetric struct {
 	// This is to prevent creating many failed allocations for a
 	// single task group.
 	CoalescedFailures int
+
+	// GangFailed is the list of task groups of the same gang whose
+	// allocations could not be placed. The placements of this task group
+	// were rolled back, since a gang is placed entirely or not at all.
+	GangFailed []string
+
+	// ScoringWeights are the weights the weighted scheduler algorithm
+	// scored the nodes with. It is nil for the other algorithms.
+	ScoringWeights *SchedulerScoringWeights
 }
 
 func (a *AllocMetric) Copy() *AllocMetric {
@@ -11953,6 +12776,8 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.QuotaExhausted = slices.Clone(na.QuotaExhausted)
 	na.Scores = maps.Clone(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+	na.GangFailed = slices.Clone(na.GangFailed)
+	na.ScoringWeights = na.ScoringWeights.Copy()
 	return na
 }
 
@@ -12192,6 +13017,11 @@ type AllocDeploymentStatus struct {
 	// been promoted will have this field set to false.
 	Canary bool
 
+	// Replaces is the ID of the allocation a surge allocation is placed
+	// alongside by a rolling update with a max surge. The replaced allocation
+	// is stopped once the surge allocation is healthy.
+	Replaces string
+
 	// ModifyIndex is the raft index in which the deployment status was last
 	// changed.
 	ModifyIndex uint64
@@ -12222,6 +13052,16 @@ func (a *AllocDeploymentStatus) IsUnhealthy() bool {
 	return a.Healthy != nil && !*a.Healthy
 }
 
+// ReplacedAllocation returns the ID of the allocation replaced by a surge
+// allocation, or an empty string if the allocation is not a surge allocation.
+func (a *AllocDeploymentStatus) ReplacedAllocation() string {
+	if a == nil {
+		return ""
+	}
+
+	return a.Replaces
+}
+
 // IsCanary returns if the allocation is marked as a canary
 func (a *AllocDeploymentStatus) IsCanary() bool {
 	if a == nil {
@@ -12258,6 +13098,8 @@ func (a *AllocDeploymentStatus) Equal(o *AllocDeploymentStatus) bool {
 		return false
 	case a.Canary != o.Canary:
 		return false
+	case a.Replaces != o.Replaces:
+		return false
 	case a.ModifyIndex != o.ModifyIndex:
 		return false
 	}
@@ -12290,6 +13132,8 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerJobDependency        = "job-dependency"
+	EvalTriggerRebalance            = "rebalance"
 )
 
 const (
@@ -12348,6 +13192,10 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobVariableVersionsGC is used for the garbage collection of previous
+	// versions of variables that exceed their namespace's retention.
+	CoreJobVariableVersionsGC = "variable-versions-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -12449,6 +13297,11 @@ type Evaluation struct {
 	// evaluation.
 	QuotaLimitReached string
 
+	// UnmetDependencies is the set of job dependencies which hadn't reached
+	// their condition when the evaluation was processed. The rollout of the
+	// job is held until they do.
+	UnmetDependencies []*JobDependency
+
 	// EscapedComputedClass marks whether the job has constraints that are not
 	// captured by computed node classes.
 	EscapedComputedClass bool
@@ -12474,6 +13327,14 @@ type Evaluation struct {
 	// the SnapshotIndex being less than the CreateIndex.
 	SnapshotIndex uint64
 
+	// TraceContext is the propagated context of the trace of the request
+	// which created the evaluation, so that enqueuing and scheduling it is
+	// recorded in the same trace. It is nil if the request wasn't traced.
+	// Unlike the trace context of RPCs, it is written to the raft log and
+	// the state store with the evaluation, as the eval broker enqueues the
+	// evaluations applied by the FSM.
+	TraceContext map[string]string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -12613,6 +13474,8 @@ func (e *Evaluation) Copy() *Evaluation {
 		ne.FailedTGAllocs = failedTGs
 	}
 
+	ne.UnmetDependencies = CopySliceJobDependencies(e.UnmetDependencies)
+
 	// Copy queued allocations
 	if e.QueuedAllocations != nil {
 		queuedAllocations := make(map[string]int, len(e.QueuedAllocations))
@@ -12622,6 +13485,8 @@ func (e *Evaluation) Copy() *Evaluation {
 		ne.QueuedAllocations = queuedAllocations
 	}
 
+	ne.TraceContext = maps.Clone(e.TraceContext)
+
 	return ne
 }
 
@@ -12713,6 +13578,26 @@ func (e *Evaluation) CreateBlockedEval(classEligibility map[string]bool,
 	}
 }
 
+// CreateDependencyBlockedEval creates a blocked evaluation which is unblocked
+// once the dependencies of the job may have reached their condition.
+func (e *Evaluation) CreateDependencyBlockedEval(unmet []*JobDependency) *Evaluation {
+	now := time.Now().UTC().UnixNano()
+	return &Evaluation{
+		ID:                uuid.Generate(),
+		Namespace:         e.Namespace,
+		Priority:          e.Priority,
+		Type:              e.Type,
+		TriggeredBy:       EvalTriggerJobDependency,
+		JobID:             e.JobID,
+		JobModifyIndex:    e.JobModifyIndex,
+		Status:            EvalStatusBlocked,
+		PreviousEval:      e.ID,
+		UnmetDependencies: unmet,
+		CreateTime:        now,
+		ModifyTime:        now,
+	}
+}
+
 // CreateFailedFollowUpEval creates a follow up evaluation when the current one
 // has been marked as failed because it has hit the delivery limit and will not
 // be retried by the eval_broker. Callers should copy the created eval's ID to
@@ -12804,6 +13689,11 @@ type Plan struct {
 	// as evicted.
 	NodePreemptions map[string][]*Allocation
 
+	// TraceContext is the propagated context of the trace of the scheduler
+	// which created the plan, so that applying the plan is recorded in the
+	// same trace.
+	TraceContext map[string]string
+
 	// SnapshotIndex is the Raft index of the snapshot used to create the
 	// Plan. The leader will wait to evaluate the plan until its StateStore
 	// has reached at least this index.
@@ -12960,6 +13850,61 @@ func (p *Plan) PopUpdate(alloc *Allocation) {
 	}
 }
 
+// RemoveAlloc removes the placement of the allocation from the plan, along
+// with the preemptions it required.
+func (p *Plan) RemoveAlloc(alloc *Allocation) {
+	removeByID(p.NodeAllocation, alloc.NodeID, alloc.ID)
+	for nodeID, preempted := range p.NodePreemptions {
+		p.NodePreemptions[nodeID] = slices.DeleteFunc(preempted, func(a *Allocation) bool {
+			return a.PreemptedByAllocation == alloc.ID
+		})
+		if len(p.NodePreemptions[nodeID]) == 0 {
+			delete(p.NodePreemptions, nodeID)
+		}
+	}
+}
+
+// RemoveUpdate removes the stop of the allocation from the plan.
+func (p *Plan) RemoveUpdate(alloc *Allocation) {
+	removeByID(p.NodeUpdate, alloc.NodeID, alloc.ID)
+}
+
+// removeByID removes the allocation with the given ID of the node from the
+// allocations by node.
+func removeByID(allocs map[string][]*Allocation, nodeID, allocID string) {
+	existing := slices.DeleteFunc(allocs[nodeID], func(a *Allocation) bool {
+		return a.ID == allocID
+	})
+	if len(existing) > 0 {
+		allocs[nodeID] = existing
+	} else {
+		delete(allocs, nodeID)
+	}
+}
+
+// RequiresAllAtOnce returns whether the plan must be applied entirely or not
+// at all, either because the job requires it or because the plan places
+// allocations of task groups that are gang scheduled.
+func (p *Plan) RequiresAllAtOnce() bool {
+	if p.AllAtOnce {
+		return true
+	}
+	if len(p.Job.Gangs()) == 0 {
+		return false
+	}
+	for _, allocs := range p.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.CreateIndex != 0 {
+				continue
+			}
+			if tg := p.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.Gang != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // AppendAlloc appends the alloc to the plan allocations.
 // Uses the passed job if explicitly passed, otherwise
 // it is assumed the alloc will use the plan Job version.
@@ -61,11 +61,35 @@ func TestNamespace_Validate(t *testing.T) {
 			},
 			Expected: "description longer than",
 		},
+		{
+			Test: "negative variable retention",
+			Namespace: &Namespace{
+				Name: "foo",
+				VariablesConfiguration: &NamespaceVariablesConfiguration{
+					RetainVersions: -1,
+				},
+			},
+			Expected: "invalid variables configuration",
+		},
+		{
+			Test: "too many variable versions for path",
+			Namespace: &Namespace{
+				Name: "foo",
+				VariablesConfiguration: &NamespaceVariablesConfiguration{
+					PathRetainVersions: map[string]int{"app/": 1000},
+				},
+			},
+			Expected: "invalid variables configuration",
+		},
 		{
 			Test: "valid",
 			Namespace: &Namespace{
 				Name:        "foo",
 				Description: "bar",
+				VariablesConfiguration: &NamespaceVariablesConfiguration{
+					RetainVersions:     5,
+					PathRetainVersions: map[string]int{"app/": 10},
+				},
 			},
 		},
 	}
@@ -111,6 +135,9 @@ func TestNamespace_SetHash(t *testing.T) {
 			Default: "default",
 			Allowed: []string{"default"},
 		},
+		VariablesConfiguration: &NamespaceVariablesConfiguration{
+			RetainVersions: 3,
+		},
 		Meta: map[string]string{
 			"a": "b",
 			"c": "d",
@@ -178,6 +205,13 @@ func TestNamespace_SetHash(t *testing.T) {
 	must.NotNil(t, ns.Hash)
 	must.Eq(t, out8, ns.Hash)
 	must.NotEq(t, out7, out8)
+
+	ns.VariablesConfiguration.PathRetainVersions = map[string]int{"app/": 10}
+	out9 := ns.SetHash()
+	must.NotNil(t, out9)
+	must.NotNil(t, ns.Hash)
+	must.Eq(t, out9, ns.Hash)
+	must.NotEq(t, out8, out9)
 }
 
 func TestNamespace_Copy(t *testing.T) {
@@ -203,6 +237,9 @@ func TestNamespace_Copy(t *testing.T) {
 			Default: "default",
 			Allowed: []string{"default"},
 		},
+		VariablesConfiguration: &NamespaceVariablesConfiguration{
+			RetainVersions: 3,
+		},
 		Meta: map[string]string{
 			"a": "b",
 			"c": "d",
@@ -225,13 +262,33 @@ func TestNamespace_Copy(t *testing.T) {
 	nsCopy.ConsulConfiguration.Default = "infra"
 	nsCopy.ConsulConfiguration.Allowed = []string{}
 	nsCopy.ConsulConfiguration.Denied = []string{"dev"}
+	nsCopy.VariablesConfiguration.PathRetainVersions = map[string]int{"app/": 10}
 	nsCopy.Meta["a"] = "z"
 	must.NotEq(t, ns, nsCopy)
+	must.Nil(t, ns.VariablesConfiguration.PathRetainVersions)
 
 	nsCopy2 := ns.Copy()
 	must.Eq(t, ns, nsCopy2)
 }
 
+func TestNamespaceVariablesConfiguration_RetainVersionsForPath(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilConfig *NamespaceVariablesConfiguration
+	must.Eq(t, 0, nilConfig.RetainVersionsForPath("app/config"))
+
+	config := &NamespaceVariablesConfiguration{
+		RetainVersions: 3,
+		PathRetainVersions: map[string]int{
+			"app/":         10,
+			"app/scratch/": 0,
+		},
+	}
+	must.Eq(t, 3, config.RetainVersionsForPath("db/config"))
+	must.Eq(t, 10, config.RetainVersionsForPath("app/config"))
+	must.Eq(t, 0, config.RetainVersionsForPath("app/scratch/tmp"))
+}
+
 func TestAuthenticatedIdentity_String(t *testing.T) {
 	ci.Parallel(t)
 
@@ -465,6 +522,36 @@ func TestJob_ValidateScaling(t *testing.T) {
 	)
 }
 
+func TestJob_ValidateActiveDeadline(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.Type = JobTypeBatch
+	job.TaskGroups[0].Update = nil
+	job.TaskGroups[0].Migrate = nil
+	job.ActiveDeadline = time.Hour
+	job.TaskGroups[0].ActiveDeadline = 30 * time.Minute
+	job.TaskGroups[0].Tasks[0].ActiveDeadline = 10 * time.Minute
+	must.NoError(t, job.Validate())
+
+	// Deadlines can't be negative
+	job.ActiveDeadline = -time.Hour
+	job.TaskGroups[0].ActiveDeadline = -time.Hour
+	job.TaskGroups[0].Tasks[0].ActiveDeadline = -time.Hour
+	err := job.Validate()
+	must.ErrorContains(t, err, "ActiveDeadline must be a positive value")
+	must.Len(t, 3, strings.Split(err.Error(), "ActiveDeadline must be a positive value")[1:])
+
+	// Deadlines are only supported by batch jobs
+	job = testJob()
+	job.ActiveDeadline = time.Hour
+	job.TaskGroups[0].ActiveDeadline = time.Hour
+	job.TaskGroups[0].Tasks[0].ActiveDeadline = time.Hour
+	err = job.Validate()
+	must.ErrorContains(t, err, `Active deadline can only be used with "batch" or "sysbatch" scheduler`)
+	must.Len(t, 3, strings.Split(err.Error(), "Active deadline can only be used")[1:])
+}
+
 func TestJob_ValidateNullChar(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1495,6 +1582,87 @@ func TestTaskGroup_Validate(t *testing.T) {
 			},
 			jobType: JobTypeBatch,
 		},
+		{
+			name: "canary percent for service job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 1,
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+				Update: &UpdateStrategy{
+					MaxParallel:     1,
+					HealthCheck:     UpdateStrategyHealthCheck_Checks,
+					MinHealthyTime:  10 * time.Second,
+					HealthyDeadline: 5 * time.Minute,
+					Stagger:         30 * time.Second,
+					CanaryPercent:   10,
+				},
+			},
+			expErr: []string{
+				"Canary percent can only be used",
+			},
+			jobType: JobTypeService,
+		},
+		{
+			name: "blue/green for system job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 1,
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+				Update: &UpdateStrategy{
+					MaxParallel:     1,
+					HealthCheck:     UpdateStrategyHealthCheck_Checks,
+					MinHealthyTime:  10 * time.Second,
+					HealthyDeadline: 5 * time.Minute,
+					Stagger:         30 * time.Second,
+					BlueGreen:       true,
+				},
+			},
+			expErr: []string{
+				"Blue/green deployments can only be used",
+			},
+			jobType: JobTypeSystem,
+		},
+		{
+			name: "max surge for system job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 1,
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+				Update: &UpdateStrategy{
+					MaxParallel:     1,
+					HealthCheck:     UpdateStrategyHealthCheck_Checks,
+					MinHealthyTime:  10 * time.Second,
+					HealthyDeadline: 5 * time.Minute,
+					Stagger:         30 * time.Second,
+					MaxSurge:        1,
+				},
+			},
+			expErr: []string{
+				"Max surge can only be used",
+			},
+			jobType: JobTypeSystem,
+		},
+		{
+			name: "gang for system job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 1,
+				Gang:  "training",
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+			},
+			expErr: []string{
+				"Gang can only be used",
+			},
+			jobType: JobTypeSystem,
+		},
 		{
 			name: "invalid reschedule policy for system job",
 			tg: &TaskGroup{
@@ -3187,6 +3355,16 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equal(b))
 	})
 
+	t.Run("sink", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sink: &LogSinkConfig{
+			Type:    LogSinkTypeUnix,
+			Address: "/run/logs.sock",
+		}}
+		require.False(t, a.Equal(b))
+		require.True(t, b.Equal(b.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -3665,6 +3843,17 @@ func TestConstraint_Validate(t *testing.T) {
 		t.Fatalf("expected valid constraint: %v", err)
 	}
 
+	// Perform colocated_with validation
+	for _, o := range []string{ConstraintColocatedWith, ConstraintNotColocatedWith} {
+		c.Operand = o
+		c.RTarget = "job=api,group=web"
+		require.NoError(t, c.Validate())
+
+		c.RTarget = "host=web"
+		err = c.Validate()
+		require.ErrorContains(t, err, "Invalid colocation selector")
+	}
+
 	// Perform set_contains* validation
 	c.RTarget = ""
 	for _, o := range []string{ConstraintSetContains, ConstraintSetContainsAll, ConstraintSetContainsAny} {
@@ -3759,6 +3948,20 @@ func TestAffinity_Validate(t *testing.T) {
 			},
 			err: fmt.Errorf("Regular expression failed to compile"),
 		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintColocatedWith,
+				RTarget: "job=api",
+				Weight:  50,
+			},
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintNotColocatedWith,
+				Weight:  50,
+			},
+			err: fmt.Errorf("Invalid colocation selector"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3798,6 +4001,86 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 		"Minimum healthy time must be less than healthy deadline",
 		"Healthy deadline must be less than progress deadline",
 	)
+
+	u = DefaultUpdateStrategy.Copy()
+	u.Canary = 1
+	u.CanaryPercent = 101
+	requireErrors(t, u.Validate(),
+		"Canary percent must be between 0 and 100",
+		"Canary count and canary percent can not both be set",
+	)
+
+	u = DefaultUpdateStrategy.Copy()
+	u.BlueGreen = true
+	u.AutoPromote = true
+	must.NoError(t, u.Validate())
+
+	u.Canary = 2
+	requireErrors(t, u.Validate(),
+		"Canary count and canary percent can not be set with blue/green deployments",
+	)
+
+	u = DefaultUpdateStrategy.Copy()
+	u.MaxSurge = 2
+	must.NoError(t, u.Validate())
+
+	u.MaxSurge = -1
+	requireErrors(t, u.Validate(),
+		"Max surge can not be less than zero",
+	)
+
+	u.MaxSurge = 1
+	u.Canary = 1
+	requireErrors(t, u.Validate(),
+		"Max surge can not be set with canary deployments",
+	)
+}
+
+func TestUpdateStrategy_CanaryCount(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		strategy *UpdateStrategy
+		count    int
+		exp      int
+	}{
+		{name: "nil", strategy: nil, count: 10, exp: 0},
+		{name: "no canaries", strategy: &UpdateStrategy{}, count: 10, exp: 0},
+		{name: "canary", strategy: &UpdateStrategy{Canary: 2}, count: 10, exp: 2},
+		{name: "blue/green", strategy: &UpdateStrategy{BlueGreen: true}, count: 10, exp: 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.exp, tc.strategy.CanaryCount(tc.count))
+		})
+	}
+}
+
+func TestUpdateStrategy_DesiredCanaries(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		strategy *UpdateStrategy
+		nodes    int
+		exp      int
+	}{
+		{name: "nil", strategy: nil, nodes: 10, exp: 0},
+		{name: "no canaries", strategy: &UpdateStrategy{}, nodes: 10, exp: 0},
+		{name: "count", strategy: &UpdateStrategy{Canary: 2}, nodes: 10, exp: 2},
+		{name: "count above nodes", strategy: &UpdateStrategy{Canary: 5}, nodes: 3, exp: 3},
+		{name: "percent", strategy: &UpdateStrategy{CanaryPercent: 20}, nodes: 10, exp: 2},
+		{name: "percent rounded up", strategy: &UpdateStrategy{CanaryPercent: 10}, nodes: 3, exp: 1},
+		{name: "no nodes", strategy: &UpdateStrategy{CanaryPercent: 10}, nodes: 0, exp: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.exp, tc.strategy.DesiredCanaries(tc.nodes))
+		})
+	}
 }
 
 func TestResource_NetIndex(t *testing.T) {
@@ -4509,6 +4792,178 @@ func TestPeriodicConfig_ValidCron(t *testing.T) {
 	}
 }
 
+func TestPeriodicConfig_CatchUp_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name            string
+		catchUp         string
+		deadline        time.Duration
+		prohibitOverlap bool
+		expErr          string
+	}{
+		{name: "default"},
+		{name: "skip", catchUp: PeriodicCatchUpSkip},
+		{name: "latest", catchUp: PeriodicCatchUpLatest, deadline: time.Hour, prohibitOverlap: true},
+		{name: "all", catchUp: PeriodicCatchUpAll, deadline: time.Hour},
+		{
+			name:    "no deadline",
+			catchUp: PeriodicCatchUpLatest,
+			expErr:  `Starting deadline must be set with catch up policy "latest"`,
+		},
+		{
+			name:            "all prohibits overlap",
+			catchUp:         PeriodicCatchUpAll,
+			deadline:        time.Hour,
+			prohibitOverlap: true,
+			expErr:          `Catch up policy "all" can only be used with concurrency policy "allow"`,
+		},
+		{
+			name:    "unknown",
+			catchUp: "some",
+			expErr:  `Unknown catch up policy "some"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &PeriodicConfig{
+				Enabled:          true,
+				SpecType:         PeriodicSpecCron,
+				Spec:             "@hourly",
+				CatchUp:          tc.catchUp,
+				StartingDeadline: tc.deadline,
+				ProhibitOverlap:  tc.prohibitOverlap,
+			}
+			err := p.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+				return
+			}
+			must.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestPeriodicConfig_ConcurrencyPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name            string
+		policy          string
+		prohibitOverlap bool
+		expPolicy       string
+		expErr          string
+	}{
+		{name: "default", expPolicy: PeriodicConcurrencyAllow},
+		{name: "prohibit overlap", prohibitOverlap: true, expPolicy: PeriodicConcurrencyForbid},
+		{
+			name:            "forbid prohibits overlap",
+			policy:          PeriodicConcurrencyForbid,
+			prohibitOverlap: true,
+			expPolicy:       PeriodicConcurrencyForbid,
+		},
+		{name: "replace", policy: PeriodicConcurrencyReplace, expPolicy: PeriodicConcurrencyReplace},
+		{name: "queue", policy: PeriodicConcurrencyQueue, expPolicy: PeriodicConcurrencyQueue},
+		{
+			name:            "queue prohibits overlap",
+			policy:          PeriodicConcurrencyQueue,
+			prohibitOverlap: true,
+			expPolicy:       PeriodicConcurrencyQueue,
+			expErr:          `Concurrency policy "queue" can not be used with prohibit_overlap`,
+		},
+		{
+			name:      "unknown",
+			policy:    "some",
+			expPolicy: "some",
+			expErr:    `Unknown concurrency policy "some"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &PeriodicConfig{
+				Enabled:           true,
+				SpecType:          PeriodicSpecCron,
+				Spec:              "@hourly",
+				ConcurrencyPolicy: tc.policy,
+				ProhibitOverlap:   tc.prohibitOverlap,
+			}
+			must.Eq(t, tc.expPolicy, p.Concurrency())
+
+			err := p.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+				return
+			}
+			must.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestPeriodicConfig_MissedLaunches(t *testing.T) {
+	ci.Parallel(t)
+
+	last := time.Date(2024, time.March, 1, 0, 30, 0, 0, time.UTC)
+	now := time.Date(2024, time.March, 1, 6, 30, 0, 0, time.UTC)
+	hour := func(h int) time.Time {
+		return time.Date(2024, time.March, 1, h, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		catchUp  string
+		deadline time.Duration
+		expected []time.Time
+	}{
+		{
+			name:     "skip",
+			catchUp:  PeriodicCatchUpSkip,
+			deadline: 24 * time.Hour,
+		},
+		{
+			name:     "latest",
+			catchUp:  PeriodicCatchUpLatest,
+			deadline: 24 * time.Hour,
+			expected: []time.Time{hour(6)},
+		},
+		{
+			name:     "all",
+			catchUp:  PeriodicCatchUpAll,
+			deadline: 24 * time.Hour,
+			expected: []time.Time{hour(1), hour(2), hour(3), hour(4), hour(5), hour(6)},
+		},
+		{
+			name:     "all within deadline",
+			catchUp:  PeriodicCatchUpAll,
+			deadline: 150 * time.Minute,
+			expected: []time.Time{hour(5), hour(6)},
+		},
+		{
+			name:     "latest past deadline",
+			catchUp:  PeriodicCatchUpLatest,
+			deadline: 15 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &PeriodicConfig{
+				Enabled:          true,
+				SpecType:         PeriodicSpecCron,
+				Spec:             "@hourly",
+				CatchUp:          tc.catchUp,
+				StartingDeadline: tc.deadline,
+			}
+			p.Canonicalize()
+
+			missed, err := p.MissedLaunches(last, now)
+			must.NoError(t, err)
+			must.Eq(t, tc.expected, missed)
+		})
+	}
+}
+
 func TestPeriodicConfig_NextCron(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4699,6 +5154,54 @@ func TestRestartPolicy_Validate(t *testing.T) {
 	}
 }
 
+func TestExitRule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	// Rules of the restart policy may use every action
+	p := &RestartPolicy{
+		Mode:     RestartPolicyModeFail,
+		Attempts: 1,
+		Interval: 5 * time.Second,
+		ExitRules: []*ExitRule{
+			{ExitCodes: []int{3}, Action: ExitRuleActionFail},
+			{ExitCodes: []int{75}, Signals: []int{15}, Action: ExitRuleActionRestart},
+			{Signals: []int{9}, Action: ExitRuleActionReschedule},
+			{ExitCodes: []int{2}, Action: ExitRuleActionSucceed},
+		},
+	}
+	must.NoError(t, p.Validate())
+
+	p.ExitRules = []*ExitRule{
+		{Action: ExitRuleActionFail},
+		{ExitCodes: []int{256}, Signals: []int{-1}, Action: "nope"},
+	}
+	err := p.Validate()
+	must.ErrorContains(t, err, "Exit rule 1: Exit rule must match at least one exit code or signal")
+	must.ErrorContains(t, err, `Exit rule 2: Unsupported exit rule action "nope"`)
+	must.ErrorContains(t, err, "exit code must be between 0 and 255 (got 256)")
+	must.ErrorContains(t, err, "signal must be positive (got -1)")
+
+	// Rules of the reschedule policy can't restart tasks
+	rp := &ReschedulePolicy{
+		Attempts:      1,
+		Interval:      1 * time.Hour,
+		Delay:         30 * time.Second,
+		DelayFunction: "constant",
+		ExitRules: []*ExitRule{
+			{ExitCodes: []int{3}, Action: ExitRuleActionFail},
+			{ExitCodes: []int{75}, Action: ExitRuleActionReschedule},
+		},
+	}
+	must.NoError(t, rp.Validate())
+
+	rp.ExitRules = append(rp.ExitRules, &ExitRule{ExitCodes: []int{1}, Action: ExitRuleActionRestart})
+	must.ErrorContains(t, rp.Validate(), `Exit rule 3: Unsupported exit rule action "restart"`)
+
+	// Nor can they complete allocations
+	rp.ExitRules[2].Action = ExitRuleActionSucceed
+	must.ErrorContains(t, rp.Validate(), `Exit rule 3: Unsupported exit rule action "succeed"`)
+}
+
 func TestReschedulePolicy_Validate(t *testing.T) {
 	ci.Parallel(t)
 	type testCase struct {
@@ -5326,6 +5829,59 @@ func TestPlan_AppendPreemptedAllocAppendsAllocWithUpdatedAttrs(t *testing.T) {
 	assert.Equal(t, expectedAlloc, appendedAlloc)
 }
 
+func TestPlan_RemoveAlloc(t *testing.T) {
+	ci.Parallel(t)
+	plan := &Plan{
+		NodeUpdate:      make(map[string][]*Allocation),
+		NodeAllocation:  make(map[string][]*Allocation),
+		NodePreemptions: make(map[string][]*Allocation),
+	}
+
+	alloc, other := MockAlloc(), MockAlloc()
+	other.NodeID = alloc.NodeID
+	plan.AppendAlloc(alloc, nil)
+	plan.AppendAlloc(other, nil)
+
+	preempted, stopped := MockAlloc(), MockAlloc()
+	plan.AppendPreemptedAlloc(preempted, alloc.ID)
+	plan.AppendStoppedAlloc(stopped, "", "", "")
+
+	plan.RemoveAlloc(alloc)
+	must.Eq(t, []*Allocation{other}, plan.NodeAllocation[alloc.NodeID])
+	must.MapEmpty(t, plan.NodePreemptions)
+
+	plan.RemoveAlloc(other)
+	must.MapEmpty(t, plan.NodeAllocation)
+
+	plan.RemoveUpdate(stopped)
+	must.MapEmpty(t, plan.NodeUpdate)
+}
+
+func TestPlan_RequiresAllAtOnce(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	alloc := MockAlloc()
+	alloc.Job = job
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	plan := &Plan{
+		Job:            job,
+		NodeAllocation: map[string][]*Allocation{alloc.NodeID: {alloc}},
+	}
+	must.False(t, plan.RequiresAllAtOnce())
+
+	plan.AllAtOnce = true
+	must.True(t, plan.RequiresAllAtOnce())
+
+	plan.AllAtOnce = false
+	job.TaskGroups[0].Gang = "training"
+	must.True(t, plan.RequiresAllAtOnce())
+
+	// Updates of existing allocations don't place the gang
+	alloc.CreateIndex = 10
+	must.False(t, plan.RequiresAllAtOnce())
+}
+
 func TestMsgPackTags(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5388,6 +5944,166 @@ func TestAllocation_Terminated(t *testing.T) {
 	}
 }
 
+func TestTaskState_DeadlineExceeded(t *testing.T) {
+	ci.Parallel(t)
+
+	deadline := NewTaskEvent(TaskDeadlineExceeded)
+	started := NewTaskEvent(TaskStarted)
+	terminated := NewTaskEvent(TaskTerminated)
+
+	testCases := []struct {
+		name     string
+		failed   bool
+		events   []*TaskEvent
+		expected bool
+	}{
+		{
+			name:     "last run exceeded its deadline",
+			failed:   true,
+			events:   []*TaskEvent{started, deadline, terminated},
+			expected: true,
+		},
+		{
+			name:     "task did not fail",
+			failed:   false,
+			events:   []*TaskEvent{started, deadline, terminated},
+			expected: false,
+		},
+		{
+			name:     "earlier run exceeded its deadline",
+			failed:   true,
+			events:   []*TaskEvent{started, deadline, terminated, started, terminated},
+			expected: false,
+		},
+		{
+			name:     "no events",
+			failed:   true,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := &TaskState{State: TaskStateDead, Failed: tc.failed, Events: tc.events}
+			must.Eq(t, tc.expected, ts.DeadlineExceeded())
+		})
+	}
+}
+
+func TestAllocation_ExitRuleAction(t *testing.T) {
+	ci.Parallel(t)
+
+	terminated := func(exitCode, signal int) *TaskEvent {
+		return NewTaskEvent(TaskTerminated).SetExitCode(exitCode).SetSignal(signal)
+	}
+	notRestarting := func(rule *ExitRule) *TaskEvent {
+		return NewTaskEvent(TaskNotRestarting).SetExitRule(rule).SetFailsTask()
+	}
+
+	policy := &ReschedulePolicy{
+		Attempts:      1,
+		Interval:      1 * time.Hour,
+		Delay:         30 * time.Second,
+		DelayFunction: "constant",
+		ExitRules: []*ExitRule{
+			{ExitCodes: []int{3}, Action: ExitRuleActionFail},
+			{Signals: []int{9}, Action: ExitRuleActionFail},
+			{ExitCodes: []int{75}, Action: ExitRuleActionReschedule},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		states map[string]*TaskState
+		action string
+	}{
+		{
+			name: "no match",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{terminated(1, 0)}},
+			},
+			action: "",
+		},
+		{
+			name: "reschedule policy exit code",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{terminated(3, 0)}},
+			},
+			action: ExitRuleActionFail,
+		},
+		{
+			name: "reschedule policy signal",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{terminated(137, 9)}},
+			},
+			action: ExitRuleActionFail,
+		},
+		{
+			name: "only the last exit is matched",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{terminated(3, 0), terminated(1, 0)}},
+			},
+			action: "",
+		},
+		{
+			name: "tasks which did not fail are ignored",
+			states: map[string]*TaskState{
+				"web": {Failed: false, Events: []*TaskEvent{terminated(3, 0)}},
+			},
+			action: "",
+		},
+		{
+			name: "restart policy fail",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{
+					terminated(75, 0),
+					notRestarting(&ExitRule{ExitCodes: []int{75}, Action: ExitRuleActionFail}),
+				}},
+			},
+			action: ExitRuleActionFail,
+		},
+		{
+			name: "restart policy reschedule",
+			states: map[string]*TaskState{
+				"web": {Failed: true, Events: []*TaskEvent{
+					terminated(1, 0),
+					notRestarting(&ExitRule{ExitCodes: []int{1}, Action: ExitRuleActionReschedule}),
+				}},
+			},
+			action: ExitRuleActionReschedule,
+		},
+		{
+			name: "fail takes precedence",
+			states: map[string]*TaskState{
+				"web":    {Failed: true, Events: []*TaskEvent{terminated(75, 0)}},
+				"worker": {Failed: true, Events: []*TaskEvent{terminated(3, 0)}},
+			},
+			action: ExitRuleActionFail,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alloc := MockAlloc()
+			alloc.Job.TaskGroups[0].ReschedulePolicy = policy
+			alloc.ClientStatus = AllocClientStatusFailed
+			alloc.TaskStates = tc.states
+			for _, ts := range tc.states {
+				ts.State = TaskStateDead
+				ts.FinishedAt = time.Now()
+			}
+
+			must.Eq(t, tc.action, alloc.ExitRuleAction(policy))
+
+			// Allocations whose exit matched a fail rule are not rescheduled
+			prevented := tc.action == ExitRuleActionFail
+			must.Eq(t, !prevented, alloc.ShouldReschedule(policy, time.Now()))
+			_, eligible := alloc.NextRescheduleTime()
+			must.Eq(t, !prevented, eligible)
+		})
+	}
+}
+
 func TestAllocation_ShouldReschedule(t *testing.T) {
 	ci.Parallel(t)
 	type testCase struct {
@@ -6452,6 +7168,18 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "disjoint") {
 		t.Fatalf("Expected meta not being disjoint error: %v", err)
 	}
+
+	d.MetaRequired = []string{"baz"}
+	d.MaxConcurrent = -1
+
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "non-negative") {
+		t.Fatalf("Expected negative max concurrent error: %v", err)
+	}
+
+	d.MaxConcurrent = 5
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 }
 
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
@@ -6803,6 +7531,7 @@ func TestTaskEventPopulate(t *testing.T) {
 		{NewTaskEvent(TaskKilled).SetKillError(fmt.Errorf("undead creatures can't be killed")), "undead creatures can't be killed"},
 		{NewTaskEvent(TaskNotRestarting).SetRestartReason("Chaos Monkey did it"), "Chaos Monkey did it"},
 		{NewTaskEvent(TaskNotRestarting), "Task exceeded restart policy"},
+		{NewTaskEvent(TaskNotRestarting).SetRescheduleExitRule(&ExitRule{ExitCodes: []int{3}, Action: ExitRuleActionFail}), "Task exceeded restart policy; reschedule exit rule \"exit codes [3] => fail\" matched"},
 		{NewTaskEvent(TaskLeaderDead), "Leader Task in Group dead"},
 		{NewTaskEvent(TaskSiblingFailed), "Task's sibling failed"},
 		{NewTaskEvent(TaskSiblingFailed).SetFailedSibling("patient zero"), "Task's sibling \"patient zero\" failed"},
@@ -7243,6 +7972,45 @@ func TestSpread_Validate(t *testing.T) {
 			err:  nil,
 			name: "Valid spread",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   -1,
+			},
+			err:  fmt.Errorf("Spread max skew must not be negative; got -1"),
+			name: "Invalid max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute:           "${node.datacenter}",
+				Weight:              50,
+				MaxSkew:             1,
+				InsufficientDomains: "wait",
+			},
+			err:  fmt.Errorf("Spread insufficient domains must be \"block\" or \"ignore\"; got \"wait\""),
+			name: "Invalid insufficient domains",
+		},
+		{
+			spread: &Spread{
+				Attribute:  "${node.datacenter}",
+				Weight:     50,
+				MinDomains: 3,
+			},
+			err:  fmt.Errorf("Spread min domains and insufficient domains require a max skew"),
+			name: "Min domains without max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute:           "${meta.rack}",
+				Weight:              50,
+				MaxSkew:             1,
+				MinDomains:          3,
+				InsufficientDomains: SpreadInsufficientDomainsIgnore,
+			},
+			err:  nil,
+			name: "Valid max skew",
+		},
 	}
 
 	for _, tc := range testCases {
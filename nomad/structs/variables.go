@@ -46,6 +46,24 @@ const (
 	// Reply: VariablesRenewLockResponse
 	VariablesRenewLockRPCMethod = "Variables.RenewLock"
 
+	// VariablesHistoryRPCMethod is the RPC method for listing the previous
+	// versions of a variable retained in the state store.
+	//
+	// Args: VariablesHistoryRequest
+	// Reply: VariablesHistoryResponse
+	VariablesHistoryRPCMethod = "Variables.History"
+
+	// VariablesRestoreRPCMethod is the RPC method for restoring a previous
+	// version of a variable as its current value.
+	//
+	// Args: VariablesRestoreRequest
+	// Reply: VariablesApplyResponse
+	VariablesRestoreRPCMethod = "Variables.Restore"
+
+	// maxVariableRetainVersions is the maximum number of previous versions of
+	// a single variable that a namespace can be configured to retain.
+	maxVariableRetainVersions = 100
+
 	// maxVariableSize is the maximum size of the unencrypted contents of a
 	// variable. This size is deliberately set low and is not configurable, to
 	// discourage DoS'ing the cluster
@@ -700,3 +718,64 @@ type VariablesRenewLockResponse struct {
 	VarMeta *VariableMetadata
 	WriteMeta
 }
+
+// VariablesHistoryRequest is used to list the previous versions of the
+// variable at Path.
+type VariablesHistoryRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesHistoryResponse returns the metadata of the previous versions of
+// a variable, newest first. The ModifyIndex of each version identifies it in
+// a VariablesRestoreRequest.
+type VariablesHistoryResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesRestoreRequest is used to make a previous version of the variable
+// at Path its current value.
+type VariablesRestoreRequest struct {
+	Path string
+
+	// Version is the ModifyIndex of the previous version to restore.
+	Version uint64
+
+	WriteRequest
+}
+
+func (v *VariablesRestoreRequest) Validate() error {
+	var mErr multierror.Error
+
+	if v.Path == "" {
+		mErr.Errors = append(mErr.Errors, errNoPath)
+	}
+	if v.Version == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("missing version"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// VariableVersionKey identifies a single previous version of a variable.
+type VariableVersionKey struct {
+	Namespace string
+	Path      string
+	Version   uint64
+}
+
+// VariableVersionsDeleteRequest is used by the core scheduler to garbage
+// collect previous versions of variables.
+type VariableVersionsDeleteRequest struct {
+	Versions []*VariableVersionKey
+	WriteRequest
+}
+
+// VariableVersionsRekeyRequest is used by the core scheduler to replace the
+// data of previous versions of variables with the data re-encrypted with the
+// active root key.
+type VariableVersionsRekeyRequest struct {
+	Versions []*VariableEncrypted
+	WriteRequest
+}
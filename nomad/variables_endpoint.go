@@ -34,6 +34,7 @@ var (
 	errLockOnVarCreation = structs.NewErrRPCCoded(http.StatusBadRequest, "variable should not contain lock definition")
 	errItemsOnRelease    = structs.NewErrRPCCoded(http.StatusBadRequest, "lock release operation doesn't take variable items")
 	errNoPath            = structs.NewErrRPCCoded(http.StatusBadRequest, "delete requires a Path")
+	errVersionNotFound   = structs.NewErrRPCCoded(http.StatusNotFound, "variable version doesn't exist")
 )
 
 type variableTimers interface {
@@ -106,7 +107,16 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
 	}
 
+	return sv.apply(args, aclObj, reply)
+}
+
+// apply encrypts and writes a validated variable request to raft, and
+// populates the reply according to the caller's permissions.
+func (sv *Variables) apply(args *structs.VariablesApplyRequest, aclObj *acl.ACL,
+	reply *structs.VariablesApplyResponse) error {
+
 	var ev *structs.VariableEncrypted
+	var err error
 
 	switch args.Op {
 	case structs.VarOpSet, structs.VarOpCAS, structs.VarOpLockAcquire,
@@ -165,6 +175,128 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 	return nil
 }
 
+// Restore is used to make a previous version of a variable its current value.
+// The restored value is written as a new version of the variable, so the
+// value it replaces is retained in turn.
+func (sv *Variables) Restore(args *structs.VariablesRestoreRequest, reply *structs.VariablesApplyResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesRestoreRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "restore"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	// The restored items are returned to the caller, so restoring a version
+	// requires being able to read it as well as write the variable.
+	namespace := args.RequestNamespace()
+	if !hasReadPermission(aclObj, namespace, args.Path) {
+		return structs.ErrPermissionDenied
+	}
+	err = hasOperationPermissions(aclObj, namespace, args.Path, structs.VarOpSet)
+	if err != nil {
+		return err
+	}
+
+	version, err := sv.srv.State().GetVariableVersion(nil, namespace, args.Path, args.Version)
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		return errVersionNotFound
+	}
+
+	dv, err := sv.decrypt(version)
+	if err != nil {
+		return fmt.Errorf("variable error: decrypt: %w", err)
+	}
+
+	applyArgs := &structs.VariablesApplyRequest{
+		Op: structs.VarOpSet,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: namespace,
+				Path:      args.Path,
+			},
+			Items: dv.Items,
+		},
+		WriteRequest: args.WriteRequest,
+	}
+
+	err = canonicalizeAndValidate(applyArgs)
+	if err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	return sv.apply(applyArgs, aclObj, reply)
+}
+
+// ReapVersions is an internal endpoint used by the core scheduler to garbage
+// collect previous versions of variables.
+func (sv *Variables) ReapVersions(args *structs.VariableVersionsDeleteRequest,
+	reply *structs.GenericResponse) error {
+
+	aclObj, err := sv.srv.AuthenticateServerOnly(sv.ctx, args)
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if err != nil || !aclObj.AllowServerOp() {
+		return structs.ErrPermissionDenied
+	}
+
+	if done, err := sv.srv.forward("Variables.ReapVersions", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "reap_versions"}, time.Now())
+
+	// Update via Raft
+	_, index, err := sv.srv.raftApply(structs.VariableVersionsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// RekeyVersions is an internal endpoint used by the core scheduler to replace
+// the data of previous versions of variables with the data re-encrypted with
+// the active root key.
+func (sv *Variables) RekeyVersions(args *structs.VariableVersionsRekeyRequest,
+	reply *structs.GenericResponse) error {
+
+	aclObj, err := sv.srv.AuthenticateServerOnly(sv.ctx, args)
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if err != nil || !aclObj.AllowServerOp() {
+		return structs.ErrPermissionDenied
+	}
+
+	if done, err := sv.srv.forward("Variables.RekeyVersions", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "rekey_versions"}, time.Now())
+
+	// Update via Raft
+	_, index, err := sv.srv.raftApply(structs.VariableVersionsRekeyRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
 func hasReadPermission(aclObj *acl.ACL, namespace, path string) bool {
 	return aclObj.AllowVariableOperation(namespace,
 		path, acl.VariablesCapabilityRead, nil)
@@ -382,6 +514,52 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 	return sv.srv.blockingRPC(&opts)
 }
 
+// History is used to list the previous versions of a variable, newest first.
+// Only the metadata of each version is returned; the ModifyIndex of a version
+// can be passed to Restore to make it the current value.
+func (sv *Variables) History(
+	args *structs.VariablesHistoryRequest,
+	reply *structs.VariablesHistoryResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesHistoryRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "history"}, time.Now())
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyList,
+		auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())) {
+		return structs.ErrPermissionDenied
+	}
+
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+			versions, err := stateStore.GetVariableVersions(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = make([]*structs.VariableMetadata, 0, len(versions))
+			for _, version := range versions {
+				reply.Data = append(reply.Data, version.VariableMetadata.Copy())
+			}
+
+			return sv.srv.setReplyQueryMeta(stateStore, state.TableVariableVersions, &reply.QueryMeta)
+		},
+	})
+}
+
 // List is used to list variables held within state. It supports single
 // and wildcard namespace listings.
 func (sv *Variables) List(
@@ -1684,3 +1684,112 @@ func TestVariablesEndpoint_RenewLock(t *testing.T) {
 		must.NoError(t, err)
 	})
 }
+
+func TestVariablesEndpoint_HistoryAndRestore(t *testing.T) {
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+	state := srv.fsm.State()
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		RetainVersions: 5,
+	}
+	must.NoError(t, state.UpsertNamespaces(100, []*structs.Namespace{ns}))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 101, "read",
+		mock.NamespacePolicyWithVariables(ns.Name, "", []string{},
+			map[string][]string{"app/*": {"list", "read"}}))
+	writeToken := mock.CreatePolicyAndToken(t, state, 102, "write",
+		mock.NamespacePolicyWithVariables(ns.Name, "", []string{},
+			map[string][]string{"app/*": {"list", "read", "write"}}))
+
+	path := "app/config"
+	put := func(value string) {
+		t.Helper()
+		req := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{
+					Namespace: ns.Name,
+					Path:      path,
+				},
+				Items: structs.VariableItems{"value": value},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: ns.Name,
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, req, &resp))
+		must.True(t, resp.IsOk())
+	}
+	put("v1")
+	put("v2")
+	put("v3")
+
+	history := func(token string) ([]*structs.VariableMetadata, error) {
+		req := &structs.VariablesHistoryRequest{
+			Path: path,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: ns.Name,
+				AuthToken: token,
+			},
+		}
+		var resp structs.VariablesHistoryResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.VariablesHistoryRPCMethod, req, &resp)
+		return resp.Data, err
+	}
+
+	_, err := history(uuid.Generate())
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	versions, err := history(readToken.SecretID)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Greater(t, versions[1].ModifyIndex, versions[0].ModifyIndex)
+	oldest := versions[1].ModifyIndex
+
+	restore := func(token string, version uint64) (*structs.VariablesApplyResponse, error) {
+		req := &structs.VariablesRestoreRequest{
+			Path:    path,
+			Version: version,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: ns.Name,
+				AuthToken: token,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.VariablesRestoreRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	_, err = restore(readToken.SecretID, oldest)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	_, err = restore(writeToken.SecretID, 1)
+	must.EqError(t, err, errVersionNotFound.Error())
+
+	resp, err := restore(writeToken.SecretID, oldest)
+	must.NoError(t, err)
+	must.True(t, resp.IsOk())
+	must.Eq(t, structs.VariableItems{"value": "v1"}, resp.Output.Items)
+
+	current, err := state.GetVariable(nil, ns.Name, path)
+	must.NoError(t, err)
+	must.Eq(t, resp.Output.ModifyIndex, current.ModifyIndex)
+
+	// The value replaced by the restore is retained in turn
+	versions, err = history(readToken.SecretID)
+	must.NoError(t, err)
+	must.Len(t, 3, versions)
+}
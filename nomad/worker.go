@@ -676,6 +676,7 @@ func (w *Worker) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, sstructs.S
 			Region: w.srv.config.Region,
 		},
 	}
+	req.SetTraceContext(plan.TraceContext)
 	var resp structs.PlanResponse
 
 SUBMIT:
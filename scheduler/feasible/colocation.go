@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package feasible
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// colocationTerm is a colocated_with or not_colocated_with constraint or
+// affinity along with its parsed selector.
+type colocationTerm struct {
+	operand  string
+	target   string
+	weight   int8
+	selector *structs.ColocationSelector
+}
+
+// newColocationTerm returns the term for the operand and target, or nil if the
+// operand isn't a colocation operand or the selector is invalid.
+func newColocationTerm(operand, target string, weight int8) *colocationTerm {
+	if operand != structs.ConstraintColocatedWith && operand != structs.ConstraintNotColocatedWith {
+		return nil
+	}
+	selector, err := structs.ParseColocationSelector(target)
+	if err != nil {
+		return nil
+	}
+	return &colocationTerm{
+		operand:  operand,
+		target:   target,
+		weight:   weight,
+		selector: selector,
+	}
+}
+
+// satisfiedBy returns whether the proposed allocations of a node satisfy the
+// term. The allocations of the job being placed are never selected.
+func (t *colocationTerm) satisfiedBy(job *structs.Job, proposed []*structs.Allocation) bool {
+	found := false
+	for _, alloc := range proposed {
+		if alloc.JobID == job.ID && alloc.Namespace == job.Namespace {
+			continue
+		}
+		if t.selector.Matches(alloc, job.Namespace) {
+			found = true
+			break
+		}
+	}
+	if t.operand == structs.ConstraintNotColocatedWith {
+		return !found
+	}
+	return found
+}
+
+func (t *colocationTerm) String() string {
+	return fmt.Sprintf("%s %s", t.operand, t.target)
+}
+
+// ColocationIterator is a FeasibleIterator which returns nodes that pass the
+// colocated_with and not_colocated_with constraints. The constraints select
+// the allocations of other jobs that must, or must not, be running on a node
+// for it to be feasible.
+type ColocationIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+
+	jobTerms []*colocationTerm
+	terms    []*colocationTerm
+}
+
+// NewColocationIterator creates a ColocationIterator from a source.
+func NewColocationIterator(ctx Context, source FeasibleIterator) *ColocationIterator {
+	return &ColocationIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *ColocationIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobTerms = nil
+	for _, c := range job.Constraints {
+		if term := newColocationTerm(c.Operand, c.RTarget, 0); term != nil {
+			iter.jobTerms = append(iter.jobTerms, term)
+		}
+	}
+}
+
+func (iter *ColocationIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.terms = append([]*colocationTerm(nil), iter.jobTerms...)
+	for _, c := range TaskGroupConstraints(tg).Constraints {
+		if term := newColocationTerm(c.Operand, c.RTarget, 0); term != nil {
+			iter.terms = append(iter.terms, term)
+		}
+	}
+}
+
+func (iter *ColocationIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.terms) == 0 {
+			return option
+		}
+
+		if iter.satisfiesColocation(option) {
+			return option
+		}
+	}
+}
+
+// satisfiesColocation returns whether the option satisfies every colocation
+// constraint. If not it will be filtered.
+func (iter *ColocationIterator) satisfiesColocation(option *structs.Node) bool {
+	proposed, err := iter.ctx.ProposedAllocs(option.ID)
+	if err != nil {
+		iter.ctx.Logger().Named("colocation").Error("failed to get proposed allocations", "error", err)
+		return false
+	}
+
+	for _, term := range iter.terms {
+		if !term.satisfiedBy(iter.job, proposed) {
+			iter.ctx.Metrics().FilterNode(option, term.String())
+			return false
+		}
+	}
+	return true
+}
+
+func (iter *ColocationIterator) Reset() {
+	iter.source.Reset()
+}
+
+// ColocationAffinityIterator is a RankIterator that applies a weighted score
+// according to whether nodes satisfy the colocated_with and
+// not_colocated_with affinities of the job or task group.
+type ColocationAffinityIterator struct {
+	ctx      Context
+	source   RankIterator
+	job      *structs.Job
+	jobTerms []*colocationTerm
+	terms    []*colocationTerm
+}
+
+// NewColocationAffinityIterator creates a ColocationAffinityIterator from a
+// source.
+func NewColocationAffinityIterator(ctx Context, source RankIterator) *ColocationAffinityIterator {
+	return &ColocationAffinityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *ColocationAffinityIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobTerms = nil
+	for _, a := range job.Affinities {
+		if term := newColocationTerm(a.Operand, a.RTarget, a.Weight); term != nil {
+			iter.jobTerms = append(iter.jobTerms, term)
+		}
+	}
+}
+
+func (iter *ColocationAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.terms = append([]*colocationTerm(nil), iter.jobTerms...)
+	add := func(affinities []*structs.Affinity) {
+		for _, a := range affinities {
+			if term := newColocationTerm(a.Operand, a.RTarget, a.Weight); term != nil {
+				iter.terms = append(iter.terms, term)
+			}
+		}
+	}
+	add(tg.Affinities)
+	for _, task := range tg.Tasks {
+		add(task.Affinities)
+	}
+}
+
+func (iter *ColocationAffinityIterator) Reset() {
+	iter.source.Reset()
+}
+
+func (iter *ColocationAffinityIterator) hasAffinities() bool {
+	return len(iter.terms) > 0
+}
+
+func (iter *ColocationAffinityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.hasAffinities() {
+		return option
+	}
+
+	proposed, err := option.ProposedAllocs(iter.ctx)
+	if err != nil {
+		iter.ctx.Logger().Named("colocation_affinity").Error("failed to get proposed allocations", "error", err)
+		return option
+	}
+
+	sumWeight, totalScore := 0.0, 0.0
+	for _, term := range iter.terms {
+		sumWeight += math.Abs(float64(term.weight))
+		if term.satisfiedBy(iter.job, proposed) {
+			totalScore += float64(term.weight)
+		}
+	}
+	normScore := totalScore / sumWeight
+	option.Scores = append(option.Scores, normScore)
+	iter.ctx.Metrics().ScoreNode(option.Node, "colocation-affinity", normScore)
+	return option
+}
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package feasible
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// colocationTestAlloc returns an allocation of the task group of the job.
+func colocationTestAlloc(job *structs.Job, tg string) *structs.Allocation {
+	return &structs.Allocation{
+		ID:        uuid.Generate(),
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Job:       job,
+		TaskGroup: tg,
+	}
+}
+
+func TestColocationIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	api := mock.Job()
+	api.ID = "api"
+	noisy := mock.BatchJob()
+	noisy.ID = "noisy"
+	noisy.Meta = map[string]string{"noisy": "true"}
+
+	job := mock.BatchJob()
+	job.ID = "cache"
+	tg := job.TaskGroups[0]
+
+	testCases := []struct {
+		name           string
+		jobConstraints []*structs.Constraint
+		tgConstraints  []*structs.Constraint
+		expNodes       []int
+		expReason      string
+	}{
+		{
+			name:     "no constraints",
+			expNodes: []int{0, 1, 2, 3},
+		},
+		{
+			name: "colocated with job",
+			jobConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintColocatedWith, RTarget: "job=api"},
+			},
+			expNodes:  []int{0, 1},
+			expReason: "colocated_with job=api",
+		},
+		{
+			name: "colocated with group",
+			tgConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintColocatedWith, RTarget: "job=api,group=web"},
+			},
+			expNodes:  []int{0},
+			expReason: "colocated_with job=api,group=web",
+		},
+		{
+			name: "not colocated with meta",
+			tgConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintNotColocatedWith, RTarget: "meta.noisy=true"},
+			},
+			expNodes:  []int{0, 2, 3},
+			expReason: "not_colocated_with meta.noisy=true",
+		},
+		{
+			name: "job and group",
+			jobConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintColocatedWith, RTarget: "job=api"},
+			},
+			tgConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintNotColocatedWith, RTarget: "meta.noisy=true"},
+			},
+			expNodes: []int{0},
+		},
+		{
+			name: "other namespace",
+			jobConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintColocatedWith, RTarget: "job=api,namespace=dev"},
+			},
+			expNodes: []int{},
+		},
+		{
+			name: "own allocations ignored",
+			jobConstraints: []*structs.Constraint{
+				{Operand: structs.ConstraintNotColocatedWith, RTarget: "job=cache"},
+			},
+			expNodes: []int{0, 1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ctx := MockContext(t)
+			nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node(), mock.Node()}
+
+			// api:web is on node 0, api:worker and noisy on node 1, and
+			// another allocation of the job being placed on node 2.
+			plan := ctx.Plan()
+			plan.NodeAllocation[nodes[0].ID] = []*structs.Allocation{
+				colocationTestAlloc(api, "web"),
+			}
+			plan.NodeAllocation[nodes[1].ID] = []*structs.Allocation{
+				colocationTestAlloc(api, "worker"),
+				colocationTestAlloc(noisy, noisy.TaskGroups[0].Name),
+			}
+			plan.NodeAllocation[nodes[2].ID] = []*structs.Allocation{
+				colocationTestAlloc(job, tg.Name),
+			}
+
+			j := job.Copy()
+			j.Constraints = tc.jobConstraints
+			group := j.TaskGroups[0]
+			group.Constraints = tc.tgConstraints
+
+			static := NewStaticIterator(ctx, nodes)
+			iter := NewColocationIterator(ctx, static)
+			iter.SetJob(j)
+			iter.SetTaskGroup(group)
+
+			out := collectFeasible(iter)
+			must.Len(t, len(tc.expNodes), out)
+			for i, n := range tc.expNodes {
+				must.Eq(t, nodes[n].ID, out[i].ID)
+			}
+			if tc.expReason != "" {
+				must.Positive(t, ctx.Metrics().ConstraintFiltered[tc.expReason])
+			}
+		})
+	}
+}
+
+func TestColocationAffinityIterator(t *testing.T) {
+	ci.Parallel(t)
+	_, ctx := MockContext(t)
+
+	api := mock.Job()
+	api.ID = "api"
+	noisy := mock.BatchJob()
+	noisy.ID = "noisy"
+
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].Node.ID] = []*structs.Allocation{
+		colocationTestAlloc(api, "web"),
+	}
+	plan.NodeAllocation[nodes[1].Node.ID] = []*structs.Allocation{
+		colocationTestAlloc(api, "web"),
+		colocationTestAlloc(noisy, noisy.TaskGroups[0].Name),
+	}
+
+	job := mock.Job()
+	job.Affinities = []*structs.Affinity{
+		{Operand: structs.ConstraintColocatedWith, RTarget: "job=api", Weight: 100},
+	}
+	tg := job.TaskGroups[0]
+	tg.Affinities = []*structs.Affinity{
+		{Operand: structs.ConstraintNotColocatedWith, RTarget: "job=noisy", Weight: 50},
+		{Operand: "=", LTarget: "${node.datacenter}", RTarget: "dc1", Weight: 50},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+	nodeAffinity := NewNodeAffinityIterator(ctx, static)
+	colocationAffinity := NewColocationAffinityIterator(ctx, nodeAffinity)
+	nodeAffinity.SetJob(job)
+	nodeAffinity.SetTaskGroup(tg)
+	colocationAffinity.SetJob(job)
+	colocationAffinity.SetTaskGroup(tg)
+	scoreNorm := NewScoreNormalizationIterator(ctx, colocationAffinity)
+	out := collectRanked(scoreNorm)
+	must.Len(t, 3, out)
+
+	// Every node matches the node affinity, so the colocation affinities are
+	// scored separately in the second score. Total colocation weight = 150.
+	must.Eq(t, []float64{1, 1}, out[0].Scores)
+	must.Eq(t, []float64{1, 2.0 / 3.0}, out[1].Scores)
+	must.Eq(t, []float64{1, 1.0 / 3.0}, out[2].Scores)
+
+	ctx.Metrics().PopulateScoreMetaData()
+	scores := ctx.Metrics().ScoreMetaData
+	must.Len(t, 3, scores)
+	for _, s := range scores {
+		must.MapContainsKey(t, s.Scores, "colocation-affinity")
+	}
+}
@@ -989,7 +989,8 @@ func resolveTarget(target string, node *structs.Node) (string, bool) {
 func checkConstraint(ctx ConstraintContext, operand string, lVal, rVal interface{}, lFound, rFound bool) bool {
 	// Check for constraints not handled by this checker.
 	switch operand {
-	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty:
+	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty,
+		structs.ConstraintColocatedWith, structs.ConstraintNotColocatedWith:
 		return true
 	default:
 		break
@@ -167,6 +167,10 @@ type BinPackIterator struct {
 	taskGroup              *structs.TaskGroup
 	memoryOversubscription bool
 	scoreFit               func(*structs.Node, *structs.ComparableResources) float64
+
+	// scoringWeights are the weights used by the weighted scheduler
+	// algorithm, which are recorded alongside each node's score.
+	scoringWeights *structs.SchedulerScoringWeights
 }
 
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
@@ -198,8 +202,16 @@ func (iter *BinPackIterator) SetSchedulerConfiguration(schedConfig *structs.Sche
 	// Set scoring function.
 	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
 	scoreFn := structs.ScoreFitBinPack
-	if algorithm == structs.SchedulerAlgorithmSpread {
+	iter.scoringWeights = nil
+	switch algorithm {
+	case structs.SchedulerAlgorithmSpread:
 		scoreFn = structs.ScoreFitSpread
+	case structs.SchedulerAlgorithmWeighted:
+		weights := schedConfig.EffectiveScoringWeights()
+		scoreFn = func(node *structs.Node, util *structs.ComparableResources) float64 {
+			return structs.ScoreFitWeighted(node, util, weights)
+		}
+		iter.scoringWeights = weights
 	}
 	iter.scoreFit = scoreFn
 
@@ -770,6 +782,9 @@ NEXTNODE:
 		normalizedFit := fitness / binPackingMaxFitScore
 		option.Scores = append(option.Scores, normalizedFit)
 		iter.ctx.Metrics().ScoreNode(option.Node, "binpack", normalizedFit)
+		if iter.scoringWeights != nil {
+			iter.ctx.Metrics().ScoringWeights = iter.scoringWeights
+		}
 
 		// Score the device affinity
 		if totalDeviceAffinityWeight != 0 {
@@ -927,18 +942,32 @@ func (iter *NodeAffinityIterator) SetJob(job *structs.Job) {
 func (iter *NodeAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
 	// Merge job affinities
 	if iter.jobAffinities != nil {
-		iter.affinities = append(iter.affinities, iter.jobAffinities...)
+		iter.affinities = appendNodeAffinities(iter.affinities, iter.jobAffinities)
 	}
 
 	// Merge task group affinities and task affinities
 	if tg.Affinities != nil {
-		iter.affinities = append(iter.affinities, tg.Affinities...)
+		iter.affinities = appendNodeAffinities(iter.affinities, tg.Affinities)
 	}
 	for _, task := range tg.Tasks {
 		if task.Affinities != nil {
-			iter.affinities = append(iter.affinities, task.Affinities...)
+			iter.affinities = appendNodeAffinities(iter.affinities, task.Affinities)
+		}
+	}
+}
+
+// appendNodeAffinities appends the affinities that match against node
+// attributes. Colocation affinities are scored by the
+// ColocationAffinityIterator instead.
+func appendNodeAffinities(dst, affinities []*structs.Affinity) []*structs.Affinity {
+	for _, a := range affinities {
+		switch a.Operand {
+		case structs.ConstraintColocatedWith, structs.ConstraintNotColocatedWith:
+		default:
+			dst = append(dst, a)
 		}
 	}
+	return dst
 }
 
 func (iter *NodeAffinityIterator) Reset() {
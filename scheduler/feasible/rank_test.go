@@ -147,6 +147,100 @@ func TestBinPackIterator_NoExistingAlloc(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_Weighted(t *testing.T) {
+	ci.Parallel(t)
+
+	newNodes := func() []*RankedNode {
+		return []*RankedNode{
+			{
+				Node: &structs.Node{
+					// Plenty of CPU, little memory
+					ID: "cpu",
+					NodeResources: &structs.NodeResources{
+						Processors: processorResources4096,
+						Cpu:        legacyCpuResources4096,
+						Memory: structs.NodeMemoryResources{
+							MemoryMB: 2048,
+						},
+					},
+				},
+			},
+			{
+				Node: &structs.Node{
+					// Little CPU, plenty of memory
+					ID: "memory",
+					NodeResources: &structs.NodeResources{
+						Processors: processorResources2048,
+						Cpu:        legacyCpuResources2048,
+						Memory: structs.NodeMemoryResources{
+							MemoryMB: 8192,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		weights  *structs.SchedulerScoringWeights
+		expected string
+	}{
+		{
+			name:     "cpu weighted",
+			weights:  &structs.SchedulerScoringWeights{CPU: 3, Memory: 1},
+			expected: "cpu",
+		},
+		{
+			name:     "memory weighted",
+			weights:  &structs.SchedulerScoringWeights{CPU: 1, Memory: 3},
+			expected: "memory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ctx := MockContext(t)
+			nodes := newNodes()
+			static := NewStaticRankIterator(ctx, nodes)
+
+			binp := NewBinPackIterator(ctx, static, false, 0)
+			binp.SetTaskGroup(taskGroup)
+			binp.SetSchedulerConfiguration(&structs.SchedulerConfiguration{
+				SchedulerAlgorithm: structs.SchedulerAlgorithmWeighted,
+				ScoringWeights:     tc.weights,
+			})
+			scoreNorm := NewScoreNormalizationIterator(ctx, binp)
+
+			out := collectRanked(scoreNorm)
+			must.Len(t, 2, out)
+			sort.Slice(out, func(i, j int) bool { return out[i].FinalScore > out[j].FinalScore })
+			must.Eq(t, tc.expected, out[0].Node.ID)
+
+			// The weights are recorded in the metrics, not as node scores
+			must.Eq(t, tc.weights, ctx.Metrics().ScoringWeights)
+			ctx.Metrics().PopulateScoreMetaData()
+			for _, meta := range ctx.Metrics().ScoreMetaData {
+				must.MapNotContainsKey(t, meta.Scores, "weight.cpu")
+				must.MapNotContainsKey(t, meta.Scores, "weight.memory")
+			}
+		})
+	}
+}
+
 // TestBinPackIterator_NoExistingAlloc_MixedReserve asserts that node's with
 // reserved resources are scored equivalent to as if they had a lower amount of
 // resources.
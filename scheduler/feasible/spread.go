@@ -4,6 +4,9 @@
 package feasible
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -291,3 +294,219 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// SpreadSkewIterator is a FeasibleIterator which filters out the nodes whose
+// placement would exceed the max skew of a spread of the task group. The skew
+// is the difference between the number of allocations of the task group on
+// the value of the spread attribute of the node and on the least used value.
+type SpreadSkewIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// domains is a memoized map from task group to attribute to the values
+	// of the attribute of the base nodes feasible for the task group
+	nodes   []*structs.Node
+	domains map[string]map[string]map[string]struct{}
+
+	// The checkers find the base nodes feasible for the task group. They
+	// don't record metrics as they don't filter placements.
+	jobConstraint       *ConstraintChecker
+	taskGroupDrivers    *DriverChecker
+	taskGroupConstraint *ConstraintChecker
+	taskGroupDevices    *DeviceChecker
+
+	// groupSkews is a memoized map from task group to the spreads with a max
+	// skew that apply to it
+	groupSkews map[string][]*spreadSkew
+}
+
+// spreadSkew tracks the allocations of a task group for a spread with a max
+// skew.
+type spreadSkew struct {
+	spread *structs.Spread
+	pset   *propertySet
+}
+
+// NewSpreadSkewIterator creates a SpreadSkewIterator from a source.
+func NewSpreadSkewIterator(ctx Context, source FeasibleIterator) *SpreadSkewIterator {
+	checkCtx := &noMetricsContext{Context: ctx}
+	return &SpreadSkewIterator{
+		ctx:                 ctx,
+		source:              source,
+		domains:             make(map[string]map[string]map[string]struct{}),
+		groupSkews:          make(map[string][]*spreadSkew),
+		jobConstraint:       NewConstraintChecker(checkCtx, nil),
+		taskGroupDrivers:    NewDriverChecker(checkCtx, nil),
+		taskGroupConstraint: NewConstraintChecker(checkCtx, nil),
+		taskGroupDevices:    NewDeviceChecker(checkCtx),
+	}
+}
+
+// SetNodes sets the base nodes, whose attribute values are the domains the
+// allocations are spread over.
+func (iter *SpreadSkewIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	iter.domains = make(map[string]map[string]map[string]struct{})
+}
+
+func (iter *SpreadSkewIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.groupSkews = make(map[string][]*spreadSkew)
+	iter.domains = make(map[string]map[string]map[string]struct{})
+	iter.jobConstraint.SetConstraints(job.Constraints)
+}
+
+func (iter *SpreadSkewIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+
+	tgConstr := TaskGroupConstraints(tg)
+	iter.taskGroupDrivers.SetDrivers(tgConstr.Drivers)
+	iter.taskGroupConstraint.SetConstraints(tgConstr.Constraints)
+	iter.taskGroupDevices.SetTaskGroup(tg)
+
+	if _, ok := iter.groupSkews[tg.Name]; ok {
+		return
+	}
+
+	skews := []*spreadSkew{}
+	if iter.job == nil {
+		iter.groupSkews[tg.Name] = skews
+		return
+	}
+	for _, spread := range slices.Concat(iter.job.Spreads, tg.Spreads) {
+		if spread.MaxSkew == 0 {
+			continue
+		}
+		pset := NewPropertySet(iter.ctx, iter.job)
+		pset.SetTargetAttribute(spread.Attribute, tg.Name)
+		skews = append(skews, &spreadSkew{spread: spread, pset: pset})
+	}
+	iter.groupSkews[tg.Name] = skews
+}
+
+func (iter *SpreadSkewIterator) Next() *structs.Node {
+	for {
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.groupSkews[iter.tg.Name]) == 0 {
+			return option
+		}
+
+		if !iter.satisfiesSkews(option) {
+			continue
+		}
+		return option
+	}
+}
+
+// satisfiesSkews returns whether placing on the option keeps the skew of every
+// spread within its max skew. If not the option is filtered.
+func (iter *SpreadSkewIterator) satisfiesSkews(option *structs.Node) bool {
+	for _, s := range iter.groupSkews[iter.tg.Name] {
+		value, errorMsg, used := s.pset.UsedCount(option, iter.tg.Name)
+		if errorMsg != "" {
+			iter.ctx.Metrics().FilterNode(option, fmt.Sprintf("spread: %s", errorMsg))
+			return false
+		}
+
+		skew := int(used) + 1 - iter.minCount(s)
+		if skew > s.spread.MaxSkew {
+			iter.ctx.Metrics().FilterNode(option, fmt.Sprintf("spread: %s=%s would exceed max skew of %d",
+				s.spread.Attribute, value, s.spread.MaxSkew))
+			return false
+		}
+	}
+	return true
+}
+
+// minCount returns the number of allocations on the least used value of the
+// spread attribute.
+func (iter *SpreadSkewIterator) minCount(s *spreadSkew) int {
+	used := s.pset.GetCombinedUseMap()
+	domains := iter.attributeDomains(s.spread.Attribute)
+
+	// Values used by allocations on nodes that are no longer eligible or
+	// feasible are still domains of the spread.
+	count := len(domains)
+	for value := range used {
+		if _, ok := domains[value]; !ok {
+			count++
+		}
+	}
+	if count < s.spread.MinDomains && s.spread.InsufficientDomains != structs.SpreadInsufficientDomainsIgnore {
+		return 0
+	}
+
+	minCount := -1
+	for value := range domains {
+		if n := int(used[value]); minCount == -1 || n < minCount {
+			minCount = n
+		}
+	}
+	for value, n := range used {
+		if _, ok := domains[value]; !ok && (minCount == -1 || int(n) < minCount) {
+			minCount = int(n)
+		}
+	}
+	return max(minCount, 0)
+}
+
+// attributeDomains returns the values of the attribute of the base nodes
+// feasible for the task group. Values only found on nodes which can't run the
+// task group, such as nodes missing its driver, aren't domains the allocations
+// can be spread over.
+func (iter *SpreadSkewIterator) attributeDomains(attribute string) map[string]struct{} {
+	tgDomains, ok := iter.domains[iter.tg.Name]
+	if !ok {
+		tgDomains = make(map[string]map[string]struct{})
+		iter.domains[iter.tg.Name] = tgDomains
+	}
+	if domains, ok := tgDomains[attribute]; ok {
+		return domains
+	}
+
+	domains := make(map[string]struct{})
+	for _, node := range iter.nodes {
+		value, ok := getProperty(node, attribute)
+		if !ok {
+			continue
+		}
+		if _, ok := domains[value]; ok || !iter.feasible(node) {
+			continue
+		}
+		domains[value] = struct{}{}
+	}
+	tgDomains[attribute] = domains
+	return domains
+}
+
+// feasible returns whether the node satisfies the constraints of the job and
+// the drivers, constraints and devices of the task group.
+func (iter *SpreadSkewIterator) feasible(node *structs.Node) bool {
+	return iter.jobConstraint.Feasible(node) &&
+		iter.taskGroupDrivers.Feasible(node) &&
+		iter.taskGroupConstraint.Feasible(node) &&
+		iter.taskGroupDevices.Feasible(node)
+}
+
+func (iter *SpreadSkewIterator) Reset() {
+	iter.source.Reset()
+	for _, skews := range iter.groupSkews {
+		for _, s := range skews {
+			s.pset.PopulateProposed()
+		}
+	}
+}
+
+// noMetricsContext is a Context whose metrics are discarded, for feasibility
+// checks that don't filter placements.
+type noMetricsContext struct {
+	Context
+}
+
+func (*noMetricsContext) Metrics() *structs.AllocMetric {
+	return new(structs.AllocMetric)
+}
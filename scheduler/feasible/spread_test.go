@@ -683,3 +683,187 @@ func Test_evenSpreadScoreBoost(t *testing.T) {
 	must.False(t, math.IsInf(boost, 1))
 	must.Eq(t, 1.0, boost)
 }
+
+func TestSpreadSkewIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	// alloc returns an allocation of the task group on the node.
+	alloc := func(job *structs.Job, node *structs.Node) *structs.Allocation {
+		return &structs.Allocation{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: job.TaskGroups[0].Name,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			NodeID:    node.ID,
+		}
+	}
+
+	testCases := []struct {
+		name string
+		// allocs is the number of existing allocations on the first node of
+		// each datacenter
+		allocs  map[string]int
+		spread  *structs.Spread
+		expDCs  []string
+		expFilt map[string]int
+	}{
+		{
+			name:   "over max skew",
+			allocs: map[string]int{"dc1": 2},
+			spread: &structs.Spread{MaxSkew: 1},
+			expDCs: []string{"dc2"},
+			expFilt: map[string]int{
+				"spread: ${node.datacenter}=dc1 would exceed max skew of 1": 2,
+			},
+		},
+		{
+			name:   "within max skew",
+			allocs: map[string]int{"dc1": 2},
+			spread: &structs.Spread{MaxSkew: 3},
+			expDCs: []string{"dc1", "dc1", "dc2"},
+		},
+		{
+			name:   "enough domains",
+			allocs: map[string]int{"dc1": 1, "dc2": 1},
+			spread: &structs.Spread{MaxSkew: 1, MinDomains: 2},
+			expDCs: []string{"dc1", "dc1", "dc2"},
+		},
+		{
+			name:   "insufficient domains block",
+			allocs: map[string]int{"dc1": 1, "dc2": 1},
+			spread: &structs.Spread{MaxSkew: 1, MinDomains: 3},
+			expDCs: nil,
+			expFilt: map[string]int{
+				"spread: ${node.datacenter}=dc1 would exceed max skew of 1": 2,
+				"spread: ${node.datacenter}=dc2 would exceed max skew of 1": 1,
+			},
+		},
+		{
+			name:   "insufficient domains ignore",
+			allocs: map[string]int{"dc1": 1, "dc2": 1},
+			spread: &structs.Spread{
+				MaxSkew:             1,
+				MinDomains:          3,
+				InsufficientDomains: structs.SpreadInsufficientDomainsIgnore,
+			},
+			expDCs: []string{"dc1", "dc1", "dc2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, ctx := MockContext(t)
+
+			var nodes []*structs.Node
+			for i, dc := range []string{"dc1", "dc1", "dc2"} {
+				node := mock.Node()
+				node.Datacenter = dc
+				must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+				nodes = append(nodes, node)
+			}
+
+			job := mock.Job()
+			tc.spread.Attribute = "${node.datacenter}"
+			tc.spread.Weight = 50
+			job.TaskGroups[0].Spreads = []*structs.Spread{tc.spread}
+
+			var allocs []*structs.Allocation
+			for dc, n := range tc.allocs {
+				node := nodes[0]
+				if dc == "dc2" {
+					node = nodes[2]
+				}
+				for range n {
+					allocs = append(allocs, alloc(job, node))
+				}
+			}
+			must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+			iter := NewSpreadSkewIterator(ctx, NewStaticIterator(ctx, nodes))
+			iter.SetNodes(nodes)
+			iter.SetJob(job)
+			iter.SetTaskGroup(job.TaskGroups[0])
+
+			var dcs []string
+			for _, node := range collectFeasible(iter) {
+				dcs = append(dcs, node.Datacenter)
+			}
+			must.SliceContainsAll(t, tc.expDCs, dcs)
+			must.Eq(t, len(tc.expFilt) == 0, ctx.Metrics().ConstraintFiltered == nil)
+			for reason, n := range tc.expFilt {
+				must.Eq(t, n, ctx.Metrics().ConstraintFiltered[reason])
+			}
+		})
+	}
+
+	t.Run("proposed allocations", func(t *testing.T) {
+		state, ctx := MockContext(t)
+
+		var nodes []*structs.Node
+		for i, dc := range []string{"dc1", "dc2"} {
+			node := mock.Node()
+			node.Datacenter = dc
+			must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+			nodes = append(nodes, node)
+		}
+
+		job := mock.Job()
+		job.Spreads = []*structs.Spread{{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+		}}
+
+		iter := NewSpreadSkewIterator(ctx, NewStaticIterator(ctx, nodes))
+		iter.SetNodes(nodes)
+		iter.SetJob(job)
+		iter.SetTaskGroup(job.TaskGroups[0])
+		must.Len(t, 2, collectFeasible(iter))
+
+		// Propose an allocation in dc1, after which only dc2 is feasible
+		ctx.Plan().NodeAllocation[nodes[0].ID] = []*structs.Allocation{alloc(job, nodes[0])}
+		iter.Reset()
+		out := collectFeasible(iter)
+		must.Len(t, 1, out)
+		must.Eq(t, "dc2", out[0].Datacenter)
+	})
+	t.Run("infeasible domains", func(t *testing.T) {
+		state, ctx := MockContext(t)
+
+		var nodes []*structs.Node
+		for i, dc := range []string{"dc1", "dc2", "dc3"} {
+			node := mock.Node()
+			node.Datacenter = dc
+			must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+			nodes = append(nodes, node)
+		}
+
+		// The node in dc3 can't run the task group
+		delete(nodes[2].Drivers, "exec")
+		delete(nodes[2].Attributes, "driver.exec")
+
+		job := mock.Job()
+		job.Spreads = []*structs.Spread{{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+		}}
+		must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{
+			alloc(job, nodes[0]), alloc(job, nodes[1]),
+		}))
+
+		// dc3 isn't a domain of the spread, so placing in dc1 or dc2 stays
+		// within the max skew
+		iter := NewSpreadSkewIterator(ctx, NewStaticIterator(ctx, nodes[:2]))
+		iter.SetNodes(nodes)
+		iter.SetJob(job)
+		iter.SetTaskGroup(job.TaskGroups[0])
+		must.Len(t, 2, collectFeasible(iter))
+
+		// Finding the domains doesn't record filtered nodes
+		must.Eq(t, 0, ctx.Metrics().NodesFiltered)
+		must.Nil(t, ctx.Metrics().ConstraintFiltered)
+	})
+}
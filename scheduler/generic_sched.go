@@ -6,6 +6,7 @@ package scheduler
 import (
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"time"
 
@@ -14,11 +15,13 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler/feasible"
 	"github.com/hashicorp/nomad/scheduler/reconciler"
 	sstructs "github.com/hashicorp/nomad/scheduler/structs"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -77,6 +80,18 @@ type GenericScheduler struct {
 	failedTGAllocs  map[string]*structs.AllocMetric
 	queuedAllocs    map[string]int
 	planAnnotations *structs.PlanAnnotations
+
+	// unmetDependencies are the dependencies of the job which haven't reached
+	// their condition, holding the rollout of the job.
+	unmetDependencies []*structs.JobDependency
+
+	// heldForDependencies is set when the rollout of the job was held because
+	// of its unmet dependencies.
+	heldForDependencies bool
+
+	// traceContext is the propagated context of the span processing the
+	// evaluation, which is attached to submitted plans.
+	traceContext map[string]string
 }
 
 // NewServiceScheduler is a factory function to instantiate a new service scheduler
@@ -116,6 +131,16 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 	// Store the evaluation
 	s.eval = eval
 
+	// The span is only recorded in the trace of the request which created
+	// the evaluation, so evaluations created by the servers aren't traced.
+	ctx, span := tracing.Continue(eval.TraceContext, "scheduler.Process",
+		trace.WithAttributes(tracing.EvalAttributes(eval)...),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+	s.traceContext = tracing.Inject(ctx)
+
 	// Update our logger with the eval's information
 	s.logger = s.logger.With("eval_id", eval.ID, "job_id", eval.JobID, "namespace", eval.Namespace)
 
@@ -128,7 +153,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerJobDependency, structs.EvalTriggerRebalance:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -137,6 +163,22 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 			s.deployment.GetID())
 	}
 
+	// Hold the rollout of a new version of the job until the jobs it depends
+	// on have reached their conditions. The rollout is held whatever the
+	// trigger of the evaluation, but lost or failed allocations are still
+	// replaced.
+	unmet, err := unmetJobDependencies(s.state, eval.Namespace, eval.JobID)
+	if err != nil {
+		return err
+	}
+	s.unmetDependencies = unmet
+	if len(eval.UnmetDependencies) != 0 {
+		// The dependencies the evaluation was blocked on have been met, or
+		// are replaced by the dependencies still unmet.
+		s.eval = eval.Copy()
+		s.eval.UnmetDependencies = nil
+	}
+
 	// Retry up to the maxScheduleAttempts and reset if progress is made.
 	progress := func() bool { return progressMade(s.planResult) }
 	limit := maxServiceScheduleAttempts
@@ -161,6 +203,16 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		return err
 	}
 
+	// If the rollout was held, the evaluation is complete and the blocked
+	// evaluation resumes it once the dependencies are met.
+	if s.heldForDependencies {
+		eval := s.eval.Copy()
+		eval.UnmetDependencies = s.unmetDependencies
+		return setStatus(s.logger, s.planner, eval, nil, s.blocked,
+			s.failedTGAllocs, s.planAnnotations, structs.EvalStatusComplete,
+			s.blocked.StatusDescription, s.queuedAllocs, s.deployment.GetID())
+	}
+
 	// If the current evaluation is a blocked evaluation and we didn't place
 	// everything, do not update the status to complete.
 	if s.eval.Status == structs.EvalStatusBlocked && len(s.failedTGAllocs) != 0 {
@@ -201,6 +253,44 @@ func (s *GenericScheduler) createBlockedEval(planFailure bool) error {
 	return s.planner.CreateEval(s.blocked)
 }
 
+// createDependencyBlockedEval creates a blocked eval which is unblocked when
+// the unmet dependencies of the job change and submits it to the planner.
+func (s *GenericScheduler) createDependencyBlockedEval() error {
+	s.blocked = s.eval.CreateDependencyBlockedEval(s.unmetDependencies)
+	s.blocked.StatusDescription = fmt.Sprintf("%s: %s", sstructs.DescBlockedEvalJobDependencies,
+		structs.JobDependenciesString(s.unmetDependencies))
+	return s.planner.CreateEval(s.blocked)
+}
+
+// holdForDependencies removes the results of the reconciler which roll out
+// the job while its dependencies are unmet: in-place and destructive updates,
+// canaries, new placements and the creation of a deployment. Replacements of
+// lost or failed allocations are kept so the job doesn't lose capacity, and
+// are placed with the version of the job of the allocation they replace.
+// It returns whether any result was held.
+func (s *GenericScheduler) holdForDependencies(result *reconciler.ReconcileResults) bool {
+	held := len(result.InplaceUpdate) != 0 || len(result.DestructiveUpdate) != 0
+	result.InplaceUpdate = nil
+	result.DestructiveUpdate = nil
+
+	place := make([]reconciler.AllocPlaceResult, 0, len(result.Place))
+	for _, p := range result.Place {
+		prev := p.PreviousAllocation()
+		if p.Canary() || prev == nil || prev.Job == nil || prev.Job.LookupTaskGroup(p.TaskGroup().Name) == nil {
+			held = true
+			continue
+		}
+		place = append(place, p)
+	}
+	result.Place = place
+
+	if result.Deployment != nil && result.Deployment.ID != s.deployment.GetID() {
+		result.Deployment = nil
+		held = true
+	}
+	return held
+}
+
 // process is wrapped in retryMax to iteratively run the handler until we have no
 // further work or we've made the maximum number of attempts.
 func (s *GenericScheduler) process() (bool, error) {
@@ -222,6 +312,7 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Create a plan
 	s.plan = s.eval.MakePlan(s.job)
+	s.plan.TraceContext = s.traceContext
 
 	if !s.batch {
 		// Get any existing deployment
@@ -233,6 +324,7 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Reset the failed allocations
 	s.failedTGAllocs = nil
+	s.heldForDependencies = false
 
 	// Create an evaluation context
 	s.ctx = feasible.NewEvalContext(s.eventsCh, s.state, s.plan, s.logger)
@@ -256,6 +348,17 @@ func (s *GenericScheduler) process() (bool, error) {
 	// be delayed, do that instead.
 	delayInstead := len(s.followUpEvals) > 0 && s.eval.WaitUntil.IsZero()
 
+	// If the rollout of the job was held, create a blocked evaluation to
+	// resume it once the dependencies are met. It also retries any failed
+	// placements, so no other blocked evaluation is needed.
+	if s.heldForDependencies && s.blocked == nil {
+		if err := s.createDependencyBlockedEval(); err != nil {
+			s.logger.Error("failed to make blocked eval", "error", err)
+			return false, err
+		}
+		s.logger.Debug("job dependencies not met, blocked eval created", "blocked_eval_id", s.blocked.ID)
+	}
+
 	if s.eval.Status != structs.EvalStatusBlocked && len(s.failedTGAllocs) != 0 && s.blocked == nil &&
 		!delayInstead {
 		if err := s.createBlockedEval(false); err != nil {
@@ -363,6 +466,10 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.logger.Debug("reconciled current state with desired state", result.Fields()...)
 	}
 
+	if len(s.unmetDependencies) != 0 {
+		s.heldForDependencies = s.holdForDependencies(result)
+	}
+
 	s.planAnnotations = &structs.PlanAnnotations{
 		DesiredTGUpdates: result.DesiredTGUpdates,
 	}
@@ -499,6 +606,10 @@ func (s *GenericScheduler) computePlacements(
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of gang scheduled task groups so they can be
+	// rolled back if any allocation of their gang can't be placed.
+	var gangPlacements []gangPlacement
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -514,8 +625,19 @@ func (s *GenericScheduler) computePlacements(
 			taskGroupNameIndex := nameIndex[tg.Name]
 
 			var downgradedJob *structs.Job
-
-			if missing.DowngradeNonCanary() {
+			allocDeploymentID := deploymentID
+
+			if len(s.unmetDependencies) != 0 {
+				// The rollout is held for the dependencies of the job, so
+				// the replacement keeps the version of the allocation it
+				// replaces.
+				prevJob := missing.PreviousAllocation().Job
+				if prevJob.Version != s.job.Version || prevJob.CreateIndex != s.job.CreateIndex {
+					tg = prevJob.LookupTaskGroup(tg.Name)
+					downgradedJob = prevJob
+					allocDeploymentID = ""
+				}
+			} else if missing.DowngradeNonCanary() {
 				jobDeploymentID, job, err := s.downgradedJobForPlacement(missing)
 				if err != nil {
 					return err
@@ -526,6 +648,7 @@ func (s *GenericScheduler) computePlacements(
 					tg = job.LookupTaskGroup(tg.Name)
 					downgradedJob = job
 					deploymentID = jobDeploymentID
+					allocDeploymentID = jobDeploymentID
 				} else {
 					jobVersion := -1
 					if job != nil {
@@ -572,7 +695,8 @@ func (s *GenericScheduler) computePlacements(
 			}
 
 			// Compute penalty nodes for rescheduled allocs
-			selectOptions := getSelectOptions(prevAllocation, preferredNode)
+			selectOptions := getSelectOptions(prevAllocation, preferredNode,
+				s.eval.TriggeredBy == structs.EvalTriggerRebalance)
 			selectOptions.AllocName = missing.Name()
 			option := s.selectNextOption(tg, selectOptions)
 
@@ -644,7 +768,7 @@ func (s *GenericScheduler) computePlacements(
 					Metrics:            s.ctx.Metrics(),
 					NodeID:             option.Node.ID,
 					NodeName:           option.Node.Name,
-					DeploymentID:       deploymentID,
+					DeploymentID:       allocDeploymentID,
 					TaskResources:      resources.OldTaskResources(),
 					AllocatedResources: resources,
 					DesiredStatus:      structs.AllocDesiredStatusRun,
@@ -678,11 +802,27 @@ func (s *GenericScheduler) computePlacements(
 					}
 				}
 
+				// Record the allocation a surge placement replaces so the
+				// pair is kept across reschedules of the surge allocation.
+				if id := missing.Replaces(); id != "" && s.deployment != nil {
+					alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+						Replaces: id,
+					}
+				}
+
 				s.handlePreemptions(option, alloc, missing)
 
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if tg.Gang != "" {
+					p := gangPlacement{alloc: alloc}
+					if stopPrevAlloc {
+						p.stopped = prevAllocation
+					}
+					gangPlacements = append(gangPlacements, p)
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -718,9 +858,58 @@ func (s *GenericScheduler) computePlacements(
 		}
 	}
 
+	s.rollbackFailedGangs(gangPlacements)
 	return nil
 }
 
+// gangPlacement is the placement of an allocation of a gang scheduled task
+// group, along with the allocation it stops.
+type gangPlacement struct {
+	alloc   *structs.Allocation
+	stopped *structs.Allocation
+}
+
+// rollbackFailedGangs removes the placements of every task group of the gangs
+// for which some allocation could not be placed, so that a gang is placed
+// entirely or not at all. The failure metrics of the rolled back task groups
+// record the task groups that could not be placed.
+func (s *GenericScheduler) rollbackFailedGangs(placements []gangPlacement) {
+	if len(placements) == 0 {
+		return
+	}
+
+	for _, groups := range s.job.Gangs() {
+		var failed []string
+		for _, name := range groups {
+			if _, ok := s.failedTGAllocs[name]; ok {
+				failed = append(failed, name)
+			}
+		}
+		if len(failed) == 0 {
+			continue
+		}
+
+		for _, p := range placements {
+			if !slices.Contains(groups, p.alloc.TaskGroup) {
+				continue
+			}
+
+			s.plan.RemoveAlloc(p.alloc)
+			if p.stopped != nil {
+				s.plan.RemoveUpdate(p.stopped)
+			}
+
+			if metric, ok := s.failedTGAllocs[p.alloc.TaskGroup]; ok {
+				metric.CoalescedFailures += 1
+				continue
+			}
+			metric := p.alloc.Metrics.Copy()
+			metric.GangFailed = failed
+			s.failedTGAllocs[p.alloc.TaskGroup] = metric
+		}
+	}
+}
+
 // swapAllocInPlan updates a plan to swap out an allocation that's already in
 // the plan with an updated definition of that allocation. The updated
 // definition should be a deep copy.
@@ -779,7 +968,7 @@ func needsToSetNodes(a, b *structs.Job) bool {
 }
 
 // getSelectOptions sets up preferred nodes and penalty nodes
-func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs.Node) *feasible.SelectOptions {
+func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs.Node, rebalance bool) *feasible.SelectOptions {
 	selectOptions := &feasible.SelectOptions{}
 	if prevAllocation != nil {
 		penaltyNodes := make(map[string]struct{})
@@ -789,6 +978,12 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 		if prevAllocation.ClientStatus == structs.AllocClientStatusFailed {
 			penaltyNodes[prevAllocation.NodeID] = struct{}{}
 		}
+
+		// If alloc is migrated by the rebalancer, penalize the node it is
+		// moved off so it isn't placed back on it.
+		if rebalance && prevAllocation.DesiredTransition.ShouldMigrate() {
+			penaltyNodes[prevAllocation.NodeID] = struct{}{}
+		}
 		if prevAllocation.RescheduleTracker != nil {
 			for _, reschedEvent := range prevAllocation.RescheduleTracker.Events {
 				penaltyNodes[reschedEvent.PrevNodeID] = struct{}{}
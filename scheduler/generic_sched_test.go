@@ -127,6 +127,173 @@ func TestServiceSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	for range 3 {
+		node := mock.Node()
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create the batch job depended on, which hasn't run yet
+	migrate := mock.BatchJob()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, migrate))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.DependsOn = []*structs.JobDependency{
+		{JobID: migrate.ID, Condition: structs.JobDependencyConditionComplete},
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Nothing is placed and a blocked eval waits on the dependency
+	must.SliceEmpty(t, h.Plans)
+	must.Len(t, 1, h.CreateEvals)
+	blocked := h.CreateEvals[0]
+	must.Eq(t, structs.EvalStatusBlocked, blocked.Status)
+	must.Eq(t, structs.EvalTriggerJobDependency, blocked.TriggeredBy)
+	must.Eq(t, job.DependsOn, blocked.UnmetDependencies)
+
+	must.Len(t, 1, h.Evals)
+	must.Eq(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	must.Eq(t, blocked.ID, h.Evals[0].BlockedEval)
+	must.Eq(t, job.DependsOn, h.Evals[0].UnmetDependencies)
+
+	// Complete the batch job
+	migrateEval := mock.Eval()
+	migrateEval.JobID = migrate.ID
+	migrateEval.Status = structs.EvalStatusComplete
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{migrateEval}))
+	alloc := mock.Alloc()
+	alloc.Job = migrate
+	alloc.JobID = migrate.ID
+	alloc.TaskGroup = migrate.TaskGroups[0].Name
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+	alloc = alloc.Copy()
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, h.State.UpdateAllocsFromClient(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+	migrate, err := h.State.JobByID(nil, migrate.Namespace, migrate.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusDead, migrate.Status)
+
+	// The unblocked eval places the job
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{blocked}))
+	must.NoError(t, h.Process(NewServiceScheduler, blocked))
+
+	must.Len(t, 1, h.Plans)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 3, planned)
+	must.Len(t, 1, h.CreateEvals)
+	must.Len(t, 2, h.Evals)
+	must.Eq(t, structs.EvalStatusComplete, h.Evals[1].Status)
+	must.SliceEmpty(t, h.Evals[1].UnmetDependencies)
+}
+
+func TestServiceSched_DependsOn_NodeUpdate(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	var nodes []*structs.Node
+	for range 4 {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Run the first version of the job
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for i := range 3 {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job with a destructive change and a dependency on a job
+	// which doesn't exist
+	job2 := job.Copy()
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	job2.DependsOn = []*structs.JobDependency{
+		{JobID: "missing", Condition: structs.JobDependencyConditionComplete},
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+	job2, err := h.State.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, job.Version+1, job2.Version)
+
+	// Lose the node of an allocation
+	down := nodes[0].Copy()
+	down.Status = structs.NodeStatusDown
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), down))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		NodeID:      down.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Only the lost allocation is replaced, with the version of the job it
+	// was running. The other allocations aren't updated and no deployment
+	// of the new version is created.
+	must.Len(t, 1, h.Plans)
+	plan := h.Plans[0]
+	must.Nil(t, plan.Deployment)
+	must.Len(t, 1, plan.NodeUpdate[down.ID])
+	must.Eq(t, allocs[0].ID, plan.NodeUpdate[down.ID][0].ID)
+
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 1, planned)
+	must.Eq(t, allocs[0].ID, planned[0].PreviousAllocation)
+	must.Eq(t, job.Version, planned[0].Job.Version)
+	must.Eq(t, "", planned[0].DeploymentID)
+
+	// The rollout of the new version waits on the dependency
+	must.Len(t, 1, h.CreateEvals)
+	blocked := h.CreateEvals[0]
+	must.Eq(t, structs.EvalStatusBlocked, blocked.Status)
+	must.Eq(t, structs.EvalTriggerJobDependency, blocked.TriggeredBy)
+	must.Eq(t, job2.DependsOn, blocked.UnmetDependencies)
+
+	must.Len(t, 1, h.Evals)
+	must.Eq(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	must.Eq(t, blocked.ID, h.Evals[0].BlockedEval)
+	must.Eq(t, job2.DependsOn, h.Evals[0].UnmetDependencies)
+}
+
 func TestServiceSched_JobRegister_StickyAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1573,6 +1740,83 @@ func TestServiceSched_JobRegister_FeasibleAndInfeasibleTG(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_JobRegister_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	// Create one node
+	node := mock.Node()
+	node.NodeClass = "class_0"
+	must.NoError(t, node.ComputeClass())
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	// Create a job with a gang of two groups, one of which can't be placed,
+	// and a group outside of the gang
+	job := mock.BatchJob()
+	job.TaskGroups[0].Name = "worker"
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Gang = "training"
+
+	ps := job.TaskGroups[0].Copy()
+	ps.Name = "ps"
+	ps.Count = 1
+	ps.Constraints = append(ps.Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "class_1",
+		Operand: "=",
+	})
+
+	other := job.TaskGroups[0].Copy()
+	other.Name = "other"
+	other.Count = 1
+	other.Gang = ""
+	job.TaskGroups = append(job.TaskGroups, ps, other)
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewBatchScheduler, eval))
+
+	// Ensure only the group outside of the gang was placed
+	must.Len(t, 1, h.Plans)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 1, planned)
+	must.Eq(t, "other", planned[0].TaskGroup)
+
+	// Ensure the eval has a blocked eval and explains the failures
+	must.Len(t, 1, h.Evals)
+	outEval := h.Evals[0]
+	must.Len(t, 1, h.CreateEvals)
+	must.Eq(t, h.CreateEvals[0].ID, outEval.BlockedEval)
+	must.MapLen(t, 2, outEval.FailedTGAllocs)
+
+	metrics := outEval.FailedTGAllocs["ps"]
+	must.NotNil(t, metrics)
+	must.Eq(t, 1, metrics.NodesFiltered)
+	must.SliceEmpty(t, metrics.GangFailed)
+
+	metrics = outEval.FailedTGAllocs["worker"]
+	must.NotNil(t, metrics)
+	must.Eq(t, []string{"ps"}, metrics.GangFailed)
+	must.Eq(t, 1, metrics.CoalescedFailures)
+
+	must.Eq(t, 2, outEval.QueuedAllocations["worker"])
+	must.Eq(t, 1, outEval.QueuedAllocations["ps"])
+	must.Eq(t, 0, outEval.QueuedAllocations["other"])
+}
+
 func TestServiceSched_JobRegister_SchedulerAlgorithm(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2744,6 +2988,119 @@ func TestServiceSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+// TestServiceSched_JobModify_MaxSurge_Reschedule asserts a failed surge
+// allocation is rescheduled alongside the allocation it replaces, keeping its
+// name rather than being renamed as a duplicate.
+func TestServiceSched_JobModify_MaxSurge_Reschedule(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	tgName := job.TaskGroups[0].Name
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = structs.AllocName(job.ID, tgName, uint(i))
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job destructively with a rolling update of one surge
+	// allocation at a time
+	job2 := job.Copy()
+	job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+		MaxParallel:     1,
+		MaxSurge:        1,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_Checks,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 10 * time.Minute,
+	}
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	must.Len(t, 1, h.Plans)
+	must.MapEmpty(t, h.Plans[0].NodeUpdate)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 1, planned)
+	must.Eq(t, allocs[0].Name, planned[0].Name)
+	must.Eq(t, allocs[0].ID, planned[0].DeploymentStatus.ReplacedAllocation())
+
+	// Fail the surge allocation and mark it for rescheduling
+	now := time.Now()
+	surge, err := h.State.AllocByID(nil, planned[0].ID)
+	must.NoError(t, err)
+	surge = surge.Copy()
+	surge.ClientStatus = structs.AllocClientStatusFailed
+	surge.DeploymentStatus.Healthy = pointer.Of(false)
+	surge.TaskStates = map[string]*structs.TaskState{tgName: {
+		State:      structs.TaskStateDead,
+		Failed:     true,
+		StartedAt:  now.Add(-1 * time.Hour),
+		FinishedAt: now.Add(-10 * time.Second),
+	}}
+	must.NoError(t, h.State.UpdateAllocsFromClient(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{surge}))
+	must.NoError(t, h.State.UpdateAllocsDesiredTransitions(structs.MsgTypeTestSetup, h.NextIndex(),
+		map[string]*structs.DesiredTransition{surge.ID: {Reschedule: pointer.Of(true)}}, nil))
+
+	eval = &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerRetryFailedAlloc,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The surge allocation is replaced under the same name, and the
+	// allocation it replaces is left running
+	must.Len(t, 2, h.Plans)
+	var stopped []*structs.Allocation
+	for _, updateList := range h.Plans[1].NodeUpdate {
+		stopped = append(stopped, updateList...)
+	}
+	must.Len(t, 1, stopped)
+	must.Eq(t, surge.ID, stopped[0].ID)
+
+	planned = nil
+	for _, allocList := range h.Plans[1].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 1, planned)
+	must.Eq(t, allocs[0].Name, planned[0].Name)
+	must.Eq(t, surge.ID, planned[0].PreviousAllocation)
+	must.Eq(t, allocs[0].ID, planned[0].DeploymentStatus.ReplacedAllocation())
+}
+
 // This tests that the old allocation is stopped before placing.
 // It is critical to test that the updated job attempts to place more
 // allocations as this allows us to assert that destructive changes are done
@@ -4035,6 +4392,59 @@ func TestServiceSched_NodeDrain(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_Rebalance_PenaltyNode(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	// Mark the allocation for migration as the rebalancer does
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = nodes[0].ID
+	alloc.Name = "my-job.web[0]"
+	alloc.DesiredTransition.Migrate = pointer.Of(true)
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerRebalance,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+	must.Len(t, 1, h.Plans)
+
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 1, planned)
+
+	// The node the allocation is moved off is penalized, so it isn't placed
+	// back on it
+	must.Eq(t, nodes[1].ID, planned[0].NodeID)
+	for _, scoreMeta := range planned[0].Metrics.ScoreMetaData {
+		if scoreMeta.NodeID == alloc.NodeID {
+			must.Eq(t, -1.0, scoreMeta.Scores["node-reschedule-penalty"])
+		}
+	}
+}
+
 func TestServiceSched_NodeDrain_Down(t *testing.T) {
 	ci.Parallel(t)
 
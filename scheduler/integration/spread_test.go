@@ -488,3 +488,61 @@ func TestSpread_ImplicitTargets(t *testing.T) {
 		})
 	}
 }
+
+func TestSpread_MaxSkew(t *testing.T) {
+	ci.Parallel(t)
+
+	// racksToAllocCount returns the number of allocations placed by the plan
+	// on each rack.
+	racksToAllocCount := func(h *tests.Harness) map[string]int {
+		counts := map[string]int{}
+		for nodeID, nodeAllocs := range h.Plans[0].NodeAllocation {
+			node, err := h.State.NodeByID(nil, nodeID)
+			must.NoError(t, err)
+			counts[node.Meta["rack"]] += len(nodeAllocs)
+		}
+		return counts
+	}
+
+	t.Run("uneven racks", func(t *testing.T) {
+		h := tests.NewHarness(t)
+		must.NoError(t, upsertNodes(h, 20, map[string]int{"0": 14, "1": 4, "2": 2}))
+
+		job := generateJob(9)
+		job.Spreads = []*structs.Spread{{Attribute: "${meta.rack}", Weight: 50, MaxSkew: 1}}
+		eval, err := upsertJob(h, job)
+		must.NoError(t, err)
+		must.NoError(t, h.Process(scheduler.NewServiceScheduler, eval))
+
+		must.Len(t, 1, h.Plans)
+		must.Eq(t, map[string]int{"r0": 3, "r1": 3, "r2": 3}, racksToAllocCount(h))
+	})
+
+	t.Run("insufficient domains", func(t *testing.T) {
+		h := tests.NewHarness(t)
+		must.NoError(t, upsertNodes(h, 10, map[string]int{"0": 5, "1": 5}))
+
+		job := generateJob(4)
+		job.Spreads = []*structs.Spread{{
+			Attribute:  "${meta.rack}",
+			Weight:     50,
+			MaxSkew:    1,
+			MinDomains: 3,
+		}}
+		eval, err := upsertJob(h, job)
+		must.NoError(t, err)
+		must.NoError(t, h.Process(scheduler.NewServiceScheduler, eval))
+
+		// Only a single allocation fits on each rack until a third rack
+		// exists
+		must.Len(t, 1, h.Plans)
+		must.Eq(t, map[string]int{"r0": 1, "r1": 1}, racksToAllocCount(h))
+
+		must.Len(t, 1, h.Evals)
+		metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+		must.NotNil(t, metrics)
+		must.Eq(t, 1, metrics.CoalescedFailures)
+		must.MapContainsKey(t, metrics.ConstraintFiltered,
+			"spread: ${meta.rack}=r0 would exceed max skew of 1")
+	})
+}
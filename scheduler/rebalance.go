@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler/feasible"
+	sstructs "github.com/hashicorp/nomad/scheduler/structs"
+)
+
+// RebalanceCandidate is a running allocation which would score better on
+// another node than on the node it is running on.
+type RebalanceCandidate struct {
+	Alloc *structs.Allocation
+
+	// NodeID is the ID of the best scoring node for the allocation.
+	NodeID string
+
+	// CurrentScore is the normalized score of the node the allocation is
+	// running on, and BestScore the one of the best scoring node.
+	CurrentScore float64
+	BestScore    float64
+}
+
+// Improvement returns by how much the score of the allocation would improve
+// if it was moved to the best scoring node.
+func (c *RebalanceCandidate) Improvement() float64 {
+	return c.BestScore - c.CurrentScore
+}
+
+// RebalanceCandidates scores the current placement of the given allocations
+// of the job against the other ready nodes the job may be placed on, using
+// the same iterators the generic scheduler uses to place a replacement. Each
+// allocation is scored as if it was placed anew, so it doesn't count against
+// the resources or spread of its own node. It returns the allocations whose
+// score would improve by at least the threshold, ordered by descending
+// improvement. Allocations on nodes which are no longer ready are skipped as
+// they are handled by drains and node updates.
+func RebalanceCandidates(logger log.Logger, state sstructs.State, job *structs.Job,
+	allocs []*structs.Allocation, threshold float64) ([]*RebalanceCandidate, error) {
+
+	pool, err := state.NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job node pool %q: %v", job.NodePool, err)
+	}
+	_, schedConfig, err := state.SchedulerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduler configuration: %v", err)
+	}
+	nodes, _, _, err := readyNodesInDCsAndPool(state, job.Datacenters, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+	nodesByID := make(map[string]*structs.Node, len(nodes))
+	for _, node := range nodes {
+		nodesByID[node.ID] = node
+	}
+
+	plan := &structs.Plan{
+		EvalID:          uuid.Generate(),
+		Priority:        job.Priority,
+		Job:             job,
+		NodeUpdate:      make(map[string][]*structs.Allocation),
+		NodeAllocation:  make(map[string][]*structs.Allocation),
+		NodePreemptions: make(map[string][]*structs.Allocation),
+	}
+	ctx := feasible.NewEvalContext(nil, state, plan, logger)
+	stack := feasible.NewGenericStack(false, ctx)
+	stack.SetJob(job)
+	stack.SetSchedulerConfiguration(schedConfig.WithNodePool(pool))
+
+	var candidates []*RebalanceCandidate
+	for _, alloc := range allocs {
+		tg := job.LookupTaskGroup(alloc.TaskGroup)
+		node, ok := nodesByID[alloc.NodeID]
+		if tg == nil || !ok {
+			continue
+		}
+
+		// Stop the allocation in the plan so its own resources and placement
+		// are not held against its current node.
+		clear(plan.NodeUpdate)
+		plan.NodeUpdate[alloc.NodeID] = []*structs.Allocation{alloc}
+		options := &feasible.SelectOptions{AllocName: alloc.Name}
+
+		stack.SetNodes([]*structs.Node{node})
+		current := stack.Select(tg, options)
+		if current == nil {
+			continue
+		}
+
+		others := make([]*structs.Node, 0, len(nodes)-1)
+		for _, n := range nodes {
+			if n.ID != alloc.NodeID {
+				others = append(others, n)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		stack.SetNodes(others)
+		best := stack.Select(tg, options)
+		if best == nil {
+			continue
+		}
+
+		candidate := &RebalanceCandidate{
+			Alloc:        alloc,
+			NodeID:       best.Node.ID,
+			CurrentScore: current.FinalScore,
+			BestScore:    best.FinalScore,
+		}
+		if candidate.Improvement() >= threshold {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Improvement() > candidates[j].Improvement()
+	})
+	return candidates, nil
+}
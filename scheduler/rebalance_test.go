@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestRebalanceCandidates(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	// Three allocations share the first node and one is alone on the second
+	// node, while the other nodes are empty. The allocations don't reserve
+	// ports so they don't collide with each other.
+	var allocs []*structs.Allocation
+	for i, n := range []int{0, 0, 0, 1} {
+		alloc := mock.Alloc()
+		alloc.ID = uuid.Generate()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[n].ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		alloc.AllocatedResources.Shared.Ports = nil
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 300, allocs))
+
+	candidates, err := RebalanceCandidates(testlog.HCLogger(t), store, job, allocs, 0.2)
+	must.NoError(t, err)
+	must.Len(t, 3, candidates)
+	for _, c := range candidates {
+		must.Eq(t, nodes[0].ID, c.Alloc.NodeID)
+		must.NotEq(t, nodes[0].ID, c.NodeID)
+		must.NotEq(t, nodes[1].ID, c.NodeID)
+		must.GreaterEq(t, 0.2, c.Improvement())
+	}
+
+	// Nothing is gained by moving an allocation which is alone on its node.
+	candidates, err = RebalanceCandidates(testlog.HCLogger(t), store, job, allocs[3:], 0.01)
+	must.NoError(t, err)
+	must.SliceEmpty(t, candidates)
+
+	// Allocations on nodes which are no longer ready are skipped.
+	must.NoError(t, store.UpdateNodeStatus(structs.MsgTypeTestSetup, 400,
+		nodes[0].ID, structs.NodeStatusDown, 0, nil))
+	candidates, err = RebalanceCandidates(testlog.HCLogger(t), store, job, allocs, 0.2)
+	must.NoError(t, err)
+	must.SliceEmpty(t, candidates)
+}
@@ -46,6 +46,10 @@ type PlacementResult interface {
 	// PreviousLost is true if the previous allocation was lost.
 	PreviousLost() bool
 
+	// Replaces returns the ID of the allocation a surge placement is placed
+	// alongside and replaces once it is healthy.
+	Replaces() string
+
 	// DowngradeNonCanary indicates that placement should use the latest stable job
 	// with the MinJobVersion, rather than the current deployment version
 	DowngradeNonCanary() bool
@@ -70,6 +74,7 @@ type AllocPlaceResult struct {
 	previousAlloc *structs.Allocation
 	reschedule    bool
 	lost          bool
+	replaces      string
 
 	downgradeNonCanary bool
 	minJobVersion      uint64
@@ -87,6 +92,7 @@ func (a AllocPlaceResult) StopPreviousAlloc() (bool, string)   { return false, "
 func (a AllocPlaceResult) DowngradeNonCanary() bool            { return a.downgradeNonCanary }
 func (a AllocPlaceResult) MinJobVersion() uint64               { return a.minJobVersion }
 func (a AllocPlaceResult) PreviousLost() bool                  { return a.lost }
+func (a AllocPlaceResult) Replaces() string                    { return a.replaces }
 func (a *AllocPlaceResult) SetTaskGroup(tg *structs.TaskGroup) { a.taskGroup = tg }
 
 // allocDestructiveResult contains the information required to do a destructive
@@ -111,6 +117,7 @@ func (a allocDestructiveResult) StopPreviousAlloc() (bool, string) {
 func (a allocDestructiveResult) DowngradeNonCanary() bool { return false }
 func (a allocDestructiveResult) MinJobVersion() uint64    { return 0 }
 func (a allocDestructiveResult) PreviousLost() bool       { return false }
+func (a allocDestructiveResult) Replaces() string         { return "" }
 
 // allocMatrix is a mapping of task groups to their allocation set.
 type allocMatrix map[string]allocSet
@@ -525,10 +525,22 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 		// reschedulable later and mark the allocations for in place updating
 		a.createRescheduleLaterEvals(rescheduleLater, all, tg.Name, result)
 	}
+	// Handle the allocations placed alongside the allocations they replace
+	// by a rolling update with a max surge. The surge allocations, including
+	// the failed ones waiting to be rescheduled, are kept out of the
+	// untainted set so they don't count towards the group count.
+	rescheduling := rescheduleNow.union()
+	for _, r := range rescheduleLater {
+		rescheduling[r.allocID] = r.alloc
+	}
+	untainted, surge, surgeReplaced := a.computeSurge(untainted, rescheduling, group, result)
+
 	// Create a structure for choosing names. Seed with the taken names
 	// which is the union of untainted, rescheduled, allocs on migrating
-	// nodes, and allocs on down nodes (includes canaries)
-	nameIndex := newAllocNameIndex(a.jobState.JobID, group, tg.Count, untainted.union(migrate, rescheduleNow, lost))
+	// nodes, and allocs on down nodes (includes canaries). Rescheduled surge
+	// allocations share the name of the allocation they replace.
+	nameIndex := newAllocNameIndex(a.jobState.JobID, group, tg.Count,
+		untainted.union(migrate, rescheduleNow.difference(surge), lost))
 	allocNameIndexForGroup := nameIndex
 	result.TaskGroupAllocNameIndexes = map[string]*AllocNameIndex{group: allocNameIndexForGroup}
 
@@ -549,6 +561,12 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 		dstate.DesiredTotal += len(destructive) + len(inplace)
 	}
 
+	// The allocations replaced by a surge allocation are stopped once it is
+	// healthy, so they are not updated destructively.
+	destructive = destructive.difference(surgeReplaced)
+	pendingSurge := surge.difference(rescheduleNow)
+	result.DesiredTGUpdates[group].Ignore += uint64(len(pendingSurge) + len(surgeReplaced))
+
 	// Remove the canaries now that we have handled rescheduling so that we do
 	// not consider them when making placement decisions.
 	if isCanarying {
@@ -561,7 +579,7 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 
 	// Determine how many non-canary allocs we can place
 	isCanarying = dstate != nil && dstate.DesiredCanaries != 0 && !dstate.Promoted
-	underProvisionedBy := a.computeUnderProvisionedBy(tg, untainted, destructive, migrate, isCanarying)
+	underProvisionedBy := a.computeUnderProvisionedBy(tg, untainted.union(pendingSurge), destructive, migrate, isCanarying)
 
 	// Place if:
 	// * The deployment is not paused or failed
@@ -571,7 +589,7 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 	// * An alloc was lost
 	var place []AllocPlaceResult
 	if len(lostLater) == 0 {
-		place = computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, lost, isCanarying)
+		place = computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, lost, surge, isCanarying)
 		if !existingDeployment {
 			dstate.DesiredTotal += len(place)
 		}
@@ -587,7 +605,12 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 	result.Place = append(result.Place, replacements...)
 
 	if deploymentPlaceReady {
-		result.DestructiveUpdate = a.computeDestructiveUpdates(destructive, underProvisionedBy, result.DesiredTGUpdates[group], tg)
+		var surgePlace []AllocPlaceResult
+		var remaining allocSet
+		surgePlace, remaining, underProvisionedBy = a.computeSurgePlacements(
+			destructive, surge, dstate, underProvisionedBy, result.DesiredTGUpdates[group], tg)
+		result.Place = append(result.Place, surgePlace...)
+		result.DestructiveUpdate = a.computeDestructiveUpdates(remaining, underProvisionedBy, result.DesiredTGUpdates[group], tg)
 	} else {
 		result.DesiredTGUpdates[group].Ignore += uint64(len(destructive))
 	}
@@ -608,7 +631,7 @@ func (a *AllocReconciler) computeGroup(group string, all allocSet) (*ReconcileRe
 		result.DesiredTGUpdates[tg.Name].Place = uint64(tg.Count)
 	}
 
-	deploymentComplete := a.isDeploymentComplete(group, destructive, inplace,
+	deploymentComplete := a.isDeploymentComplete(group, destructive.union(surgeReplaced), inplace,
 		migrate, rescheduleNow, result.Place, rescheduleLater, requiresCanaries)
 
 	return result, deploymentComplete
@@ -679,6 +702,7 @@ func (a *AllocReconciler) initializeDeploymentState(group string, tg *structs.Ta
 			dstate.AutoRevert = tg.Update.AutoRevert
 			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
+			dstate.DesiredSurge = tg.Update.MaxSurge
 		}
 	}
 
@@ -690,7 +714,7 @@ func requiresCanaries(tg *structs.TaskGroup, dstate *structs.DeploymentState, de
 	canariesPromoted := dstate != nil && dstate.Promoted
 	return tg.Update != nil &&
 		len(destructive) != 0 &&
-		len(canaries) < tg.Update.Canary &&
+		len(canaries) < tg.Update.CanaryCount(tg.Count) &&
 		!canariesPromoted
 }
 
@@ -704,12 +728,12 @@ func (a *AllocReconciler) computeCanaries(
 	result *ReconcileResults,
 ) {
 
-	dstate.DesiredCanaries = tg.Update.Canary
+	dstate.DesiredCanaries = tg.Update.CanaryCount(tg.Count)
 
 	placementResult := []AllocPlaceResult{}
 
 	if !a.jobState.DeploymentPaused && !a.jobState.DeploymentFailed {
-		result.DesiredTGUpdates[group].Canary += uint64(tg.Update.Canary - len(canaries))
+		result.DesiredTGUpdates[group].Canary += uint64(dstate.DesiredCanaries - len(canaries))
 		total := uint(result.DesiredTGUpdates[group].Canary)
 
 		for _, name := range nameIndex.NextCanaries(total, canaries, destructive) {
@@ -834,18 +858,25 @@ func (a *AllocReconciler) computeUnderProvisionedBy(group *structs.TaskGroup, un
 //
 // Placements will meet or exceed group count.
 func computePlacements(group *structs.TaskGroup,
-	nameIndex *AllocNameIndex, untainted, migrate, reschedule, lost allocSet,
+	nameIndex *AllocNameIndex, untainted, migrate, reschedule, lost, surge allocSet,
 	isCanarying bool) []AllocPlaceResult {
 
-	// Add rescheduled placement results
+	// Add rescheduled placement results. Rescheduled surge allocations keep
+	// replacing the same allocation.
 	var place []AllocPlaceResult
 	for _, alloc := range reschedule {
+		var replaces string
+		if _, ok := surge[alloc.ID]; ok {
+			replaces = alloc.DeploymentStatus.ReplacedAllocation()
+		}
+
 		place = append(place, AllocPlaceResult{
 			name:          alloc.Name,
 			taskGroup:     group,
 			previousAlloc: alloc,
 			reschedule:    true,
 			canary:        alloc.DeploymentStatus.IsCanary(),
+			replaces:      replaces,
 
 			downgradeNonCanary: isCanarying && !alloc.DeploymentStatus.IsCanary(),
 			minJobVersion:      alloc.Job.Version,
@@ -854,7 +885,7 @@ func computePlacements(group *structs.TaskGroup,
 	}
 
 	// Add replacements for disconnected and lost allocs up to group.Count
-	existing := len(untainted) + len(migrate) + len(reschedule)
+	existing := len(untainted) + len(migrate) + len(reschedule.difference(surge))
 
 	// Add replacements for lost
 	for _, alloc := range lost {
@@ -997,6 +1028,123 @@ func (a *AllocReconciler) computeDestructiveUpdates(destructive allocSet, underP
 	return destructiveResult
 }
 
+// computeSurge handles the allocations placed by a rolling update with a max
+// surge, which run alongside the allocation they replace under the same name.
+// Once a surge allocation is healthy the allocation it replaces is stopped,
+// while the surge allocations of an older or failed deployment which are not
+// healthy are stopped themselves. Failed surge allocations of the current
+// deployment waiting to be rescheduled keep the allocation they replace. It
+// returns the untainted set without the stopped and surge allocations, the
+// surge allocations of the current deployment still waiting to be healthy or
+// rescheduled, and the allocations they replace.
+func (a *AllocReconciler) computeSurge(untainted, rescheduling allocSet, group string, result *ReconcileResults) (allocSet, allocSet, allocSet) {
+	var deployments []*structs.Deployment
+	if a.jobState.DeploymentOld != nil {
+		deployments = append(deployments, a.jobState.DeploymentOld)
+	}
+	if a.jobState.DeploymentCurrent != nil {
+		deployments = append(deployments, a.jobState.DeploymentCurrent)
+	}
+
+	surge := make(allocSet)
+	replaced := make(allocSet)
+	for _, d := range deployments {
+		if dstate, ok := d.TaskGroups[group]; !ok || dstate.DesiredSurge == 0 {
+			continue
+		}
+		active := d == a.jobState.DeploymentCurrent && d.Status != structs.DeploymentStatusFailed
+
+		// Find the allocations of the deployment running alongside the
+		// allocation they replace.
+		running := untainted.filterByTerminal().difference(rescheduling)
+		partOf, others := running.filterByDeployment(d.ID)
+		bySurge := make(map[string]*structs.Allocation)
+		for _, alloc := range partOf {
+			if _, ok := others[alloc.DeploymentStatus.ReplacedAllocation()]; ok {
+				bySurge[alloc.DeploymentStatus.ReplacedAllocation()] = alloc
+			}
+		}
+
+		stopReplaced := make(allocSet)
+		for id, alloc := range others {
+			s, ok := bySurge[id]
+			switch {
+			case !ok:
+			case s.DeploymentStatus.IsHealthy():
+				stopReplaced[id] = alloc
+			case active:
+				replaced[id] = alloc
+			}
+		}
+
+		stopSurge := make(allocSet)
+		for _, alloc := range bySurge {
+			switch {
+			case alloc.DeploymentStatus.IsHealthy():
+			case active:
+				surge[alloc.ID] = alloc
+			default:
+				stopSurge[alloc.ID] = alloc
+			}
+		}
+
+		// The failed surge allocations of the current deployment are
+		// rescheduled alongside the allocation they replace.
+		if active {
+			failed, _ := rescheduling.filterByDeployment(d.ID)
+			for _, alloc := range failed {
+				id := alloc.DeploymentStatus.ReplacedAllocation()
+				if _, ok := others[id]; ok && stopReplaced[id] == nil {
+					surge[alloc.ID] = alloc
+					replaced[id] = others[id]
+				}
+			}
+		}
+
+		result.Stop = slices.Concat(result.Stop,
+			markStop(stopReplaced, "", sstructs.StatusAllocUpdating),
+			markStop(stopSurge, "", sstructs.StatusAllocNotNeeded),
+		)
+		result.DesiredTGUpdates[group].Stop += uint64(len(stopReplaced) + len(stopSurge))
+		untainted = untainted.difference(stopReplaced, stopSurge)
+	}
+
+	return untainted.difference(surge), surge, replaced
+}
+
+// computeSurgePlacements returns the new allocations to place alongside the
+// allocations they replace, up to the max surge of the deployment minus the
+// surge allocations still waiting to be healthy, along with the destructive
+// updates left and the number of allocs still needed. It mutates the
+// DestructiveUpdate field on the DesiredUpdates counts.
+func (a *AllocReconciler) computeSurgePlacements(destructive, surge allocSet,
+	dstate *structs.DeploymentState, underProvisionedBy int,
+	desiredChanges *structs.DesiredUpdates, tg *structs.TaskGroup) ([]AllocPlaceResult, allocSet, int) {
+
+	if dstate == nil || dstate.DesiredSurge == 0 {
+		return nil, destructive, underProvisionedBy
+	}
+
+	minimum := min(len(destructive), underProvisionedBy, dstate.DesiredSurge-len(surge))
+	if minimum <= 0 {
+		return nil, destructive, underProvisionedBy
+	}
+
+	var place []AllocPlaceResult
+	surged := make(allocSet)
+	for _, alloc := range destructive.nameOrder()[:minimum] {
+		place = append(place, AllocPlaceResult{
+			name:      alloc.Name,
+			taskGroup: tg,
+			replaces:  alloc.ID,
+		})
+		surged[alloc.ID] = alloc
+	}
+	desiredChanges.DestructiveUpdate += uint64(minimum)
+
+	return place, destructive.difference(surged), underProvisionedBy - minimum
+}
+
 // computeMigrations updates the result with the stops and placements required
 // for migration.
 func (a *AllocReconciler) computeMigrations(result *ReconcileResults, migrate allocSet,
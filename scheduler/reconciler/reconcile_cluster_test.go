@@ -2371,6 +2371,85 @@ func TestReconciler_RescheduleNow_Service(t *testing.T) {
 	assertPlacementsAreRescheduled(t, 1, r.Place)
 }
 
+// Tests that the exit rules of the reschedule policy prevent failed allocations
+// from being rescheduled
+func TestReconciler_RescheduleNow_ExitRules(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	tgName := job.TaskGroups[0].Name
+	now := time.Now()
+
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Attempts:      1,
+		Interval:      24 * time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+		MaxDelay:      1 * time.Hour,
+		ExitRules: []*structs.ExitRule{
+			{ExitCodes: []int{3}, Action: structs.ExitRuleActionFail},
+		},
+	}
+	job.TaskGroups[0].Update = noCanaryUpdate
+
+	// Create 2 failed allocations, one of which exited with a code matching
+	// the fail rule
+	var allocs []*structs.Allocation
+	for i, exitCode := range []int{3, 1} {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, tgName, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusFailed
+		alloc.TaskStates = map[string]*structs.TaskState{tgName: {
+			State:      structs.TaskStateDead,
+			Failed:     true,
+			StartedAt:  now.Add(-1 * time.Hour),
+			FinishedAt: now.Add(-10 * time.Second),
+			Events: []*structs.TaskEvent{
+				structs.NewTaskEvent(structs.TaskTerminated).SetExitCode(exitCode),
+			},
+		}}
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), allocUpdateFnIgnore, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: nil,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	// Only the allocation which did not match the rule is rescheduled
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		inplace:           0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			tgName: {
+				Place:  1,
+				Stop:   1,
+				Ignore: 1,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(1, 1), placeResultsToNames(r.Place))
+	assertPlacementsAreRescheduled(t, 1, r.Place)
+}
+
 // Tests rescheduling failed service allocations when there's clock drift (upto a second)
 func TestReconciler_RescheduleNow_WithinAllowedTimeWindow(t *testing.T) {
 	ci.Parallel(t)
@@ -4569,6 +4648,615 @@ func TestReconciler_PromoteCanaries_CanariesEqualCount(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), stopResultsToNames(r.Stop))
 }
 
+// blueGreenJob returns a job whose task group of the given count is deployed
+// with a blue/green update strategy, along with its allocations from the old
+// job version.
+func blueGreenJob(count int) (*structs.Job, []*structs.Allocation) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = count
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 1
+	job.TaskGroups[0].Update.BlueGreen = true
+
+	var allocs []*structs.Allocation
+	for i := 0; i < count; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+	return job, allocs
+}
+
+// blueGreenCanaries returns the canaries of the deployment which replace the
+// allocations of the old job version, and marks them as ignored by the update
+// function.
+func blueGreenCanaries(job *structs.Job, d *structs.Deployment, count int, handled map[string]AllocUpdateType) []*structs.Allocation {
+	state := d.TaskGroups[job.TaskGroups[0].Name]
+
+	var canaries []*structs.Allocation
+	for i := 0; i < count; i++ {
+		canary := mock.Alloc()
+		canary.Job = job
+		canary.JobID = job.ID
+		canary.NodeID = uuid.Generate()
+		canary.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		canary.TaskGroup = job.TaskGroups[0].Name
+		canary.DeploymentID = d.ID
+		canary.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: pointer.Of(true),
+			Canary:  true,
+		}
+		state.PlacedCanaries = append(state.PlacedCanaries, canary.ID)
+		canaries = append(canaries, canary)
+		handled[canary.ID] = allocUpdateFnIgnore
+	}
+	return canaries
+}
+
+// Tests the reconciler places a full set of canaries for blue/green
+// deployments, regardless of the max parallel
+func TestReconciler_BlueGreen_NewCanaries(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := blueGreenJob(4)
+
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), allocUpdateFnDestructive, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: nil,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	newD := structs.NewDeployment(job, 50, r.Deployment.CreateTime)
+	newD.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
+	newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredCanaries: 4,
+		DesiredTotal:    4,
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  newD,
+		deploymentUpdates: nil,
+		place:             4,
+		inplace:           0,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Canary: 4,
+				Ignore: 4,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(0, 3), placeResultsToNames(r.Place))
+	for _, place := range r.Place {
+		must.True(t, place.canary)
+	}
+}
+
+// Tests the reconciler stops all the allocations of the old job version at
+// once when a blue/green deployment is promoted
+func TestReconciler_BlueGreen_Promoted(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := blueGreenJob(4)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		Promoted:        true,
+		DesiredTotal:    4,
+		DesiredCanaries: 4,
+		PlacedAllocs:    4,
+		HealthyAllocs:   4,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	allocs = append(allocs, blueGreenCanaries(job, d, 4, handled)...)
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment: nil,
+		deploymentUpdates: []*structs.DeploymentStatusUpdate{
+			{
+				DeploymentID:      d.ID,
+				Status:            structs.DeploymentStatusSuccessful,
+				StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+			},
+		},
+		place:   0,
+		inplace: 0,
+		stop:    4,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   4,
+				Ignore: 4,
+			},
+		},
+	})
+
+	assertNoCanariesStopped(t, d, r.Stop)
+}
+
+// Tests the reconciler only stops the canaries of a failed blue/green
+// deployment, and leaves the allocations of the old job version running
+func TestReconciler_BlueGreen_FailedDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := blueGreenJob(4)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.Status = structs.DeploymentStatusFailed
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:    4,
+		DesiredCanaries: 4,
+		PlacedAllocs:    4,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	canaries := blueGreenCanaries(job, d, 4, handled)
+	allocs = append(allocs, canaries...)
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		stop:              4,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   4,
+				Ignore: 4,
+			},
+		},
+	})
+
+	stopped := make([]string, 0, len(r.Stop))
+	for _, stop := range r.Stop {
+		stopped = append(stopped, stop.Alloc.ID)
+	}
+	must.SliceContainsAll(t, d.TaskGroups[job.TaskGroups[0].Name].PlacedCanaries, stopped)
+}
+
+// maxSurgeJob returns a job whose task group of the given count is deployed
+// with a rolling update of the given max parallel and max surge, along with
+// its allocations from the old job version.
+func maxSurgeJob(count, maxParallel, maxSurge int) (*structs.Job, []*structs.Allocation) {
+	job, allocs := blueGreenJob(count)
+	job.TaskGroups[0].Update.BlueGreen = false
+	job.TaskGroups[0].Update.MaxParallel = maxParallel
+	job.TaskGroups[0].Update.MaxSurge = maxSurge
+	return job, allocs
+}
+
+// surgeAlloc returns an allocation of the deployment placed alongside the
+// given allocation of the old job version, and marks it as ignored by the
+// update function.
+func surgeAlloc(job *structs.Job, d *structs.Deployment, replaced *structs.Allocation, healthy *bool, handled map[string]AllocUpdateType) *structs.Allocation {
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = uuid.Generate()
+	alloc.Name = replaced.Name
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc.DeploymentID = d.ID
+	alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy:  healthy,
+		Replaces: replaced.ID,
+	}
+	handled[alloc.ID] = allocUpdateFnIgnore
+	return alloc
+}
+
+// Tests the reconciler places new allocations alongside the ones they replace
+// up to the max surge, and updates the others destructively
+func TestReconciler_MaxSurge_NewDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		maxParallel int
+		maxSurge    int
+		place       int
+		destructive int
+	}{
+		{
+			name:        "surge only",
+			maxParallel: 2,
+			maxSurge:    2,
+			place:       2,
+		},
+		{
+			name:        "surge and destructive",
+			maxParallel: 3,
+			maxSurge:    1,
+			place:       1,
+			destructive: 2,
+		},
+		{
+			name:        "limited by max parallel",
+			maxParallel: 1,
+			maxSurge:    3,
+			place:       1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			job, allocs := maxSurgeJob(4, c.maxParallel, c.maxSurge)
+
+			reconciler := NewAllocReconciler(
+				testlog.HCLogger(t), allocUpdateFnDestructive, ReconcilerState{
+					JobIsBatch:        false,
+					JobID:             job.ID,
+					Job:               job,
+					DeploymentCurrent: nil,
+					ExistingAllocs:    allocs,
+					EvalPriority:      50,
+				}, ClusterState{
+					TaintedNodes:                nil,
+					SupportsDisconnectedClients: true,
+					Now:                         time.Now().UTC(),
+				})
+			r := reconciler.Compute()
+
+			newD := structs.NewDeployment(job, 50, r.Deployment.CreateTime)
+			newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+				DesiredTotal: 4,
+				DesiredSurge: c.maxSurge,
+			}
+
+			assertResults(t, r, &resultExpectation{
+				createDeployment:  newD,
+				deploymentUpdates: nil,
+				place:             c.place,
+				destructive:       c.destructive,
+				inplace:           0,
+				stop:              0,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					job.TaskGroups[0].Name: {
+						DestructiveUpdate: uint64(c.place + c.destructive),
+						Ignore:            uint64(4 - c.place - c.destructive),
+					},
+				},
+			})
+
+			assertNamesHaveIndexes(t, intRange(0, c.place-1), placeResultsToNames(r.Place))
+			byID := make(map[string]*structs.Allocation)
+			for _, alloc := range allocs {
+				byID[alloc.ID] = alloc
+			}
+			for _, place := range r.Place {
+				must.Nil(t, place.PreviousAllocation())
+				must.MapContainsKey(t, byID, place.Replaces())
+				must.Eq(t, byID[place.Replaces()].Name, place.Name())
+			}
+		})
+	}
+}
+
+// Tests the reconciler keeps the allocation replaced by a surge allocation
+// until it is healthy
+func TestReconciler_MaxSurge_Pending(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := maxSurgeJob(2, 1, 1)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 2,
+		DesiredSurge: 1,
+		PlacedAllocs: 1,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	allocs = append(allocs, surgeAlloc(job, d, allocs[0], nil, handled))
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 3,
+			},
+		},
+	})
+}
+
+// Tests the reconciler stops the allocation replaced by a healthy surge
+// allocation and places the next one
+func TestReconciler_MaxSurge_Healthy(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := maxSurgeJob(2, 1, 1)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:  2,
+		DesiredSurge:  1,
+		PlacedAllocs:  1,
+		HealthyAllocs: 1,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	allocs = append(allocs, surgeAlloc(job, d, allocs[0], pointer.Of(true), handled))
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		inplace:           0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 1,
+				Stop:              1,
+				Ignore:            1,
+			},
+		},
+	})
+
+	must.Eq(t, allocs[0].ID, r.Stop[0].Alloc.ID)
+	assertNamesHaveIndexes(t, []int{1}, placeResultsToNames(r.Place))
+}
+
+// Tests the reconciler completes the deployment once the last surge
+// allocation is healthy and the allocation it replaces is stopped
+func TestReconciler_MaxSurge_Complete(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := maxSurgeJob(2, 1, 1)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:  2,
+		DesiredSurge:  1,
+		PlacedAllocs:  2,
+		HealthyAllocs: 2,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	allocs = append(allocs[1:],
+		surgeAlloc(job, d, allocs[0], pointer.Of(true), handled),
+		surgeAlloc(job, d, allocs[1], pointer.Of(true), handled))
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment: nil,
+		deploymentUpdates: []*structs.DeploymentStatusUpdate{
+			{
+				DeploymentID:      d.ID,
+				Status:            structs.DeploymentStatusSuccessful,
+				StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+			},
+		},
+		place:   0,
+		inplace: 0,
+		stop:    1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   1,
+				Ignore: 2,
+			},
+		},
+	})
+
+	must.Eq(t, allocs[0].ID, r.Stop[0].Alloc.ID)
+}
+
+// Tests the reconciler reschedules a failed surge allocation alongside the
+// allocation it replaces, under the same name and without updating the
+// replaced allocation
+func TestReconciler_MaxSurge_Reschedule(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := maxSurgeJob(2, 1, 1)
+	tgName := job.TaskGroups[0].Name
+	now := time.Now()
+
+	d := structs.NewDeployment(job, 50, now.UnixNano())
+	d.TaskGroups[tgName] = &structs.DeploymentState{
+		DesiredTotal:    2,
+		DesiredSurge:    1,
+		PlacedAllocs:    1,
+		UnhealthyAllocs: 1,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	surge := surgeAlloc(job, d, allocs[0], pointer.Of(false), handled)
+	surge.ClientStatus = structs.AllocClientStatusFailed
+	surge.DesiredTransition = structs.DesiredTransition{Reschedule: pointer.Of(true)}
+	surge.TaskStates = map[string]*structs.TaskState{tgName: {
+		State:      structs.TaskStateDead,
+		Failed:     true,
+		StartedAt:  now.Add(-1 * time.Hour),
+		FinishedAt: now.Add(-10 * time.Second),
+	}}
+	allocs = append(allocs, surge)
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         now.UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		inplace:           0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			tgName: {
+				Place:  1,
+				Stop:   1,
+				Ignore: 2,
+			},
+		},
+	})
+
+	must.Eq(t, surge.ID, r.Stop[0].Alloc.ID)
+	must.Eq(t, surge.ID, r.Place[0].PreviousAllocation().ID)
+	must.True(t, r.Place[0].IsRescheduling())
+	must.Eq(t, allocs[0].ID, r.Place[0].Replaces())
+	must.Eq(t, allocs[0].Name, r.Place[0].Name())
+}
+
+// Tests the reconciler stops the surge allocations of a failed deployment
+// which are not healthy, and leaves the allocations they replace running
+func TestReconciler_MaxSurge_FailedDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	job, allocs := maxSurgeJob(2, 1, 1)
+
+	d := structs.NewDeployment(job, 50, time.Now().UnixNano())
+	d.Status = structs.DeploymentStatusFailed
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:    2,
+		DesiredSurge:    1,
+		PlacedAllocs:    1,
+		UnhealthyAllocs: 1,
+	}
+
+	handled := make(map[string]AllocUpdateType)
+	surge := surgeAlloc(job, d, allocs[0], pointer.Of(false), handled)
+	allocs = append(allocs, surge)
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(
+		testlog.HCLogger(t), mockUpdateFn, ReconcilerState{
+			JobIsBatch:        false,
+			JobID:             job.ID,
+			Job:               job,
+			DeploymentCurrent: d,
+			ExistingAllocs:    allocs,
+			EvalPriority:      50,
+		}, ClusterState{
+			TaintedNodes:                nil,
+			SupportsDisconnectedClients: true,
+			Now:                         time.Now().UTC(),
+		})
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   1,
+				Ignore: 2,
+			},
+		},
+	})
+
+	must.Eq(t, surge.ID, r.Stop[0].Alloc.ID)
+}
+
 // Tests the reconciler checks the health of placed allocs to determine the
 // limit
 func TestReconciler_DeploymentLimit_HealthAccounting(t *testing.T) {
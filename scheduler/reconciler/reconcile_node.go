@@ -5,6 +5,8 @@ package reconciler
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -323,11 +325,14 @@ type AllocTuple struct {
 	Name      string
 	TaskGroup *structs.TaskGroup
 	Alloc     *structs.Allocation
+
+	// Canary marks the placement as a canary of the job's deployment.
+	Canary bool
 }
 
 // NodeReconcileResult is used to return the sets that result from the diff
 type NodeReconcileResult struct {
-	Place, Update, Migrate, Stop, Ignore, Lost, Disconnecting, Reconnecting []AllocTuple
+	Place, Update, Canary, Migrate, Stop, Ignore, Lost, Disconnecting, Reconnecting []AllocTuple
 }
 
 func (d *NodeReconcileResult) Fields() []any {
@@ -335,6 +340,7 @@ func (d *NodeReconcileResult) Fields() []any {
 		"ignore", d.Ignore,
 		"place", d.Place,
 		"update", d.Update,
+		"canary", d.Canary,
 		"stop", d.Stop,
 		"migrate", d.Migrate,
 		"lost", d.Lost,
@@ -348,6 +354,7 @@ func (d *NodeReconcileResult) Fields() []any {
 func (d *NodeReconcileResult) Append(other *NodeReconcileResult) {
 	d.Place = append(d.Place, other.Place...)
 	d.Update = append(d.Update, other.Update...)
+	d.Canary = append(d.Canary, other.Canary...)
 	d.Migrate = append(d.Migrate, other.Migrate...)
 	d.Stop = append(d.Stop, other.Stop...)
 	d.Ignore = append(d.Ignore, other.Ignore...)
@@ -355,3 +362,273 @@ func (d *NodeReconcileResult) Append(other *NodeReconcileResult) {
 	d.Disconnecting = append(d.Disconnecting, other.Disconnecting...)
 	d.Reconnecting = append(d.Reconnecting, other.Reconnecting...)
 }
+
+// NodeDeploymentResult is the result of reconciling the deployment of a system
+// or sysbatch job.
+type NodeDeploymentResult struct {
+	// Deployment is the current deployment of the job, if any. It may have
+	// been created by the reconciler.
+	Deployment *structs.Deployment
+
+	// CreatedDeployment is set to the deployment created by the reconciler,
+	// which must be submitted with the plan.
+	CreatedDeployment *structs.Deployment
+
+	// DeploymentUpdates are the status updates of the deployments of the job.
+	DeploymentUpdates []*structs.DeploymentStatusUpdate
+}
+
+// NodeDeployment reconciles the deployment of a system or sysbatch job with the
+// result of Node, once the in-place updates have been split from the
+// destructive ones left in result.Update.
+//
+// A deployment is created as soon as a task group whose update strategy uses
+// canaries has destructive updates. Canaries are picked out of the destructive
+// updates of the group, and so replace the allocation of the node they are
+// placed on. The remaining destructive updates and the new placements of the
+// group are held until the canaries are promoted, after which the destructive
+// updates roll out max_parallel at a time as the allocations of the deployment
+// become healthy. Held updates are moved to result.Ignore and canaries to
+// result.Canary.
+func NodeDeployment(
+	job *structs.Job, // job whose allocations are being reconciled
+	deployment *structs.Deployment, // latest deployment of the job
+	result *NodeReconcileResult, // result of Node, with only destructive updates
+	inplace []AllocTuple, // updates made in-place
+	evalPriority int,
+	now time.Time,
+) *NodeDeploymentResult {
+
+	res := new(NodeDeploymentResult)
+	var old *structs.Deployment
+	old, res.Deployment, res.DeploymentUpdates = cancelUnneededDeployments(job, deployment)
+	if job.Stopped() {
+		return res
+	}
+
+	groups := groupNodeUpdates(job, result, inplace)
+
+	// Canaries of an older deployment that were never promoted run a version
+	// of the job that was never deemed healthy, so they are replaced right
+	// away instead of being held behind new canaries.
+	var replace []AllocTuple
+	if old != nil {
+		var unpromoted []string
+		for _, dstate := range old.TaskGroups {
+			if !dstate.Promoted {
+				unpromoted = append(unpromoted, dstate.PlacedCanaries...)
+			}
+		}
+		for _, g := range groups {
+			g.destructive = slices.DeleteFunc(g.destructive, func(t AllocTuple) bool {
+				if slices.Contains(unpromoted, t.Alloc.ID) {
+					replace = append(replace, t)
+					return true
+				}
+				return false
+			})
+		}
+	}
+
+	if res.Deployment == nil {
+		res.CreatedDeployment = newNodeDeployment(job, old, groups, evalPriority, now)
+		res.Deployment = res.CreatedDeployment
+	}
+
+	result.Update = replace
+	complete := true
+	for _, g := range groups {
+		var dstate *structs.DeploymentState
+		if res.Deployment != nil {
+			dstate = res.Deployment.TaskGroups[g.tg.Name]
+		}
+		if dstate == nil {
+			result.Update = append(result.Update, g.destructive...)
+			continue
+		}
+		complete = g.reconcile(res.Deployment, dstate, result) && complete
+	}
+
+	// Mark the deployment as successful once every group of the deployment
+	// has been rolled out and is healthy.
+	if d := res.Deployment; d != nil && d != res.CreatedDeployment && d.Active() && complete {
+		res.DeploymentUpdates = append(res.DeploymentUpdates, &structs.DeploymentStatusUpdate{
+			DeploymentID:      d.ID,
+			Status:            structs.DeploymentStatusSuccessful,
+			StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+		})
+	}
+
+	return res
+}
+
+// nodeGroupUpdates are the sets of the node reconcile result of a single task
+// group which matter to its deployment.
+type nodeGroupUpdates struct {
+	tg          *structs.TaskGroup
+	destructive []AllocTuple
+	inplace     []AllocTuple
+	place       []AllocTuple
+	ignore      []AllocTuple
+}
+
+// groupNodeUpdates splits the node reconcile result by task group, in the order
+// of the task groups of the job.
+func groupNodeUpdates(job *structs.Job, result *NodeReconcileResult, inplace []AllocTuple) []*nodeGroupUpdates {
+	groups := make([]*nodeGroupUpdates, 0, len(job.TaskGroups))
+	byName := make(map[string]*nodeGroupUpdates, len(job.TaskGroups))
+	for _, tg := range job.TaskGroups {
+		g := &nodeGroupUpdates{tg: tg}
+		groups = append(groups, g)
+		byName[tg.Name] = g
+	}
+
+	add := func(tuples []AllocTuple, fn func(g *nodeGroupUpdates, t AllocTuple)) {
+		for _, t := range tuples {
+			if g, ok := byName[t.TaskGroup.Name]; ok {
+				fn(g, t)
+			}
+		}
+	}
+	add(result.Update, func(g *nodeGroupUpdates, t AllocTuple) { g.destructive = append(g.destructive, t) })
+	add(result.Place, func(g *nodeGroupUpdates, t AllocTuple) { g.place = append(g.place, t) })
+	add(result.Ignore, func(g *nodeGroupUpdates, t AllocTuple) { g.ignore = append(g.ignore, t) })
+	add(inplace, func(g *nodeGroupUpdates, t AllocTuple) {
+		// Reconnecting and terminal allocations are not updated by the plan.
+		if t.Alloc.Job.JobModifyIndex != job.JobModifyIndex && !t.Alloc.TerminalStatus() {
+			g.inplace = append(g.inplace, t)
+		}
+	})
+	return groups
+}
+
+// newNodeDeployment returns a new deployment for the job if any of its task
+// groups requires canaries, or nil otherwise. A version of the job that was
+// never deployed, such as its first version, also gets a deployment as soon as
+// a group using canaries has allocations to place or update, so that the
+// version is marked stable once healthy and can later be reverted to.
+func newNodeDeployment(job *structs.Job, old *structs.Deployment, groups []*nodeGroupUpdates, evalPriority int, now time.Time) *structs.Deployment {
+	deployed := old != nil && old.JobCreateIndex == job.CreateIndex && old.JobVersion == job.Version
+	if !slices.ContainsFunc(groups, (*nodeGroupUpdates).requiresCanaries) &&
+		(deployed || !slices.ContainsFunc(groups, (*nodeGroupUpdates).requiresInitialDeployment)) {
+		return nil
+	}
+
+	d := structs.NewDeployment(job, evalPriority, now.UnixNano())
+	for _, g := range groups {
+		u := g.tg.Update
+		updates := len(g.destructive) + len(g.inplace) + len(g.place)
+		if u.IsEmpty() || updates == 0 {
+			continue
+		}
+
+		dstate := &structs.DeploymentState{
+			AutoRevert:       u.AutoRevert,
+			AutoPromote:      u.AutoPromote,
+			ProgressDeadline: u.ProgressDeadline,
+			DesiredTotal:     updates,
+		}
+		if g.requiresCanaries() {
+			nodes := len(g.destructive) + len(g.inplace) + len(g.place) + len(g.ignore)
+			dstate.DesiredCanaries = u.DesiredCanaries(nodes)
+		}
+		d.TaskGroups[g.tg.Name] = dstate
+	}
+
+	if d.RequiresPromotion() {
+		if d.HasAutoPromote() {
+			d.StatusDescription = structs.DeploymentStatusDescriptionRunningAutoPromotion
+		} else {
+			d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
+		}
+	}
+	return d
+}
+
+// requiresCanaries returns whether the destructive updates of the group must
+// be canaried.
+func (g *nodeGroupUpdates) requiresCanaries() bool {
+	return g.tg.Update.HasCanaries() && len(g.destructive) != 0
+}
+
+// requiresInitialDeployment returns whether the group uses canaries and has
+// allocations to place or update, and so must be part of the deployment of a
+// version of the job that was never deployed.
+func (g *nodeGroupUpdates) requiresInitialDeployment() bool {
+	return g.tg.Update.HasCanaries() && len(g.destructive)+len(g.inplace)+len(g.place) != 0
+}
+
+// reconcile picks the canaries and the destructive updates of the group that
+// can be made given the state of its deployment, and holds the others. It
+// returns whether the deployment of the group is complete.
+func (g *nodeGroupUpdates) reconcile(d *structs.Deployment, dstate *structs.DeploymentState, result *NodeReconcileResult) bool {
+	paused := d.Status == structs.DeploymentStatusPaused ||
+		d.Status == structs.DeploymentStatusPending ||
+		d.Status == structs.DeploymentStatusInitializing
+	failed := d.Status == structs.DeploymentStatusFailed
+	isCanarying := dstate.DesiredCanaries != 0 && !dstate.Promoted
+
+	// Count the canaries that are placed and the allocations of the
+	// deployment that are not healthy yet.
+	canaries, unhealthy := 0, 0
+	for _, t := range g.ignore {
+		a := t.Alloc
+		if a == nil || a.DeploymentID != d.ID {
+			continue
+		}
+		if a.TerminalStatus() && a.ClientStatus != structs.AllocClientStatusComplete {
+			continue
+		}
+		if slices.Contains(dstate.PlacedCanaries, a.ID) {
+			canaries++
+		}
+		if !a.DeploymentStatus.IsHealthy() {
+			unhealthy++
+		}
+	}
+
+	// New placements are held while canarying, apart from the replacements of
+	// failed canaries.
+	placedCanaries := 0
+	if isCanarying {
+		place := result.Place[:0]
+		for _, t := range result.Place {
+			if t.TaskGroup.Name == g.tg.Name {
+				if t.Alloc == nil || !slices.Contains(dstate.PlacedCanaries, t.Alloc.ID) {
+					continue
+				}
+				t.Canary = true
+				canaries++
+				placedCanaries++
+			}
+			place = append(place, t)
+		}
+		result.Place = place
+	}
+
+	var allowed, held []AllocTuple
+	switch {
+	case paused || failed:
+		held = g.destructive
+	case isCanarying:
+		slices.SortFunc(g.destructive, func(a, b AllocTuple) int {
+			return strings.Compare(a.Alloc.NodeID, b.Alloc.NodeID)
+		})
+		n := min(max(dstate.DesiredCanaries-canaries, 0), len(g.destructive))
+		for _, t := range g.destructive[:n] {
+			t.Canary = true
+			result.Canary = append(result.Canary, t)
+		}
+		placedCanaries += n
+		held = g.destructive[n:]
+	default:
+		n := min(max(g.tg.Update.MaxParallel-unhealthy, 0), len(g.destructive))
+		allowed, held = g.destructive[:n], g.destructive[n:]
+	}
+	result.Update = append(result.Update, allowed...)
+	result.Ignore = append(result.Ignore, held...)
+
+	return len(g.destructive)+len(g.inplace)+placedCanaries == 0 &&
+		dstate.HealthyAllocs >= max(dstate.DesiredTotal, dstate.DesiredCanaries) &&
+		(dstate.DesiredCanaries == 0 || dstate.Promoted)
+}
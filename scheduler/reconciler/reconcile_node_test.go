@@ -632,3 +632,220 @@ func TestDiffSystemAllocs(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+
+	job := mock.SystemJob()
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 2
+	tg := job.TaskGroups[0]
+
+	oldJob := job.Copy()
+	oldJob.JobModifyIndex -= 1
+	oldJob.Version -= 1
+
+	// nodeResult returns a node reconcile result with a destructive update of
+	// the old job on each of the n nodes.
+	nodeResult := func(n int) *NodeReconcileResult {
+		result := new(NodeReconcileResult)
+		for i := range n {
+			result.Update = append(result.Update, AllocTuple{
+				Name:      "my-job.web[0]",
+				TaskGroup: tg,
+				Alloc: &structs.Allocation{
+					ID:        uuid.Generate(),
+					NodeID:    fmt.Sprintf("node-%d", i),
+					Name:      "my-job.web[0]",
+					TaskGroup: tg.Name,
+					Job:       oldJob,
+				},
+			})
+		}
+		return result
+	}
+
+	// running returns a tuple of an allocation of the new job running as part
+	// of the deployment.
+	running := func(d *structs.Deployment, healthy bool) AllocTuple {
+		return AllocTuple{
+			Name:      "my-job.web[0]",
+			TaskGroup: tg,
+			Alloc: &structs.Allocation{
+				ID:               uuid.Generate(),
+				NodeID:           uuid.Generate(),
+				Name:             "my-job.web[0]",
+				TaskGroup:        tg.Name,
+				Job:              job,
+				DeploymentID:     d.ID,
+				ClientStatus:     structs.AllocClientStatusRunning,
+				DeploymentStatus: &structs.AllocDeploymentStatus{Healthy: pointer.Of(healthy)},
+			},
+		}
+	}
+
+	t.Run("no canaries", func(t *testing.T) {
+		result := nodeResult(4)
+		res := NodeDeployment(job, nil, result, nil, 50, now)
+		must.Nil(t, res.Deployment)
+		must.Nil(t, res.CreatedDeployment)
+		must.Len(t, 4, result.Update)
+		must.Len(t, 0, result.Canary)
+	})
+
+	t.Run("canary count", func(t *testing.T) {
+		job := job.Copy()
+		job.TaskGroups[0].Update.Canary = 2
+
+		result := nodeResult(4)
+		res := NodeDeployment(job, nil, result, nil, 50, now)
+		must.NotNil(t, res.CreatedDeployment)
+		must.Eq(t, structs.DeploymentStatusDescriptionRunningNeedsPromotion,
+			res.CreatedDeployment.StatusDescription)
+
+		dstate := res.CreatedDeployment.TaskGroups[tg.Name]
+		must.NotNil(t, dstate)
+		must.Eq(t, 2, dstate.DesiredCanaries)
+		must.Eq(t, 4, dstate.DesiredTotal)
+
+		must.Len(t, 0, result.Update)
+		must.Len(t, 2, result.Canary)
+		must.Len(t, 2, result.Ignore)
+		for _, c := range result.Canary {
+			must.True(t, c.Canary)
+		}
+	})
+
+	t.Run("canary percent", func(t *testing.T) {
+		job := job.Copy()
+		job.TaskGroups[0].Update.CanaryPercent = 50
+
+		result := nodeResult(5)
+		res := NodeDeployment(job, nil, result, nil, 50, now)
+		must.NotNil(t, res.CreatedDeployment)
+		must.Eq(t, 3, res.CreatedDeployment.TaskGroups[tg.Name].DesiredCanaries)
+		must.Len(t, 3, result.Canary)
+		must.Len(t, 2, result.Ignore)
+	})
+
+	t.Run("held until promoted", func(t *testing.T) {
+		job := job.Copy()
+		job.TaskGroups[0].Update.Canary = 1
+
+		d := structs.NewDeployment(job, 50, now.UnixNano())
+		canary := running(d, true)
+		d.TaskGroups[tg.Name] = &structs.DeploymentState{
+			DesiredCanaries: 1,
+			DesiredTotal:    4,
+			PlacedCanaries:  []string{canary.Alloc.ID},
+			HealthyAllocs:   1,
+		}
+
+		result := nodeResult(3)
+		result.Ignore = append(result.Ignore, canary)
+		result.Place = append(result.Place, AllocTuple{Name: "my-job.web[0]", TaskGroup: tg})
+
+		res := NodeDeployment(job, d, result, nil, 50, now)
+		must.Nil(t, res.CreatedDeployment)
+		must.Len(t, 0, res.DeploymentUpdates)
+		must.Len(t, 0, result.Update)
+		must.Len(t, 0, result.Canary)
+		must.Len(t, 0, result.Place)
+		must.Len(t, 4, result.Ignore)
+	})
+
+	t.Run("rolling after promotion", func(t *testing.T) {
+		d := structs.NewDeployment(job, 50, now.UnixNano())
+		d.TaskGroups[tg.Name] = &structs.DeploymentState{
+			DesiredCanaries: 1,
+			DesiredTotal:    4,
+			Promoted:        true,
+		}
+
+		result := nodeResult(3)
+		result.Ignore = append(result.Ignore, running(d, false))
+
+		res := NodeDeployment(job, d, result, nil, 50, now)
+		must.Len(t, 0, res.DeploymentUpdates)
+		must.Len(t, 1, result.Update)
+		must.Len(t, 3, result.Ignore)
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		d := structs.NewDeployment(job, 50, now.UnixNano())
+		d.TaskGroups[tg.Name] = &structs.DeploymentState{
+			DesiredCanaries: 1,
+			DesiredTotal:    2,
+			Promoted:        true,
+			HealthyAllocs:   2,
+		}
+
+		result := &NodeReconcileResult{
+			Ignore: []AllocTuple{running(d, true), running(d, true)},
+		}
+
+		res := NodeDeployment(job, d, result, nil, 50, now)
+		must.Len(t, 1, res.DeploymentUpdates)
+		must.Eq(t, d.ID, res.DeploymentUpdates[0].DeploymentID)
+		must.Eq(t, structs.DeploymentStatusSuccessful, res.DeploymentUpdates[0].Status)
+	})
+
+	t.Run("initial version", func(t *testing.T) {
+		job := job.Copy()
+		job.TaskGroups[0].Update.Canary = 1
+
+		result := &NodeReconcileResult{}
+		for range 3 {
+			result.Place = append(result.Place, AllocTuple{Name: "my-job.web[0]", TaskGroup: tg})
+		}
+
+		// The first version of the job is deployed without canaries so that
+		// it is marked stable once healthy
+		res := NodeDeployment(job, nil, result, nil, 50, now)
+		must.NotNil(t, res.CreatedDeployment)
+		must.Eq(t, structs.DeploymentStatusDescriptionRunning, res.CreatedDeployment.StatusDescription)
+
+		dstate := res.CreatedDeployment.TaskGroups[tg.Name]
+		must.NotNil(t, dstate)
+		must.Eq(t, 0, dstate.DesiredCanaries)
+		must.Eq(t, 3, dstate.DesiredTotal)
+		must.Len(t, 3, result.Place)
+		must.Len(t, 0, result.Canary)
+
+		// Placements on new nodes don't deploy the version again
+		d := res.CreatedDeployment.Copy()
+		d.Status = structs.DeploymentStatusSuccessful
+		result = &NodeReconcileResult{
+			Place: []AllocTuple{{Name: "my-job.web[0]", TaskGroup: tg}},
+		}
+		res = NodeDeployment(job, d, result, nil, 50, now)
+		must.Nil(t, res.CreatedDeployment)
+		must.Len(t, 1, result.Place)
+	})
+
+	t.Run("replaces unpromoted canaries", func(t *testing.T) {
+		job := job.Copy()
+		job.TaskGroups[0].Update.Canary = 1
+
+		old := structs.NewDeployment(oldJob, 50, now.UnixNano())
+		result := nodeResult(3)
+		canary := result.Update[2].Alloc
+		old.TaskGroups[tg.Name] = &structs.DeploymentState{
+			DesiredCanaries: 1,
+			DesiredTotal:    3,
+			PlacedCanaries:  []string{canary.ID},
+		}
+
+		res := NodeDeployment(job, old, result, nil, 50, now)
+		must.Len(t, 1, res.DeploymentUpdates)
+		must.Eq(t, structs.DeploymentStatusCancelled, res.DeploymentUpdates[0].Status)
+		must.NotNil(t, res.CreatedDeployment)
+
+		must.Len(t, 1, result.Update)
+		must.Eq(t, canary.ID, result.Update[0].Alloc.ID)
+		must.Len(t, 1, result.Canary)
+		must.Len(t, 1, result.Ignore)
+	})
+}
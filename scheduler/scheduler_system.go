@@ -6,6 +6,7 @@ package scheduler
 import (
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -40,6 +41,7 @@ type SystemScheduler struct {
 
 	eval       *structs.Evaluation
 	job        *structs.Job
+	deployment *structs.Deployment
 	plan       *structs.Plan
 	planResult *structs.PlanResult
 	ctx        *feasible.EvalContext
@@ -100,7 +102,7 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) (err error) {
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason", eval.TriggeredBy)
 		return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil,
 			s.failedTGAllocs, s.planAnnotations, structs.EvalStatusFailed, desc,
-			s.queuedAllocs, "")
+			s.queuedAllocs, s.deployment.GetID())
 	}
 
 	limit := maxSystemScheduleAttempts
@@ -114,7 +116,7 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) (err error) {
 		if statusErr, ok := err.(*SetStatusError); ok {
 			return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil,
 				s.failedTGAllocs, s.planAnnotations, statusErr.EvalStatus, err.Error(),
-				s.queuedAllocs, "")
+				s.queuedAllocs, s.deployment.GetID())
 		}
 		return err
 	}
@@ -122,7 +124,7 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) (err error) {
 	// Update the status to complete
 	return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil,
 		s.failedTGAllocs, s.planAnnotations, structs.EvalStatusComplete, "",
-		s.queuedAllocs, "")
+		s.queuedAllocs, s.deployment.GetID())
 }
 
 // process is wrapped in retryMax to iteratively run the handler until we have no
@@ -154,6 +156,12 @@ func (s *SystemScheduler) process() (bool, error) {
 	// Create a plan
 	s.plan = s.eval.MakePlan(s.job)
 
+	// Get any existing deployment
+	s.deployment, err = s.state.LatestDeploymentByJobID(ws, s.eval.Namespace, s.eval.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job deployment %q: %v", s.eval.JobID, err)
+	}
+
 	// Reset the failed allocations
 	s.failedTGAllocs = nil
 
@@ -305,11 +313,24 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		allocExistsForTaskGroup[inplaceUpdate.TaskGroup.Name] = true
 	}
 
+	// Pick the canaries and hold the updates the deployment isn't ready for.
+	d := reconciler.NodeDeployment(s.job, s.deployment, r, inplaceUpdates, s.eval.Priority, time.Now().UTC())
+	s.deployment = d.Deployment
+	s.plan.Deployment = d.CreatedDeployment
+	s.plan.DeploymentUpdates = d.DeploymentUpdates
+	s.setInplaceDeployment(inplaceUpdates)
+
 	s.planAnnotations = &structs.PlanAnnotations{
-		DesiredTGUpdates: desiredUpdates(r, inplaceUpdates, destructiveUpdates),
+		DesiredTGUpdates: desiredUpdates(r, inplaceUpdates, r.Update),
 	}
 
-	// Check if a rolling upgrade strategy is being used
+	// Canaries replace the allocation of their node regardless of the
+	// rolling upgrade strategy.
+	canaries := len(r.Canary)
+	evictAndPlace(s.ctx, r, r.Canary, sstructs.StatusAllocUpdating, &canaries)
+
+	// Check if a rolling upgrade strategy is being used. The updates of a
+	// deployment are further limited by the health of its allocations.
 	limit := len(r.Update)
 	if !s.job.Stopped() && s.job.Update.Rolling() {
 		limit = s.job.Update.MaxParallel
@@ -426,6 +447,12 @@ func (s *SystemScheduler) computePlacements(place []reconciler.AllocTuple, exist
 					desired.Place -= 1
 				}
 
+				// Nodes that don't meet the constraints won't run the
+				// allocations of the deployment being created either.
+				if d := s.plan.Deployment; d != nil && d.TaskGroups[tgName] != nil {
+					d.TaskGroups[tgName].DesiredTotal--
+				}
+
 				// Filtered nodes are not reported to users, just omitted from the job status
 				continue
 			}
@@ -492,6 +519,7 @@ func (s *SystemScheduler) computePlacements(place []reconciler.AllocTuple, exist
 			Metrics:            s.ctx.Metrics(),
 			NodeID:             option.Node.ID,
 			NodeName:           option.Node.Name,
+			DeploymentID:       s.deploymentIDForGroup(tgName),
 			TaskResources:      resources.OldTaskResources(),
 			AllocatedResources: resources,
 			DesiredStatus:      structs.AllocDesiredStatusRun,
@@ -510,6 +538,14 @@ func (s *SystemScheduler) computePlacements(place []reconciler.AllocTuple, exist
 			alloc.PreviousAllocation = missing.Alloc.ID
 		}
 
+		// If we are placing a canary, mark it as such so that it's added to
+		// the deployment state of its group.
+		if missing.Canary && alloc.DeploymentID != "" {
+			alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+				Canary: true,
+			}
+		}
+
 		// If this placement involves preemption, set DesiredState to evict for those allocations
 		if option.PreemptedAllocs != nil {
 			var preemptedAllocIDs []string
@@ -534,6 +570,32 @@ func (s *SystemScheduler) computePlacements(place []reconciler.AllocTuple, exist
 	return nil
 }
 
+// deploymentIDForGroup returns the ID of the active deployment of the job if
+// the task group is part of it, or an empty string otherwise.
+func (s *SystemScheduler) deploymentIDForGroup(tgName string) string {
+	if s.deployment == nil || !s.deployment.Active() || s.deployment.TaskGroups[tgName] == nil {
+		return ""
+	}
+	return s.deployment.ID
+}
+
+// setInplaceDeployment adds the allocations updated in-place to the active
+// deployment of their task group, if any, so their health is tracked again.
+func (s *SystemScheduler) setInplaceDeployment(inplace []reconciler.AllocTuple) {
+	for _, update := range inplace {
+		deploymentID := s.deploymentIDForGroup(update.TaskGroup.Name)
+		if deploymentID == "" {
+			continue
+		}
+		for _, alloc := range s.plan.NodeAllocation[update.Alloc.NodeID] {
+			if alloc.ID == update.Alloc.ID && alloc.DeploymentID != deploymentID {
+				alloc.DeploymentID = deploymentID
+				alloc.DeploymentStatus = nil
+			}
+		}
+	}
+}
+
 // addBlocked creates a new blocked eval for this job on this node
 // and submit to the planner (worker.go), which keeps the eval for execution later
 func (s *SystemScheduler) addBlocked(node *structs.Node) error {
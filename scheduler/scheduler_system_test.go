@@ -723,6 +723,193 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobModify_Canaries(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	// Create some nodes
+	nodes := createNodes(t, h, 10)
+
+	// Generate a fake job with allocations
+	job := mock.SystemJob()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.AllocForNode(node)
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job with canaries, such that it cannot be done in-place
+	job2 := job.Copy()
+	job2.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job2.TaskGroups[0].Update.Canary = 2
+	job2.TaskGroups[0].Update.MaxParallel = 3
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+	process := func() {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		must.NoError(t, h.Process(NewSystemScheduler, eval))
+		must.Len(t, 0, h.CreateEvals)
+	}
+
+	// Ensure the plan created a deployment and replaced only the canaries
+	process()
+	must.Len(t, 1, h.Plans)
+	plan := h.Plans[0]
+	must.NotNil(t, plan.Deployment)
+	dstate := plan.Deployment.TaskGroups["web"]
+	must.NotNil(t, dstate)
+	must.Eq(t, 2, dstate.DesiredCanaries)
+	must.Eq(t, 10, dstate.DesiredTotal)
+
+	var update, planned []*structs.Allocation
+	for _, updateList := range plan.NodeUpdate {
+		update = append(update, updateList...)
+	}
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 2, update)
+	must.Len(t, 2, planned)
+	for _, alloc := range planned {
+		must.Eq(t, plan.Deployment.ID, alloc.DeploymentID)
+		must.True(t, alloc.DeploymentStatus.IsCanary())
+	}
+
+	// Ensure the remaining updates are held while the canaries are running
+	process()
+	must.Len(t, 1, h.Plans)
+
+	// Mark the canaries healthy and promote the deployment
+	d, err := h.State.LatestDeploymentByJobID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	var canaries []*structs.Allocation
+	for _, alloc := range planned {
+		alloc = alloc.Copy()
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus.Healthy = pointer.Of(true)
+		canaries = append(canaries, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), canaries))
+	d = d.Copy()
+	d.TaskGroups["web"].Promoted = true
+	d.TaskGroups["web"].HealthyAllocs = 2
+	must.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+
+	// Ensure the rollout proceeds up to max_parallel
+	process()
+	must.Len(t, 2, h.Plans)
+	plan = h.Plans[1]
+	update = nil
+	for _, updateList := range plan.NodeUpdate {
+		update = append(update, updateList...)
+	}
+	must.Len(t, 3, update)
+	for _, allocList := range plan.NodeAllocation {
+		for _, alloc := range allocList {
+			must.Eq(t, d.ID, alloc.DeploymentID)
+			must.False(t, alloc.DeploymentStatus.IsCanary())
+		}
+	}
+}
+
+func TestSystemSched_JobModify_Canaries_Rolling(t *testing.T) {
+	ci.Parallel(t)
+
+	h := tests.NewHarness(t)
+
+	// Create some nodes
+	nodes := createNodes(t, h, 10)
+
+	// Generate a fake job with two task groups and allocations
+	job := mock.SystemJob()
+	api := job.TaskGroups[0].Copy()
+	api.Name = "api"
+	job.TaskGroups = append(job.TaskGroups, api)
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		for _, tg := range job.TaskGroups {
+			alloc := mock.AllocForNode(node)
+			alloc.Job = job
+			alloc.JobID = job.ID
+			alloc.TaskGroup = tg.Name
+			alloc.Name = structs.AllocName(job.Name, tg.Name, 0)
+			allocs = append(allocs, alloc)
+		}
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job with a rolling upgrade and canaries for the web group,
+	// such that it cannot be done in-place
+	job2 := job.Copy()
+	job2.Update = structs.UpdateStrategy{
+		Stagger:     30 * time.Second,
+		MaxParallel: 2,
+	}
+	for _, tg := range job2.TaskGroups {
+		tg.Update = structs.DefaultUpdateStrategy.Copy()
+		tg.Update.MaxParallel = 3
+		tg.Tasks[0].Config["command"] = "/bin/other"
+	}
+	job2.TaskGroups[0].Update.Canary = 1
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+	process := func(triggeredBy string) *structs.Plan {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: triggeredBy,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		must.NoError(t, h.Process(NewSystemScheduler, eval))
+		return h.Plans[len(h.Plans)-1]
+	}
+	updatesByGroup := func(plan *structs.Plan) map[string]int {
+		updates := make(map[string]int)
+		for _, updateList := range plan.NodeUpdate {
+			for _, alloc := range updateList {
+				updates[alloc.TaskGroup]++
+			}
+		}
+		return updates
+	}
+
+	// Ensure the first eval places the canary and updates the api group up
+	// to the max_parallel of the job, then creates a follow up eval
+	plan := process(structs.EvalTriggerJobRegister)
+	must.NotNil(t, plan.Deployment)
+	must.Eq(t, map[string]int{"web": 1, "api": 2}, updatesByGroup(plan))
+	must.Len(t, 1, h.CreateEvals)
+	must.Eq(t, structs.EvalTriggerRollingUpdate, h.CreateEvals[0].TriggeredBy)
+
+	// Ensure the follow up eval is limited further by the allocations of the
+	// deployment which are not healthy yet
+	plan = process(structs.EvalTriggerRollingUpdate)
+	must.Len(t, 2, h.Plans)
+	must.Eq(t, map[string]int{"api": 1}, updatesByGroup(plan))
+	must.Len(t, 1, h.CreateEvals)
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,480 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package simulator runs the scheduler against an in-memory state store, such
+// as one restored from a snapshot, to find out how a cluster would respond to
+// proposed jobs and node changes without touching a live cluster.
+package simulator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	sstructs "github.com/hashicorp/nomad/scheduler/structs"
+)
+
+// Simulator applies changes to a state store and runs the scheduler for the
+// evaluations they trigger. It is the Planner of the schedulers it runs and
+// applies every plan to the state store, so each change sees the placements
+// of the changes before it. A Simulator is not safe for concurrent use.
+type Simulator struct {
+	logger log.Logger
+	state  *state.StateStore
+
+	// index is the last index written to the state store
+	index uint64
+
+	// result collects the outcome of the evaluations of the current change
+	result *Result
+}
+
+// Result is the outcome of a simulated change.
+type Result struct {
+	// Placed are the new allocations placed by the scheduler.
+	Placed []*structs.Allocation
+
+	// Stopped are the allocations stopped by the scheduler, because they
+	// were replaced, lost or are no longer needed.
+	Stopped []*structs.Allocation
+
+	// Preempted are the allocations preempted to make room for higher
+	// priority placements.
+	Preempted []*structs.Allocation
+
+	// Failures are the task groups that could not be placed.
+	Failures []*Failure
+}
+
+// Failure describes the allocations of a task group that could not be placed.
+type Failure struct {
+	Namespace string
+	JobID     string
+	TaskGroup string
+	Metrics   *structs.AllocMetric
+}
+
+// NodeUtilization is the share of the resources of a node allocated once the
+// simulated changes have been applied.
+type NodeUtilization struct {
+	Node      *structs.Node
+	Allocs    int
+	Used      *structs.ComparableResources
+	Available *structs.ComparableResources
+}
+
+// NewSimulator returns a Simulator that writes to the given state store,
+// starting after its latest index.
+func NewSimulator(logger log.Logger, store *state.StateStore) (*Simulator, error) {
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest index: %v", err)
+	}
+	return &Simulator{
+		logger: logger.Named("simulator"),
+		state:  store,
+		index:  index,
+	}, nil
+}
+
+// RegisterJob registers the job, or updates it if it already exists, and
+// runs the scheduler for its evaluation.
+func (s *Simulator) RegisterJob(job *structs.Job) (*Result, error) {
+	job = job.Copy()
+	job.Canonicalize()
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("job %q is invalid: %w", job.ID, err)
+	}
+
+	if err := s.state.UpsertJob(structs.IgnoreUnknownTypeFlag, s.nextIndex(), nil, job); err != nil {
+		return nil, fmt.Errorf("failed to register job %q: %v", job.ID, err)
+	}
+	stored, err := s.state.JobByID(nil, job.Namespace, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up job %q: %v", job.ID, err)
+	}
+
+	result := &Result{}
+	eval := s.newEval(stored, structs.EvalTriggerJobRegister)
+	if err := s.process(eval, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AddNodes adds count ready nodes copied from an existing node of the node
+// class, and runs the scheduler for the system jobs that may now be placed on
+// them and the jobs whose placements are blocked on capacity.
+func (s *Simulator) AddNodes(class string, count int) (*Result, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("node count must be greater than zero; got %d", count)
+	}
+
+	nodes, err := s.nodes(func(n *structs.Node) bool { return n.NodeClass == class })
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node of class %q to copy", class)
+	}
+
+	// Prefer a ready node as the template so the copies are placed on.
+	template := nodes[0]
+	for _, n := range nodes {
+		if n.Ready() {
+			template = n
+			break
+		}
+	}
+
+	pools := make(map[string]struct{})
+	for i := range count {
+		node := template.Copy()
+		node.ID = uuid.Generate()
+		node.SecretID = uuid.Generate()
+		node.Name = fmt.Sprintf("%s-simulated-%d", template.Name, i+1)
+		node.Status = structs.NodeStatusReady
+		node.SchedulingEligibility = structs.NodeSchedulingEligible
+		node.DrainStrategy = nil
+		node.Events = nil
+		if err := node.ComputeClass(); err != nil {
+			return nil, fmt.Errorf("failed to compute node class: %v", err)
+		}
+		if err := s.state.UpsertNode(structs.IgnoreUnknownTypeFlag, s.nextIndex(), node); err != nil {
+			return nil, fmt.Errorf("failed to add node: %v", err)
+		}
+		pools[node.NodePool] = struct{}{}
+	}
+
+	result := &Result{}
+	evals, err := s.capacityEvals(pools)
+	if err != nil {
+		return nil, err
+	}
+	for _, eval := range evals {
+		if err := s.process(eval, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// RemoveNodePool marks every node of the node pool as down, and runs the
+// scheduler for the jobs that had allocations on them.
+func (s *Simulator) RemoveNodePool(pool string) (*Result, error) {
+	nodes, err := s.nodes(func(n *structs.Node) bool {
+		return n.NodePool == pool && n.Status != structs.NodeStatusDown
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node in node pool %q to remove", pool)
+	}
+
+	jobs := make(map[structs.NamespacedID]struct{})
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemCluster).
+		SetMessage("Node removed by scheduler simulation")
+	for _, node := range nodes {
+		now := time.Now().Unix()
+		if err := s.state.UpdateNodeStatus(structs.IgnoreUnknownTypeFlag, s.nextIndex(),
+			node.ID, structs.NodeStatusDown, now, event); err != nil {
+			return nil, fmt.Errorf("failed to remove node %q: %v", node.ID, err)
+		}
+
+		allocs, err := s.state.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up allocations of node %q: %v", node.ID, err)
+		}
+		for _, alloc := range allocs {
+			if !alloc.TerminalStatus() {
+				jobs[structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}] = struct{}{}
+			}
+		}
+	}
+
+	result := &Result{}
+	for _, id := range sortedIDs(jobs) {
+		job, err := s.state.JobByID(nil, id.Namespace, id.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up job %q: %v", id.ID, err)
+		}
+		if job == nil {
+			continue
+		}
+		if err := s.process(s.newEval(job, structs.EvalTriggerNodeUpdate), result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Utilization returns the resources allocated on every node that isn't down,
+// sorted by node name.
+func (s *Simulator) Utilization() ([]*NodeUtilization, error) {
+	nodes, err := s.nodes(func(n *structs.Node) bool { return n.Status != structs.NodeStatusDown })
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*NodeUtilization, 0, len(nodes))
+	for _, node := range nodes {
+		allocs, err := s.state.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up allocations of node %q: %v", node.ID, err)
+		}
+
+		// Nodes registered by older clients may be missing their resources,
+		// which then count as zero.
+		u := &NodeUtilization{
+			Node:      node,
+			Used:      new(structs.ComparableResources),
+			Available: new(structs.ComparableResources),
+		}
+		u.Available.Add(node.NodeResources.Comparable())
+		u.Available.Subtract(node.ReservedResources.Comparable())
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() || alloc.AllocatedResources == nil {
+				continue
+			}
+			u.Allocs++
+			u.Used.Add(alloc.AllocatedResources.Comparable())
+		}
+		out = append(out, u)
+	}
+
+	slices.SortFunc(out, func(a, b *NodeUtilization) int {
+		if a.Node.Name != b.Node.Name {
+			return cmp.Compare(a.Node.Name, b.Node.Name)
+		}
+		return cmp.Compare(a.Node.ID, b.Node.ID)
+	})
+	return out, nil
+}
+
+// capacityEvals returns the evaluations of the system jobs of the node pools
+// and of the jobs with blocked evaluations, which new nodes may unblock.
+func (s *Simulator) capacityEvals(pools map[string]struct{}) ([]*structs.Evaluation, error) {
+	var evals []*structs.Evaluation
+	blocked := make(map[structs.NamespacedID]struct{})
+
+	iter, err := s.state.Evals(nil, state.SortDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evaluations: %v", err)
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eval := raw.(*structs.Evaluation)
+		if eval.Status != structs.EvalStatusBlocked {
+			continue
+		}
+		id := structs.NamespacedID{Namespace: eval.Namespace, ID: eval.JobID}
+		if _, ok := blocked[id]; ok {
+			continue
+		}
+		blocked[id] = struct{}{}
+
+		// Process the blocked evaluation as if it had been unblocked.
+		unblocked := eval.Copy()
+		unblocked.Status = structs.EvalStatusPending
+		unblocked.StatusDescription = ""
+		evals = append(evals, unblocked)
+	}
+
+	jobs, err := s.state.Jobs(nil, state.SortDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	for raw := jobs.Next(); raw != nil; raw = jobs.Next() {
+		job := raw.(*structs.Job)
+		if job.Type != structs.JobTypeSystem && job.Type != structs.JobTypeSysBatch {
+			continue
+		}
+		if _, ok := pools[job.NodePool]; !ok && job.NodePool != structs.NodePoolAll {
+			continue
+		}
+		if job.Stopped() || job.IsParameterized() || job.IsPeriodic() {
+			continue
+		}
+		if _, ok := blocked[job.NamespacedID()]; ok {
+			continue
+		}
+		evals = append(evals, s.newEval(job, structs.EvalTriggerNodeUpdate))
+	}
+	return evals, nil
+}
+
+// nodes returns the nodes that match the filter, sorted by name.
+func (s *Simulator) nodes(filter func(*structs.Node) bool) ([]*structs.Node, error) {
+	iter, err := s.state.Nodes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	var nodes []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if filter(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *structs.Node) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return nodes, nil
+}
+
+func (s *Simulator) newEval(job *structs.Job, triggeredBy string) *structs.Evaluation {
+	now := time.Now().UnixNano()
+	return &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      job.Namespace,
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    triggeredBy,
+		JobID:          job.ID,
+		JobModifyIndex: job.JobModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+}
+
+// process runs the scheduler for the evaluation, adding its outcome to the
+// result.
+func (s *Simulator) process(eval *structs.Evaluation, result *Result) error {
+	if err := s.state.UpsertEvals(structs.IgnoreUnknownTypeFlag, s.nextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return fmt.Errorf("failed to create evaluation: %v", err)
+	}
+
+	snap, err := s.state.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot state: %v", err)
+	}
+	sched, err := scheduler.NewScheduler(eval.Type, s.logger, nil, snap, s)
+	if err != nil {
+		return err
+	}
+
+	s.result = result
+	defer func() { s.result = nil }()
+
+	if err := sched.Process(eval); err != nil {
+		return fmt.Errorf("failed to process evaluation for job %q: %v", eval.JobID, err)
+	}
+	return nil
+}
+
+func (s *Simulator) nextIndex() uint64 {
+	s.index++
+	return s.index
+}
+
+// SubmitPlan applies the plan to the state store.
+func (s *Simulator) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, sstructs.State, error) {
+	index := s.nextIndex()
+	now := time.Now().UTC().UnixNano()
+
+	var allocs, preempted []*structs.Allocation
+	for _, updates := range plan.NodeUpdate {
+		for _, alloc := range updates {
+			alloc.ModifyTime = now
+			allocs = append(allocs, alloc)
+			s.result.Stopped = append(s.result.Stopped, alloc)
+		}
+	}
+	for _, placed := range plan.NodeAllocation {
+		for _, alloc := range placed {
+			if alloc.CreateTime == 0 {
+				alloc.CreateTime = now
+			}
+			alloc.ModifyTime = now
+			allocs = append(allocs, alloc)
+			if alloc.CreateIndex == 0 {
+				s.result.Placed = append(s.result.Placed, alloc)
+			}
+		}
+	}
+	for _, preemptions := range plan.NodePreemptions {
+		for _, alloc := range preemptions {
+			alloc.ModifyTime = now
+			preempted = append(preempted, alloc)
+			s.result.Preempted = append(s.result.Preempted, alloc)
+		}
+	}
+
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job:   plan.Job,
+			Alloc: allocs,
+		},
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		EvalID:            plan.EvalID,
+		NodePreemptions:   preempted,
+	}
+	if err := s.state.UpsertPlanResults(structs.IgnoreUnknownTypeFlag, index, &req); err != nil {
+		return nil, nil, err
+	}
+
+	result := &structs.PlanResult{
+		NodeUpdate:        plan.NodeUpdate,
+		NodeAllocation:    plan.NodeAllocation,
+		NodePreemptions:   plan.NodePreemptions,
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		AllocIndex:        index,
+	}
+	return result, nil, nil
+}
+
+// UpdateEval stores the evaluation and records its failed placements.
+func (s *Simulator) UpdateEval(eval *structs.Evaluation) error {
+	for _, tg := range slices.Sorted(maps.Keys(eval.FailedTGAllocs)) {
+		s.result.Failures = append(s.result.Failures, &Failure{
+			Namespace: eval.Namespace,
+			JobID:     eval.JobID,
+			TaskGroup: tg,
+			Metrics:   eval.FailedTGAllocs[tg],
+		})
+	}
+	return s.state.UpsertEvals(structs.IgnoreUnknownTypeFlag, s.nextIndex(), []*structs.Evaluation{eval})
+}
+
+// CreateEval stores the evaluation, such as the blocked evaluation of failed
+// placements, without processing it.
+func (s *Simulator) CreateEval(eval *structs.Evaluation) error {
+	return s.state.UpsertEvals(structs.IgnoreUnknownTypeFlag, s.nextIndex(), []*structs.Evaluation{eval})
+}
+
+// ReblockEval stores the blocked evaluation.
+func (s *Simulator) ReblockEval(eval *structs.Evaluation) error {
+	return s.CreateEval(eval)
+}
+
+// ServersMeetMinimumVersion always returns true as the simulation runs the
+// scheduler of the local binary.
+func (s *Simulator) ServersMeetMinimumVersion(_ *version.Version, _ bool) bool {
+	return true
+}
+
+func sortedIDs(ids map[structs.NamespacedID]struct{}) []structs.NamespacedID {
+	out := make([]structs.NamespacedID, 0, len(ids))
+	for id := range ids {
+		out = append(out, id)
+	}
+	slices.SortFunc(out, func(a, b structs.NamespacedID) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return out
+}
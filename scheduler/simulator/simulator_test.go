@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testSimulator returns a Simulator of a state store with the given nodes.
+func testSimulator(t *testing.T, nodes ...*structs.Node) (*Simulator, *state.StateStore) {
+	store := state.TestStateStore(t)
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	must.NoError(t, err)
+	return sim, store
+}
+
+// testJob returns a service job whose allocations use 2GB of memory, so three
+// of them fit on a mock node.
+func testJob(count int) *structs.Job {
+	job := mock.Job()
+	job.TaskGroups[0].Count = count
+	job.TaskGroups[0].Tasks[0].Resources.MemoryMB = 2048
+	return job
+}
+
+func TestSimulator_RegisterJob_AddNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	sim, store := testSimulator(t, mock.Node(), mock.Node())
+
+	result, err := sim.RegisterJob(testJob(10))
+	must.NoError(t, err)
+	must.Len(t, 6, result.Placed)
+	must.Len(t, 1, result.Failures)
+	must.Eq(t, "web", result.Failures[0].TaskGroup)
+	must.Eq(t, 3, result.Failures[0].Metrics.CoalescedFailures)
+
+	// Adding nodes unblocks the placements that failed.
+	result, err = sim.AddNodes("linux-medium-pci", 2)
+	must.NoError(t, err)
+	must.Len(t, 4, result.Placed)
+	must.SliceEmpty(t, result.Failures)
+
+	nodes, err := store.Nodes(nil)
+	must.NoError(t, err)
+	count := 0
+	for raw := nodes.Next(); raw != nil; raw = nodes.Next() {
+		count++
+	}
+	must.Eq(t, 4, count)
+
+	util, err := sim.Utilization()
+	must.NoError(t, err)
+	must.Len(t, 4, util)
+	allocs := 0
+	for _, u := range util {
+		allocs += u.Allocs
+		must.Eq(t, int64(u.Allocs*2048), u.Used.Flattened.Memory.MemoryMB)
+		must.Eq(t, int64(8192-256), u.Available.Flattened.Memory.MemoryMB)
+	}
+	must.Eq(t, 10, allocs)
+
+	_, err = sim.AddNodes("missing", 1)
+	must.ErrorContains(t, err, `no node of class "missing"`)
+}
+
+func TestSimulator_Utilization_MissingResources(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	node.NodeResources = nil
+	node.ReservedResources = nil
+	sim, _ := testSimulator(t, node)
+
+	util, err := sim.Utilization()
+	must.NoError(t, err)
+	must.Len(t, 1, util)
+	must.Eq(t, 0, util[0].Allocs)
+	must.Eq(t, int64(0), util[0].Available.Flattened.Memory.MemoryMB)
+	must.Eq(t, int64(0), util[0].Available.Flattened.Cpu.CpuShares)
+}
+
+func TestSimulator_RemoveNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	blue := []*structs.Node{mock.Node(), mock.Node()}
+	for _, node := range blue {
+		node.NodePool = "blue"
+	}
+	sim, store := testSimulator(t, blue[0], blue[1], mock.Node(), mock.Node())
+	must.NoError(t, store.UpsertNodePools(structs.MsgTypeTestSetup, 200,
+		[]*structs.NodePool{{Name: "blue"}}))
+
+	job := testJob(4)
+	job.NodePool = structs.NodePoolAll
+	result, err := sim.RegisterJob(job)
+	must.NoError(t, err)
+	must.Len(t, 4, result.Placed)
+
+	onBlue := 0
+	for _, alloc := range result.Placed {
+		if alloc.NodeID == blue[0].ID || alloc.NodeID == blue[1].ID {
+			onBlue++
+		}
+	}
+	must.Positive(t, onBlue)
+
+	// The allocations on the removed nodes are replaced on the other nodes.
+	result, err = sim.RemoveNodePool("blue")
+	must.NoError(t, err)
+	must.Len(t, onBlue, result.Stopped)
+	must.Len(t, onBlue, result.Placed)
+	for _, alloc := range result.Placed {
+		must.NotEq(t, blue[0].ID, alloc.NodeID)
+		must.NotEq(t, blue[1].ID, alloc.NodeID)
+	}
+
+	util, err := sim.Utilization()
+	must.NoError(t, err)
+	must.Len(t, 2, util)
+
+	_, err = sim.RemoveNodePool("blue")
+	must.ErrorContains(t, err, `no node in node pool "blue"`)
+}
+
+func TestSimulator_RegisterJob_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	sim, _ := testSimulator(t, mock.Node())
+	job := mock.Job()
+	job.TaskGroups = nil
+	_, err := sim.RegisterJob(job)
+	must.ErrorContains(t, err, "is invalid")
+}
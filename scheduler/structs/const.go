@@ -43,6 +43,10 @@ const (
 	// that are a result of failing to place all allocations.
 	DescBlockedEvalFailedPlacements = "created to place remaining allocations"
 
+	// DescBlockedEvalJobDependencies is the description used for blocked
+	// evals that are waiting for the dependencies of the job.
+	DescBlockedEvalJobDependencies = "waiting for job dependencies"
+
 	// DescReschedulingFollowupEval is the description used when creating follow
 	// up evals for delayed rescheduling
 	DescReschedulingFollowupEval = "created for delayed rescheduling"
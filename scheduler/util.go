@@ -730,6 +730,7 @@ func desiredUpdates(diff *reconciler.NodeReconcileResult, inplaceUpdates,
 	incUpdates(diff.Migrate, func(des *structs.DesiredUpdates) { des.Migrate++ })
 	incUpdates(inplaceUpdates, func(des *structs.DesiredUpdates) { des.InPlaceUpdate++ })
 	incUpdates(destructiveUpdates, func(des *structs.DesiredUpdates) { des.DestructiveUpdate++ })
+	incUpdates(diff.Canary, func(des *structs.DesiredUpdates) { des.Canary++ })
 	incUpdates(diff.Disconnecting, func(des *structs.DesiredUpdates) { des.Disconnect++ })
 	incUpdates(diff.Reconnecting, func(des *structs.DesiredUpdates) { des.Reconnect++ })
 
@@ -915,3 +916,64 @@ func genericAllocUpdateFn(ctx feasible.Context, stack feasible.Stack, evalID str
 		return false, false, newAlloc
 	}
 }
+
+// unmetJobDependencies returns the dependencies of the job which haven't
+// reached their condition. Dependencies only hold the rollout of a new version
+// of the job, so none are returned once an allocation of the current version
+// exists.
+func unmetJobDependencies(state sstructs.State, namespace, jobID string) ([]*structs.JobDependency, error) {
+	ws := memdb.NewWatchSet()
+	job, err := state.JobByID(ws, namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %q: %v", jobID, err)
+	}
+	if job == nil || job.Stopped() || len(job.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	allocs, err := state.AllocsByJob(ws, namespace, jobID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocs for job %q: %v", jobID, err)
+	}
+	for _, alloc := range allocs {
+		if alloc.Job != nil && alloc.Job.Version == job.Version &&
+			alloc.Job.CreateIndex == job.CreateIndex {
+			return nil, nil
+		}
+	}
+
+	var unmet []*structs.JobDependency
+	for _, dep := range job.DependsOn {
+		satisfied, err := JobDependencySatisfied(state, namespace, dep)
+		if err != nil {
+			return nil, err
+		}
+		if !satisfied {
+			unmet = append(unmet, dep.Copy())
+		}
+	}
+	return unmet, nil
+}
+
+// JobDependencySatisfied returns whether the job depended on in the namespace
+// has reached the condition of the dependency in the passed state.
+func JobDependencySatisfied(state sstructs.State, namespace string, dep *structs.JobDependency) (bool, error) {
+	ws := memdb.NewWatchSet()
+	job, err := state.JobByID(ws, namespace, dep.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job %q: %v", dep.JobID, err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	allocs, err := state.AllocsByJob(ws, namespace, dep.JobID, true)
+	if err != nil {
+		return false, fmt.Errorf("failed to get allocs for job %q: %v", dep.JobID, err)
+	}
+	deployment, err := state.LatestDeploymentByJobID(ws, namespace, dep.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get deployment for job %q: %v", dep.JobID, err)
+	}
+	return dep.SatisfiedBy(job, allocs, deployment), nil
+}